@@ -147,6 +147,7 @@ func getConfigFileName() string {
 func setupEnvVarMappings() {
 	// Server
 	viper.BindEnv("server.port", "PORT")
+	viper.BindEnv("server.outbound_allowed_networks", "OUTBOUND_ALLOWED_NETWORKS")
 
 	// Database
 	viper.BindEnv("database.host", "HOST_NAME")
@@ -169,6 +170,9 @@ func setupEnvVarMappings() {
 
 	// Deployment
 	viper.BindEnv("deployment.mount_path", "MOUNT_PATH")
+	viper.BindEnv("deployment.templates_path", "TEMPLATES_PATH")
+	viper.BindEnv("deployment.release_command_timeout", "RELEASE_COMMAND_TIMEOUT")
+	viper.BindEnv("deployment.backups_path", "BACKUPS_PATH")
 
 	// Docker
 	viper.BindEnv("docker.host", "DOCKER_HOST")
@@ -177,6 +181,18 @@ func setupEnvVarMappings() {
 
 	// Proxy
 	viper.BindEnv("proxy.caddy_endpoint", "CADDY_ENDPOINT")
+	viper.BindEnv("proxy.certificate_expiry_days", "CERTIFICATE_EXPIRY_DAYS")
+	viper.BindEnv("proxy.nginx_config_dir", "NGINX_CONFIG_DIR")
+	viper.BindEnv("proxy.nginx_certificates_dir", "NGINX_CERTIFICATES_DIR")
+	viper.BindEnv("proxy.nginx_test_command", "NGINX_TEST_COMMAND")
+	viper.BindEnv("proxy.nginx_reload_command", "NGINX_RELOAD_COMMAND")
+	viper.BindEnv("proxy.layer4_listen", "LAYER4_LISTEN")
+	viper.BindEnv("proxy.reconcile_mode", "PROXY_RECONCILE_MODE")
+	viper.BindEnv("proxy.access_log_port", "ACCESS_LOG_PORT")
+	viper.BindEnv("proxy.access_log_listen_host", "ACCESS_LOG_LISTEN_HOST")
+	viper.BindEnv("proxy.access_log_allowed_ips", "ACCESS_LOG_ALLOWED_IPS")
+	viper.BindEnv("proxy.access_log_retention_hours", "ACCESS_LOG_RETENTION_HOURS")
+	viper.BindEnv("proxy.http_metrics_retention_days", "HTTP_METRICS_RETENTION_DAYS")
 
 	// CORS
 	viper.BindEnv("cors.allowed_origin", "ALLOWED_ORIGIN")
@@ -9,6 +9,7 @@ import (
 	"github.com/raghavyuva/nixopus-api/internal/features/auth/utils"
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
 	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	webhook_service "github.com/raghavyuva/nixopus-api/internal/features/webhooks/service"
 
 	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
 )
@@ -66,6 +67,7 @@ func (ar *AuthController) Login(c fuego.ContextWithBody[types.LoginRequest]) (*s
 	}
 
 	ar.Notify(notification.NotificationPayloadTypeLogin, &response.User, c.Request())
+	webhook_service.PublishLogin(&response.User, c.Request())
 
 	return &shared_types.Response{
 		Status:  "success",
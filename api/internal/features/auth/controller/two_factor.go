@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-fuego/fuego"
 	"github.com/raghavyuva/nixopus-api/internal/features/auth/types"
+	webhook_service "github.com/raghavyuva/nixopus-api/internal/features/webhooks/service"
 	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
 	"github.com/raghavyuva/nixopus-api/internal/utils"
 )
@@ -124,6 +125,8 @@ func (c *AuthController) TwoFactorLogin(ctx fuego.ContextWithBody[types.TwoFacto
 		}
 	}
 
+	webhook_service.PublishLogin(&response.User, ctx.Request())
+
 	return &shared_types.Response{
 		Status:  "success",
 		Message: "User logged in successfully",
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// CreateBackup starts a one-off backup. It returns as soon as the backup is started, its
+// progress can be followed through the status of the returned backup.
+func (c *BackupController) CreateBackup(f fuego.ContextWithBody[types.CreateBackupRequest]) (*shared_types.Response, error) {
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+	if err := requireVolumeAccess(user, request.ResourceType); err != nil {
+		return nil, err
+	}
+
+	backup, err := c.service.CreateBackup(request, user.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to start backup", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Backup started",
+		Data:    backup,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *BackupController) CreateSchedule(f fuego.ContextWithBody[types.CreateScheduleRequest]) (*shared_types.Response, error) {
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+	if err := requireVolumeAccess(user, request.ResourceType); err != nil {
+		return nil, err
+	}
+
+	schedule, err := c.service.CreateSchedule(request, user.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to create backup schedule", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Backup schedule created successfully",
+		Data:    schedule,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *BackupController) DeleteBackup(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	id, err := c.validator.ValidateID(f.PathParam("id"), types.ErrInvalidBackupID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	_, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.service.DeleteBackup(id, organizationID); err != nil {
+		c.logger.Log(logger.Error, "failed to delete backup", id.String()+": "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Backup deleted successfully",
+		Data:    nil,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *BackupController) DeleteSchedule(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	id, err := c.validator.ValidateID(f.PathParam("id"), types.ErrInvalidScheduleID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	_, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.service.DeleteSchedule(id, organizationID); err != nil {
+		c.logger.Log(logger.Error, "failed to delete backup schedule", id.String()+": "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Backup schedule deleted successfully",
+		Data:    nil,
+	}, nil
+}
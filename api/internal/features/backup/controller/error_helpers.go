@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	database_types "github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// backupErrorStatus maps errors of the backup service to HTTP statuses.
+func backupErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrBackupNotFound),
+		errors.Is(err, types.ErrScheduleNotFound),
+		errors.Is(err, types.ErrDestinationNotConfigured),
+		errors.Is(err, types.ErrDatabaseNotFound),
+		errors.Is(err, types.ErrVolumeNotFound),
+		errors.Is(err, types.ErrBackupResourceNotFound),
+		errors.Is(err, database_types.ErrDatabaseServiceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrBackupInProgress),
+		errors.Is(err, types.ErrBackupNotCompleted),
+		errors.Is(err, types.ErrDatabaseStopped),
+		errors.Is(err, database_types.ErrDatabaseNameAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrRestoreEngineMismatch),
+		errors.Is(err, types.ErrRestoreTargetConflict),
+		errors.Is(err, types.ErrMissingS3Settings),
+		errors.Is(err, types.ErrDestinationUnreachable):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrBackupChecksumMismatch):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// requestUser returns the user and organization of the request or an unauthorized error.
+func requestUser(w http.ResponseWriter, r *http.Request) (*shared_types.User, uuid.UUID, error) {
+	user := utils.GetUser(w, r)
+	if user == nil {
+		return nil, uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(r)
+	if organizationID == uuid.Nil {
+		return nil, uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+	return user, organizationID, nil
+}
+
+// requireVolumeAccess restricts backups and restores of volumes to admins, as restores replace
+// the data of the volume. The service only resolves volumes owned by the organization.
+func requireVolumeAccess(user *shared_types.User, resourceType shared_types.BackupResourceType) error {
+	if resourceType == shared_types.BackupResourceVolume && user.Type != shared_types.UserTypeAdmin {
+		return fuego.HTTPError{
+			Err:    types.ErrVolumeBackupsRequireAdmin,
+			Status: http.StatusForbidden,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetBackups lists the backups of the organization, newest first. The schedule_id query
+// parameter restricts the list to the backups taken by one schedule.
+func (c *BackupController) GetBackups(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	var scheduleID *uuid.UUID
+	if param := f.QueryParam("schedule_id"); param != "" {
+		id, err := c.validator.ValidateID(param, types.ErrInvalidScheduleID)
+		if err != nil {
+			return nil, fuego.HTTPError{
+				Err:    err,
+				Status: http.StatusBadRequest,
+			}
+		}
+		scheduleID = &id
+	}
+
+	_, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := c.service.GetBackups(organizationID, scheduleID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get backups", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Backups fetched successfully",
+		Data:    backups,
+	}, nil
+}
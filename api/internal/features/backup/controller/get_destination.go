@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *BackupController) GetDestination(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	_, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := c.service.GetDestination(organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get backup destination", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Backup destination fetched successfully",
+		Data:    destination,
+	}, nil
+}
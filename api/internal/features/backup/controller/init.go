@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/validation"
+	database_service "github.com/raghavyuva/nixopus-api/internal/features/database/service"
+	database_storage "github.com/raghavyuva/nixopus-api/internal/features/database/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type BackupController struct {
+	store        *shared_storage.Store
+	validator    *validation.Validator
+	service      *service.BackupService
+	ctx          context.Context
+	logger       logger.Logger
+	notification *notification.NotificationManager
+}
+
+func NewBackupController(
+	store *shared_storage.Store,
+	ctx context.Context,
+	l logger.Logger,
+	notificationManager *notification.NotificationManager,
+) *BackupController {
+	storage := storage.BackupStorage{DB: store.DB, Ctx: ctx}
+	docker_repo := docker.NewDockerService()
+	databases := database_service.NewDatabaseService(store, ctx, l, &database_storage.DatabaseStorage{DB: store.DB, Ctx: ctx}, docker_repo)
+	backupService := service.NewBackupService(store, ctx, l, &storage, docker_repo, databases, notificationManager)
+	backupService.StartScheduler(ctx)
+
+	return &BackupController{
+		store:        store,
+		validator:    validation.NewValidator(&storage),
+		service:      backupService,
+		ctx:          ctx,
+		logger:       l,
+		notification: notificationManager,
+	}
+}
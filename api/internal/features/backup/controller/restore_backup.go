@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// RestoreBackup restores a backup and waits for the restore to finish.
+func (c *BackupController) RestoreBackup(f fuego.ContextWithBody[types.RestoreBackupRequest]) (*shared_types.Response, error) {
+	id, err := c.validator.ValidateID(f.PathParam("id"), types.ErrInvalidBackupID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := c.service.GetBackup(id, organizationID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+	if err := requireVolumeAccess(user, backup.ResourceType); err != nil {
+		return nil, err
+	}
+
+	restored, err := c.service.RestoreBackup(id, request, user.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to restore backup", id.String()+": "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Backup restored successfully",
+		Data:    restored,
+	}, nil
+}
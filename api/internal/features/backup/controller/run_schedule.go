@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// RunSchedule takes a backup for a schedule right away.
+func (c *BackupController) RunSchedule(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	id, err := c.validator.ValidateID(f.PathParam("id"), types.ErrInvalidScheduleID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := c.service.GetSchedule(id, organizationID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+	if err := requireVolumeAccess(user, schedule.ResourceType); err != nil {
+		return nil, err
+	}
+
+	backup, err := c.service.RunSchedule(id, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to run backup schedule", id.String()+": "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: backupErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Backup started",
+		Data:    backup,
+	}, nil
+}
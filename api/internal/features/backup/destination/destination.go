@@ -0,0 +1,43 @@
+package destination
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// Destination stores backup files under keys such as org/database/name/file.
+type Destination interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the destination described by the backup settings of an organization.
+func New(config shared_types.BackupDestination) (Destination, error) {
+	switch config.Type {
+	case shared_types.BackupDestinationLocal:
+		return &Local{Root: LocalRoot(config.OrganizationID, config.LocalPath)}, nil
+	case shared_types.BackupDestinationS3:
+		return NewS3(config)
+	default:
+		return nil, fmt.Errorf("unsupported backup destination type: %s", config.Type)
+	}
+}
+
+// LocalRoot returns the directory the local backups of an organization are written to. The
+// path saved with the destination is always taken relative to the organization's directory in
+// BACKUPS_PATH, so organizations can not write outside of it or into each other's backups.
+func LocalRoot(organizationID uuid.UUID, localPath string) string {
+	base := config.AppConfig.Deployment.BackupsPath
+	if base == "" {
+		base = filepath.Join(config.AppConfig.Deployment.MountPath, "backups")
+	}
+	// cleaning below the root drops any .. that would climb out of the organization's directory
+	return filepath.Join(base, organizationID.String(), filepath.Clean(string(filepath.Separator)+localPath))
+}
@@ -0,0 +1,50 @@
+package destination
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalRoot(t *testing.T) {
+	previous := config.AppConfig.Deployment
+	defer func() { config.AppConfig.Deployment = previous }()
+
+	organizationID := uuid.MustParse("7b6b5f4e-8a52-4b8e-9f3a-0c1d2e3f4a5b")
+	org := organizationID.String()
+
+	tests := []struct {
+		name        string
+		backupsPath string
+		localPath   string
+		want        string
+	}{
+		{name: "Empty path", backupsPath: "/srv/backups", localPath: "", want: filepath.Join("/srv/backups", org)},
+		{name: "Relative path", backupsPath: "/srv/backups", localPath: "daily", want: filepath.Join("/srv/backups", org, "daily")},
+		{name: "Legacy absolute path", backupsPath: "/srv/backups", localPath: "/var/backups", want: filepath.Join("/srv/backups", org, "var/backups")},
+		{name: "Parent directories are dropped", backupsPath: "/srv/backups", localPath: "../../etc", want: filepath.Join("/srv/backups", org, "etc")},
+		{name: "Defaults to the mount path", backupsPath: "", localPath: "daily", want: filepath.Join("/etc/nixopus", "backups", org, "daily")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig.Deployment.MountPath = "/etc/nixopus"
+			config.AppConfig.Deployment.BackupsPath = tt.backupsPath
+			assert.Equal(t, tt.want, LocalRoot(organizationID, tt.localPath))
+		})
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	local := &Local{Root: "/srv/backups/org"}
+
+	p, err := local.path("db/name/backup.sql.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("/srv/backups/org", "db/name/backup.sql.gz"), p)
+
+	_, err = local.path("../other/backup.sql.gz")
+	assert.Error(t, err)
+}
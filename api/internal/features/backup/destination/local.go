@@ -0,0 +1,69 @@
+package destination
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores backups in a directory of the host running the API.
+type Local struct {
+	Root string
+}
+
+func (l *Local) path(key string) (string, error) {
+	p := filepath.Join(l.Root, filepath.FromSlash(key))
+	if !strings.HasPrefix(p, filepath.Clean(l.Root)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid backup key: %s", key)
+	}
+	return p, nil
+}
+
+func (l *Local) Put(ctx context.Context, key string, body io.Reader, size int64) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+
+	// write next to the target and rename, so a failed upload never leaves a partial backup
+	tmp := p + ".partial"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/destination"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (s *BackupService) GetDestination(organizationID uuid.UUID) (*shared_types.BackupDestination, error) {
+	return s.storage.GetDestination(organizationID)
+}
+
+// UpsertDestination saves the backup destination of an organization after checking that a
+// file can be written to it. An empty secret key keeps the one that is already stored.
+func (s *BackupService) UpsertDestination(req types.UpsertDestinationRequest, organizationID uuid.UUID) (*shared_types.BackupDestination, error) {
+	config := &shared_types.BackupDestination{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Type:           req.Type,
+		LocalPath:      req.LocalPath,
+		Endpoint:       req.Endpoint,
+		Region:         req.Region,
+		Bucket:         req.Bucket,
+		Prefix:         strings.Trim(req.Prefix, "/"),
+		AccessKey:      req.AccessKey,
+		SecretKey:      req.SecretKey,
+		UseSSL:         true,
+		ForcePathStyle: true,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if req.UseSSL != nil {
+		config.UseSSL = *req.UseSSL
+	}
+	if req.ForcePathStyle != nil {
+		config.ForcePathStyle = *req.ForcePathStyle
+	}
+	if config.Type == shared_types.BackupDestinationS3 && config.SecretKey == "" {
+		if existing, err := s.storage.GetDestination(organizationID); err == nil && existing.Type == shared_types.BackupDestinationS3 {
+			config.SecretKey = existing.SecretKey
+		}
+	}
+	if config.Type == shared_types.BackupDestinationS3 && config.SecretKey == "" {
+		return nil, types.ErrMissingS3Settings
+	}
+
+	if err := checkDestination(s.Ctx, *config); err != nil {
+		return nil, fmt.Errorf("%w: %v", types.ErrDestinationUnreachable, err)
+	}
+
+	if err := s.storage.UpsertDestination(config); err != nil {
+		return nil, err
+	}
+	return s.storage.GetDestination(organizationID)
+}
+
+// checkDestination writes and removes a small file to make sure backups can be uploaded.
+func checkDestination(ctx context.Context, config shared_types.BackupDestination) error {
+	dest, err := destination.New(config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	key := path.Join(config.Prefix, ".nixopus-check")
+	if err := dest.Put(ctx, key, strings.NewReader("ok"), 2); err != nil {
+		return err
+	}
+	return dest.Delete(ctx, key)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/destination"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (s *BackupService) GetBackups(organizationID uuid.UUID, scheduleID *uuid.UUID) ([]shared_types.Backup, error) {
+	return s.storage.GetBackups(organizationID, scheduleID)
+}
+
+func (s *BackupService) GetBackup(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Backup, error) {
+	return s.storage.GetBackup(id, organizationID)
+}
+
+// DeleteBackup removes a backup from the destination and deletes its record.
+func (s *BackupService) DeleteBackup(id uuid.UUID, organizationID uuid.UUID) error {
+	backup, err := s.storage.GetBackup(id, organizationID)
+	if err != nil {
+		return err
+	}
+	if backup.Status == shared_types.BackupStatusRunning {
+		return types.ErrBackupInProgress
+	}
+
+	if backup.Status == shared_types.BackupStatusCompleted {
+		config, err := s.storage.GetDestination(organizationID)
+		if err != nil {
+			return err
+		}
+		dest, err := destination.New(*config)
+		if err != nil {
+			return err
+		}
+		if err := dest.Delete(s.Ctx, backup.StorageKey); err != nil {
+			return err
+		}
+	}
+	return s.storage.DeleteBackup(backup.ID)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+// helperFile is the path backups are written to and read from inside helper containers.
+const helperFile = "/tmp/nixopus-backup"
+
+// helperJob is a shell command run in a throwaway container to dump or restore a resource.
+type helperJob struct {
+	Image   string
+	Command string
+	Env     []string
+	// Network is the ID of the network the container joins to reach a database.
+	Network string
+	Mounts  []mount.Mount
+}
+
+// runHelper runs a job to completion. When input is set it is copied to helperFile before the
+// job starts, when output is set helperFile is copied to it once the job succeeded.
+func (s *BackupService) runHelper(ctx context.Context, job helperJob, input *os.File, output io.Writer) error {
+	if err := s.docker.PullImage(job.Image); err != nil {
+		// the image may still be available locally
+		s.logger.Log(logger.Warning, "failed to pull backup helper image", job.Image+": "+err.Error())
+	}
+
+	containerConfig := container.Config{
+		Image:      job.Image,
+		Entrypoint: []string{"/bin/sh", "-c"},
+		Cmd:        []string{job.Command},
+		Env:        job.Env,
+		Labels: map[string]string{
+			"com.nixopus.backup.helper": "true",
+		},
+	}
+	hostConfig := container.HostConfig{
+		Mounts: job.Mounts,
+	}
+	networkConfig := network.NetworkingConfig{}
+	if job.Network != "" {
+		hostConfig.NetworkMode = container.NetworkMode(job.Network)
+		networkConfig.EndpointsConfig = map[string]*network.EndpointSettings{
+			job.Network: {},
+		}
+	}
+
+	resp, err := s.docker.CreateContainer(containerConfig, hostConfig, networkConfig, "")
+	if err != nil {
+		return fmt.Errorf("failed to create helper container: %w", err)
+	}
+	defer func() {
+		if err := s.docker.RemoveContainer(resp.ID, container.RemoveOptions{Force: true}); err != nil {
+			s.logger.Log(logger.Error, "failed to remove backup helper container", err.Error())
+		}
+	}()
+
+	if input != nil {
+		if err := s.copyFileToContainer(resp.ID, input); err != nil {
+			return fmt.Errorf("failed to copy backup into helper container: %w", err)
+		}
+	}
+
+	waitC, waitErrC := s.docker.WaitContainer(ctx, resp.ID, container.WaitConditionNextExit)
+	if err := s.docker.StartContainer(resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start helper container: %w", err)
+	}
+
+	select {
+	case result := <-waitC:
+		if result.Error != nil {
+			return fmt.Errorf("%s: %s", result.Error.Message, s.helperOutput(resp.ID))
+		}
+		if result.StatusCode != 0 {
+			return fmt.Errorf("helper exited with code %d: %s", result.StatusCode, s.helperOutput(resp.ID))
+		}
+	case err := <-waitErrC:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if output == nil {
+		return nil
+	}
+
+	archive, err := s.docker.CopyFromContainer(resp.ID, helperFile)
+	if err != nil {
+		return fmt.Errorf("failed to copy backup from helper container: %w", err)
+	}
+	defer archive.Close()
+
+	reader := tar.NewReader(archive)
+	if _, err := reader.Next(); err != nil {
+		return fmt.Errorf("failed to read backup from helper container: %w", err)
+	}
+	_, err = io.Copy(output, reader)
+	return err
+}
+
+// copyFileToContainer copies f to helperFile inside the container.
+func (s *BackupService) copyFileToContainer(containerID string, f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		writer := tar.NewWriter(pw)
+		err := writer.WriteHeader(&tar.Header{
+			Name:    path.Base(helperFile),
+			Mode:    0o644,
+			Size:    info.Size(),
+			ModTime: time.Now(),
+		})
+		if err == nil {
+			_, err = io.Copy(writer, f)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return s.docker.CopyToContainer(containerID, path.Dir(helperFile), pr)
+}
+
+// helperOutput returns the last lines a helper container logged, to explain why it failed.
+func (s *BackupService) helperOutput(containerID string) string {
+	logs, err := s.docker.ContainerLogs(context.Background(), containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       "20",
+	})
+	if err != nil {
+		return "no output"
+	}
+	defer logs.Close()
+
+	var output bytes.Buffer
+	stdcopy.StdCopy(&output, &output, logs)
+	return strings.TrimSpace(output.String())
+}
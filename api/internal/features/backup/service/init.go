@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/storage"
+	database_service "github.com/raghavyuva/nixopus-api/internal/features/database/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type BackupService struct {
+	storage      storage.BackupStorageInterface
+	docker       docker.DockerRepository
+	databases    *database_service.DatabaseService
+	notification *notification.NotificationManager
+	Ctx          context.Context
+	store        *shared_storage.Store
+	logger       logger.Logger
+	// running holds the resources a backup or restore is in progress for
+	running sync.Map
+}
+
+func NewBackupService(
+	store *shared_storage.Store,
+	ctx context.Context,
+	logger logger.Logger,
+	backup_repo storage.BackupStorageInterface,
+	dockerRepo docker.DockerRepository,
+	databases *database_service.DatabaseService,
+	notificationManager *notification.NotificationManager,
+) *BackupService {
+	return &BackupService{
+		storage:      backup_repo,
+		docker:       dockerRepo,
+		databases:    databases,
+		notification: notificationManager,
+		store:        store,
+		Ctx:          ctx,
+		logger:       logger,
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	database_types "github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// volumeHelperImage archives and extracts volumes.
+const volumeHelperImage = "alpine:3"
+
+// composeProjectLabel is set by docker compose on the volumes it creates. The project of a
+// compose application is named after the application ID.
+const composeProjectLabel = "com.docker.compose.project"
+
+// waitForDatabase is prepended to restore commands, a database that was just created can
+// take a while before it accepts connections.
+var waitForDatabase = map[shared_types.DatabaseEngine]string{
+	shared_types.DatabaseEnginePostgres: `for i in $(seq 90); do pg_isready -h "$DB_HOST" -p "$DB_PORT" -q && break; sleep 2; done; `,
+	shared_types.DatabaseEngineMySQL:    `for i in $(seq 90); do mysqladmin ping -h "$DB_HOST" -P "$DB_PORT" -u root --silent && break; sleep 2; done; `,
+	shared_types.DatabaseEngineMongoDB:  `for i in $(seq 90); do mongosh --quiet "$DB_URI" --eval 'db.runCommand({ping: 1})' >/dev/null 2>&1 && break; sleep 2; done; `,
+}
+
+var dumpCommands = map[shared_types.DatabaseEngine]string{
+	shared_types.DatabaseEnginePostgres: `pg_dump -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -Fc -f ` + helperFile,
+	shared_types.DatabaseEngineMySQL:    `mysqldump -h "$DB_HOST" -P "$DB_PORT" -u root --single-transaction --routines --triggers "$DB_NAME" > ` + helperFile,
+	shared_types.DatabaseEngineRedis:    `redis-cli -h "$DB_HOST" -p "$DB_PORT" -a "$REDIS_PASSWORD" --no-auth-warning --rdb ` + helperFile,
+	shared_types.DatabaseEngineMongoDB:  `mongodump --uri="$DB_URI/$DB_NAME?authSource=admin" --archive=` + helperFile + ` --gzip`,
+}
+
+var restoreCommands = map[shared_types.DatabaseEngine]string{
+	shared_types.DatabaseEnginePostgres: `pg_restore -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" --clean --if-exists --no-owner --no-privileges ` + helperFile,
+	shared_types.DatabaseEngineMySQL:    `mysql -h "$DB_HOST" -P "$DB_PORT" -u root "$DB_NAME" < ` + helperFile,
+	shared_types.DatabaseEngineMongoDB:  `mongorestore --uri="$DB_URI" --archive=` + helperFile + ` --gzip --drop --nsFrom="$SOURCE_DB_NAME.*" --nsTo="$DB_NAME.*"`,
+	// redis can only load a snapshot at startup, so the file is placed in the stopped database volume
+	shared_types.DatabaseEngineRedis: `rm -rf /data/appendonlydir /data/appendonly.aof && cp ` + helperFile + ` /data/dump.rdb && (chown 999:999 /data/dump.rdb || true)`,
+}
+
+var fileExtensions = map[shared_types.DatabaseEngine]string{
+	shared_types.DatabaseEnginePostgres: ".dump",
+	shared_types.DatabaseEngineMySQL:    ".sql",
+	shared_types.DatabaseEngineRedis:    ".rdb",
+	shared_types.DatabaseEngineMongoDB:  ".archive.gz",
+}
+
+// resource is a database or a volume that is backed up or restored.
+type resource struct {
+	Type     shared_types.BackupResourceType
+	Database *shared_types.Database
+	Volume   string
+}
+
+// key identifies the resource while a backup or restore of it is running.
+func (r resource) key() string {
+	if r.Type == shared_types.BackupResourceDatabase {
+		return "database:" + r.Database.ID.String()
+	}
+	return "volume:" + r.Volume
+}
+
+func (r resource) name() string {
+	if r.Type == shared_types.BackupResourceDatabase {
+		return r.Database.Name
+	}
+	return r.Volume
+}
+
+func (r resource) extension() string {
+	if r.Type == shared_types.BackupResourceDatabase {
+		return fileExtensions[r.Database.Engine]
+	}
+	return ".tar.gz"
+}
+
+// resolveResource loads the database or checks the volume a backup is requested for. Volumes
+// are only resolved when they belong to the organization, other volumes are reported as not
+// found so that their names are not disclosed.
+func (s *BackupService) resolveResource(organizationID uuid.UUID, resourceType shared_types.BackupResourceType, databaseID *uuid.UUID, volumeName string) (resource, error) {
+	if resourceType == shared_types.BackupResourceDatabase {
+		database, err := s.storage.GetDatabase(*databaseID, organizationID)
+		if err != nil {
+			return resource{}, err
+		}
+		if database.Status != shared_types.DatabaseStatusRunning {
+			return resource{}, types.ErrDatabaseStopped
+		}
+		return resource{Type: resourceType, Database: database}, nil
+	}
+
+	volumes, err := s.docker.GetClusterVolumes()
+	if err != nil {
+		return resource{}, err
+	}
+	for _, v := range volumes {
+		if v.Name != volumeName {
+			continue
+		}
+		owned, err := s.volumeOwned(organizationID, v)
+		if err != nil {
+			return resource{}, err
+		}
+		if !owned {
+			return resource{}, types.ErrVolumeNotFound
+		}
+		return resource{Type: resourceType, Volume: volumeName}, nil
+	}
+	return resource{}, types.ErrVolumeNotFound
+}
+
+// volumeOwned reports whether v belongs to a compose application or a database of the organization.
+func (s *BackupService) volumeOwned(organizationID uuid.UUID, v *volume.Volume) (bool, error) {
+	if applicationID, err := uuid.Parse(v.Labels[composeProjectLabel]); err == nil {
+		owned, err := s.storage.ApplicationExists(applicationID, organizationID)
+		if err != nil || owned {
+			return owned, err
+		}
+	}
+	return s.storage.DatabaseVolumeExists(v.Name, organizationID)
+}
+
+func (s *BackupService) dumpJob(r resource) (helperJob, error) {
+	if r.Type == shared_types.BackupResourceVolume {
+		return helperJob{
+			Image:   volumeHelperImage,
+			Command: "tar -czf " + helperFile + " -C /data .",
+			Mounts:  []mount.Mount{{Type: mount.TypeVolume, Source: r.Volume, Target: "/data", ReadOnly: true}},
+		}, nil
+	}
+
+	networkID, err := s.databaseNetwork(r.Database)
+	if err != nil {
+		return helperJob{}, err
+	}
+	return helperJob{
+		Image:   database_types.Image(*r.Database),
+		Command: dumpCommands[r.Database.Engine],
+		Env:     databaseEnv(r.Database),
+		Network: networkID,
+	}, nil
+}
+
+// restoreJob returns the job restoring a backup into r. sourceName is the name of the
+// database the backup was taken from, which may differ from the one it is restored into.
+func (s *BackupService) restoreJob(r resource, sourceName string) (helperJob, error) {
+	if r.Type == shared_types.BackupResourceVolume {
+		return helperJob{
+			Image:   volumeHelperImage,
+			Command: "find /data -mindepth 1 -delete && tar -xzf " + helperFile + " -C /data",
+			Mounts:  []mount.Mount{{Type: mount.TypeVolume, Source: r.Volume, Target: "/data"}},
+		}, nil
+	}
+
+	if r.Database.Engine == shared_types.DatabaseEngineRedis {
+		return helperJob{
+			Image:   database_types.Image(*r.Database),
+			Command: restoreCommands[r.Database.Engine],
+			Mounts:  []mount.Mount{{Type: mount.TypeVolume, Source: r.Database.VolumeName, Target: "/data"}},
+		}, nil
+	}
+
+	networkID, err := s.databaseNetwork(r.Database)
+	if err != nil {
+		return helperJob{}, err
+	}
+	return helperJob{
+		Image:   database_types.Image(*r.Database),
+		Command: waitForDatabase[r.Database.Engine] + restoreCommands[r.Database.Engine],
+		Env:     append(databaseEnv(r.Database), "SOURCE_DB_NAME="+sourceName),
+		Network: networkID,
+	}, nil
+}
+
+func (s *BackupService) databaseNetwork(database *shared_types.Database) (string, error) {
+	return s.docker.CreateNetwork(database_types.NetworkName(database.OrganizationID), database_types.NetworkOptions())
+}
+
+// databaseEnv passes the connection settings to helper containers through the environment,
+// so that credentials never appear in their command line.
+func databaseEnv(database *shared_types.Database) []string {
+	env := []string{
+		"DB_HOST=" + database.ServiceName,
+		"DB_PORT=" + strconv.Itoa(database.Port),
+		"DB_USER=" + database.Username,
+		"DB_NAME=" + database.DatabaseName,
+	}
+	switch database.Engine {
+	case shared_types.DatabaseEnginePostgres:
+		env = append(env, "PGPASSWORD="+database.Password)
+	case shared_types.DatabaseEngineMySQL:
+		env = append(env, "MYSQL_PWD="+database.Password)
+	case shared_types.DatabaseEngineRedis:
+		env = append(env, "REDIS_PASSWORD="+database.Password)
+	case shared_types.DatabaseEngineMongoDB:
+		u := url.URL{
+			Scheme: "mongodb",
+			User:   url.UserPassword(database.Username, database.Password),
+			Host:   database.ServiceName + ":" + strconv.Itoa(database.Port),
+		}
+		env = append(env, "DB_URI="+u.String())
+	}
+	return env
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/volume"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ownedResources answers the ownership lookups of a single organization.
+type ownedResources struct {
+	storage.BackupStorageInterface
+	organizationID  uuid.UUID
+	applications    []uuid.UUID
+	databaseVolumes []string
+}
+
+func (s *ownedResources) ApplicationExists(id uuid.UUID, organizationID uuid.UUID) (bool, error) {
+	for _, application := range s.applications {
+		if application == id && organizationID == s.organizationID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *ownedResources) DatabaseVolumeExists(volumeName string, organizationID uuid.UUID) (bool, error) {
+	for _, name := range s.databaseVolumes {
+		if name == volumeName && organizationID == s.organizationID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type clusterVolumes struct {
+	docker.DockerRepository
+	volumes []*volume.Volume
+}
+
+func (d *clusterVolumes) GetClusterVolumes() ([]*volume.Volume, error) {
+	return d.volumes, nil
+}
+
+func TestResolveVolume(t *testing.T) {
+	organizationID := uuid.New()
+	applicationID := uuid.New()
+	foreignApplicationID := uuid.New()
+
+	s := &BackupService{
+		storage: &ownedResources{
+			organizationID:  organizationID,
+			applications:    []uuid.UUID{applicationID},
+			databaseVolumes: []string{"nixopus-db-orders-data"},
+		},
+		docker: &clusterVolumes{volumes: []*volume.Volume{
+			{Name: applicationID.String() + "_uploads", Labels: map[string]string{composeProjectLabel: applicationID.String()}},
+			{Name: foreignApplicationID.String() + "_uploads", Labels: map[string]string{composeProjectLabel: foreignApplicationID.String()}},
+			{Name: "nixopus-db-orders-data"},
+			{Name: "nixopus-db-billing-data"},
+			{Name: "nixopus_db-data", Labels: map[string]string{composeProjectLabel: "nixopus"}},
+		}},
+	}
+
+	tests := []struct {
+		name           string
+		volume         string
+		organizationID uuid.UUID
+		wantErr        error
+	}{
+		{name: "Volume of a compose application", volume: applicationID.String() + "_uploads", organizationID: organizationID},
+		{name: "Volume of a database", volume: "nixopus-db-orders-data", organizationID: organizationID},
+		{name: "Volume of another organization's application", volume: foreignApplicationID.String() + "_uploads", organizationID: organizationID, wantErr: types.ErrVolumeNotFound},
+		{name: "Volume of another organization's database", volume: "nixopus-db-billing-data", organizationID: organizationID, wantErr: types.ErrVolumeNotFound},
+		{name: "Volume of Nixopus", volume: "nixopus_db-data", organizationID: organizationID, wantErr: types.ErrVolumeNotFound},
+		{name: "Owned volume requested by another organization", volume: applicationID.String() + "_uploads", organizationID: uuid.New(), wantErr: types.ErrVolumeNotFound},
+		{name: "Missing volume", volume: "missing", organizationID: organizationID, wantErr: types.ErrVolumeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := s.resolveResource(tt.organizationID, shared_types.BackupResourceVolume, nil, tt.volume)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.volume, r.Volume)
+		})
+	}
+}
+
+func TestResolveRestoreVolume(t *testing.T) {
+	organizationID := uuid.New()
+	s := &BackupService{
+		storage: &ownedResources{organizationID: organizationID, databaseVolumes: []string{"nixopus-db-orders-data"}},
+		docker: &clusterVolumes{volumes: []*volume.Volume{
+			{Name: "nixopus-db-orders-data"},
+			{Name: "nixopus-db-billing-data"},
+		}},
+	}
+	backup := &shared_types.Backup{ResourceType: shared_types.BackupResourceVolume, VolumeName: "nixopus-db-orders-data"}
+
+	r, err := s.resolveRestoreTarget(backup, types.RestoreBackupRequest{}, uuid.New(), organizationID)
+	require.NoError(t, err)
+	assert.Equal(t, "nixopus-db-orders-data", r.Volume)
+
+	_, err = s.resolveRestoreTarget(backup, types.RestoreBackupRequest{VolumeName: "nixopus-db-billing-data"}, uuid.New(), organizationID)
+	assert.ErrorIs(t, err, types.ErrVolumeNotFound)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/destination"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	database_types "github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// RestoreBackup restores a completed backup into the resource it was taken from or into the
+// target chosen in the request. The backup is verified against its checksum before anything
+// is overwritten.
+func (s *BackupService) RestoreBackup(id uuid.UUID, req types.RestoreBackupRequest, userID uuid.UUID, organizationID uuid.UUID) (*types.RestoreBackupResponse, error) {
+	backup, err := s.storage.GetBackup(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if backup.Status != shared_types.BackupStatusCompleted {
+		return nil, types.ErrBackupNotCompleted
+	}
+
+	config, err := s.storage.GetDestination(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := destination.New(*config)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.resolveRestoreTarget(backup, req, userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, busy := s.running.LoadOrStore(target.key(), struct{}{}); busy {
+		return nil, types.ErrBackupInProgress
+	}
+	defer s.running.Delete(target.key())
+
+	startedAt := time.Now()
+	if err := s.restore(backup, target, dest); err != nil {
+		s.logger.Log(logger.Error, "restore failed", target.key()+": "+err.Error())
+		s.notifyFailure("Restore", target.Type, target.name(), backup.ID, err, startedAt, userID)
+		if errors.Is(err, types.ErrBackupChecksumMismatch) {
+			return nil, err
+		}
+		return nil, errors.Join(types.ErrRestoreFailed, err)
+	}
+
+	response := &types.RestoreBackupResponse{ResourceType: target.Type, VolumeName: target.Volume}
+	if target.Database != nil {
+		response.DatabaseID = &target.Database.ID
+	}
+	return response, nil
+}
+
+// resolveRestoreTarget returns the resource a backup is restored into, creating a new
+// database when one is requested.
+func (s *BackupService) resolveRestoreTarget(backup *shared_types.Backup, req types.RestoreBackupRequest, userID uuid.UUID, organizationID uuid.UUID) (resource, error) {
+	if backup.ResourceType == shared_types.BackupResourceVolume {
+		volume := backup.VolumeName
+		if req.VolumeName != "" {
+			volume = req.VolumeName
+		}
+		r, err := s.resolveResource(organizationID, backup.ResourceType, nil, volume)
+		if errors.Is(err, types.ErrVolumeNotFound) && req.VolumeName == "" {
+			return resource{}, types.ErrBackupResourceNotFound
+		}
+		return r, err
+	}
+
+	if req.DatabaseID != nil && req.NewDatabaseName != "" {
+		return resource{}, types.ErrRestoreTargetConflict
+	}
+
+	if req.NewDatabaseName != "" {
+		created, err := s.databases.CreateDatabase(database_types.CreateDatabaseRequest{
+			Name:         req.NewDatabaseName,
+			Engine:       backup.DatabaseEngine,
+			Version:      backup.DatabaseVersion,
+			DatabaseName: backup.DatabaseName,
+		}, userID, organizationID)
+		if err != nil {
+			return resource{}, err
+		}
+		return resource{Type: backup.ResourceType, Database: &created.Database}, nil
+	}
+
+	databaseID := backup.DatabaseID
+	if req.DatabaseID != nil {
+		databaseID = req.DatabaseID
+	}
+	if databaseID == nil {
+		return resource{}, types.ErrBackupResourceNotFound
+	}
+	r, err := s.resolveResource(organizationID, backup.ResourceType, databaseID, "")
+	if err != nil {
+		if errors.Is(err, types.ErrDatabaseNotFound) && req.DatabaseID == nil {
+			return resource{}, types.ErrBackupResourceNotFound
+		}
+		return resource{}, err
+	}
+	if r.Database.Engine != backup.DatabaseEngine {
+		return resource{}, types.ErrRestoreEngineMismatch
+	}
+	return r, nil
+}
+
+// restore downloads the backup, verifies it and runs the restore job against the target.
+func (s *BackupService) restore(backup *shared_types.Backup, target resource, dest destination.Destination) error {
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+
+	file, err := s.download(ctx, backup, dest)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	job, err := s.restoreJob(target, backup.DatabaseName)
+	if err != nil {
+		return err
+	}
+
+	if target.Database != nil && target.Database.Engine == shared_types.DatabaseEngineRedis {
+		return s.whileStopped(ctx, target.Database, func() error {
+			return s.runHelper(ctx, job, file, io.Discard)
+		})
+	}
+	return s.runHelper(ctx, job, file, io.Discard)
+}
+
+// download fetches a backup into a temporary file and checks it against the recorded checksum.
+func (s *BackupService) download(ctx context.Context, backup *shared_types.Backup, dest destination.Destination) (*os.File, error) {
+	body, err := dest.Get(ctx, backup.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	file, err := os.CreateTemp("", "nixopus-restore-*")
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), body); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != backup.Checksum {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, types.ErrBackupChecksumMismatch
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+// whileStopped scales the service of a database down, runs fn against its volume and scales
+// it back up, whether fn succeeded or not.
+func (s *BackupService) whileStopped(ctx context.Context, database *shared_types.Database, fn func() error) error {
+	service, err := s.findService(database.ServiceName)
+	if err != nil {
+		return err
+	}
+	if err := s.docker.ScaleService(service.ID, 0, ""); err != nil {
+		return err
+	}
+	defer func() {
+		if err := s.docker.ScaleService(service.ID, 1, ""); err != nil {
+			s.logger.Log(logger.Error, "failed to start database after restore", database.ServiceName+": "+err.Error())
+		}
+	}()
+
+	for {
+		running, _, err := s.docker.GetServiceHealth(*service)
+		if err != nil {
+			return err
+		}
+		if running == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+	return fn()
+}
+
+func (s *BackupService) findService(name string) (*swarm.Service, error) {
+	services, err := s.docker.GetClusterServices()
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		if service.Spec.Annotations.Name == name {
+			return &service, nil
+		}
+	}
+	return nil, database_types.ErrDatabaseServiceNotFound
+}
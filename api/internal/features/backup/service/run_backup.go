@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/destination"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// backupTimeout bounds a single backup or restore, including the transfer to the destination.
+const backupTimeout = 6 * time.Hour
+
+var unsafeKeyCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// CreateBackup starts a one-off backup of a database or volume. The backup runs in the
+// background, its status is tracked on the returned record.
+func (s *BackupService) CreateBackup(req types.CreateBackupRequest, userID uuid.UUID, organizationID uuid.UUID) (*shared_types.Backup, error) {
+	r, err := s.resolveResource(organizationID, req.ResourceType, req.DatabaseID, req.VolumeName)
+	if err != nil {
+		return nil, err
+	}
+	return s.startBackup(r, nil, userID, organizationID)
+}
+
+// startBackup records a running backup of r and runs it in the background.
+func (s *BackupService) startBackup(r resource, schedule *shared_types.BackupSchedule, userID uuid.UUID, organizationID uuid.UUID) (*shared_types.Backup, error) {
+	config, err := s.storage.GetDestination(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := destination.New(*config)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, busy := s.running.LoadOrStore(r.key(), struct{}{}); busy {
+		return nil, types.ErrBackupInProgress
+	}
+
+	backup := &shared_types.Backup{
+		ID:              uuid.New(),
+		OrganizationID:  organizationID,
+		ResourceType:    r.Type,
+		VolumeName:      r.Volume,
+		Status:          shared_types.BackupStatusRunning,
+		DestinationType: config.Type,
+		StartedAt:       time.Now(),
+	}
+	if schedule != nil {
+		backup.ScheduleID = &schedule.ID
+	}
+	if r.Database != nil {
+		backup.DatabaseID = &r.Database.ID
+		backup.DatabaseEngine = r.Database.Engine
+		backup.DatabaseVersion = r.Database.Version
+		backup.DatabaseName = r.Database.DatabaseName
+	}
+	backup.StorageKey = path.Join(
+		config.Prefix,
+		organizationID.String(),
+		string(r.Type),
+		unsafeKeyCharacters.ReplaceAllString(r.name(), "_"),
+		backup.StartedAt.UTC().Format("20060102T150405Z")+"-"+backup.ID.String()[:8]+r.extension(),
+	)
+
+	if err := s.storage.CreateBackup(backup); err != nil {
+		s.running.Delete(r.key())
+		return nil, err
+	}
+
+	result := *backup
+	go func() {
+		defer s.running.Delete(r.key())
+
+		if err := s.runBackup(r, backup, dest); err != nil {
+			s.logger.Log(logger.Error, "backup failed", r.key()+": "+err.Error())
+			now := time.Now()
+			backup.Status = shared_types.BackupStatusFailed
+			backup.Error = err.Error()
+			backup.CompletedAt = &now
+			if err := s.storage.UpdateBackup(backup); err != nil {
+				s.logger.Log(logger.Error, "failed to record backup failure", err.Error())
+			}
+			s.notifyFailure("Backup", r.Type, r.name(), backup.ID, err, backup.StartedAt, userID)
+			return
+		}
+
+		if schedule != nil {
+			s.pruneBackups(schedule, dest)
+		}
+	}()
+
+	return &result, nil
+}
+
+// runBackup dumps the resource into a temporary file, hashing it on the way, and uploads it.
+func (s *BackupService) runBackup(r resource, backup *shared_types.Backup, dest destination.Destination) error {
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+
+	job, err := s.dumpJob(r)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "nixopus-backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	hash := sha256.New()
+	if err := s.runHelper(ctx, job, nil, io.MultiWriter(file, hash)); err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := dest.Put(ctx, backup.StorageKey, file, size); err != nil {
+		return fmt.Errorf("failed to upload backup: %w", err)
+	}
+
+	now := time.Now()
+	backup.Status = shared_types.BackupStatusCompleted
+	backup.Size = size
+	backup.Checksum = hex.EncodeToString(hash.Sum(nil))
+	backup.CompletedAt = &now
+	return s.storage.UpdateBackup(backup)
+}
+
+// pruneBackups removes the backups of a schedule beyond its retention count.
+func (s *BackupService) pruneBackups(schedule *shared_types.BackupSchedule, dest destination.Destination) {
+	expired, err := s.storage.GetExpiredBackups(schedule.ID, schedule.RetentionCount)
+	if err != nil {
+		s.logger.Log(logger.Error, "failed to list expired backups", err.Error())
+		return
+	}
+	for _, backup := range expired {
+		if err := dest.Delete(s.Ctx, backup.StorageKey); err != nil {
+			s.logger.Log(logger.Error, "failed to delete expired backup", backup.StorageKey+": "+err.Error())
+			continue
+		}
+		if err := s.storage.DeleteBackup(backup.ID); err != nil {
+			s.logger.Log(logger.Error, "failed to delete expired backup record", err.Error())
+		}
+	}
+}
+
+func (s *BackupService) notifyFailure(operation string, resourceType shared_types.BackupResourceType, name string, backupID uuid.UUID, err error, startedAt time.Time, userID uuid.UUID) {
+	if s.notification == nil {
+		return
+	}
+	s.notification.SendNotification(notification.NewNotificationPayload(
+		notification.NotificationPayloadTypeBackupFailed,
+		userID.String(),
+		notification.BackupFailedData{
+			Operation:    operation,
+			ResourceType: string(resourceType),
+			ResourceName: name,
+			BackupID:     backupID.String(),
+			Error:        err.Error(),
+			StartedAt:    startedAt,
+		},
+		notification.NotificationCategoryBackup,
+	))
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+// scheduleCheckInterval is how often due backup schedules are looked up.
+const scheduleCheckInterval = time.Minute
+
+// StartScheduler runs due backup schedules until ctx is done. Backups left running by a
+// previous instance of the API are marked as failed first.
+func (s *BackupService) StartScheduler(ctx context.Context) {
+	if err := s.storage.FailRunningBackups(); err != nil {
+		s.logger.Log(logger.Error, "Failed to mark interrupted backups as failed", err.Error())
+	}
+
+	go func() {
+		ticker := time.NewTicker(scheduleCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runDueSchedules()
+			}
+		}
+	}()
+}
+
+func (s *BackupService) runDueSchedules() {
+	schedules, err := s.storage.ClaimDueSchedules(time.Now())
+	if err != nil {
+		s.logger.Log(logger.Error, "Failed to claim due backup schedules", err.Error())
+		return
+	}
+
+	for i := range schedules {
+		schedule := &schedules[i]
+		_, err := s.runSchedule(schedule)
+		if err == nil || errors.Is(err, types.ErrBackupInProgress) {
+			continue
+		}
+		s.logger.Log(logger.Error, "Failed to start scheduled backup", schedule.ID.String()+": "+err.Error())
+
+		name := schedule.VolumeName
+		if schedule.DatabaseID != nil {
+			name = schedule.DatabaseID.String()
+			if database, err := s.storage.GetDatabase(*schedule.DatabaseID, schedule.OrganizationID); err == nil {
+				name = database.Name
+			}
+		}
+		s.notifyFailure("Backup", schedule.ResourceType, name, uuid.Nil, err, time.Now(), schedule.UserID)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// CreateSchedule creates a recurring backup of a database or volume. The first backup
+// is taken on the next tick of the scheduler.
+func (s *BackupService) CreateSchedule(req types.CreateScheduleRequest, userID uuid.UUID, organizationID uuid.UUID) (*shared_types.BackupSchedule, error) {
+	r, err := s.resolveResource(organizationID, req.ResourceType, req.DatabaseID, req.VolumeName)
+	if err != nil && err != types.ErrDatabaseStopped {
+		return nil, err
+	}
+
+	now := time.Now()
+	schedule := &shared_types.BackupSchedule{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		UserID:         userID,
+		ResourceType:   req.ResourceType,
+		DatabaseID:     req.DatabaseID,
+		VolumeName:     r.Volume,
+		IntervalHours:  req.IntervalHours,
+		RetentionCount: req.RetentionCount,
+		Enabled:        true,
+		NextRunAt:      now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.storage.CreateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *BackupService) GetSchedules(organizationID uuid.UUID) ([]shared_types.BackupSchedule, error) {
+	return s.storage.GetSchedules(organizationID)
+}
+
+func (s *BackupService) GetSchedule(id uuid.UUID, organizationID uuid.UUID) (*shared_types.BackupSchedule, error) {
+	return s.storage.GetSchedule(id, organizationID)
+}
+
+// UpdateSchedule changes the interval, retention or state of a schedule. Changing the interval
+// moves the next run to one interval after the last one.
+func (s *BackupService) UpdateSchedule(id uuid.UUID, req types.UpdateScheduleRequest, organizationID uuid.UUID) (*shared_types.BackupSchedule, error) {
+	schedule, err := s.storage.GetSchedule(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IntervalHours != nil && *req.IntervalHours != schedule.IntervalHours {
+		schedule.IntervalHours = *req.IntervalHours
+		base := time.Now()
+		if schedule.LastRunAt != nil {
+			base = *schedule.LastRunAt
+		}
+		schedule.NextRunAt = base.Add(time.Duration(schedule.IntervalHours) * time.Hour)
+	}
+	if req.RetentionCount != nil {
+		schedule.RetentionCount = *req.RetentionCount
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+
+	if err := s.storage.UpdateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// DeleteSchedule deletes a schedule, the backups it took are kept.
+func (s *BackupService) DeleteSchedule(id uuid.UUID, organizationID uuid.UUID) error {
+	schedule, err := s.storage.GetSchedule(id, organizationID)
+	if err != nil {
+		return err
+	}
+	return s.storage.DeleteSchedule(schedule.ID)
+}
+
+// RunSchedule takes a backup for a schedule right away, outside of its interval. The backup
+// counts towards the retention of the schedule.
+func (s *BackupService) RunSchedule(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Backup, error) {
+	schedule, err := s.storage.GetSchedule(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.runSchedule(schedule)
+}
+
+func (s *BackupService) runSchedule(schedule *shared_types.BackupSchedule) (*shared_types.Backup, error) {
+	r, err := s.resolveResource(schedule.OrganizationID, schedule.ResourceType, schedule.DatabaseID, schedule.VolumeName)
+	if err != nil {
+		return nil, err
+	}
+	return s.startBackup(r, schedule, schedule.UserID, schedule.OrganizationID)
+}
@@ -0,0 +1,259 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/uptrace/bun"
+)
+
+type BackupStorage struct {
+	DB  *bun.DB
+	Ctx context.Context
+}
+
+type BackupStorageInterface interface {
+	GetDestination(organizationID uuid.UUID) (*shared_types.BackupDestination, error)
+	UpsertDestination(destination *shared_types.BackupDestination) error
+	CreateSchedule(schedule *shared_types.BackupSchedule) error
+	GetSchedule(id uuid.UUID, organizationID uuid.UUID) (*shared_types.BackupSchedule, error)
+	GetSchedules(organizationID uuid.UUID) ([]shared_types.BackupSchedule, error)
+	UpdateSchedule(schedule *shared_types.BackupSchedule) error
+	DeleteSchedule(id uuid.UUID) error
+	ClaimDueSchedules(now time.Time) ([]shared_types.BackupSchedule, error)
+	CreateBackup(backup *shared_types.Backup) error
+	UpdateBackup(backup *shared_types.Backup) error
+	GetBackup(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Backup, error)
+	GetBackups(organizationID uuid.UUID, scheduleID *uuid.UUID) ([]shared_types.Backup, error)
+	GetExpiredBackups(scheduleID uuid.UUID, keep int) ([]shared_types.Backup, error)
+	DeleteBackup(id uuid.UUID) error
+	FailRunningBackups() error
+	GetDatabase(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Database, error)
+	ApplicationExists(id uuid.UUID, organizationID uuid.UUID) (bool, error)
+	DatabaseVolumeExists(volumeName string, organizationID uuid.UUID) (bool, error)
+}
+
+func (s *BackupStorage) GetDestination(organizationID uuid.UUID) (*shared_types.BackupDestination, error) {
+	var destination shared_types.BackupDestination
+	err := s.DB.NewSelect().
+		Model(&destination).
+		Where("organization_id = ?", organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrDestinationNotConfigured
+		}
+		return nil, err
+	}
+	return &destination, nil
+}
+
+func (s *BackupStorage) UpsertDestination(destination *shared_types.BackupDestination) error {
+	_, err := s.DB.NewInsert().
+		Model(destination).
+		On("CONFLICT (organization_id) DO UPDATE").
+		Set("type = EXCLUDED.type").
+		Set("local_path = EXCLUDED.local_path").
+		Set("endpoint = EXCLUDED.endpoint").
+		Set("region = EXCLUDED.region").
+		Set("bucket = EXCLUDED.bucket").
+		Set("prefix = EXCLUDED.prefix").
+		Set("access_key = EXCLUDED.access_key").
+		Set("secret_key = EXCLUDED.secret_key").
+		Set("use_ssl = EXCLUDED.use_ssl").
+		Set("force_path_style = EXCLUDED.force_path_style").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *BackupStorage) CreateSchedule(schedule *shared_types.BackupSchedule) error {
+	_, err := s.DB.NewInsert().Model(schedule).Exec(s.Ctx)
+	return err
+}
+
+func (s *BackupStorage) GetSchedule(id uuid.UUID, organizationID uuid.UUID) (*shared_types.BackupSchedule, error) {
+	var schedule shared_types.BackupSchedule
+	err := s.DB.NewSelect().
+		Model(&schedule).
+		Relation("Database").
+		Where("bs.id = ? AND bs.organization_id = ?", id, organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (s *BackupStorage) GetSchedules(organizationID uuid.UUID) ([]shared_types.BackupSchedule, error) {
+	var schedules []shared_types.BackupSchedule
+	err := s.DB.NewSelect().
+		Model(&schedules).
+		Relation("Database").
+		Where("bs.organization_id = ?", organizationID).
+		Order("bs.created_at DESC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (s *BackupStorage) UpdateSchedule(schedule *shared_types.BackupSchedule) error {
+	schedule.UpdatedAt = time.Now()
+	_, err := s.DB.NewUpdate().
+		Model(schedule).
+		Column("interval_hours", "retention_count", "enabled", "next_run_at", "updated_at").
+		WherePK().
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *BackupStorage) DeleteSchedule(id uuid.UUID) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.BackupSchedule)(nil)).
+		Where("id = ?", id).
+		Exec(s.Ctx)
+	return err
+}
+
+// ClaimDueSchedules moves the next run of every enabled schedule that is due forward by its
+// interval and returns them, so that a schedule is picked up once even if the API runs in
+// several replicas.
+func (s *BackupStorage) ClaimDueSchedules(now time.Time) ([]shared_types.BackupSchedule, error) {
+	var schedules []shared_types.BackupSchedule
+	err := s.DB.NewUpdate().
+		Model(&schedules).
+		Set("last_run_at = ?", now).
+		Set("next_run_at = ?::timestamptz + make_interval(hours => interval_hours)", now).
+		Where("enabled = ?", true).
+		Where("next_run_at <= ?", now).
+		Returning("*").
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (s *BackupStorage) CreateBackup(backup *shared_types.Backup) error {
+	_, err := s.DB.NewInsert().Model(backup).Exec(s.Ctx)
+	return err
+}
+
+func (s *BackupStorage) UpdateBackup(backup *shared_types.Backup) error {
+	_, err := s.DB.NewUpdate().
+		Model(backup).
+		Column("status", "storage_key", "size", "checksum", "error", "completed_at").
+		WherePK().
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *BackupStorage) GetBackup(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Backup, error) {
+	var backup shared_types.Backup
+	err := s.DB.NewSelect().
+		Model(&backup).
+		Where("id = ? AND organization_id = ?", id, organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrBackupNotFound
+		}
+		return nil, err
+	}
+	return &backup, nil
+}
+
+func (s *BackupStorage) GetBackups(organizationID uuid.UUID, scheduleID *uuid.UUID) ([]shared_types.Backup, error) {
+	var backups []shared_types.Backup
+	query := s.DB.NewSelect().
+		Model(&backups).
+		Where("organization_id = ?", organizationID)
+	if scheduleID != nil {
+		query = query.Where("schedule_id = ?", *scheduleID)
+	}
+	err := query.Order("started_at DESC").Limit(500).Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+// GetExpiredBackups returns the completed backups of a schedule beyond the newest keep ones.
+func (s *BackupStorage) GetExpiredBackups(scheduleID uuid.UUID, keep int) ([]shared_types.Backup, error) {
+	var backups []shared_types.Backup
+	err := s.DB.NewSelect().
+		Model(&backups).
+		Where("schedule_id = ?", scheduleID).
+		Where("status = ?", shared_types.BackupStatusCompleted).
+		Order("started_at DESC").
+		Offset(keep).
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+func (s *BackupStorage) DeleteBackup(id uuid.UUID) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.Backup)(nil)).
+		Where("id = ?", id).
+		Exec(s.Ctx)
+	return err
+}
+
+// FailRunningBackups marks backups that were interrupted by a restart of the API as failed.
+func (s *BackupStorage) FailRunningBackups() error {
+	_, err := s.DB.NewUpdate().
+		Model((*shared_types.Backup)(nil)).
+		Set("status = ?", shared_types.BackupStatusFailed).
+		Set("error = ?", "interrupted by a restart of the API").
+		Set("completed_at = ?", time.Now()).
+		Where("status = ?", shared_types.BackupStatusRunning).
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *BackupStorage) GetDatabase(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Database, error) {
+	var database shared_types.Database
+	err := s.DB.NewSelect().
+		Model(&database).
+		Where("id = ? AND organization_id = ?", id, organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrDatabaseNotFound
+		}
+		return nil, err
+	}
+	return &database, nil
+}
+
+// ApplicationExists reports whether the application belongs to the organization.
+func (s *BackupStorage) ApplicationExists(id uuid.UUID, organizationID uuid.UUID) (bool, error) {
+	return s.DB.NewSelect().
+		Model((*shared_types.Application)(nil)).
+		Where("id = ? AND organization_id = ?", id, organizationID).
+		Exists(s.Ctx)
+}
+
+// DatabaseVolumeExists reports whether the volume holds the data of a database of the organization.
+func (s *BackupStorage) DatabaseVolumeExists(volumeName string, organizationID uuid.UUID) (bool, error) {
+	return s.DB.NewSelect().
+		Model((*shared_types.Database)(nil)).
+		Where("volume_name = ? AND organization_id = ?", volumeName, organizationID).
+		Exists(s.Ctx)
+}
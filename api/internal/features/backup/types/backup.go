@@ -0,0 +1,88 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+var (
+	ErrInvalidRequestType        = errors.New("invalid request type")
+	ErrInvalidBackupID           = errors.New("invalid backup id")
+	ErrInvalidScheduleID         = errors.New("invalid backup schedule id")
+	ErrBackupNotFound            = errors.New("backup not found")
+	ErrScheduleNotFound          = errors.New("backup schedule not found")
+	ErrDestinationNotConfigured  = errors.New("no backup destination is configured for this organization")
+	ErrInvalidDestinationType    = errors.New("invalid destination type, expected local or s3")
+	ErrInvalidLocalPath          = errors.New("local_path must be a directory inside the backups directory of the organization")
+	ErrMissingS3Settings         = errors.New("endpoint, bucket, access_key and secret_key are required for s3 destinations")
+	ErrDestinationUnreachable    = errors.New("backup destination is not writable")
+	ErrInvalidResourceType       = errors.New("invalid resource type, expected database or volume")
+	ErrMissingDatabaseID         = errors.New("database_id is required for database backups")
+	ErrMissingVolumeName         = errors.New("volume_name is required for volume backups")
+	ErrInvalidVolumeName         = errors.New("invalid volume name")
+	ErrInvalidInterval           = errors.New("interval_hours must be between 1 and 8760")
+	ErrInvalidRetention          = errors.New("retention_count must be between 1 and 1000")
+	ErrBackupInProgress          = errors.New("a backup of this resource is already running")
+	ErrBackupNotCompleted        = errors.New("only completed backups can be restored")
+	ErrBackupChecksumMismatch    = errors.New("backup checksum does not match, the file may be corrupted")
+	ErrRestoreEngineMismatch     = errors.New("backups can only be restored into a database of the same engine")
+	ErrRestoreTargetConflict     = errors.New("choose either an existing database or a new database name")
+	ErrBackupFailed              = errors.New("backup failed")
+	ErrRestoreFailed             = errors.New("restore failed")
+	ErrBackupResourceNotFound    = errors.New("backed up resource no longer exists, restore into another one")
+	ErrDatabaseNotFound          = errors.New("database not found")
+	ErrDatabaseStopped           = errors.New("database is stopped, start it before backing it up or restoring into it")
+	ErrVolumeNotFound            = errors.New("volume not found")
+	ErrVolumeBackupsRequireAdmin = errors.New("volume backups are only available to admins")
+)
+
+type UpsertDestinationRequest struct {
+	Type           shared_types.BackupDestinationType `json:"type"`
+	LocalPath      string                             `json:"local_path,omitempty"`
+	Endpoint       string                             `json:"endpoint,omitempty"`
+	Region         string                             `json:"region,omitempty"`
+	Bucket         string                             `json:"bucket,omitempty"`
+	Prefix         string                             `json:"prefix,omitempty"`
+	AccessKey      string                             `json:"access_key,omitempty"`
+	SecretKey      string                             `json:"secret_key,omitempty"`
+	UseSSL         *bool                              `json:"use_ssl,omitempty"`
+	ForcePathStyle *bool                              `json:"force_path_style,omitempty"`
+}
+
+type CreateScheduleRequest struct {
+	ResourceType   shared_types.BackupResourceType `json:"resource_type"`
+	DatabaseID     *uuid.UUID                      `json:"database_id,omitempty"`
+	VolumeName     string                          `json:"volume_name,omitempty"`
+	IntervalHours  int                             `json:"interval_hours"`
+	RetentionCount int                             `json:"retention_count"`
+}
+
+type UpdateScheduleRequest struct {
+	IntervalHours  *int  `json:"interval_hours,omitempty"`
+	RetentionCount *int  `json:"retention_count,omitempty"`
+	Enabled        *bool `json:"enabled,omitempty"`
+}
+
+// CreateBackupRequest starts a one-off backup that is not subject to any retention.
+type CreateBackupRequest struct {
+	ResourceType shared_types.BackupResourceType `json:"resource_type"`
+	DatabaseID   *uuid.UUID                      `json:"database_id,omitempty"`
+	VolumeName   string                          `json:"volume_name,omitempty"`
+}
+
+// RestoreBackupRequest chooses where a backup is restored to. A database backup is restored
+// into DatabaseID or a new database named NewDatabaseName, a volume backup into VolumeName.
+// Without a target the backup replaces the data of the resource it was taken from.
+type RestoreBackupRequest struct {
+	DatabaseID      *uuid.UUID `json:"database_id,omitempty"`
+	NewDatabaseName string     `json:"new_database_name,omitempty"`
+	VolumeName      string     `json:"volume_name,omitempty"`
+}
+
+type RestoreBackupResponse struct {
+	ResourceType shared_types.BackupResourceType `json:"resource_type"`
+	DatabaseID   *uuid.UUID                      `json:"database_id,omitempty"`
+	VolumeName   string                          `json:"volume_name,omitempty"`
+}
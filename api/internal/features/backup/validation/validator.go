@@ -0,0 +1,123 @@
+package validation
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	database_types "github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+var volumeNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]+$`)
+
+// Validator handles backup validation logic
+type Validator struct {
+	storage storage.BackupStorageInterface
+}
+
+// NewValidator creates a new validator instance
+func NewValidator(storage storage.BackupStorageInterface) *Validator {
+	return &Validator{
+		storage: storage,
+	}
+}
+
+// ValidateID parses a backup or schedule ID taken from the request path
+func (v *Validator) ValidateID(id string, invalid error) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil || parsed == uuid.Nil {
+		return uuid.Nil, invalid
+	}
+	return parsed, nil
+}
+
+// ValidateRequest validates different backup request types
+func (v *Validator) ValidateRequest(req interface{}) error {
+	switch r := req.(type) {
+	case *types.UpsertDestinationRequest:
+		return v.validateUpsertDestinationRequest(*r)
+	case *types.CreateScheduleRequest:
+		if err := v.validateResource(r.ResourceType, r.DatabaseID, r.VolumeName); err != nil {
+			return err
+		}
+		return v.validateSchedule(&r.IntervalHours, &r.RetentionCount)
+	case *types.UpdateScheduleRequest:
+		return v.validateSchedule(r.IntervalHours, r.RetentionCount)
+	case *types.CreateBackupRequest:
+		return v.validateResource(r.ResourceType, r.DatabaseID, r.VolumeName)
+	case *types.RestoreBackupRequest:
+		return v.validateRestoreBackupRequest(*r)
+	default:
+		return types.ErrInvalidRequestType
+	}
+}
+
+func (v *Validator) validateUpsertDestinationRequest(req types.UpsertDestinationRequest) error {
+	switch req.Type {
+	case shared_types.BackupDestinationLocal:
+		if !validLocalPath(req.LocalPath) {
+			return types.ErrInvalidLocalPath
+		}
+	case shared_types.BackupDestinationS3:
+		if req.Endpoint == "" || req.Bucket == "" || req.AccessKey == "" {
+			return types.ErrMissingS3Settings
+		}
+	default:
+		return types.ErrInvalidDestinationType
+	}
+	return nil
+}
+
+// validLocalPath accepts an empty path or a relative one that stays inside the backups directory
+// of the organization.
+func validLocalPath(localPath string) bool {
+	if localPath == "" {
+		return true
+	}
+	return filepath.IsLocal(localPath)
+}
+
+func (v *Validator) validateResource(resourceType shared_types.BackupResourceType, databaseID *uuid.UUID, volumeName string) error {
+	switch resourceType {
+	case shared_types.BackupResourceDatabase:
+		if databaseID == nil || *databaseID == uuid.Nil {
+			return types.ErrMissingDatabaseID
+		}
+	case shared_types.BackupResourceVolume:
+		if volumeName == "" {
+			return types.ErrMissingVolumeName
+		}
+		if !volumeNamePattern.MatchString(volumeName) {
+			return types.ErrInvalidVolumeName
+		}
+	default:
+		return types.ErrInvalidResourceType
+	}
+	return nil
+}
+
+func (v *Validator) validateSchedule(intervalHours *int, retentionCount *int) error {
+	if intervalHours != nil && (*intervalHours < 1 || *intervalHours > 8760) {
+		return types.ErrInvalidInterval
+	}
+	if retentionCount != nil && (*retentionCount < 1 || *retentionCount > 1000) {
+		return types.ErrInvalidRetention
+	}
+	return nil
+}
+
+func (v *Validator) validateRestoreBackupRequest(req types.RestoreBackupRequest) error {
+	if req.DatabaseID != nil && req.NewDatabaseName != "" {
+		return types.ErrRestoreTargetConflict
+	}
+	if len(req.NewDatabaseName) > 63 {
+		return database_types.ErrDatabaseNameTooLong
+	}
+	if req.VolumeName != "" && !volumeNamePattern.MatchString(req.VolumeName) {
+		return types.ErrInvalidVolumeName
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/backup/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUpsertDestinationRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request types.UpsertDestinationRequest
+		wantErr error
+	}{
+		{name: "Local without path", request: types.UpsertDestinationRequest{Type: shared_types.BackupDestinationLocal}},
+		{name: "Local relative path", request: types.UpsertDestinationRequest{Type: shared_types.BackupDestinationLocal, LocalPath: "daily/db"}},
+		{name: "Local absolute path", request: types.UpsertDestinationRequest{Type: shared_types.BackupDestinationLocal, LocalPath: "/etc"}, wantErr: types.ErrInvalidLocalPath},
+		{name: "Local path climbing out", request: types.UpsertDestinationRequest{Type: shared_types.BackupDestinationLocal, LocalPath: "daily/../../other"}, wantErr: types.ErrInvalidLocalPath},
+		{name: "S3 without bucket", request: types.UpsertDestinationRequest{Type: shared_types.BackupDestinationS3, Endpoint: "s3.amazonaws.com", AccessKey: "key"}, wantErr: types.ErrMissingS3Settings},
+		{name: "S3", request: types.UpsertDestinationRequest{Type: shared_types.BackupDestinationS3, Endpoint: "s3.amazonaws.com", Bucket: "backups", AccessKey: "key"}},
+		{name: "Unknown type", request: types.UpsertDestinationRequest{Type: "ftp"}, wantErr: types.ErrInvalidDestinationType},
+	}
+
+	validator := NewValidator(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validator.ValidateRequest(&tt.request))
+		})
+	}
+}
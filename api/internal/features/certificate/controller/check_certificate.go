@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// CheckCertificate inspects the certificate served for a domain without waiting for the
+// periodic check.
+func (c *CertificateController) CheckCertificate(f fuego.ContextWithBody[types.CheckCertificateRequest]) (*shared_types.Response, error) {
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID, err := requestOrganization(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := c.service.CheckCertificate(request, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to check certificate", request.Domain+": "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: certificateErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Certificate checked successfully",
+		Data:    certificate,
+	}, nil
+}
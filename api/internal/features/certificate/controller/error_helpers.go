@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// certificateErrorStatus maps errors of the certificate service to HTTP statuses.
+func certificateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrCertificateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrApplyCertificate):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// requestOrganization returns the organization of the request or an unauthorized error.
+func requestOrganization(w http.ResponseWriter, r *http.Request) (uuid.UUID, error) {
+	user := utils.GetUser(w, r)
+	if user == nil {
+		return uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(r)
+	if organizationID == uuid.Nil {
+		return uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+	return organizationID, nil
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetCertificates lists the certificates of the application domains of the organization with
+// their mode, issuer, expiry and the last error seen on the proxy.
+func (c *CertificateController) GetCertificates(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	organizationID, err := requestOrganization(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	certificates, err := c.service.GetCertificates(organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get certificates", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: certificateErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Certificates fetched successfully",
+		Data:    certificates,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/validation"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type CertificateController struct {
+	store        *shared_storage.Store
+	validator    *validation.Validator
+	service      *service.CertificateService
+	ctx          context.Context
+	logger       logger.Logger
+	notification *notification.NotificationManager
+}
+
+func NewCertificateController(
+	store *shared_storage.Store,
+	ctx context.Context,
+	l logger.Logger,
+	notificationManager *notification.NotificationManager,
+) *CertificateController {
+	storage := storage.CertificateStorage{DB: store.DB, Ctx: ctx}
+	certificateService := service.NewCertificateService(store, ctx, l, &storage, notificationManager)
+	certificateService.StartChecks(ctx)
+
+	return &CertificateController{
+		store:        store,
+		validator:    validation.NewValidator(),
+		service:      certificateService,
+		ctx:          ctx,
+		logger:       l,
+		notification: notificationManager,
+	}
+}
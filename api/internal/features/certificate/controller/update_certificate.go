@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// UpdateCertificate switches a domain between ACME, an uploaded certificate and the internal CA.
+func (c *CertificateController) UpdateCertificate(f fuego.ContextWithBody[types.UpdateCertificateRequest]) (*shared_types.Response, error) {
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID, err := requestOrganization(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := c.service.UpdateCertificate(request, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to update certificate", request.Domain+": "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: certificateErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Certificate updated successfully",
+		Data:    certificate,
+	}, nil
+}
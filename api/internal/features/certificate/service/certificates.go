@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetCertificates returns the certificates of the application domains of the organization
+// as they were last observed on the proxy.
+func (s *CertificateService) GetCertificates(organizationID uuid.UUID) ([]shared_types.DomainCertificate, error) {
+	if err := s.storage.EnsureCertificates(); err != nil {
+		return nil, err
+	}
+	return s.storage.GetCertificates(organizationID)
+}
+
+// UpdateCertificate switches the certificate of a domain to another mode, applies the
+// configuration to the proxy and inspects the certificate served afterwards.
+func (s *CertificateService) UpdateCertificate(req types.UpdateCertificateRequest, organizationID uuid.UUID) (*shared_types.DomainCertificate, error) {
+	if err := s.storage.EnsureCertificates(); err != nil {
+		return nil, err
+	}
+	certificate, err := s.storage.GetCertificate(req.Domain, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate.Mode = req.Mode
+	certificate.CertificatePEM = req.Certificate
+	certificate.PrivateKeyPEM = req.PrivateKey
+	if err := s.storage.UpdateMode(certificate); err != nil {
+		return nil, err
+	}
+
+	if err := s.syncProxy(); err != nil {
+		return nil, fmt.Errorf("%w: %v", types.ErrApplyCertificate, err)
+	}
+
+	if err := s.check(certificate); err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// CheckCertificate inspects the certificate served for a domain right away.
+func (s *CertificateService) CheckCertificate(req types.CheckCertificateRequest, organizationID uuid.UUID) (*shared_types.DomainCertificate, error) {
+	if err := s.storage.EnsureCertificates(); err != nil {
+		return nil, err
+	}
+	certificate, err := s.storage.GetCertificate(req.Domain, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(certificate); err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// syncProxy writes the custom and internal certificates of every domain to the proxy.
+func (s *CertificateService) syncProxy() error {
+	s.syncing.Lock()
+	defer s.syncing.Unlock()
+
+	certificates, err := s.storage.GetAllCertificates()
+	if err != nil {
+		return err
+	}
+
+	managed := make([]proxy.ManagedCertificate, 0, len(certificates))
+	for _, certificate := range certificates {
+		managed = append(managed, proxy.ManagedCertificate{
+			Domain:      certificate.Name,
+			Mode:        certificate.Mode,
+			Certificate: certificate.CertificatePEM,
+			PrivateKey:  certificate.PrivateKeyPEM,
+		})
+	}
+	return proxy.NewCaddy(&s.logger, "", "", "", proxy.ReverseProxy).SyncCertificates(managed)
+}
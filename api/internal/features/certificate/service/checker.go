@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	// certificateCheckInterval is how often the served certificates are inspected and the
+	// TLS configuration of the proxy is restored.
+	certificateCheckInterval = time.Hour
+	// defaultExpiryWarningDays is used when CERTIFICATE_EXPIRY_DAYS is not set.
+	defaultExpiryWarningDays = 14
+)
+
+// StartChecks restores the certificate configuration of the proxy, which is lost when Caddy
+// restarts or its config is reloaded, and inspects the served certificates once at start and
+// then every certificateCheckInterval until ctx is done.
+func (s *CertificateService) StartChecks(ctx context.Context) {
+	go func() {
+		s.checkCertificates()
+
+		ticker := time.NewTicker(certificateCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkCertificates()
+			}
+		}
+	}()
+}
+
+func (s *CertificateService) checkCertificates() {
+	if err := s.storage.EnsureCertificates(); err != nil {
+		s.logger.Log(logger.Error, "Failed to add missing domain certificates", err.Error())
+		return
+	}
+	if err := s.syncProxy(); err != nil {
+		s.logger.Log(logger.Error, "Failed to apply certificates to the proxy", err.Error())
+	}
+
+	certificates, err := s.storage.GetAllCertificates()
+	if err != nil {
+		s.logger.Log(logger.Error, "Failed to get domain certificates", err.Error())
+		return
+	}
+	for i := range certificates {
+		certificate := &certificates[i]
+		if err := s.check(certificate); err != nil {
+			s.logger.Log(logger.Error, "Failed to check certificate", certificate.Name+": "+err.Error())
+			continue
+		}
+		s.notifyExpiring(certificate)
+	}
+}
+
+// check inspects the certificate the proxy serves for the domain and saves what it found.
+// Problems with the certificate are recorded in LastError, only storage errors are returned.
+func (s *CertificateService) check(certificate *shared_types.DomainCertificate) error {
+	now := time.Now()
+	certificate.CheckedAt = &now
+
+	chain, err := proxy.NewCaddy(&s.logger, "", certificate.Name, "", proxy.ReverseProxy).ServedCertificate()
+	if err != nil {
+		certificate.LastError = err.Error()
+		return s.storage.UpdateCheck(certificate)
+	}
+
+	leaf := chain[0]
+	if certificate.NotAfter == nil || !certificate.NotAfter.Equal(leaf.NotAfter) {
+		// a renewed or replaced certificate gets its own expiry notification
+		certificate.ExpiryNotifiedAt = nil
+	}
+	certificate.Issuer = certificateName(leaf.Issuer.CommonName, leaf.Issuer.Organization)
+	certificate.Subject = certificateName(leaf.Subject.CommonName, leaf.DNSNames)
+	certificate.NotBefore = &leaf.NotBefore
+	certificate.NotAfter = &leaf.NotAfter
+	certificate.LastError = ""
+	if err := verifyServedCertificate(certificate, chain, now); err != nil {
+		certificate.LastError = err.Error()
+	}
+	return s.storage.UpdateCheck(certificate)
+}
+
+// verifyServedCertificate checks the served chain against what the mode of the domain should
+// serve. Only ACME certificates are expected to chain to a publicly trusted root.
+func verifyServedCertificate(certificate *shared_types.DomainCertificate, chain []*x509.Certificate, now time.Time) error {
+	leaf := chain[0]
+	if err := leaf.VerifyHostname(certificate.Name); err != nil {
+		return err
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))
+	}
+
+	switch certificate.Mode {
+	case shared_types.CertificateModeACME:
+		intermediates := x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			DNSName:       certificate.Name,
+			Intermediates: intermediates,
+			CurrentTime:   now,
+		})
+		return err
+	case shared_types.CertificateModeCustom:
+		block, _ := pem.Decode([]byte(certificate.CertificatePEM))
+		if block == nil || !bytes.Equal(block.Bytes, leaf.Raw) {
+			return fmt.Errorf("the proxy does not serve the uploaded certificate yet")
+		}
+	}
+	return nil
+}
+
+func certificateName(commonName string, fallback []string) string {
+	if commonName != "" || len(fallback) == 0 {
+		return commonName
+	}
+	return fallback[0]
+}
+
+// notifyExpiring warns the owner of the application once per certificate when it expires
+// within the configured number of days. Certificates of the internal CA are short lived and
+// renewed by Caddy, they are left out.
+func (s *CertificateService) notifyExpiring(certificate *shared_types.DomainCertificate) {
+	if s.notification == nil || certificate.NotAfter == nil || certificate.ExpiryNotifiedAt != nil {
+		return
+	}
+	if certificate.Mode == shared_types.CertificateModeInternal {
+		return
+	}
+
+	days := config.AppConfig.Proxy.CertificateExpiryDays
+	if days <= 0 {
+		days = defaultExpiryWarningDays
+	}
+	remaining := time.Until(*certificate.NotAfter)
+	if remaining > time.Duration(days)*24*time.Hour {
+		return
+	}
+
+	s.notification.SendNotification(notification.NewNotificationPayload(
+		notification.NotificationPayloadTypeCertificateExpiring,
+		certificate.UserID.String(),
+		notification.CertificateExpiringData{
+			Domain:    certificate.Name,
+			Mode:      string(certificate.Mode),
+			Issuer:    certificate.Issuer,
+			ExpiresAt: *certificate.NotAfter,
+			DaysLeft:  int(remaining.Hours() / 24),
+			LastError: certificate.LastError,
+		},
+		notification.NotificationCategoryCertificate,
+	))
+
+	now := time.Now()
+	if err := s.storage.SetExpiryNotified(certificate.ID, now); err != nil {
+		s.logger.Log(logger.Error, "Failed to save certificate expiry notification", err.Error())
+		return
+	}
+	certificate.ExpiryNotifiedAt = &now
+}
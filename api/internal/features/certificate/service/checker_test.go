@@ -0,0 +1,75 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selfSigned returns a certificate for domain valid until notAfter.
+func selfSigned(t *testing.T, domain string, notAfter time.Time) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate
+}
+
+func TestVerifyServedCertificate(t *testing.T) {
+	now := time.Now()
+	served := selfSigned(t, "shop.example.com", now.Add(30*24*time.Hour))
+	servedPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: served.Raw}))
+	other := selfSigned(t, "shop.example.com", now.Add(30*24*time.Hour))
+	otherPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: other.Raw}))
+	expired := selfSigned(t, "shop.example.com", now.Add(-time.Hour))
+
+	tests := []struct {
+		name        string
+		certificate shared_types.DomainCertificate
+		chain       []*x509.Certificate
+		wantErr     bool
+	}{
+		{name: "Internal", certificate: shared_types.DomainCertificate{Name: "shop.example.com", Mode: shared_types.CertificateModeInternal}, chain: []*x509.Certificate{served}},
+		{name: "Uploaded certificate is served", certificate: shared_types.DomainCertificate{Name: "shop.example.com", Mode: shared_types.CertificateModeCustom, CertificatePEM: servedPEM}, chain: []*x509.Certificate{served}},
+		{name: "Another certificate is served", certificate: shared_types.DomainCertificate{Name: "shop.example.com", Mode: shared_types.CertificateModeCustom, CertificatePEM: otherPEM}, chain: []*x509.Certificate{served}, wantErr: true},
+		{name: "ACME certificate is not publicly trusted", certificate: shared_types.DomainCertificate{Name: "shop.example.com", Mode: shared_types.CertificateModeACME}, chain: []*x509.Certificate{served}, wantErr: true},
+		{name: "Other domain", certificate: shared_types.DomainCertificate{Name: "example.com", Mode: shared_types.CertificateModeInternal}, chain: []*x509.Certificate{served}, wantErr: true},
+		{name: "Expired", certificate: shared_types.DomainCertificate{Name: "shop.example.com", Mode: shared_types.CertificateModeInternal}, chain: []*x509.Certificate{expired}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyServedCertificate(&tt.certificate, tt.chain, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCertificateName(t *testing.T) {
+	assert.Equal(t, "R11", certificateName("R11", []string{"Let's Encrypt"}))
+	assert.Equal(t, "Let's Encrypt", certificateName("", []string{"Let's Encrypt"}))
+	assert.Equal(t, "", certificateName("", nil))
+}
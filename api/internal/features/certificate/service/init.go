@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type CertificateService struct {
+	storage      storage.CertificateStorageInterface
+	notification *notification.NotificationManager
+	Ctx          context.Context
+	store        *shared_storage.Store
+	logger       logger.Logger
+	// syncing serializes the updates of the TLS configuration of the proxy
+	syncing sync.Mutex
+}
+
+func NewCertificateService(
+	store *shared_storage.Store,
+	ctx context.Context,
+	logger logger.Logger,
+	certificate_repo storage.CertificateStorageInterface,
+	notificationManager *notification.NotificationManager,
+) *CertificateService {
+	return &CertificateService{
+		storage:      certificate_repo,
+		notification: notificationManager,
+		store:        store,
+		Ctx:          ctx,
+		logger:       logger,
+	}
+}
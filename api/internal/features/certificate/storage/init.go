@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/uptrace/bun"
+)
+
+type CertificateStorage struct {
+	DB  *bun.DB
+	Ctx context.Context
+}
+
+type CertificateStorageInterface interface {
+	EnsureCertificates() error
+	GetCertificates(organizationID uuid.UUID) ([]shared_types.DomainCertificate, error)
+	GetCertificate(name string, organizationID uuid.UUID) (*shared_types.DomainCertificate, error)
+	GetAllCertificates() ([]shared_types.DomainCertificate, error)
+	UpdateMode(certificate *shared_types.DomainCertificate) error
+	UpdateCheck(certificate *shared_types.DomainCertificate) error
+	SetExpiryNotified(id uuid.UUID, notifiedAt time.Time) error
+}
+
+// EnsureCertificates adds the ACME certificate of the application domains that have none yet.
+// Only domains served by Caddy are managed, Nginx reads the certificates from disk. A domain
+// shared by applications below different paths has a single certificate.
+func (s *CertificateStorage) EnsureCertificates() error {
+	_, err := s.DB.NewRaw(`
+		INSERT INTO domain_certificates (application_domain_id, name)
+		SELECT DISTINCT ON (LOWER(apd.name)) apd.id, apd.name FROM application_domains AS apd
+		JOIN applications AS a ON a.id = apd.application_id
+		WHERE a.proxy_server = 'caddy'
+		AND NOT EXISTS (SELECT 1 FROM domain_certificates AS dc WHERE LOWER(dc.name) = LOWER(apd.name))
+		ORDER BY LOWER(apd.name), apd.created_at
+		ON CONFLICT (application_domain_id) DO NOTHING`).
+		Exec(s.Ctx)
+	return err
+}
+
+// selectCertificates selects certificates along with the application and owner of their domain.
+func (s *CertificateStorage) selectCertificates(certificates interface{}) *bun.SelectQuery {
+	return s.DB.NewSelect().
+		Model(certificates).
+		ColumnExpr("dc.*").
+		ColumnExpr("a.id AS application_id").
+		ColumnExpr("a.user_id AS user_id").
+		Join("JOIN application_domains AS apd ON apd.id = dc.application_domain_id").
+		Join("JOIN applications AS a ON a.id = apd.application_id").
+		Where("a.proxy_server = ?", shared_types.Caddy)
+}
+
+func (s *CertificateStorage) GetCertificates(organizationID uuid.UUID) ([]shared_types.DomainCertificate, error) {
+	var certificates []shared_types.DomainCertificate
+	err := s.selectCertificates(&certificates).
+		Where("a.organization_id = ?", organizationID).
+		Order("dc.name ASC").
+		Scan(s.Ctx)
+	return certificates, err
+}
+
+func (s *CertificateStorage) GetCertificate(name string, organizationID uuid.UUID) (*shared_types.DomainCertificate, error) {
+	var certificate shared_types.DomainCertificate
+	err := s.selectCertificates(&certificate).
+		Where("LOWER(dc.name) = LOWER(?)", name).
+		Where("a.organization_id = ?", organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrCertificateNotFound
+		}
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+func (s *CertificateStorage) GetAllCertificates() ([]shared_types.DomainCertificate, error) {
+	var certificates []shared_types.DomainCertificate
+	err := s.selectCertificates(&certificates).
+		Order("dc.name ASC").
+		Scan(s.Ctx)
+	return certificates, err
+}
+
+// UpdateMode saves the mode and the custom certificate of a domain. The observed certificate
+// is kept until the next check.
+func (s *CertificateStorage) UpdateMode(certificate *shared_types.DomainCertificate) error {
+	certificate.UpdatedAt = time.Now()
+	_, err := s.DB.NewUpdate().
+		Model(certificate).
+		Column("mode", "certificate_pem", "private_key_pem", "updated_at").
+		WherePK().
+		Exec(s.Ctx)
+	return err
+}
+
+// UpdateCheck saves the certificate observed on the proxy.
+func (s *CertificateStorage) UpdateCheck(certificate *shared_types.DomainCertificate) error {
+	certificate.UpdatedAt = time.Now()
+	_, err := s.DB.NewUpdate().
+		Model(certificate).
+		Column("issuer", "subject", "not_before", "not_after", "last_error", "checked_at", "expiry_notified_at", "updated_at").
+		WherePK().
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *CertificateStorage) SetExpiryNotified(id uuid.UUID, notifiedAt time.Time) error {
+	_, err := s.DB.NewUpdate().
+		Model((*shared_types.DomainCertificate)(nil)).
+		Set("expiry_notified_at = ?", notifiedAt).
+		Where("id = ?", id).
+		Exec(s.Ctx)
+	return err
+}
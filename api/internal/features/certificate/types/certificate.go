@@ -0,0 +1,34 @@
+package types
+
+import (
+	"errors"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+var (
+	ErrInvalidRequestType        = errors.New("invalid request type")
+	ErrMissingDomain             = errors.New("domain is required")
+	ErrInvalidCertificateMode    = errors.New("invalid certificate mode, expected acme, custom or internal")
+	ErrMissingCertificate        = errors.New("certificate and private_key are required for custom certificates")
+	ErrUnexpectedCertificate     = errors.New("certificate and private_key can only be set for custom certificates")
+	ErrInvalidCertificate        = errors.New("certificate and private key are not a valid PEM encoded pair")
+	ErrCertificateDomainMismatch = errors.New("certificate is not valid for the domain")
+	ErrCertificateExpired        = errors.New("certificate has expired")
+	ErrCertificateNotFound       = errors.New("domain is not a domain of an application of this organization")
+	ErrApplyCertificate          = errors.New("failed to apply the certificate configuration to the proxy")
+)
+
+// UpdateCertificateRequest chooses where the certificate of a domain comes from. Certificate
+// and PrivateKey are the PEM encoded certificate chain and key of the custom mode.
+type UpdateCertificateRequest struct {
+	Domain      string                       `json:"domain"`
+	Mode        shared_types.CertificateMode `json:"mode"`
+	Certificate string                       `json:"certificate,omitempty"`
+	PrivateKey  string                       `json:"private_key,omitempty"`
+}
+
+// CheckCertificateRequest inspects the certificate served for a domain right away.
+type CheckCertificateRequest struct {
+	Domain string `json:"domain"`
+}
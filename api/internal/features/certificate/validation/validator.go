@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// Validator handles certificate validation logic
+type Validator struct {
+}
+
+// NewValidator creates a new validator instance
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateRequest validates different certificate request types
+func (v *Validator) ValidateRequest(req interface{}) error {
+	switch r := req.(type) {
+	case *types.UpdateCertificateRequest:
+		return validateUpdateCertificateRequest(r)
+	case *types.CheckCertificateRequest:
+		return validateDomain(&r.Domain)
+	default:
+		return types.ErrInvalidRequestType
+	}
+}
+
+func validateUpdateCertificateRequest(req *types.UpdateCertificateRequest) error {
+	if err := validateDomain(&req.Domain); err != nil {
+		return err
+	}
+
+	switch req.Mode {
+	case shared_types.CertificateModeACME, shared_types.CertificateModeInternal:
+		if req.Certificate != "" || req.PrivateKey != "" {
+			return types.ErrUnexpectedCertificate
+		}
+		return nil
+	case shared_types.CertificateModeCustom:
+		if strings.TrimSpace(req.Certificate) == "" || strings.TrimSpace(req.PrivateKey) == "" {
+			return types.ErrMissingCertificate
+		}
+		return validateKeyPair(req.Domain, req.Certificate, req.PrivateKey)
+	default:
+		return types.ErrInvalidCertificateMode
+	}
+}
+
+func validateDomain(domain *string) error {
+	*domain = strings.ToLower(strings.TrimSpace(*domain))
+	if *domain == "" {
+		return types.ErrMissingDomain
+	}
+	return nil
+}
+
+// validateKeyPair checks that the key belongs to the certificate and that the certificate is
+// currently valid for domain. The chain is not verified, custom certificates are often
+// issued by a private CA.
+func validateKeyPair(domain string, certificate string, privateKey string) error {
+	pair, err := tls.X509KeyPair([]byte(certificate), []byte(privateKey))
+	if err != nil {
+		return types.ErrInvalidCertificate
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return types.ErrInvalidCertificate
+	}
+	if err := leaf.VerifyHostname(domain); err != nil {
+		return types.ErrCertificateDomainMismatch
+	}
+	if time.Now().After(leaf.NotAfter) {
+		return types.ErrCertificateExpired
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/certificate/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selfSigned returns a PEM encoded certificate for domain valid until notAfter and its key.
+func selfSigned(t *testing.T, domain string, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestValidateUpdateCertificateRequest(t *testing.T) {
+	valid := time.Now().Add(30 * 24 * time.Hour)
+	certificate, key := selfSigned(t, "shop.example.com", valid)
+	_, otherKey := selfSigned(t, "shop.example.com", valid)
+	expired, expiredKey := selfSigned(t, "shop.example.com", time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name    string
+		request types.UpdateCertificateRequest
+		wantErr error
+	}{
+		{name: "ACME", request: types.UpdateCertificateRequest{Domain: "shop.example.com", Mode: shared_types.CertificateModeACME}},
+		{name: "Internal", request: types.UpdateCertificateRequest{Domain: "shop.example.com", Mode: shared_types.CertificateModeInternal}},
+		{name: "Custom", request: types.UpdateCertificateRequest{Domain: " Shop.Example.com ", Mode: shared_types.CertificateModeCustom, Certificate: certificate, PrivateKey: key}},
+		{name: "Missing domain", request: types.UpdateCertificateRequest{Domain: " ", Mode: shared_types.CertificateModeACME}, wantErr: types.ErrMissingDomain},
+		{name: "Unknown mode", request: types.UpdateCertificateRequest{Domain: "shop.example.com", Mode: "self-signed"}, wantErr: types.ErrInvalidCertificateMode},
+		{name: "Certificate with ACME", request: types.UpdateCertificateRequest{Domain: "shop.example.com", Mode: shared_types.CertificateModeACME, Certificate: certificate}, wantErr: types.ErrUnexpectedCertificate},
+		{name: "Custom without key", request: types.UpdateCertificateRequest{Domain: "shop.example.com", Mode: shared_types.CertificateModeCustom, Certificate: certificate}, wantErr: types.ErrMissingCertificate},
+		{name: "Key of another certificate", request: types.UpdateCertificateRequest{Domain: "shop.example.com", Mode: shared_types.CertificateModeCustom, Certificate: certificate, PrivateKey: otherKey}, wantErr: types.ErrInvalidCertificate},
+		{name: "Not PEM", request: types.UpdateCertificateRequest{Domain: "shop.example.com", Mode: shared_types.CertificateModeCustom, Certificate: "certificate", PrivateKey: "key"}, wantErr: types.ErrInvalidCertificate},
+		{name: "Other domain", request: types.UpdateCertificateRequest{Domain: "example.com", Mode: shared_types.CertificateModeCustom, Certificate: certificate, PrivateKey: key}, wantErr: types.ErrCertificateDomainMismatch},
+		{name: "Expired", request: types.UpdateCertificateRequest{Domain: "shop.example.com", Mode: shared_types.CertificateModeCustom, Certificate: expired, PrivateKey: expiredKey}, wantErr: types.ErrCertificateExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validateUpdateCertificateRequest(&tt.request))
+		})
+	}
+}
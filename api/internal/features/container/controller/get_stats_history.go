@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/container/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// GetStatsHistory returns the CPU, memory, network and block IO usage of the containers of an
+// application during the last hours, 24 by default, down-sampled to buckets of five minutes.
+// With deployment_id only the containers started by that deployment are returned.
+func (c *ContainerController) GetStatsHistory(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	organizationID := utils.GetOrganizationID(f.Request())
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	applicationID, err := uuid.Parse(f.QueryParam("application_id"))
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    types.ErrInvalidApplicationID,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	var deploymentID uuid.UUID
+	if value := f.QueryParam("deployment_id"); value != "" {
+		deploymentID, err = uuid.Parse(value)
+		if err != nil {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrInvalidDeploymentID,
+				Status: http.StatusBadRequest,
+			}
+		}
+	}
+
+	hours := types.MaxStatsHistoryHours
+	if value := f.QueryParam("hours"); value != "" {
+		hours, err = strconv.Atoi(value)
+		if err != nil || hours < 1 || hours > types.MaxStatsHistoryHours {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrInvalidHours,
+				Status: http.StatusBadRequest,
+			}
+		}
+	}
+
+	history, err := c.statsService.GetHistory(&types.ContainerStatsHistoryRequest{
+		ApplicationID:  applicationID,
+		DeploymentID:   deploymentID,
+		OrganizationID: organizationID,
+		Hours:          hours,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, types.ErrApplicationNotFound) {
+			status = http.StatusNotFound
+		} else {
+			c.logger.Log(logger.Error, err.Error(), "")
+		}
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: status,
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Container stats fetched successfully",
+		Data:    history,
+	}, nil
+}
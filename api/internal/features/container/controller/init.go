@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/raghavyuva/nixopus-api/internal/features/container/service"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
 	"github.com/raghavyuva/nixopus-api/internal/features/notification"
@@ -15,6 +16,7 @@ import (
 type ContainerController struct {
 	store         *shared_storage.Store
 	dockerService *docker.DockerService
+	statsService  *service.ContainerStatsService
 	ctx           context.Context
 	logger        logger.Logger
 	notification  *notification.NotificationManager
@@ -26,9 +28,14 @@ func NewContainerController(
 	l logger.Logger,
 	notificationManager *notification.NotificationManager,
 ) *ContainerController {
+	dockerService := docker.NewDockerService()
+	statsService := service.NewContainerStatsService(store.DB, ctx, l, dockerService)
+	statsService.StartCollector(ctx)
+
 	return &ContainerController{
 		store:         store,
-		dockerService: docker.NewDockerService(),
+		dockerService: dockerService,
+		statsService:  statsService,
 		ctx:           ctx,
 		logger:        l,
 		notification:  notificationManager,
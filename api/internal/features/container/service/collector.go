@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/container/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// statsBucket accumulates the samples of a container during a bucket.
+type statsBucket struct {
+	stat     shared_types.ContainerStat
+	cpuSum   float64
+	memSum   float64
+	counters ioCounters
+}
+
+// ioCounters are the network and block IO counters of a container at its last sample.
+type ioCounters struct {
+	networkRx, networkTx, blockRead, blockWrite uint64
+}
+
+// StartCollector samples the containers of all applications every statsSampleInterval and
+// stores them down-sampled to buckets of StatsBucket, so the usage of the last day can be shown
+// without keeping every sample. Buckets older than a day are deleted. It stops when ctx is done.
+func (s *ContainerStatsService) StartCollector(ctx context.Context) {
+	go func() {
+		buckets := make(map[string]*statsBucket)
+		var lastCleanup time.Time
+
+		ticker := time.NewTicker(statsSampleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.collect(buckets)
+
+				if time.Since(lastCleanup) >= StatsBucket {
+					lastCleanup = time.Now()
+					before := lastCleanup.Add(-types.MaxStatsHistoryHours * time.Hour).Truncate(StatsBucket)
+					if err := s.storage.DeleteStatsBefore(before); err != nil {
+						s.logger.Log(logger.Error, "Failed to delete expired container stats", err.Error())
+					}
+				}
+			}
+		}
+	}()
+}
+
+// collect adds a sample of every container to its current bucket and stores the buckets, so
+// the bucket being filled is visible right away. Containers that are gone are forgotten.
+func (s *ContainerStatsService) collect(buckets map[string]*statsBucket) {
+	usages, err := s.SampleContainers(nil)
+	if err != nil {
+		s.logger.Log(logger.Error, "Failed to sample container stats", err.Error())
+		return
+	}
+
+	seen := make(map[string]bool, len(usages))
+	stats := make([]shared_types.ContainerStat, 0, len(usages))
+	for _, usage := range usages {
+		seen[usage.ContainerID] = true
+		bucket := addSample(buckets, usage)
+		stats = append(stats, bucket.stat)
+	}
+	for id := range buckets {
+		if !seen[id] {
+			delete(buckets, id)
+		}
+	}
+
+	if err := s.storage.UpsertStats(stats); err != nil {
+		s.logger.Log(logger.Error, "Failed to store container stats", err.Error())
+	}
+}
+
+// addSample adds usage to the bucket of its container, starting a new bucket when the sample
+// falls into the next one. The IO transferred since the previous sample is added to the bucket,
+// the first sample of a container only sets the counters it is measured from.
+func addSample(buckets map[string]*statsBucket, usage types.ContainerUsage) *statsBucket {
+	start := usage.Timestamp.Truncate(StatsBucket)
+	counters := ioCounters{
+		networkRx:  usage.NetworkRx,
+		networkTx:  usage.NetworkTx,
+		blockRead:  usage.BlockRead,
+		blockWrite: usage.BlockWrite,
+	}
+
+	bucket, ok := buckets[usage.ContainerID]
+	previous := counters
+	if ok {
+		previous = bucket.counters
+	}
+	if !ok || !bucket.stat.Bucket.Equal(start) {
+		bucket = &statsBucket{
+			stat: shared_types.ContainerStat{
+				ApplicationID: usage.ApplicationID,
+				DeploymentID:  usage.DeploymentID,
+				ContainerID:   usage.ContainerID,
+				Bucket:        start,
+			},
+		}
+		buckets[usage.ContainerID] = bucket
+	}
+
+	bucket.counters = counters
+	bucket.cpuSum += usage.CPUPercent
+	bucket.memSum += float64(usage.MemoryUsage)
+
+	stat := &bucket.stat
+	stat.ContainerName = usage.Name
+	stat.Samples++
+	stat.CPUPercent = bucket.cpuSum / float64(stat.Samples)
+	stat.CPUPercentMax = max(stat.CPUPercentMax, usage.CPUPercent)
+	stat.MemoryUsage = int64(bucket.memSum / float64(stat.Samples))
+	stat.MemoryUsageMax = max(stat.MemoryUsageMax, int64(usage.MemoryUsage))
+	stat.MemoryLimit = int64(usage.MemoryLimit)
+	stat.NetworkRx += int64(counterDelta(previous.networkRx, counters.networkRx))
+	stat.NetworkTx += int64(counterDelta(previous.networkTx, counters.networkTx))
+	stat.BlockRead += int64(counterDelta(previous.blockRead, counters.blockRead))
+	stat.BlockWrite += int64(counterDelta(previous.blockWrite, counters.blockWrite))
+	return bucket
+}
+
+// counterDelta returns how much a counter grew, counting from zero when it was reset.
+func counterDelta(previous, current uint64) uint64 {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
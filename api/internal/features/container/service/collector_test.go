@@ -0,0 +1,80 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/container/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterDelta(t *testing.T) {
+	tests := []struct {
+		name              string
+		previous, current uint64
+		want              uint64
+	}{
+		{name: "Unchanged", previous: 100, current: 100, want: 0},
+		{name: "Grown", previous: 100, current: 250, want: 150},
+		{name: "Reset by a restart", previous: 1000, current: 40, want: 40},
+		{name: "Reset to zero", previous: 1000, current: 0, want: 0},
+		{name: "Near the maximum", previous: math.MaxUint64 - 10, current: math.MaxUint64, want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, counterDelta(tt.previous, tt.current))
+		})
+	}
+}
+
+func TestAddSample(t *testing.T) {
+	applicationID := uuid.New()
+	deploymentID := uuid.New()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sample := func(offset time.Duration, cpu float64, memory, rx uint64) types.ContainerUsage {
+		return types.ContainerUsage{
+			ContainerID:   "c1",
+			Name:          "app.1",
+			ApplicationID: applicationID,
+			DeploymentID:  deploymentID,
+			CPUPercent:    cpu,
+			MemoryUsage:   memory,
+			MemoryLimit:   1000,
+			NetworkRx:     rx,
+			Timestamp:     start.Add(offset),
+		}
+	}
+	buckets := make(map[string]*statsBucket)
+
+	// the first sample only sets the counters the IO is measured from
+	bucket := addSample(buckets, sample(0, 10, 100, 5000))
+	assert.Equal(t, start, bucket.stat.Bucket)
+	assert.Equal(t, deploymentID, bucket.stat.DeploymentID)
+	assert.Equal(t, 1, bucket.stat.Samples)
+	assert.Equal(t, int64(0), bucket.stat.NetworkRx)
+
+	bucket = addSample(buckets, sample(30*time.Second, 30, 300, 5600))
+	assert.Equal(t, 2, bucket.stat.Samples)
+	assert.Equal(t, 20.0, bucket.stat.CPUPercent)
+	assert.Equal(t, 30.0, bucket.stat.CPUPercentMax)
+	assert.Equal(t, int64(200), bucket.stat.MemoryUsage)
+	assert.Equal(t, int64(300), bucket.stat.MemoryUsageMax)
+	assert.Equal(t, int64(600), bucket.stat.NetworkRx)
+
+	// the container restarted and its counters started over
+	bucket = addSample(buckets, sample(time.Minute, 20, 200, 250))
+	assert.Equal(t, int64(850), bucket.stat.NetworkRx)
+
+	// the next bucket starts from the counters of the last sample
+	next := addSample(buckets, sample(StatsBucket, 50, 500, 400))
+	require.Len(t, buckets, 1)
+	assert.Equal(t, start.Add(StatsBucket), next.stat.Bucket)
+	assert.Equal(t, 1, next.stat.Samples)
+	assert.Equal(t, 50.0, next.stat.CPUPercent)
+	assert.Equal(t, int64(150), next.stat.NetworkRx)
+	assert.Equal(t, 3, bucket.stat.Samples, "the previous bucket is left as it was")
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/raghavyuva/nixopus-api/internal/features/container/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+)
+
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+	// maxLogLineLength is the longest line sent at once, longer lines are split.
+	maxLogLineLength = 64 * 1024
+	// taskRefreshInterval bounds how often the tasks of a service are listed again when a line
+	// of an unknown task arrives.
+	taskRefreshInterval = 5 * time.Second
+	swarmTaskIDKey      = "com.docker.swarm.task.id"
+)
+
+// ContainerLogService follows the logs of containers and services line by line.
+type ContainerLogService struct {
+	docker *docker.DockerService
+}
+
+func NewContainerLogService(dockerService *docker.DockerService) *ContainerLogService {
+	return &ContainerLogService{docker: dockerService}
+}
+
+// FollowContainer calls emit with every line the container writes until ctx is done or the
+// container stops. emit is called from a single goroutine.
+func (s *ContainerLogService) FollowContainer(ctx context.Context, containerID string, options types.FollowLogsOptions, emit func(types.LogLine)) error {
+	details, err := s.docker.GetContainerById(containerID)
+	if err != nil {
+		return err
+	}
+	tty := details.Config != nil && details.Config.Tty
+
+	reader, err := s.docker.ContainerLogs(ctx, details.ID, logsOptions(options, false))
+	if err != nil {
+		return err
+	}
+	return copyLogs(ctx, reader, tty, newLineParser(options.Timestamps, nil, emit))
+}
+
+// FollowService calls emit with the lines written by all replicas of a swarm service, each
+// labelled with the task that wrote it, until ctx is done. Replicas started meanwhile are
+// followed as well. emit is called from a single goroutine.
+func (s *ContainerLogService) FollowService(ctx context.Context, serviceID string, options types.FollowLogsOptions, emit func(types.LogLine)) error {
+	service, err := s.docker.GetServiceByID(serviceID)
+	if err != nil {
+		return err
+	}
+	spec := service.Spec.TaskTemplate.ContainerSpec
+	tty := spec != nil && spec.TTY
+
+	reader, err := s.docker.GetServiceLogs(service.ID, logsOptions(options, true))
+	if err != nil {
+		return err
+	}
+	tasks := &taskNames{docker: s.docker, service: service, names: make(map[string]string)}
+	return copyLogs(ctx, reader, tty, newLineParser(options.Timestamps, tasks, emit))
+}
+
+func logsOptions(options types.FollowLogsOptions, details bool) container.LogsOptions {
+	return container.LogsOptions{
+		ShowStdout: options.Stdout,
+		ShowStderr: options.Stderr,
+		Since:      options.Since,
+		Tail:       options.Tail,
+		Timestamps: options.Timestamps,
+		Follow:     true,
+		Details:    details,
+	}
+}
+
+// copyLogs splits the log stream into lines until it ends or ctx is done. Streams without a
+// TTY multiplex stdout and stderr, the output of a TTY is all stdout.
+func copyLogs(ctx context.Context, reader io.ReadCloser, tty bool, parser *lineParser) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		reader.Close()
+	}()
+
+	stdout := &lineWriter{stream: StreamStdout, parser: parser}
+	stderr := &lineWriter{stream: StreamStderr, parser: parser}
+	var err error
+	if tty {
+		_, err = io.Copy(stdout, reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, reader)
+	}
+	stdout.flush()
+	stderr.flush()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// lineWriter buffers the output of a stream until a line is complete.
+type lineWriter struct {
+	stream string
+	parser *lineParser
+	buf    []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			if len(w.buf) >= maxLogLineLength {
+				w.flush()
+			}
+			break
+		}
+		w.buf = append(w.buf, p[:i]...)
+		w.flush()
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (w *lineWriter) flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+	w.parser.parse(w.stream, string(bytes.TrimSuffix(w.buf, []byte("\r"))))
+	w.buf = w.buf[:0]
+}
+
+// lineParser splits the timestamp and, for services, the details naming the task off a line.
+type lineParser struct {
+	timestamps bool
+	tasks      *taskNames
+	emit       func(types.LogLine)
+}
+
+func newLineParser(timestamps bool, tasks *taskNames, emit func(types.LogLine)) *lineParser {
+	return &lineParser{timestamps: timestamps, tasks: tasks, emit: emit}
+}
+
+func (p *lineParser) parse(stream, line string) {
+	logLine := types.LogLine{Stream: stream}
+	if p.timestamps {
+		if timestamp, rest, ok := strings.Cut(line, " "); ok {
+			logLine.Timestamp, line = timestamp, rest
+		}
+	}
+	if p.tasks != nil {
+		// details are "key=value" pairs separated by commas, before the message
+		if details, rest, ok := strings.Cut(line, " "); ok && strings.Contains(details, swarmTaskIDKey+"=") {
+			line = rest
+			for _, pair := range strings.Split(details, ",") {
+				if key, value, _ := strings.Cut(pair, "="); key == swarmTaskIDKey {
+					logLine.Task = p.tasks.name(value)
+				}
+			}
+		}
+	}
+	logLine.Message = line
+	p.emit(logLine)
+}
+
+// taskNames names the tasks of a service like `docker service ps` does: the service name and
+// the slot of the replica, or the node for global services.
+type taskNames struct {
+	docker    *docker.DockerService
+	service   swarm.Service
+	names     map[string]string
+	refreshed time.Time
+}
+
+func (t *taskNames) name(taskID string) string {
+	if name, ok := t.names[taskID]; ok {
+		return name
+	}
+	if time.Since(t.refreshed) >= taskRefreshInterval {
+		t.refresh()
+	}
+	if name, ok := t.names[taskID]; ok {
+		return name
+	}
+	return shortID(taskID)
+}
+
+func (t *taskNames) refresh() {
+	t.refreshed = time.Now()
+	tasks, err := t.docker.GetClusterTasks()
+	if err != nil {
+		return
+	}
+	for _, task := range tasks {
+		if task.ServiceID != t.service.ID {
+			continue
+		}
+		if task.Slot > 0 {
+			t.names[task.ID] = fmt.Sprintf("%s.%d", t.service.Spec.Name, task.Slot)
+		} else {
+			t.names[task.ID] = t.service.Spec.Name + "." + shortID(task.NodeID)
+		}
+	}
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/container/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/container/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/uptrace/bun"
+)
+
+const (
+	// applicationLabel links the containers of a deployment to their application.
+	applicationLabel = "com.application.id"
+	// deploymentLabel links a container to the deployment that started it.
+	deploymentLabel = "com.deployment.id"
+	// statsSampleInterval is how often the collector samples the containers of applications.
+	statsSampleInterval = 30 * time.Second
+	// StatsBucket is the resolution the samples are down-sampled to before they are stored.
+	StatsBucket = 5 * time.Minute
+	// statsSampleConcurrency bounds the containers sampled at once, as every sample keeps the
+	// daemon busy for about a second.
+	statsSampleConcurrency = 8
+)
+
+type ContainerStatsService struct {
+	storage storage.ContainerStatsRepository
+	docker  *docker.DockerService
+	logger  logger.Logger
+}
+
+func NewContainerStatsService(db *bun.DB, ctx context.Context, l logger.Logger, dockerService *docker.DockerService) *ContainerStatsService {
+	return &ContainerStatsService{
+		storage: &storage.ContainerStatsStorage{DB: db, Ctx: ctx},
+		docker:  dockerService,
+		logger:  l,
+	}
+}
+
+// SampleContainers samples the running containers of the applications include accepts, all
+// of them when include is nil. Containers that stop while they are sampled are left out.
+func (s *ContainerStatsService) SampleContainers(include func(applicationID uuid.UUID) bool) ([]types.ContainerUsage, error) {
+	containers, err := s.docker.ListContainers(container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", applicationLabel)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type target struct {
+		id            string
+		name          string
+		applicationID uuid.UUID
+		deploymentID  uuid.UUID
+	}
+	var targets []target
+	for _, c := range containers {
+		applicationID, err := uuid.Parse(c.Labels[applicationLabel])
+		if err != nil || (include != nil && !include(applicationID)) {
+			continue
+		}
+		var name string
+		if len(c.Names) > 0 {
+			name = c.Names[0]
+		}
+		// containers started before deployments were labelled keep the nil uuid
+		deploymentID, _ := uuid.Parse(c.Labels[deploymentLabel])
+		targets = append(targets, target{id: c.ID, name: name, applicationID: applicationID, deploymentID: deploymentID})
+	}
+
+	usages := make([]*types.ContainerUsage, len(targets))
+	semaphore := make(chan struct{}, statsSampleConcurrency)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, t target) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			stats, err := s.docker.SampleContainerStats(t.id)
+			if err != nil {
+				return
+			}
+			usage := types.NewContainerUsage(t.id, t.name, t.applicationID, stats)
+			usage.DeploymentID = t.deploymentID
+			usages[i] = &usage
+		}(i, t)
+	}
+	wg.Wait()
+
+	result := make([]types.ContainerUsage, 0, len(usages))
+	for _, usage := range usages {
+		if usage != nil {
+			result = append(result, *usage)
+		}
+	}
+	return result, nil
+}
+
+// OrganizationUsage samples the running containers of the applications of the organization.
+func (s *ContainerStatsService) OrganizationUsage(organizationID uuid.UUID) ([]types.ContainerUsage, error) {
+	ids, err := s.storage.GetApplicationIDs(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	applications := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		applications[id] = true
+	}
+	return s.SampleContainers(func(applicationID uuid.UUID) bool {
+		return applications[applicationID]
+	})
+}
+
+// GetHistory returns the stored usage of the containers of an application of the organization.
+func (s *ContainerStatsService) GetHistory(request *types.ContainerStatsHistoryRequest) (types.ContainerStatsHistory, error) {
+	ok, err := s.storage.IsOrganizationApplication(request.ApplicationID, request.OrganizationID)
+	if err != nil {
+		return types.ContainerStatsHistory{}, err
+	}
+	if !ok {
+		return types.ContainerStatsHistory{}, types.ErrApplicationNotFound
+	}
+
+	from := time.Now().Add(-time.Duration(request.Hours) * time.Hour).Truncate(StatsBucket)
+	points, err := s.storage.GetStats(request.ApplicationID, request.DeploymentID, from)
+	if err != nil {
+		return types.ContainerStatsHistory{}, err
+	}
+	return types.ContainerStatsHistory{
+		ApplicationID: request.ApplicationID,
+		BucketSeconds: int(StatsBucket.Seconds()),
+		Points:        points,
+	}, nil
+}
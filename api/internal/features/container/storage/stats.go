@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/uptrace/bun"
+)
+
+type ContainerStatsStorage struct {
+	DB  *bun.DB
+	Ctx context.Context
+}
+
+type ContainerStatsRepository interface {
+	GetApplicationIDs(organizationID uuid.UUID) ([]uuid.UUID, error)
+	IsOrganizationApplication(applicationID uuid.UUID, organizationID uuid.UUID) (bool, error)
+	UpsertStats(stats []shared_types.ContainerStat) error
+	GetStats(applicationID uuid.UUID, deploymentID uuid.UUID, from time.Time) ([]shared_types.ContainerStat, error)
+	DeleteStatsBefore(before time.Time) error
+}
+
+// GetApplicationIDs returns the ids of the applications of the organization.
+func (s *ContainerStatsStorage) GetApplicationIDs(organizationID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := s.DB.NewSelect().
+		Table("applications").
+		Column("id").
+		Where("organization_id = ?", organizationID).
+		Scan(s.Ctx, &ids)
+	return ids, err
+}
+
+func (s *ContainerStatsStorage) IsOrganizationApplication(applicationID uuid.UUID, organizationID uuid.UUID) (bool, error) {
+	return s.DB.NewSelect().
+		Table("applications").
+		Where("id = ?", applicationID).
+		Where("organization_id = ?", organizationID).
+		Exists(s.Ctx)
+}
+
+// UpsertStats stores the buckets of stats, replacing the buckets stored already. Buckets of
+// applications deleted meanwhile are skipped.
+func (s *ContainerStatsStorage) UpsertStats(stats []shared_types.ContainerStat) error {
+	ids := make([]uuid.UUID, 0, len(stats))
+	for _, stat := range stats {
+		ids = append(ids, stat.ApplicationID)
+	}
+	var existing []uuid.UUID
+	if len(ids) > 0 {
+		if err := s.DB.NewSelect().Table("applications").Column("id").Where("id IN (?)", bun.In(ids)).Scan(s.Ctx, &existing); err != nil {
+			return err
+		}
+	}
+	exists := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		exists[id] = true
+	}
+	kept := stats[:0:0]
+	for _, stat := range stats {
+		if exists[stat.ApplicationID] {
+			kept = append(kept, stat)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+
+	_, err := s.DB.NewInsert().
+		Model(&kept).
+		On("CONFLICT (application_id, deployment_id, container_id, bucket) DO UPDATE").
+		Set("container_name = EXCLUDED.container_name").
+		Set("samples = EXCLUDED.samples").
+		Set("cpu_percent = EXCLUDED.cpu_percent").
+		Set("cpu_percent_max = EXCLUDED.cpu_percent_max").
+		Set("memory_usage = EXCLUDED.memory_usage").
+		Set("memory_usage_max = EXCLUDED.memory_usage_max").
+		Set("memory_limit = EXCLUDED.memory_limit").
+		Set("network_rx = EXCLUDED.network_rx").
+		Set("network_tx = EXCLUDED.network_tx").
+		Set("block_read = EXCLUDED.block_read").
+		Set("block_write = EXCLUDED.block_write").
+		Exec(s.Ctx)
+	return err
+}
+
+// GetStats returns the buckets of the containers of the application since from, ordered by
+// container and time. A deploymentID other than uuid.Nil only returns the containers of that
+// deployment.
+func (s *ContainerStatsStorage) GetStats(applicationID uuid.UUID, deploymentID uuid.UUID, from time.Time) ([]shared_types.ContainerStat, error) {
+	stats := []shared_types.ContainerStat{}
+	query := s.DB.NewSelect().
+		Model(&stats).
+		Where("application_id = ?", applicationID).
+		Where("bucket >= ?", from)
+	if deploymentID != uuid.Nil {
+		query = query.Where("deployment_id = ?", deploymentID)
+	}
+	err := query.
+		Order("container_name ASC", "container_id ASC", "bucket ASC").
+		Scan(s.Ctx)
+	return stats, err
+}
+
+func (s *ContainerStatsStorage) DeleteStatsBefore(before time.Time) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.ContainerStat)(nil)).
+		Where("bucket < ?", before).
+		Exec(s.Ctx)
+	return err
+}
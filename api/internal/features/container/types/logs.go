@@ -0,0 +1,20 @@
+package types
+
+// FollowLogsOptions selects the logs followed of a container or a service. Since is a
+// timestamp or a duration like "10m", Tail the number of past lines sent first or "all".
+type FollowLogsOptions struct {
+	Since      string
+	Tail       string
+	Timestamps bool
+	Stdout     bool
+	Stderr     bool
+}
+
+// LogLine is a line written by a container. Task names the replica of a service that wrote it,
+// like "api.2", and is empty for the logs of a single container.
+type LogLine struct {
+	Stream    string `json:"stream"`
+	Task      string `json:"task,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Message   string `json:"message"`
+}
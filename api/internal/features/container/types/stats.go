@@ -0,0 +1,110 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/uuid"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	// MaxStatsHistoryHours is how far back the stored usage of containers reaches.
+	MaxStatsHistoryHours = 24
+)
+
+var (
+	ErrInvalidApplicationID = errors.New("invalid application id")
+	ErrInvalidHours         = errors.New("hours must be between 1 and 24")
+	ErrInvalidDeploymentID  = errors.New("invalid deployment id")
+	ErrApplicationNotFound  = errors.New("application not found")
+)
+
+// ContainerUsage is a resource usage sample of a container. Network and block IO are the
+// counters since the container started.
+type ContainerUsage struct {
+	ContainerID   string    `json:"container_id"`
+	Name          string    `json:"name"`
+	ApplicationID uuid.UUID `json:"application_id"`
+	DeploymentID  uuid.UUID `json:"deployment_id"`
+	CPUPercent    float64   `json:"cpu_percent"`
+	MemoryUsage   uint64    `json:"memory_usage"`
+	MemoryLimit   uint64    `json:"memory_limit"`
+	MemoryPercent float64   `json:"memory_percent"`
+	NetworkRx     uint64    `json:"network_rx"`
+	NetworkTx     uint64    `json:"network_tx"`
+	BlockRead     uint64    `json:"block_read"`
+	BlockWrite    uint64    `json:"block_write"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// NewContainerUsage computes the usage of a container from a stats sample of the Docker API,
+// the way `docker stats` does: the CPU usage is relative to a single core and the memory usage
+// leaves out the page cache.
+func NewContainerUsage(containerID, name string, applicationID uuid.UUID, stats container.StatsResponse) ContainerUsage {
+	usage := ContainerUsage{
+		ContainerID:   containerID,
+		Name:          strings.TrimPrefix(name, "/"),
+		ApplicationID: applicationID,
+		MemoryLimit:   stats.MemoryStats.Limit,
+		Timestamp:     stats.Read,
+	}
+	if usage.Timestamp.IsZero() {
+		usage.Timestamp = time.Now()
+	}
+
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	cpus := float64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		usage.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	usage.MemoryUsage = stats.MemoryStats.Usage
+	// cgroup v2 reports inactive_file, v1 total_inactive_file
+	for _, key := range []string{"inactive_file", "total_inactive_file"} {
+		if cache, ok := stats.MemoryStats.Stats[key]; ok && cache < usage.MemoryUsage {
+			usage.MemoryUsage -= cache
+			break
+		}
+	}
+	if usage.MemoryLimit > 0 {
+		usage.MemoryPercent = float64(usage.MemoryUsage) / float64(usage.MemoryLimit) * 100
+	}
+
+	for _, network := range stats.Networks {
+		usage.NetworkRx += network.RxBytes
+		usage.NetworkTx += network.TxBytes
+	}
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			usage.BlockRead += entry.Value
+		case "write":
+			usage.BlockWrite += entry.Value
+		}
+	}
+	return usage
+}
+
+// ContainerStatsHistoryRequest asks for the stored usage of the containers of an application
+// during the last Hours hours.
+type ContainerStatsHistoryRequest struct {
+	ApplicationID  uuid.UUID
+	DeploymentID   uuid.UUID
+	OrganizationID uuid.UUID
+	Hours          int
+}
+
+// ContainerStatsHistory is the usage of the containers of an application, one point per
+// container and bucket of BucketSeconds.
+type ContainerStatsHistory struct {
+	ApplicationID uuid.UUID                    `json:"application_id"`
+	BucketSeconds int                          `json:"bucket_seconds"`
+	Points        []shared_types.ContainerStat `json:"points"`
+}
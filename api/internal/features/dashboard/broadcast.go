@@ -8,30 +8,20 @@ import (
 )
 
 func (m *DashboardMonitor) Broadcast(action string, message interface{}) {
-	lockAcquired := make(chan bool, 1)
-	go func() {
-		m.connMutex.Lock()
-		lockAcquired <- true
-	}()
-
-	select {
-	case <-lockAcquired:
-		defer m.connMutex.Unlock()
-		if m.conn == nil {
-			m.log.Log(logger.Error, "WebSocket connection is nil", "")
-			return
-		}
-		_ = m.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
-
-		if err := m.conn.WriteJSON(map[string]interface{}{"action": action, "data": message, "timestamp": time.Now().Unix(), "topic": "dashboard_monitor"}); err != nil {
-			m.log.Log(logger.Error, "Failed to broadcast message", err.Error())
-		}
-
-		_ = m.conn.SetWriteDeadline(time.Time{})
+	// every writer of the connection writes with a deadline, so waiting for the lock is bounded
+	m.connMutex.Lock()
+	defer m.connMutex.Unlock()
+	if m.conn == nil {
+		m.log.Log(logger.Error, "WebSocket connection is nil", "")
+		return
+	}
+	_ = m.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
 
-	case <-time.After(3 * time.Second):
-		m.log.Log(logger.Error, "Timeout waiting for broadcast lock", "")
+	if err := m.conn.WriteJSON(map[string]interface{}{"action": action, "data": message, "timestamp": time.Now().Unix(), "topic": "dashboard_monitor"}); err != nil {
+		m.log.Log(logger.Error, "Failed to broadcast message", err.Error())
 	}
+
+	_ = m.conn.SetWriteDeadline(time.Time{})
 }
 
 func (m *DashboardMonitor) BroadcastDebug(message string) {
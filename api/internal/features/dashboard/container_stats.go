@@ -0,0 +1,28 @@
+package dashboard
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+func (m *DashboardMonitor) GetContainerStats() {
+	if m.ContainerStats == nil || m.OrganizationID == uuid.Nil {
+		m.BroadcastError("container stats need an organization", GetContainerStats)
+		return
+	}
+
+	usages, err := m.ContainerStats.OrganizationUsage(m.OrganizationID)
+	if err != nil {
+		m.log.Log(logger.Error, "Failed to get container stats", err.Error())
+		m.BroadcastError(err.Error(), GetContainerStats)
+		return
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Name < usages[j].Name
+	})
+
+	m.Broadcast(string(GetContainerStats), usages)
+}
@@ -2,6 +2,7 @@ package dashboard
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -10,12 +11,13 @@ import (
 	sshpkg "github.com/raghavyuva/nixopus-api/internal/features/ssh"
 )
 
-func NewDashboardMonitor(conn *websocket.Conn, log logger.Logger) (*DashboardMonitor, error) {
+func NewDashboardMonitor(conn *websocket.Conn, writeLock *sync.Mutex, log logger.Logger) (*DashboardMonitor, error) {
 	ssh_client := sshpkg.NewSSH()
 	ctx, cancel := context.WithCancel(context.Background())
 
 	monitor := &DashboardMonitor{
 		conn:          conn,
+		connMutex:     writeLock,
 		sshpkg:        ssh_client,
 		log:           log,
 		ctx:           ctx,
@@ -79,6 +81,8 @@ func (m *DashboardMonitor) HandleOperation(operation DashboardOperation) {
 		m.GetContainers()
 	case GetSystemStats:
 		m.GetSystemStats()
+	case GetContainerStats:
+		m.GetContainerStats()
 	default:
 		m.log.Log(logger.Error, "Unknown operation", string(operation))
 	}
@@ -2,6 +2,7 @@ package dashboard
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -142,7 +143,7 @@ func (m *DashboardMonitor) getCommandOutput(cmd string) (string, error) {
 	if err != nil {
 		errMsg := fmt.Sprintf("Command failed: %s, stderr: %s", err.Error(), stderrBuf.String())
 		m.log.Log(logger.Error, errMsg, "")
-		return "", fmt.Errorf(errMsg)
+		return "", errors.New(errMsg)
 	}
 
 	return stdoutBuf.String(), nil
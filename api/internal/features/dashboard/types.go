@@ -5,8 +5,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/melbahja/goph"
+	container_service "github.com/raghavyuva/nixopus-api/internal/features/container/service"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
 	sshpkg "github.com/raghavyuva/nixopus-api/internal/features/ssh"
@@ -17,6 +19,9 @@ type DashboardOperation string
 const (
 	GetContainers  DashboardOperation = "get_containers"
 	GetSystemStats DashboardOperation = "get_system_stats"
+	// GetContainerStats streams the usage of the containers of the applications of
+	// OrganizationID. It is only run when asked for.
+	GetContainerStats DashboardOperation = "get_container_stats"
 )
 
 var AllOperations = []DashboardOperation{
@@ -30,8 +35,9 @@ type MonitoringConfig struct {
 }
 
 type DashboardMonitor struct {
-	conn          *websocket.Conn
-	connMutex     sync.Mutex
+	conn *websocket.Conn
+	// connMutex is the write lock of the connection, shared with its other writers
+	connMutex     *sync.Mutex
 	sshpkg        *sshpkg.SSH
 	log           logger.Logger
 	client        *goph.Client
@@ -40,6 +46,9 @@ type DashboardMonitor struct {
 	cancel        context.CancelFunc
 	ctx           context.Context
 	dockerService *docker.DockerService
+
+	OrganizationID uuid.UUID
+	ContainerStats *container_service.ContainerStatsService
 }
 
 type SystemStats struct {
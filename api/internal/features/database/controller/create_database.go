@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+func (c *DatabaseController) CreateDatabase(f fuego.ContextWithBody[types.CreateDatabaseRequest]) (*shared_types.Response, error) {
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID, err := organizationID(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+	user := utils.GetUser(f.Response(), f.Request())
+
+	created, err := c.service.CreateDatabase(request, user.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to create database", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: databaseErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Database created successfully",
+		Data:    created,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *DatabaseController) DeleteDatabase(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	id, err := c.validator.ValidateID(f.PathParam("id"), types.ErrInvalidDatabaseID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID, err := organizationID(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.service.DeleteDatabase(id, organizationID); err != nil {
+		c.logger.Log(logger.Error, "failed to delete database", id.String()+": "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: databaseErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Database deleted successfully",
+		Data:    nil,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// databaseErrorStatus maps errors of the database service to HTTP statuses.
+func databaseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrDatabaseNotFound),
+		errors.Is(err, types.ErrApplicationNotFound),
+		errors.Is(err, types.ErrDatabaseNotLinked),
+		errors.Is(err, types.ErrDatabaseServiceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrDatabaseNameAlreadyExists),
+		errors.Is(err, types.ErrDatabaseAlreadyRunning),
+		errors.Is(err, types.ErrDatabaseAlreadyStopped),
+		errors.Is(err, types.ErrDatabaseInUse),
+		errors.Is(err, types.ErrDatabaseAlreadyLinked):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrLinkUnsupportedBuildPack):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// organizationID returns the organization of the request or an unauthorized error.
+func organizationID(w http.ResponseWriter, r *http.Request) (uuid.UUID, error) {
+	user := utils.GetUser(w, r)
+	if user == nil {
+		return uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(r)
+	if organizationID == uuid.Nil {
+		return uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+	return organizationID, nil
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetDatabaseLogs returns the recent logs of a database. The tail query parameter limits the
+// number of lines (default 100) and since accepts a timestamp or a duration such as 10m.
+func (c *DatabaseController) GetDatabaseLogs(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	id, err := c.validator.ValidateID(f.PathParam("id"), types.ErrInvalidDatabaseID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	tail, _ := strconv.Atoi(f.QueryParam("tail"))
+	if tail <= 0 || tail > 5000 {
+		tail = 100
+	}
+
+	organizationID, err := organizationID(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := c.service.GetDatabaseLogs(id, organizationID, tail, f.QueryParam("since"))
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get database logs", id.String()+": "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: databaseErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Database logs fetched successfully",
+		Data:    types.DatabaseLogsResponse{Logs: logs},
+	}, nil
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *DatabaseController) GetDatabases(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	organizationID, err := organizationID(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := c.service.GetDatabases(organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get databases", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Databases fetched successfully",
+		Data:    databases,
+	}, nil
+}
+
+func (c *DatabaseController) GetDatabase(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	id, err := c.validator.ValidateID(f.PathParam("id"), types.ErrInvalidDatabaseID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID, err := organizationID(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := c.service.GetDatabase(id, organizationID)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: databaseErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Database fetched successfully",
+		Data:    database,
+	}, nil
+}
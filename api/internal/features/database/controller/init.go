@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/database/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/validation"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type DatabaseController struct {
+	store        *shared_storage.Store
+	validator    *validation.Validator
+	service      *service.DatabaseService
+	ctx          context.Context
+	logger       logger.Logger
+	notification *notification.NotificationManager
+}
+
+func NewDatabaseController(
+	store *shared_storage.Store,
+	ctx context.Context,
+	l logger.Logger,
+	notificationManager *notification.NotificationManager,
+) *DatabaseController {
+	storage := storage.DatabaseStorage{DB: store.DB, Ctx: ctx}
+	return &DatabaseController{
+		store:        store,
+		validator:    validation.NewValidator(&storage),
+		service:      service.NewDatabaseService(store, ctx, l, &storage, docker.NewDockerService()),
+		ctx:          ctx,
+		logger:       l,
+		notification: notificationManager,
+	}
+}
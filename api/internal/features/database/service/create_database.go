@@ -0,0 +1,155 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// CreateDatabase provisions a database service with generated credentials and a persistent
+// volume on the network of the organization.
+func (s *DatabaseService) CreateDatabase(req types.CreateDatabaseRequest, userID uuid.UUID, organizationID uuid.UUID) (*types.CreateDatabaseResponse, error) {
+	taken, err := s.storage.IsNameTaken(req.Name, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, types.ErrDatabaseNameAlreadyExists
+	}
+
+	engine := types.Engines[req.Engine]
+	password, err := generatePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New()
+	serviceName := "nixopus-db-" + strings.ReplaceAll(id.String(), "-", "")[:12]
+	database := shared_types.Database{
+		ID:             id,
+		Name:           req.Name,
+		Engine:         req.Engine,
+		Version:        req.Version,
+		ServiceName:    serviceName,
+		VolumeName:     serviceName + "-data",
+		DatabaseName:   req.DatabaseName,
+		Username:       engine.DefaultUser,
+		Password:       password,
+		Port:           engine.Port,
+		Status:         shared_types.DatabaseStatusRunning,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if database.Version == "" {
+		database.Version = engine.DefaultVersion
+	}
+	if database.DatabaseName == "" {
+		database.DatabaseName = "nixopus"
+	}
+	if req.Engine == shared_types.DatabaseEngineRedis {
+		database.DatabaseName = "0"
+	}
+
+	networkID, err := s.ensureNetwork(organizationID)
+	if err != nil {
+		s.logger.Log(logger.Error, "failed to create database network", err.Error())
+		return nil, fmt.Errorf("%w: %v", types.ErrFailedToCreateDatabase, err)
+	}
+
+	if err := s.docker.PullImage(types.Image(database)); err != nil {
+		s.logger.Log(logger.Error, "failed to pull database image", err.Error())
+		return nil, fmt.Errorf("%w: %v", types.ErrFailedToCreateDatabase, err)
+	}
+
+	if err := s.docker.CreateService(swarm.Service{Spec: databaseServiceSpec(database, networkID)}); err != nil {
+		s.logger.Log(logger.Error, "failed to create database service", err.Error())
+		return nil, fmt.Errorf("%w: %v", types.ErrFailedToCreateDatabase, err)
+	}
+
+	if err := s.storage.CreateDatabase(&database); err != nil {
+		if service, findErr := s.findService(serviceName); findErr == nil && service != nil {
+			s.docker.DeleteService(service.ID)
+		}
+		return nil, err
+	}
+
+	return &types.CreateDatabaseResponse{
+		Database: database,
+		Credentials: types.DatabaseCredentials{
+			Host:             database.ServiceName,
+			Port:             database.Port,
+			Username:         database.Username,
+			Password:         database.Password,
+			DatabaseName:     database.DatabaseName,
+			ConnectionString: types.ConnectionString(database),
+		},
+	}, nil
+}
+
+func databaseServiceSpec(database shared_types.Database, networkID string) swarm.ServiceSpec {
+	engine := types.Engines[database.Engine]
+	replicas := uint64(1)
+
+	return swarm.ServiceSpec{
+		Annotations: swarm.Annotations{
+			Name: database.ServiceName,
+			Labels: map[string]string{
+				"com.nixopus.database.id":     database.ID.String(),
+				"com.nixopus.organization.id": database.OrganizationID.String(),
+			},
+		},
+		Mode: swarm.ServiceMode{
+			Replicated: &swarm.ReplicatedService{
+				Replicas: &replicas,
+			},
+		},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image:   types.Image(database),
+				Env:     engine.Env(database),
+				Command: engine.Command,
+				Labels: map[string]string{
+					"com.nixopus.database.id": database.ID.String(),
+				},
+				Mounts: []mount.Mount{
+					{
+						Type:   mount.TypeVolume,
+						Source: database.VolumeName,
+						Target: engine.DataPath,
+					},
+				},
+			},
+			RestartPolicy: &swarm.RestartPolicy{
+				Condition: swarm.RestartPolicyConditionAny,
+			},
+			Networks: []swarm.NetworkAttachmentConfig{
+				{
+					Target:  networkID,
+					Aliases: []string{database.ServiceName},
+				},
+			},
+		},
+		EndpointSpec: &swarm.EndpointSpec{
+			Mode: swarm.ResolutionModeVIP,
+		},
+	}
+}
+
+func generatePassword() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+// DeleteDatabase removes the service of a database and its volume. Databases that are still
+// linked to applications are not deleted.
+func (s *DatabaseService) DeleteDatabase(id uuid.UUID, organizationID uuid.UUID) error {
+	database, err := s.storage.GetDatabase(id, organizationID)
+	if err != nil {
+		return err
+	}
+	if len(database.Links) > 0 {
+		return types.ErrDatabaseInUse
+	}
+
+	service, err := s.findDatabaseService(database)
+	if err != nil && !errors.Is(err, types.ErrDatabaseServiceNotFound) {
+		return err
+	}
+	if service != nil {
+		if err := s.docker.DeleteService(service.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.storage.DeleteDatabase(database.ID); err != nil {
+		return err
+	}
+
+	// the volume stays in use until the containers of the removed service have exited
+	for attempt := 0; attempt < 15; attempt++ {
+		if err = s.docker.RemoveVolume(database.VolumeName, false); err == nil {
+			return nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+	s.logger.Log(logger.Error, types.ErrFailedToRemoveDatabaseData.Error(), database.VolumeName+": "+err.Error())
+	return types.ErrFailedToRemoveDatabaseData
+}
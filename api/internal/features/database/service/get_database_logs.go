@@ -0,0 +1,47 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/uuid"
+)
+
+// GetDatabaseLogs returns the last lines the database service logged, across its replicas.
+func (s *DatabaseService) GetDatabaseLogs(id uuid.UUID, organizationID uuid.UUID, tail int, since string) ([]string, error) {
+	database, err := s.storage.GetDatabase(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	service, err := s.findDatabaseService(database)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := s.docker.GetServiceLogs(service.ID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Tail:       strconv.Itoa(tail),
+		Since:      since,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var output bytes.Buffer
+	if _, err := stdcopy.StdCopy(&output, &output, reader); err != nil {
+		return nil, err
+	}
+
+	logs := []string{}
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		logs = append(logs, scanner.Text())
+	}
+	return logs, scanner.Err()
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (s *DatabaseService) GetDatabases(organizationID uuid.UUID) ([]shared_types.Database, error) {
+	return s.storage.GetDatabases(organizationID)
+}
+
+func (s *DatabaseService) GetDatabase(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Database, error) {
+	return s.storage.GetDatabase(id, organizationID)
+}
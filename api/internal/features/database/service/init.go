@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+type DatabaseService struct {
+	storage storage.DatabaseStorageInterface
+	docker  docker.DockerRepository
+	Ctx     context.Context
+	store   *shared_storage.Store
+	logger  logger.Logger
+}
+
+func NewDatabaseService(store *shared_storage.Store, ctx context.Context, logger logger.Logger, database_repo storage.DatabaseStorageInterface, dockerRepo docker.DockerRepository) *DatabaseService {
+	return &DatabaseService{
+		storage: database_repo,
+		docker:  dockerRepo,
+		store:   store,
+		Ctx:     ctx,
+		logger:  logger,
+	}
+}
+
+// findService returns the Swarm service with the given name.
+func (s *DatabaseService) findService(name string) (*swarm.Service, error) {
+	services, err := s.docker.GetClusterServices()
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		if service.Spec.Annotations.Name == name {
+			return &service, nil
+		}
+	}
+	return nil, nil
+}
+
+// findDatabaseService returns the Swarm service running a database.
+func (s *DatabaseService) findDatabaseService(database *shared_types.Database) (*swarm.Service, error) {
+	service, err := s.findService(database.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		return nil, types.ErrDatabaseServiceNotFound
+	}
+	return service, nil
+}
+
+// ensureNetwork creates the overlay network databases of an organization are reachable on,
+// if it does not exist yet, and returns its ID.
+func (s *DatabaseService) ensureNetwork(organizationID uuid.UUID) (string, error) {
+	return s.docker.CreateNetwork(types.NetworkName(organizationID), types.NetworkOptions())
+}
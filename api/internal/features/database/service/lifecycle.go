@@ -0,0 +1,45 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// StopDatabase scales the service of a database to zero, its data is kept on the volume.
+func (s *DatabaseService) StopDatabase(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Database, error) {
+	database, err := s.storage.GetDatabase(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if database.Status == shared_types.DatabaseStatusStopped {
+		return nil, types.ErrDatabaseAlreadyStopped
+	}
+	return s.scaleDatabase(database, 0, shared_types.DatabaseStatusStopped)
+}
+
+func (s *DatabaseService) StartDatabase(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Database, error) {
+	database, err := s.storage.GetDatabase(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if database.Status == shared_types.DatabaseStatusRunning {
+		return nil, types.ErrDatabaseAlreadyRunning
+	}
+	return s.scaleDatabase(database, 1, shared_types.DatabaseStatusRunning)
+}
+
+func (s *DatabaseService) scaleDatabase(database *shared_types.Database, replicas uint64, status shared_types.DatabaseStatus) (*shared_types.Database, error) {
+	service, err := s.findDatabaseService(database)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.docker.ScaleService(service.ID, replicas, ""); err != nil {
+		return nil, err
+	}
+	if err := s.storage.UpdateDatabaseStatus(database.ID, status); err != nil {
+		return nil, err
+	}
+	database.Status = status
+	return database, nil
+}
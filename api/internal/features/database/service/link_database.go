@@ -0,0 +1,112 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/tasks"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// LinkDatabase injects the connection string of a database into an application. A running
+// application is updated right away, otherwise the variable is set on its next deployment.
+func (s *DatabaseService) LinkDatabase(databaseID uuid.UUID, req types.LinkDatabaseRequest, organizationID uuid.UUID) (*shared_types.ApplicationDatabase, error) {
+	database, err := s.storage.GetDatabase(databaseID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	application, err := s.storage.GetApplication(req.ApplicationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if application.BuildPack != shared_types.DockerFile {
+		return nil, types.ErrLinkUnsupportedBuildPack
+	}
+
+	envVariable := req.EnvVariable
+	if envVariable == "" {
+		envVariable = types.Engines[database.Engine].EnvVariable
+	}
+
+	links, err := s.storage.GetApplicationLinks(application.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if link.DatabaseID == database.ID || link.EnvVariable == envVariable {
+			return nil, types.ErrDatabaseAlreadyLinked
+		}
+	}
+
+	link := &shared_types.ApplicationDatabase{
+		ID:            uuid.New(),
+		ApplicationID: application.ID,
+		DatabaseID:    database.ID,
+		EnvVariable:   envVariable,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.storage.CreateLink(link); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyLinks(application); err != nil {
+		s.logger.Log(logger.Error, "failed to update application service with database link", application.ID.String()+": "+err.Error())
+	}
+
+	link.Database = database
+	return link, nil
+}
+
+// UnlinkDatabase removes the connection string of a database from an application.
+func (s *DatabaseService) UnlinkDatabase(databaseID uuid.UUID, applicationID uuid.UUID, organizationID uuid.UUID) error {
+	database, err := s.storage.GetDatabase(databaseID, organizationID)
+	if err != nil {
+		return err
+	}
+	application, err := s.storage.GetApplication(applicationID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.storage.DeleteLink(application.ID, database.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.applyLinks(application, link.EnvVariable); err != nil {
+		s.logger.Log(logger.Error, "failed to remove database link from application service", application.ID.String()+": "+err.Error())
+	}
+	return nil
+}
+
+// applyLinks updates the running service of an application with its current links, removing
+// the variables of links that no longer exist.
+func (s *DatabaseService) applyLinks(application *shared_types.Application, removed ...string) error {
+	service, err := s.findService(application.Name)
+	if err != nil || service == nil {
+		return err
+	}
+
+	links, err := s.storage.GetApplicationLinks(application.ID)
+	if err != nil {
+		return err
+	}
+
+	networkID, err := s.ensureNetwork(application.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	spec := service.Spec
+	spec.TaskTemplate.ContainerSpec.Env = types.ApplyLinkEnv(
+		spec.TaskTemplate.ContainerSpec.Env,
+		tasks.GetMapFromString(application.EnvironmentVariables),
+		links,
+		removed...,
+	)
+	spec.TaskTemplate.Networks = types.ApplyLinkNetwork(spec.TaskTemplate.Networks, application.OrganizationID, networkID, len(links) > 0)
+
+	return s.docker.UpdateService(service.ID, spec, "")
+}
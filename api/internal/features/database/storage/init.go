@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/uptrace/bun"
+)
+
+type DatabaseStorage struct {
+	DB  *bun.DB
+	Ctx context.Context
+	tx  *bun.Tx
+}
+
+type DatabaseStorageInterface interface {
+	CreateDatabase(database *shared_types.Database) error
+	GetDatabase(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Database, error)
+	GetDatabases(organizationID uuid.UUID) ([]shared_types.Database, error)
+	IsNameTaken(name string, organizationID uuid.UUID) (bool, error)
+	UpdateDatabaseStatus(id uuid.UUID, status shared_types.DatabaseStatus) error
+	DeleteDatabase(id uuid.UUID) error
+	GetApplication(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Application, error)
+	CreateLink(link *shared_types.ApplicationDatabase) error
+	DeleteLink(applicationID uuid.UUID, databaseID uuid.UUID) (*shared_types.ApplicationDatabase, error)
+	GetApplicationLinks(applicationID uuid.UUID) ([]shared_types.ApplicationDatabase, error)
+	BeginTx() (bun.Tx, error)
+	WithTx(tx bun.Tx) DatabaseStorageInterface
+}
+
+func (s *DatabaseStorage) BeginTx() (bun.Tx, error) {
+	return s.DB.BeginTx(s.Ctx, nil)
+}
+
+func (s *DatabaseStorage) WithTx(tx bun.Tx) DatabaseStorageInterface {
+	return &DatabaseStorage{
+		DB:  s.DB,
+		Ctx: s.Ctx,
+		tx:  &tx,
+	}
+}
+
+func (s *DatabaseStorage) getDB() bun.IDB {
+	if s.tx != nil {
+		return *s.tx
+	}
+	return s.DB
+}
+
+func (s *DatabaseStorage) CreateDatabase(database *shared_types.Database) error {
+	_, err := s.getDB().NewInsert().Model(database).Exec(s.Ctx)
+	return err
+}
+
+func (s *DatabaseStorage) GetDatabase(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Database, error) {
+	var database shared_types.Database
+	err := s.getDB().NewSelect().
+		Model(&database).
+		Relation("Links").
+		Where("db.id = ? AND db.organization_id = ?", id, organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrDatabaseNotFound
+		}
+		return nil, err
+	}
+	return &database, nil
+}
+
+func (s *DatabaseStorage) GetDatabases(organizationID uuid.UUID) ([]shared_types.Database, error) {
+	var databases []shared_types.Database
+	err := s.getDB().NewSelect().
+		Model(&databases).
+		Relation("Links").
+		Where("db.organization_id = ?", organizationID).
+		Order("db.created_at DESC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return databases, nil
+}
+
+func (s *DatabaseStorage) IsNameTaken(name string, organizationID uuid.UUID) (bool, error) {
+	return s.getDB().NewSelect().
+		Model((*shared_types.Database)(nil)).
+		Where("name = ? AND organization_id = ?", name, organizationID).
+		Exists(s.Ctx)
+}
+
+func (s *DatabaseStorage) UpdateDatabaseStatus(id uuid.UUID, status shared_types.DatabaseStatus) error {
+	_, err := s.getDB().NewUpdate().
+		Model((*shared_types.Database)(nil)).
+		Set("status = ?", status).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *DatabaseStorage) DeleteDatabase(id uuid.UUID) error {
+	_, err := s.getDB().NewDelete().
+		Model((*shared_types.Database)(nil)).
+		Where("id = ?", id).
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *DatabaseStorage) GetApplication(id uuid.UUID, organizationID uuid.UUID) (*shared_types.Application, error) {
+	var application shared_types.Application
+	err := s.getDB().NewSelect().
+		Model(&application).
+		Where("id = ? AND organization_id = ?", id, organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (s *DatabaseStorage) CreateLink(link *shared_types.ApplicationDatabase) error {
+	_, err := s.getDB().NewInsert().Model(link).Exec(s.Ctx)
+	return err
+}
+
+func (s *DatabaseStorage) DeleteLink(applicationID uuid.UUID, databaseID uuid.UUID) (*shared_types.ApplicationDatabase, error) {
+	var link shared_types.ApplicationDatabase
+	err := s.getDB().NewDelete().
+		Model(&link).
+		Where("application_id = ? AND database_id = ?", applicationID, databaseID).
+		Returning("*").
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrDatabaseNotLinked
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (s *DatabaseStorage) GetApplicationLinks(applicationID uuid.UUID) ([]shared_types.ApplicationDatabase, error) {
+	var links []shared_types.ApplicationDatabase
+	err := s.getDB().NewSelect().
+		Model(&links).
+		Relation("Database").
+		Where("adb.application_id = ?", applicationID).
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+var (
+	ErrDatabaseNotFound           = errors.New("database not found")
+	ErrApplicationNotFound        = errors.New("application not found")
+	ErrInvalidRequestType         = errors.New("invalid request type")
+	ErrInvalidDatabaseID          = errors.New("invalid database id")
+	ErrInvalidApplicationID       = errors.New("invalid application id")
+	ErrMissingDatabaseName        = errors.New("database name is required")
+	ErrDatabaseNameTooLong        = errors.New("database name must be at most 63 characters")
+	ErrDatabaseNameAlreadyExists  = errors.New("a database with this name already exists")
+	ErrUnsupportedEngine          = errors.New("unsupported database engine, expected one of postgres, mysql, redis or mongodb")
+	ErrInvalidVersion             = errors.New("invalid database version")
+	ErrInvalidSchemaName          = errors.New("invalid database_name, use letters, digits and underscores only")
+	ErrInvalidEnvVariable         = errors.New("invalid environment variable name")
+	ErrDatabaseAlreadyRunning     = errors.New("database is already running")
+	ErrDatabaseAlreadyStopped     = errors.New("database is already stopped")
+	ErrDatabaseInUse              = errors.New("database is linked to applications, unlink it first")
+	ErrDatabaseAlreadyLinked      = errors.New("database is already linked to this application or the environment variable is taken")
+	ErrDatabaseNotLinked          = errors.New("database is not linked to this application")
+	ErrLinkUnsupportedBuildPack   = errors.New("databases can only be linked to dockerfile applications")
+	ErrDatabaseServiceNotFound    = errors.New("database service not found")
+	ErrFailedToCreateDatabase     = errors.New("failed to create database")
+	ErrFailedToRemoveDatabaseData = errors.New("database removed but its volume could not be deleted")
+)
+
+type CreateDatabaseRequest struct {
+	Name         string                      `json:"name"`
+	Engine       shared_types.DatabaseEngine `json:"engine"`
+	Version      string                      `json:"version,omitempty"`
+	DatabaseName string                      `json:"database_name,omitempty"`
+}
+
+type LinkDatabaseRequest struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	EnvVariable   string    `json:"env_variable,omitempty"`
+}
+
+// DatabaseCredentials are only returned when a database is created, afterwards the
+// connection string is only available to linked applications.
+type DatabaseCredentials struct {
+	Host             string `json:"host"`
+	Port             int    `json:"port"`
+	Username         string `json:"username"`
+	Password         string `json:"password"`
+	DatabaseName     string `json:"database_name"`
+	ConnectionString string `json:"connection_string"`
+}
+
+type CreateDatabaseResponse struct {
+	Database    shared_types.Database `json:"database"`
+	Credentials DatabaseCredentials   `json:"credentials"`
+}
+
+type DatabaseLogsResponse struct {
+	Logs []string `json:"logs"`
+}
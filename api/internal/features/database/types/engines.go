@@ -0,0 +1,188 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/uuid"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// Engine describes how a database engine is run and how applications connect to it.
+type Engine struct {
+	Image          string
+	DefaultVersion string
+	Port           int
+	DataPath       string
+	DefaultUser    string
+	// EnvVariable is the variable the connection string is injected into when a link does
+	// not name one.
+	EnvVariable string
+	Env         func(db shared_types.Database) []string
+	Command     []string
+	Scheme      string
+	// Query is appended to the connection string, e.g. to select the authentication database.
+	Query string
+}
+
+var Engines = map[shared_types.DatabaseEngine]Engine{
+	shared_types.DatabaseEnginePostgres: {
+		Image:          "postgres",
+		DefaultVersion: "16",
+		Port:           5432,
+		DataPath:       "/var/lib/postgresql/data",
+		DefaultUser:    "nixopus",
+		EnvVariable:    "DATABASE_URL",
+		Scheme:         "postgres",
+		Env: func(db shared_types.Database) []string {
+			return []string{
+				"POSTGRES_USER=" + db.Username,
+				"POSTGRES_PASSWORD=" + db.Password,
+				"POSTGRES_DB=" + db.DatabaseName,
+			}
+		},
+	},
+	shared_types.DatabaseEngineMySQL: {
+		Image:          "mysql",
+		DefaultVersion: "8.4",
+		Port:           3306,
+		DataPath:       "/var/lib/mysql",
+		DefaultUser:    "nixopus",
+		EnvVariable:    "DATABASE_URL",
+		Scheme:         "mysql",
+		Env: func(db shared_types.Database) []string {
+			return []string{
+				"MYSQL_USER=" + db.Username,
+				"MYSQL_PASSWORD=" + db.Password,
+				"MYSQL_ROOT_PASSWORD=" + db.Password,
+				"MYSQL_DATABASE=" + db.DatabaseName,
+			}
+		},
+	},
+	shared_types.DatabaseEngineRedis: {
+		Image:          "redis",
+		DefaultVersion: "7",
+		Port:           6379,
+		DataPath:       "/data",
+		DefaultUser:    "default",
+		EnvVariable:    "REDIS_URL",
+		Scheme:         "redis",
+		Env: func(db shared_types.Database) []string {
+			return []string{"REDIS_PASSWORD=" + db.Password}
+		},
+		// the password is passed through the environment so it does not show up in the service spec arguments
+		Command: []string{"sh", "-c", `exec redis-server --appendonly yes --requirepass "$REDIS_PASSWORD"`},
+	},
+	shared_types.DatabaseEngineMongoDB: {
+		Image:          "mongo",
+		DefaultVersion: "7",
+		Port:           27017,
+		DataPath:       "/data/db",
+		DefaultUser:    "nixopus",
+		EnvVariable:    "MONGODB_URL",
+		Scheme:         "mongodb",
+		Query:          "authSource=admin",
+		Env: func(db shared_types.Database) []string {
+			return []string{
+				"MONGO_INITDB_ROOT_USERNAME=" + db.Username,
+				"MONGO_INITDB_ROOT_PASSWORD=" + db.Password,
+				"MONGO_INITDB_DATABASE=" + db.DatabaseName,
+			}
+		},
+	},
+}
+
+// NetworkName returns the overlay network shared by the databases of an organization and
+// the applications linked to them.
+func NetworkName(organizationID uuid.UUID) string {
+	return "nixopus-databases-" + organizationID.String()
+}
+
+// NetworkOptions returns the options the database network of an organization is created with,
+// it must be attachable for application services to join it.
+func NetworkOptions() network.CreateOptions {
+	return network.CreateOptions{
+		Driver:     "overlay",
+		Attachable: true,
+	}
+}
+
+// ConnectionString returns the URL applications on the database network connect with.
+func ConnectionString(db shared_types.Database) string {
+	engine := Engines[db.Engine]
+	u := url.URL{
+		Scheme:   engine.Scheme,
+		User:     url.UserPassword(db.Username, db.Password),
+		Host:     net.JoinHostPort(db.ServiceName, strconv.Itoa(db.Port)),
+		Path:     "/" + db.DatabaseName,
+		RawQuery: engine.Query,
+	}
+	return u.String()
+}
+
+// Image returns the image reference the database service runs.
+func Image(db shared_types.Database) string {
+	return fmt.Sprintf("%s:%s", Engines[db.Engine].Image, db.Version)
+}
+
+// ApplyLinkEnv sets the connection strings of links on env, a list of KEY=VALUE pairs, and
+// drops the variables in removed. Variables the application defines itself take precedence.
+func ApplyLinkEnv(env []string, defined map[string]string, links []shared_types.ApplicationDatabase, removed ...string) []string {
+	linked := make(map[string]string, len(links))
+	for _, link := range links {
+		if link.Database == nil {
+			continue
+		}
+		if _, ok := defined[link.EnvVariable]; ok {
+			continue
+		}
+		linked[link.EnvVariable] = ConnectionString(*link.Database)
+	}
+
+	result := make([]string, 0, len(env)+len(linked))
+	for _, pair := range env {
+		key, _, _ := strings.Cut(pair, "=")
+		if _, ok := linked[key]; ok {
+			continue
+		}
+		if _, ok := defined[key]; !ok && contains(removed, key) {
+			continue
+		}
+		result = append(result, pair)
+	}
+	for key, value := range linked {
+		result = append(result, key+"="+value)
+	}
+	return result
+}
+
+// ApplyLinkNetwork attaches the database network of the organization to networks when the
+// application has links and detaches it otherwise. Inspected services reference networks by
+// ID, so both the ID and the name of the network are matched.
+func ApplyLinkNetwork(networks []swarm.NetworkAttachmentConfig, organizationID uuid.UUID, networkID string, linked bool) []swarm.NetworkAttachmentConfig {
+	name := NetworkName(organizationID)
+	result := make([]swarm.NetworkAttachmentConfig, 0, len(networks)+1)
+	for _, n := range networks {
+		if n.Target != name && n.Target != networkID {
+			result = append(result, n)
+		}
+	}
+	if linked {
+		result = append(result, swarm.NetworkAttachmentConfig{Target: networkID})
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+)
+
+var (
+	versionPattern     = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+	identifierPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+	envVariablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Validator handles database validation logic
+type Validator struct {
+	storage storage.DatabaseStorageInterface
+}
+
+// NewValidator creates a new validator instance
+func NewValidator(storage storage.DatabaseStorageInterface) *Validator {
+	return &Validator{
+		storage: storage,
+	}
+}
+
+// ValidateID parses a database or application ID taken from the request path
+func (v *Validator) ValidateID(id string, invalid error) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil || parsed == uuid.Nil {
+		return uuid.Nil, invalid
+	}
+	return parsed, nil
+}
+
+// ValidateRequest validates different database request types
+func (v *Validator) ValidateRequest(req interface{}) error {
+	switch r := req.(type) {
+	case *types.CreateDatabaseRequest:
+		return v.validateCreateDatabaseRequest(*r)
+	case *types.LinkDatabaseRequest:
+		return v.validateLinkDatabaseRequest(*r)
+	default:
+		return types.ErrInvalidRequestType
+	}
+}
+
+func (v *Validator) validateCreateDatabaseRequest(req types.CreateDatabaseRequest) error {
+	if req.Name == "" {
+		return types.ErrMissingDatabaseName
+	}
+	if len(req.Name) > 63 {
+		return types.ErrDatabaseNameTooLong
+	}
+	if _, ok := types.Engines[req.Engine]; !ok {
+		return types.ErrUnsupportedEngine
+	}
+	if req.Version != "" && !versionPattern.MatchString(req.Version) {
+		return types.ErrInvalidVersion
+	}
+	if req.DatabaseName != "" && !identifierPattern.MatchString(req.DatabaseName) {
+		return types.ErrInvalidSchemaName
+	}
+	return nil
+}
+
+func (v *Validator) validateLinkDatabaseRequest(req types.LinkDatabaseRequest) error {
+	if req.ApplicationID == uuid.Nil {
+		return types.ErrInvalidApplicationID
+	}
+	if req.EnvVariable != "" && !envVariablePattern.MatchString(req.EnvVariable) {
+		return types.ErrInvalidEnvVariable
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/database/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateDatabaseRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request types.CreateDatabaseRequest
+		wantErr error
+	}{
+		{name: "Valid", request: types.CreateDatabaseRequest{Name: "orders", Engine: shared_types.DatabaseEnginePostgres}},
+		{name: "Version and database name", request: types.CreateDatabaseRequest{Name: "orders", Engine: shared_types.DatabaseEngineMySQL, Version: "8.0.36-debian", DatabaseName: "orders_db"}},
+		{name: "Missing name", request: types.CreateDatabaseRequest{Engine: shared_types.DatabaseEngineRedis}, wantErr: types.ErrMissingDatabaseName},
+		{name: "Name too long", request: types.CreateDatabaseRequest{Name: strings.Repeat("a", 64), Engine: shared_types.DatabaseEngineRedis}, wantErr: types.ErrDatabaseNameTooLong},
+		{name: "Unknown engine", request: types.CreateDatabaseRequest{Name: "orders", Engine: "oracle"}, wantErr: types.ErrUnsupportedEngine},
+		{name: "Version with a tag separator", request: types.CreateDatabaseRequest{Name: "orders", Engine: shared_types.DatabaseEngineMongoDB, Version: "7:latest"}, wantErr: types.ErrInvalidVersion},
+		{name: "Version starting with a dot", request: types.CreateDatabaseRequest{Name: "orders", Engine: shared_types.DatabaseEngineMongoDB, Version: ".7"}, wantErr: types.ErrInvalidVersion},
+		{name: "Database name with a dash", request: types.CreateDatabaseRequest{Name: "orders", Engine: shared_types.DatabaseEnginePostgres, DatabaseName: "orders-db"}, wantErr: types.ErrInvalidSchemaName},
+		{name: "Database name starting with a digit", request: types.CreateDatabaseRequest{Name: "orders", Engine: shared_types.DatabaseEnginePostgres, DatabaseName: "1orders"}, wantErr: types.ErrInvalidSchemaName},
+	}
+
+	v := NewValidator(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, v.ValidateRequest(&tt.request))
+		})
+	}
+}
+
+func TestValidateLinkDatabaseRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request types.LinkDatabaseRequest
+		wantErr error
+	}{
+		{name: "Default variable", request: types.LinkDatabaseRequest{ApplicationID: uuid.New()}},
+		{name: "Custom variable", request: types.LinkDatabaseRequest{ApplicationID: uuid.New(), EnvVariable: "ORDERS_DATABASE_URL"}},
+		{name: "Missing application", request: types.LinkDatabaseRequest{EnvVariable: "DATABASE_URL"}, wantErr: types.ErrInvalidApplicationID},
+		{name: "Variable with a dash", request: types.LinkDatabaseRequest{ApplicationID: uuid.New(), EnvVariable: "DATABASE-URL"}, wantErr: types.ErrInvalidEnvVariable},
+		{name: "Variable with an assignment", request: types.LinkDatabaseRequest{ApplicationID: uuid.New(), EnvVariable: "DATABASE_URL=x"}, wantErr: types.ErrInvalidEnvVariable},
+	}
+
+	v := NewValidator(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, v.ValidateRequest(&tt.request))
+		})
+	}
+}
+
+func TestValidateRequestType(t *testing.T) {
+	assert.Equal(t, types.ErrInvalidRequestType, NewValidator(nil).ValidateRequest(struct{}{}))
+}
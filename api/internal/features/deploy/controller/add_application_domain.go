@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// AddApplicationDomain assigns a domain of the organization to an application.
+func (c *DeployController) AddApplicationDomain(f fuego.ContextWithBody[types.AddApplicationDomainRequest]) (*shared_types.Response, error) {
+	data, err := f.Body()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrMissingID,
+				Status: http.StatusBadRequest,
+			}
+		}
+		c.logger.Log(logger.Error, "failed to read request body", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user := utils.GetUser(f.Response(), f.Request())
+	if user == nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(f.Request())
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	application, err := c.taskService.AddApplicationDomain(&data, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to add application domain", "id: "+data.ID.String()+", error: "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: domainErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "application domain added", "id: "+data.ID.String()+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Domain added successfully",
+		Data:    application,
+	}, nil
+}
+
+// domainErrorStatus maps errors of the application domain operations to HTTP statuses.
+func domainErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, types.ErrApplicationDomainNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrDomainNotRegistered), errors.Is(err, types.ErrDomainNotVerified),
+		errors.Is(err, types.ErrPrimaryDomainMustServe),
+		errors.Is(err, types.ErrCannotRemovePrimaryDomain):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrDomainAlreadyTaken):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrApplyDomains):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
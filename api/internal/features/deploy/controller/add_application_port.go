@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// AddApplicationPort exposes a TCP or UDP port of an application.
+func (c *DeployController) AddApplicationPort(f fuego.ContextWithBody[types.AddApplicationPortRequest]) (*shared_types.Response, error) {
+	data, err := f.Body()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrMissingID,
+				Status: http.StatusBadRequest,
+			}
+		}
+		c.logger.Log(logger.Error, "failed to read request body", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user := utils.GetUser(f.Response(), f.Request())
+	if user == nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(f.Request())
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	application, err := c.taskService.AddApplicationPort(&data, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to add application port", "id: "+data.ID.String()+", error: "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: portErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "application port added", "id: "+data.ID.String()+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Port exposed successfully",
+		Data:    application,
+	}, nil
+}
+
+// portErrorStatus maps errors of the application port operations to HTTP statuses.
+func portErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, types.ErrApplicationPortNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrPortsNeedDockerfile), errors.Is(err, types.ErrSNIRoutingDisabled),
+		errors.Is(err, types.ErrPublishedPortReserved), errors.Is(err, types.ErrDomainNotVerified):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrPublishedPortTaken), errors.Is(err, types.ErrTargetPortAlreadyExposed),
+		errors.Is(err, types.ErrSNIAlreadyTaken), errors.Is(err, types.ErrNoAvailablePorts),
+		errors.Is(err, types.ErrDomainOwnedElsewhere):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrApplyPorts):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetDeadLetters lists the tasks of a deployment queue that ran out of retries.
+func (c *DeployController) GetDeadLetters(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	if _, err := c.requireAdmin(f.Response(), f.Request()); err != nil {
+		return nil, err
+	}
+
+	queueName := f.QueryParam("queue")
+	if queueName == "" {
+		return nil, fuego.HTTPError{
+			Err:    types.ErrUnknownQueue,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	deadLetters, err := c.taskService.GetDeadLetters(f.Request().Context(), queueName)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get dead letters", err.Error())
+		status := http.StatusInternalServerError
+		if errors.Is(err, types.ErrUnknownQueue) {
+			status = http.StatusBadRequest
+		}
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: status,
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Dead letters",
+		Data:    deadLetters,
+	}, nil
+}
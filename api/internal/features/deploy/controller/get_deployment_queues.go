@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/tasks"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetDeploymentQueues lists the waiting, in-flight and retrying tasks of every deployment queue.
+func (c *DeployController) GetDeploymentQueues(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	if _, err := c.requireAdmin(f.Response(), f.Request()); err != nil {
+		return nil, err
+	}
+
+	queues, err := c.taskService.GetQueueStatus(f.Request().Context())
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to inspect deployment queues", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Deployment queues",
+		Data:    queues,
+	}, nil
+}
+
+// GetDeploymentQueueStatus returns the current state of the deployment queues for the realtime
+// dashboard.
+func (c *DeployController) GetDeploymentQueueStatus() ([]tasks.DeploymentQueueStatus, error) {
+	return c.taskService.GetQueueStatus(c.ctx)
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetPortAllocations lists the host ports reserved for applications on every server.
+// Port allocations span organizations, so only admins can list them.
+func (c *DeployController) GetPortAllocations(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	if _, err := c.requireAdmin(f.Response(), f.Request()); err != nil {
+		return nil, err
+	}
+
+	allocations, err := c.service.GetPortAllocations()
+	if err != nil {
+		c.logger.Log(logger.Error, err.Error(), "")
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Port allocations",
+		Data:    allocations,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetServerPorts lists the ports published on every server, the HTTP ports of the applications
+// and the TCP and UDP ports they expose. Like port allocations they span organizations, so
+// only admins can list them.
+func (c *DeployController) GetServerPorts(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	if _, err := c.requireAdmin(f.Response(), f.Request()); err != nil {
+		return nil, err
+	}
+
+	servers, err := c.service.GetServerPorts()
+	if err != nil {
+		c.logger.Log(logger.Error, err.Error(), "")
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Server ports",
+		Data:    servers,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-fuego/fuego"
@@ -56,9 +57,16 @@ func (c *DeployController) HandleDeploy(f fuego.ContextWithBody[types.CreateDepl
 	application, err := c.taskService.CreateDeploymentTask(&data, user.ID, organizationID)
 	if err != nil {
 		c.logger.Log(logger.Error, "failed to create deployment", "name: "+data.Name+", error: "+err.Error())
+		status := http.StatusInternalServerError
+		if errors.Is(err, types.ErrDomainOwnedElsewhere) || errors.Is(err, types.ErrDomainAlreadyTaken) {
+			status = http.StatusConflict
+		}
+		if errors.Is(err, types.ErrDomainNotVerified) {
+			status = http.StatusBadRequest
+		}
 		return nil, fuego.HTTPError{
 			Err:    err,
-			Status: http.StatusInternalServerError,
+			Status: status,
 		}
 	}
 
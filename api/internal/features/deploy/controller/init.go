@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/go-fuego/fuego"
+
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/service"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
@@ -43,6 +45,8 @@ func NewDeployController(
 	taskService := tasks.NewTaskService(&storage, l, docker_repo, github_service, store)
 	taskService.SetupCreateDeploymentQueue()
 	taskService.StartConsumers(ctx)
+	taskService.StartIdleMonitor(ctx)
+	taskService.StartProxyReconciler(ctx)
 
 	return &DeployController{
 		store:        store,
@@ -94,3 +98,28 @@ func (c *DeployController) parseAndValidate(w http.ResponseWriter, r *http.Reque
 
 	return true
 }
+
+// requireAdmin returns the authenticated user if they are an admin.
+//
+// Some deployment resources, like port allocations and the deployment queues, are shared
+// by every organization on the server, so they are only exposed to admins.
+func (c *DeployController) requireAdmin(w http.ResponseWriter, r *http.Request) (*shared_types.User, error) {
+	user := utils.GetUser(w, r)
+	if user == nil {
+		c.logger.Log(logger.Error, "user not found", "")
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	if user.Type != shared_types.UserTypeAdmin {
+		c.logger.Log(logger.Error, "admin access required", user.ID.String())
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusForbidden,
+		}
+	}
+
+	return user, nil
+}
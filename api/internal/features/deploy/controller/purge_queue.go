@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// PurgeQueue removes a single task, all waiting and retrying tasks, or all dead-lettered
+// tasks from a deployment queue. In-flight tasks are never removed.
+func (c *DeployController) PurgeQueue(f fuego.ContextWithBody[types.PurgeQueueRequest]) (*shared_types.Response, error) {
+	user, err := c.requireAdmin(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	removed, err := c.taskService.PurgeQueueTasks(f.Request().Context(), &data)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to purge queue", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: queueErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "queue purged", "queue: "+data.Queue+", removed: "+strconv.Itoa(removed)+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Queue purged successfully",
+		Data: map[string]interface{}{
+			"removed": removed,
+		},
+	}, nil
+}
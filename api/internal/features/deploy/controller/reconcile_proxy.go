@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// ReconcileProxy compares the routes of Caddy with the deployed applications and reports the
+// drift, repairing it when fix is set. An empty body only reports.
+func (c *DeployController) ReconcileProxy(f fuego.ContextWithBody[types.ReconcileProxyRequest]) (*shared_types.Response, error) {
+	user, err := c.requireAdmin(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := f.Body()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	result, err := c.taskService.ReconcileProxy(data.Fix)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to reconcile proxy routes", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadGateway,
+		}
+	}
+
+	c.logger.Log(logger.Info, "proxy routes reconciled", "drift: "+strconv.Itoa(len(result.Drift))+", fixed: "+strconv.FormatBool(result.Fixed)+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Proxy routes reconciled successfully",
+		Data:    result,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// RemoveApplicationDomain unassigns a domain from an application.
+func (c *DeployController) RemoveApplicationDomain(f fuego.ContextWithBody[types.RemoveApplicationDomainRequest]) (*shared_types.Response, error) {
+	data, err := f.Body()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrMissingID,
+				Status: http.StatusBadRequest,
+			}
+		}
+		c.logger.Log(logger.Error, "failed to read request body", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user := utils.GetUser(f.Response(), f.Request())
+	if user == nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(f.Request())
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	application, err := c.taskService.RemoveApplicationDomain(&data, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to remove application domain", "id: "+data.ID.String()+", error: "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: domainErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "application domain removed", "id: "+data.ID.String()+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Domain removed successfully",
+		Data:    application,
+	}, nil
+}
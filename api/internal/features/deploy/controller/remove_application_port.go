@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// RemoveApplicationPort stops exposing a port of an application.
+func (c *DeployController) RemoveApplicationPort(f fuego.ContextWithBody[types.RemoveApplicationPortRequest]) (*shared_types.Response, error) {
+	data, err := f.Body()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrMissingID,
+				Status: http.StatusBadRequest,
+			}
+		}
+		c.logger.Log(logger.Error, "failed to read request body", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user := utils.GetUser(f.Response(), f.Request())
+	if user == nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(f.Request())
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	application, err := c.taskService.RemoveApplicationPort(&data, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to remove application port", "id: "+data.ID.String()+", error: "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: portErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "application port removed", "id: "+data.ID.String()+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Port removed successfully",
+		Data:    application,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/queue"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// ReprioritizeQueueTask moves a waiting task to the front or back of its queue,
+// or makes a retrying task due immediately.
+func (c *DeployController) ReprioritizeQueueTask(f fuego.ContextWithBody[types.ReprioritizeQueueTaskRequest]) (*shared_types.Response, error) {
+	user, err := c.requireAdmin(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.taskService.ReprioritizeTask(f.Request().Context(), &data); err != nil {
+		c.logger.Log(logger.Error, "failed to reprioritize task", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: queueErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "queue task reprioritized", "queue: "+data.Queue+", task_id: "+data.TaskID+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Task reprioritized successfully",
+		Data:    nil,
+	}, nil
+}
+
+// queueErrorStatus maps queue errors to the HTTP status returned to the client.
+func queueErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrUnknownQueue), errors.Is(err, queue.ErrTaskRetrying):
+		return http.StatusBadRequest
+	case errors.Is(err, queue.ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, queue.ErrTaskInFlight):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
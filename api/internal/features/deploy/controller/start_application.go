@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// StartApplication scales a stopped or sleeping application back up. It responds once the
+// service accepts connections and the proxy routes the domain to it.
+func (c *DeployController) StartApplication(f fuego.ContextWithBody[types.StartApplicationRequest]) (*shared_types.Response, error) {
+	data, err := f.Body()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrMissingID,
+				Status: http.StatusBadRequest,
+			}
+		}
+		c.logger.Log(logger.Error, "failed to read request body", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user := utils.GetUser(f.Response(), f.Request())
+	if user == nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(f.Request())
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	application, err := c.taskService.StartApplication(f.Request().Context(), data.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to start application", "id: "+data.ID.String()+", error: "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: lifecycleErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "application started", "id: "+data.ID.String()+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Application started successfully",
+		Data:    application,
+	}, nil
+}
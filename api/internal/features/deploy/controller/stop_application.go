@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// StopApplication scales the service of an application to zero until it is started again.
+func (c *DeployController) StopApplication(f fuego.ContextWithBody[types.StopApplicationRequest]) (*shared_types.Response, error) {
+	data, err := f.Body()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrMissingID,
+				Status: http.StatusBadRequest,
+			}
+		}
+		c.logger.Log(logger.Error, "failed to read request body", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user := utils.GetUser(f.Response(), f.Request())
+	if user == nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(f.Request())
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	application, err := c.taskService.StopApplication(data.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to stop application", "id: "+data.ID.String()+", error: "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: lifecycleErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "application stopped", "id: "+data.ID.String()+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Application stopped successfully",
+		Data:    application,
+	}, nil
+}
+
+// lifecycleErrorStatus maps errors of the stop, start and wake operations to HTTP statuses.
+func lifecycleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrStaticSiteCannotBeStopped), errors.Is(err, types.ErrComposeCannotBeStopped):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrApplicationAlreadyStopped), errors.Is(err, types.ErrApplicationNotStopped):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrServiceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrServiceNotHealthy):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
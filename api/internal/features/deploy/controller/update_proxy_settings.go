@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// UpdateProxySettings replaces the proxy settings of an application and applies them to its domain.
+func (c *DeployController) UpdateProxySettings(f fuego.ContextWithBody[types.UpdateProxySettingsRequest]) (*shared_types.Response, error) {
+	data, err := f.Body()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrMissingID,
+				Status: http.StatusBadRequest,
+			}
+		}
+		c.logger.Log(logger.Error, "failed to read request body", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&data); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user := utils.GetUser(f.Response(), f.Request())
+	if user == nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(f.Request())
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	application, err := c.taskService.UpdateProxySettings(&data, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to update proxy settings", "id: "+data.ID.String()+", error: "+err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: proxySettingsErrorStatus(err),
+		}
+	}
+
+	c.logger.Log(logger.Info, "proxy settings updated", "id: "+data.ID.String()+", user_id: "+user.ID.String())
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Proxy settings updated successfully",
+		Data:    application,
+	}, nil
+}
+
+func proxySettingsErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrMissingBasicAuthPassword), errors.Is(err, types.ErrPathPrefixNeedsCaddy):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrDomainAlreadyTaken):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrApplyProxySettings):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
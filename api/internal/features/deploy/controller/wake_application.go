@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/tasks"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+// WakeApplication receives the requests the proxy forwards for a sleeping application.
+//
+// The request is held until the application is running again and then proxied to it, later
+// requests reach the application directly because waking it restores its proxy route.
+func (c *DeployController) WakeApplication(w http.ResponseWriter, r *http.Request) {
+	applicationID, err := uuid.Parse(r.PathValue("application_id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !tasks.ValidWakeToken(applicationID, r.Header.Get(types.WakeTokenHeader)) {
+		c.logger.Log(logger.Error, types.ErrInvalidWakeToken.Error(), applicationID.String())
+		http.Error(w, types.ErrInvalidWakeToken.Error(), http.StatusForbidden)
+		return
+	}
+
+	application, err := c.taskService.WakeApplication(r.Context(), applicationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to wake application", applicationID.String()+": "+err.Error())
+		w.Header().Set("Retry-After", "10")
+		http.Error(w, "application is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	upstream, err := c.taskService.ApplicationUpstream(application)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to resolve application upstream", applicationID.String()+": "+err.Error())
+		http.Error(w, "application is not available", http.StatusBadGateway)
+		return
+	}
+
+	// the proxy prepends the wake path to the original request URI
+	escapedPath := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v1"+tasks.WakePath(applicationID))
+	if escapedPath == "" {
+		escapedPath = "/"
+	}
+	path, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Scheme = "http"
+			pr.Out.URL.Host = upstream
+			pr.Out.URL.Path = path
+			pr.Out.URL.RawPath = escapedPath
+			pr.Out.Host = pr.In.Host
+			pr.Out.Header.Del(types.WakeTokenHeader)
+			for _, header := range []string{"X-Forwarded-For", "X-Forwarded-Host", "X-Forwarded-Proto"} {
+				if values := pr.In.Header.Values(header); len(values) > 0 {
+					pr.Out.Header[header] = values
+				}
+			}
+		},
+	}
+	proxy.ServeHTTP(w, r)
+}
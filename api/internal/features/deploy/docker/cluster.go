@@ -1,7 +1,10 @@
 package docker
 
 import (
+	"io"
+
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
@@ -186,3 +189,33 @@ func (s *DockerService) GetServiceByID(serviceID string) (swarm.Service, error)
 	service, _, err := s.Cli.ServiceInspectWithRaw(s.Ctx, serviceID, types.ServiceInspectOptions{})
 	return service, err
 }
+
+func (s *DockerService) GetServiceLogs(serviceID string, opts container.LogsOptions) (io.ReadCloser, error) {
+	return s.Cli.ServiceLogs(s.Ctx, serviceID, opts)
+}
+
+// CreateNetwork creates a network and returns its ID, or the ID of the existing network
+// with the same name.
+func (s *DockerService) CreateNetwork(name string, opts network.CreateOptions) (string, error) {
+	networks, err := s.Cli.NetworkList(s.Ctx, network.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", name)),
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, n := range networks {
+		if n.Name == name {
+			return n.ID, nil
+		}
+	}
+
+	resp, err := s.Cli.NetworkCreate(s.Ctx, name, opts)
+	if err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (s *DockerService) RemoveVolume(volumeName string, force bool) error {
+	return s.Cli.VolumeRemove(s.Ctx, volumeName, force)
+}
@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// ExecTTY starts cmd in the container with a TTY attached to the returned connection. Input
+// written to the connection reaches the process, its output is read from the connection.
+func (s *DockerService) ExecTTY(containerID string, cmd []string, env []string, rows, cols uint) (string, types.HijackedResponse, error) {
+	created, err := s.Cli.ContainerExecCreate(s.Ctx, containerID, container.ExecOptions{
+		Tty:          true,
+		ConsoleSize:  &[2]uint{rows, cols},
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          env,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return "", types.HijackedResponse{}, err
+	}
+
+	attached, err := s.Cli.ContainerExecAttach(s.Ctx, created.ID, container.ExecAttachOptions{
+		Tty:         true,
+		ConsoleSize: &[2]uint{rows, cols},
+	})
+	if err != nil {
+		return "", types.HijackedResponse{}, err
+	}
+	return created.ID, attached, nil
+}
+
+// ResizeExec changes the size of the TTY of a process started with ExecTTY.
+func (s *DockerService) ResizeExec(execID string, rows, cols uint) error {
+	return s.Cli.ContainerExecResize(s.Ctx, execID, container.ResizeOptions{Height: rows, Width: cols})
+}
+
+// InspectExec returns the state of a process started in a container, including its exit code
+// once it finished.
+func (s *DockerService) InspectExec(execID string) (container.ExecInspect, error) {
+	return s.Cli.ContainerExecInspect(s.Ctx, execID)
+}
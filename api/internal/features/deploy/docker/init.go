@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -41,7 +42,16 @@ type DockerRepository interface {
 	CreateContainer(config container.Config, hostConfig container.HostConfig, networkConfig network.NetworkingConfig, containerName string) (container.CreateResponse, error)
 	// CreateDeployment(deployment *deploy_types.CreateDeploymentRequest, userID uuid.UUID, contextPath string) error
 	ContainerLogs(ctx context.Context, containerID string, opts container.LogsOptions) (io.ReadCloser, error)
+	WaitContainer(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
 	RestartContainer(containerID string, opts container.StopOptions) error
+	CopyToContainer(containerID string, dstPath string, content io.Reader) error
+	CopyFromContainer(containerID string, srcPath string) (io.ReadCloser, error)
+	PullImage(imageName string) error
+	GetContainerStats(containerID string) (container.StatsResponse, error)
+	SampleContainerStats(containerID string) (container.StatsResponse, error)
+	ExecTTY(containerID string, cmd []string, env []string, rows, cols uint) (string, types.HijackedResponse, error)
+	ResizeExec(execID string, rows, cols uint) error
+	InspectExec(execID string) (container.ExecInspect, error)
 
 	ComposeUp(composeFilePath string, envVars map[string]string) error
 	ComposeDown(composeFilePath string) error
@@ -71,6 +81,9 @@ type DockerRepository interface {
 	DeleteService(serviceID string) error
 	RollbackService(serviceID string) error
 	GetServiceByID(serviceID string) (swarm.Service, error)
+	GetServiceLogs(serviceID string, opts container.LogsOptions) (io.ReadCloser, error)
+	CreateNetwork(name string, opts network.CreateOptions) (string, error)
+	RemoveVolume(volumeName string, force bool) error
 }
 
 type DockerClient struct {
@@ -324,6 +337,72 @@ func (s *DockerService) ContainerLogs(Ctx context.Context, containerID string, o
 	return s.Cli.ContainerLogs(Ctx, containerID, opts)
 }
 
+// WaitContainer blocks until the container with the given ID reaches the given condition.
+//
+// The returned channels follow the docker client semantics: exactly one of them
+// receives a value, the wait response carrying the container's exit code or an
+// error if the wait itself failed.
+func (s *DockerService) WaitContainer(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	return s.Cli.ContainerWait(ctx, containerID, condition)
+}
+
+// CopyToContainer extracts the given tar archive into dstPath inside the container.
+// The destination directory must already exist in the container.
+func (s *DockerService) CopyToContainer(containerID string, dstPath string, content io.Reader) error {
+	return s.Cli.CopyToContainer(s.Ctx, containerID, dstPath, content, container.CopyToContainerOptions{})
+}
+
+// CopyFromContainer returns a tar archive of srcPath inside the container.
+// The caller is responsible for closing the returned reader.
+func (s *DockerService) CopyFromContainer(containerID string, srcPath string) (io.ReadCloser, error) {
+	reader, _, err := s.Cli.CopyFromContainer(s.Ctx, containerID, srcPath)
+	return reader, err
+}
+
+// PullImage pulls the given image from its registry and waits for the pull to complete.
+func (s *DockerService) PullImage(imageName string) error {
+	reader, err := s.Cli.ImagePull(s.Ctx, imageName, image.PullOptions{})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(io.Discard, reader)
+	return err
+}
+
+// GetContainerStats returns a single resource usage sample of the container.
+func (s *DockerService) GetContainerStats(containerID string) (container.StatsResponse, error) {
+	resp, err := s.Cli.ContainerStatsOneShot(s.Ctx, containerID)
+	if err != nil {
+		return container.StatsResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return container.StatsResponse{}, err
+	}
+	return stats, nil
+}
+
+// SampleContainerStats returns a resource usage sample of the container along with the previous
+// CPU reading, so the CPU usage between both can be computed. It takes about a second, as the
+// daemon waits for a second reading.
+func (s *DockerService) SampleContainerStats(containerID string) (container.StatsResponse, error) {
+	resp, err := s.Cli.ContainerStats(s.Ctx, containerID, false)
+	if err != nil {
+		return container.StatsResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return container.StatsResponse{}, err
+	}
+	return stats, nil
+}
+
 // ComposeUp starts the Docker Compose services defined in the specified compose file
 func (s *DockerService) ComposeUp(composeFilePath string, envVars map[string]string) error {
 	client := ssh.NewSSH()
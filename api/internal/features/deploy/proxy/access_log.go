@@ -0,0 +1,183 @@
+package proxy
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const (
+	loggingPath    = "/config/logging"
+	serverLogsPath = "/config/apps/http/servers/nixopus/logs"
+	// AccessLogger is the logger of Caddy the access logs of application hosts are written to.
+	AccessLogger = "nixopus_access"
+)
+
+type accessLogConfig struct {
+	Writer  accessLogWriter  `json:"writer"`
+	Encoder accessLogEncoder `json:"encoder"`
+	Include []string         `json:"include"`
+}
+
+type accessLogWriter struct {
+	Output    string `json:"output"`
+	Address   string `json:"address"`
+	SoftStart bool   `json:"soft_start"`
+}
+
+type accessLogEncoder struct {
+	Format         string `json:"format"`
+	TimeFormat     string `json:"time_format"`
+	DurationFormat string `json:"duration_format"`
+}
+
+// SyncAccessLogs sends the access logs of hosts as JSON lines over TCP to address, where the API
+// ingests them. The logger is removed when address is empty. Hosts mapped to other loggers and
+// other logs of Caddy are kept, and nothing is written when the configuration is unchanged, as
+// every change reloads Caddy.
+func (c *Caddy) SyncAccessLogs(address string, hosts []string) error {
+	if address == "" {
+		hosts = nil
+	}
+	if err := c.syncAccessLogger(address); err != nil {
+		return err
+	}
+	return c.syncAccessLogHosts(hosts)
+}
+
+// syncAccessLogger adds the logger writing to address and keeps its entries out of the default
+// log, which would print every request to the output of Caddy.
+func (c *Caddy) syncAccessLogger(address string) error {
+	var logging map[string]json.RawMessage
+	exists, err := c.getConfigValue(loggingPath, &logging)
+	if err != nil {
+		return err
+	}
+	if logging == nil {
+		logging = make(map[string]json.RawMessage)
+	}
+	logs := make(map[string]json.RawMessage)
+	if err := decodeIfSet(logging["logs"], &logs); err != nil {
+		return err
+	}
+	previous, err := json.Marshal(logs)
+	if err != nil {
+		return err
+	}
+
+	if address == "" {
+		delete(logs, AccessLogger)
+	} else {
+		logger := accessLogConfig{
+			Writer:  accessLogWriter{Output: "net", Address: "tcp/" + address, SoftStart: true},
+			Encoder: accessLogEncoder{Format: "json", TimeFormat: "unix_seconds_float", DurationFormat: "seconds"},
+			Include: []string{"http.log.access." + AccessLogger},
+		}
+		if err := encodeInto(logs, AccessLogger, logger); err != nil {
+			return err
+		}
+
+		defaultLog := make(map[string]json.RawMessage)
+		if err := decodeIfSet(logs["default"], &defaultLog); err != nil {
+			return err
+		}
+		var exclude []string
+		if err := decodeIfSet(defaultLog["exclude"], &exclude); err != nil {
+			return err
+		}
+		if !containsFold(exclude, logger.Include[0]) {
+			if err := encodeInto(defaultLog, "exclude", append(exclude, logger.Include[0])); err != nil {
+				return err
+			}
+			if err := encodeInto(logs, "default", defaultLog); err != nil {
+				return err
+			}
+		}
+	}
+
+	if same, err := sameJSON(previous, logs); err != nil || same {
+		return err
+	}
+	if err := encodeInto(logging, "logs", logs); err != nil {
+		return err
+	}
+	return c.setConfigValue(loggingPath, exists, logging)
+}
+
+// syncAccessLogHosts maps exactly hosts to the access logger. Requests for hosts that are not
+// mapped are not logged when the server had no logs configured before.
+func (c *Caddy) syncAccessLogHosts(hosts []string) error {
+	var serverLogs map[string]json.RawMessage
+	exists, err := c.getConfigValue(serverLogsPath, &serverLogs)
+	if err != nil {
+		return err
+	}
+	if !exists && len(hosts) == 0 {
+		return nil
+	}
+	if serverLogs == nil {
+		serverLogs = map[string]json.RawMessage{"skip_unmapped_hosts": json.RawMessage("true")}
+	}
+	names := make(map[string]json.RawMessage)
+	if err := decodeIfSet(serverLogs["logger_names"], &names); err != nil {
+		return err
+	}
+	previous, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	for host, raw := range names {
+		var loggers []string
+		if err := json.Unmarshal(raw, &loggers); err != nil {
+			// a single logger name, as written by Caddy before 2.8, belongs to someone else
+			continue
+		}
+		kept := loggers[:0]
+		for _, logger := range loggers {
+			if logger != AccessLogger {
+				kept = append(kept, logger)
+			}
+		}
+		if len(kept) == 0 {
+			delete(names, host)
+		} else if err := encodeInto(names, host, kept); err != nil {
+			return err
+		}
+	}
+	for _, host := range hosts {
+		host = strings.ToLower(host)
+		var loggers []string
+		if err := decodeIfSet(names[host], &loggers); err != nil || containsFold(loggers, AccessLogger) {
+			continue
+		}
+		if err := encodeInto(names, host, append(loggers, AccessLogger)); err != nil {
+			return err
+		}
+	}
+
+	if same, err := sameJSON(previous, names); err != nil || same {
+		return err
+	}
+	if err := encodeInto(serverLogs, "logger_names", names); err != nil {
+		return err
+	}
+	return c.setConfigValue(serverLogsPath, exists, serverLogs)
+}
+
+// sameJSON reports whether the JSON document previous and the value v encode the same data,
+// regardless of the order of object keys.
+func sameJSON(previous []byte, v interface{}) (bool, error) {
+	current, err := json.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+	var a, b interface{}
+	if err := json.Unmarshal(previous, &a); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(current, &b); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(a, b), nil
+}
@@ -35,13 +35,34 @@ func (c *Caddy) Serve() error {
 		return fmt.Errorf("caddy is not running: %w", err)
 	}
 
+	routeConfig := c.route()
+
+	// Get current config
+	config, err := c.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get current config: %w", err)
+	}
+
+	// Replace the route for our domain and its aliases
+	server := config.Apps.HTTP.Servers["nixopus"]
+	server.Routes = append(c.otherRoutes(server.Routes), routeConfig)
+	sortRoutes(server.Routes)
+	config.Apps.HTTP.Servers["nixopus"] = server
+
+	// Update the configuration
+	if err := c.UpdateConfig(config); err != nil {
+		return fmt.Errorf("failed to update caddy configuration: %w", err)
+	}
+
+	c.Logger.Log(logger.Info, "Caddy server configuration updated successfully", "")
+	return nil
+}
+
+// route builds the route serving c.Domain with the middleware of the application around it.
+func (c *Caddy) route() Route {
 	var handle interface{}
 	if c.FileServerType == FileServer {
-		handle = FileServerHandle{
-			Handler: string(c.FileServerType),
-			Root:    c.RootDir,
-			Browse:  struct{}{},
-		}
+		handle = c.staticSiteHandle()
 	} else {
 		subroute := SubrouteHandle{
 			Handler: "subroute",
@@ -52,7 +73,7 @@ func (c *Caddy) Serve() error {
 							Handler: string(c.FileServerType),
 							Upstreams: []Upstream{
 								{
-									Dial: config.AppConfig.SSH.Host + ":" + c.Port,
+									Dial: c.upstream(),
 								},
 							},
 						},
@@ -63,41 +84,41 @@ func (c *Caddy) Serve() error {
 		handle = subroute
 	}
 
-	routeConfig := Route{
-		Match: []Match{
-			{
-				Host: []string{c.Domain},
-			},
-		},
-		Handle: []interface{}{handle},
+	if s := c.Middleware.Settings; s.StripPathPrefix && s.PathPrefix != "" {
+		handle = stripPrefix(handle, s.PathPrefix)
 	}
 
-	// Get current config
-	config, err := c.GetConfig()
-	if err != nil {
-		return fmt.Errorf("failed to get current config: %w", err)
+	return Route{
+		ID:     c.routeID(),
+		Match:  c.routeMatch(),
+		Handle: c.Middleware.Wrap(c.Domain, handle),
 	}
+}
 
-	// Replace the route for our domain
-	server := config.Apps.HTTP.Servers["nixopus"]
-	var newRoutes []Route
-	for _, route := range server.Routes {
-		if len(route.Match) > 0 && len(route.Match[0].Host) > 0 && route.Match[0].Host[0] != c.Domain {
-			newRoutes = append(newRoutes, route)
-		}
+// routeMatch matches the hosts of c.Domain below the path prefix of the application.
+func (c *Caddy) routeMatch() []Match {
+	return []Match{
+		{
+			Host: c.Middleware.Hosts(c.Domain),
+			Path: pathMatch(c.Middleware.Settings.PathPrefix),
+		},
 	}
+}
 
-	newRoutes = append(newRoutes, routeConfig)
-	server.Routes = newRoutes
-	config.Apps.HTTP.Servers["nixopus"] = server
-
-	// Update the configuration
-	if err := c.UpdateConfig(config); err != nil {
-		return fmt.Errorf("failed to update caddy configuration: %w", err)
+// routeID returns the id of the route of c.Owner, none for routes without an owner.
+func (c *Caddy) routeID() string {
+	if c.Owner == "" {
+		return ""
 	}
+	return routeID(c.Owner, c.Middleware.Settings.RoutePriority)
+}
 
-	c.Logger.Log(logger.Info, "Caddy server configuration updated successfully", "")
-	return nil
+// upstream returns the address requests are proxied to, Upstream or the port on the deployment server.
+func (c *Caddy) upstream() string {
+	if c.Upstream != "" {
+		return c.Upstream
+	}
+	return config.AppConfig.SSH.Host + ":" + c.Port
 }
 
 func (c *Caddy) checkCaddyRunning() error {
@@ -153,6 +174,11 @@ func (c *Caddy) GetConfig() (CaddyConfig, error) {
 }
 
 func (c *Caddy) UpdateConfig(config CaddyConfig) error {
+	return c.loadConfig(config)
+}
+
+// loadConfig replaces the whole config of Caddy with config.
+func (c *Caddy) loadConfig(config interface{}) error {
 	jsonData, err := json.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"encoding/json"
+)
+
+const (
+	layer4AppPath = "/config/apps/layer4"
+	// layer4Server is the server of the layer4 app owned by Nixopus, other servers are kept as is.
+	layer4Server = "nixopus_tls"
+)
+
+// PassthroughRoute forwards the TLS connections for ServerName to Upstream without terminating
+// them, the application behind the upstream holds the certificate.
+type PassthroughRoute struct {
+	ServerName string
+	Upstream   string
+}
+
+type layer4ServerConfig struct {
+	Listen []string      `json:"listen"`
+	Routes []layer4Route `json:"routes"`
+}
+
+type layer4Route struct {
+	Match  []layer4Match  `json:"match"`
+	Handle []layer4Handle `json:"handle"`
+}
+
+type layer4Match struct {
+	TLS struct {
+		SNI []string `json:"sni"`
+	} `json:"tls"`
+}
+
+type layer4Handle struct {
+	Handler   string           `json:"handler"`
+	Upstreams []layer4Upstream `json:"upstreams"`
+}
+
+type layer4Upstream struct {
+	Dial []string `json:"dial"`
+}
+
+// SyncTLSPassthrough replaces the routes of the layer4 server of Nixopus, listening on listen,
+// with routes. The server is removed when there are no routes left. Caddy only accepts the
+// layer4 app when it was built with the layer4 module.
+func (c *Caddy) SyncTLSPassthrough(listen string, routes []PassthroughRoute) error {
+	var app map[string]json.RawMessage
+	exists, err := c.getConfigValue(layer4AppPath, &app)
+	if err != nil {
+		return err
+	}
+	if app == nil {
+		app = make(map[string]json.RawMessage)
+	}
+	servers := make(map[string]json.RawMessage)
+	if err := decodeIfSet(app["servers"], &servers); err != nil {
+		return err
+	}
+
+	if len(routes) == 0 {
+		delete(servers, layer4Server)
+	} else {
+		server := layer4ServerConfig{Listen: []string{listen}}
+		for _, route := range routes {
+			var match layer4Match
+			match.TLS.SNI = []string{route.ServerName}
+			server.Routes = append(server.Routes, layer4Route{
+				Match: []layer4Match{match},
+				Handle: []layer4Handle{{
+					Handler:   "proxy",
+					Upstreams: []layer4Upstream{{Dial: []string{route.Upstream}}},
+				}},
+			})
+		}
+		if err := encodeInto(servers, layer4Server, server); err != nil {
+			return err
+		}
+	}
+
+	if !exists && len(servers) == 0 {
+		return nil
+	}
+	if err := encodeInto(app, "servers", servers); err != nil {
+		return err
+	}
+	return c.setConfigValue(layer4AppPath, exists, app)
+}
@@ -0,0 +1,277 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+const serverRoutesPath = "/config/apps/http/servers/nixopus/routes"
+
+// routeUpdateAttempts bounds how often an update of the route list is retried when the list
+// changed between reading and writing it.
+const routeUpdateAttempts = 5
+
+// caddyRoutesMu serializes the updates of the route list of the nixopus server made by the
+// API. Updates made by others, such as another API instance, are caught with the ETag of the
+// route list and retried.
+var caddyRoutesMu sync.Mutex
+
+// errRoutesChanged is returned by putRoutes when the route list changed since it was read.
+var errRoutesChanged = errors.New("caddy routes changed while they were being updated")
+
+const stoppedPage = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%[1]s is stopped</title></head>
+<body style="font-family: sans-serif; text-align: center; padding-top: 10vh">
+<h1>%[1]s is stopped</h1>
+<p>This application has been stopped by its owner. Please try again later.</p>
+</body>
+</html>`
+
+// StoppedHandle answers every request with a 503 page telling visitors the application is stopped.
+func StoppedHandle(applicationName string) StaticResponseHandle {
+	return StaticResponseHandle{
+		Handler:    "static_response",
+		StatusCode: http.StatusServiceUnavailable,
+		Headers: map[string][]string{
+			"Content-Type":  {"text/html; charset=utf-8"},
+			"Cache-Control": {"no-store"},
+			"Retry-After":   {"300"},
+		},
+		Body: fmt.Sprintf(stoppedPage, html.EscapeString(applicationName)),
+	}
+}
+
+// WakeHandles forward requests for a sleeping application to wakePath on the Nixopus API.
+// The original URI is appended to wakePath and the token is set on the request, so the API
+// can tell the request came through the proxy before starting the application.
+func WakeHandles(apiUpstream string, wakePath string, tokenHeader string, token string) []interface{} {
+	return []interface{}{
+		RewriteHandle{
+			Handler: "rewrite",
+			URI:     wakePath + "{http.request.uri}",
+		},
+		ReverseProxyHandle{
+			Handler:   string(ReverseProxy),
+			Upstreams: []Upstream{{Dial: apiUpstream}},
+			Headers: &ReverseProxyHeaders{
+				Request: &HeaderOps{
+					Set: map[string][]string{tokenHeader: {token}},
+				},
+			},
+		},
+	}
+}
+
+// SetRoute replaces the route of c.Domain, matching its aliases below the path prefix of the
+// application, with a single terminal route running handles.
+//
+// Only the route list of the nixopus server is rewritten, so TLS automation and the routes
+// of other applications, including the ones sharing the domain below other paths, are left
+// untouched.
+func (c *Caddy) SetRoute(handles ...interface{}) error {
+	return c.replaceRoute(c.handleRoute(handles...))
+}
+
+// handleRoute returns the terminal route of c.Domain running handles.
+func (c *Caddy) handleRoute(handles ...interface{}) Route {
+	return Route{
+		ID:       c.routeID(),
+		Match:    c.routeMatch(),
+		Handle:   handles,
+		Terminal: true,
+	}
+}
+
+// ApplyMiddleware replaces the route of c.Domain with a reverse proxy to c.Port wrapped in
+// c.Middleware. It renders the proxy settings of an application whose domain was added with
+// the caddygo client, which only knows about a fixed set of options.
+func (c *Caddy) ApplyMiddleware() error {
+	return c.replaceRoute(c.proxyRoute())
+}
+
+// proxyRoute returns the terminal route of c.Domain proxying to its upstream.
+func (c *Caddy) proxyRoute() Route {
+	route := c.route()
+	route.Terminal = true
+	return route
+}
+
+func (c *Caddy) replaceRoute(route Route) error {
+	caddyRoutesMu.Lock()
+	defer caddyRoutesMu.Unlock()
+
+	err := c.updateRoutes(func(routes []Route) ([]Route, bool) {
+		newRoutes := append(c.otherRoutes(routes), route)
+		sortRoutes(newRoutes)
+		return newRoutes, true
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Log(logger.Info, "Caddy route updated", c.Domain)
+	return nil
+}
+
+// DeleteRoute removes the route of c.Domain and returns the hosts it matched that no other
+// route serves anymore, whose TLS configuration can be removed as well.
+func (c *Caddy) DeleteRoute() ([]string, error) {
+	caddyRoutesMu.Lock()
+	defer caddyRoutesMu.Unlock()
+
+	var unused []string
+	removed := false
+	err := c.updateRoutes(func(routes []Route) ([]Route, bool) {
+		hosts := c.Middleware.Hosts(c.Domain)
+		kept := c.otherRoutes(routes)
+		for _, route := range routes {
+			if c.ownsRoute(route, hosts) {
+				for _, match := range route.Match {
+					for _, host := range match.Host {
+						hosts = appendHost(hosts, host)
+					}
+				}
+			}
+		}
+
+		served := routeHosts(kept)
+		unused = nil
+		for _, host := range hosts {
+			if !containsFold(served, host) {
+				unused = append(unused, host)
+			}
+		}
+
+		removed = len(kept) < len(routes)
+		return kept, removed
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if removed {
+		c.Logger.Log(logger.Info, "Caddy route removed", c.Domain)
+	}
+	return unused, nil
+}
+
+// updateRoutes reads the route list of the nixopus server and writes the list update returns
+// for it, unless update reports no change. The list is read and updated again when it changed
+// in between. Callers hold caddyRoutesMu.
+func (c *Caddy) updateRoutes(update func(routes []Route) ([]Route, bool)) error {
+	for attempt := 1; ; attempt++ {
+		routes, etag, err := c.getRoutes()
+		if err != nil {
+			return err
+		}
+
+		newRoutes, changed := update(routes)
+		if !changed {
+			return nil
+		}
+
+		method := http.MethodPatch
+		if routes == nil {
+			method = http.MethodPut
+		}
+		err = c.putRoutes(method, etag, newRoutes)
+		if !errors.Is(err, errRoutesChanged) || attempt == routeUpdateAttempts {
+			return err
+		}
+	}
+}
+
+// otherRoutes returns the routes that do not belong to c, the routes of other applications.
+func (c *Caddy) otherRoutes(routes []Route) []Route {
+	hosts := c.Middleware.Hosts(c.Domain)
+	var other []Route
+	for _, route := range routes {
+		if !c.ownsRoute(route, hosts) {
+			other = append(other, route)
+		}
+	}
+	return other
+}
+
+// ownsRoute reports whether route is the route of c: the route of its owner or, for routes
+// without one such as those of the caddygo client, a route matching one of hosts below the same
+// path prefix.
+func (c *Caddy) ownsRoute(route Route, hosts []string) bool {
+	if owner, _, ok := parseRouteID(route.ID); ok {
+		return owner == c.Owner
+	}
+	return routeMatchesHost(route, hosts) && routePathPrefix(route) == c.Middleware.Settings.PathPrefix
+}
+
+// routeHosts returns the hosts matched by routes.
+func routeHosts(routes []Route) []string {
+	var hosts []string
+	for _, route := range routes {
+		for _, match := range route.Match {
+			hosts = append(hosts, match.Host...)
+		}
+	}
+	return hosts
+}
+
+// getRoutes returns the route list of the nixopus server along with its ETag.
+func (c *Caddy) getRoutes() ([]Route, string, error) {
+	resp, err := c.client.Get(c.Endpoint + serverRoutesPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get routes: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("failed to get Caddy routes: %s - %s", resp.Status, string(body))
+	}
+
+	var routes []Route
+	if err := json.NewDecoder(resp.Body).Decode(&routes); err != nil {
+		return nil, "", fmt.Errorf("failed to decode routes: %w", err)
+	}
+	return routes, resp.Header.Get("Etag"), nil
+}
+
+// putRoutes writes the route list of the nixopus server. With an etag, the list is only written
+// if it did not change since the etag was read, errRoutesChanged is returned otherwise.
+func (c *Caddy) putRoutes(method string, etag string, routes []Route) error {
+	jsonData, err := json.Marshal(routes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal routes: %w", err)
+	}
+
+	req, err := http.NewRequest(method, c.Endpoint+serverRoutesPath, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return errRoutesChanged
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update Caddy routes: %s - %s", resp.Status, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentRouteUpdates(t *testing.T) {
+	log := logger.NewLogger()
+	p := &CaddyProvider{Logger: &log}
+	admin := newFakeCaddyAdmin(t, nil)
+
+	var owners []string
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		owner := fmt.Sprintf("app-%d", i)
+		owners = append(owners, owner)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			site := Site{Owner: owner, Domain: fmt.Sprintf("app-%d.example.com", i), Upstream: "10.0.0.1:3000"}
+			assert.NoError(t, p.UpdateRoute(site))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, owners, admin.owners())
+
+	var removed sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		removed.Add(1)
+		go func(i int) {
+			defer removed.Done()
+			c := p.caddy(Site{Owner: fmt.Sprintf("app-%d", i), Domain: fmt.Sprintf("app-%d.example.com", i)}, "", ReverseProxy)
+			_, err := c.DeleteRoute()
+			assert.NoError(t, err)
+		}(i)
+	}
+	removed.Wait()
+
+	assert.ElementsMatch(t, owners[10:], admin.owners())
+}
+
+func TestRouteUpdateRetriesChangedRoutes(t *testing.T) {
+	log := logger.NewLogger()
+	p := &CaddyProvider{Logger: &log}
+
+	t.Run("Routes written in between are kept", func(t *testing.T) {
+		admin := newFakeCaddyAdmin(t, nil)
+		// another writer adds its route right after the routes are read the first time
+		admin.afterGet = func(a *fakeCaddyAdmin) {
+			a.afterGet = nil
+			a.routes = append(a.routes, Route{ID: routeID("other", 0), Match: []Match{{Host: []string{"other.example.com"}}}})
+			a.version++
+		}
+
+		require.NoError(t, p.UpdateRoute(Site{Owner: "shop", Domain: "shop.example.com", Upstream: "10.0.0.1:3000"}))
+		assert.ElementsMatch(t, []string{"shop", "other"}, admin.owners())
+	})
+
+	t.Run("Gives up when the routes keep changing", func(t *testing.T) {
+		admin := newFakeCaddyAdmin(t, nil)
+		gets := 0
+		admin.afterGet = func(a *fakeCaddyAdmin) {
+			gets++
+			a.version++
+		}
+
+		err := p.UpdateRoute(Site{Owner: "shop", Domain: "shop.example.com", Upstream: "10.0.0.1:3000"})
+		assert.ErrorIs(t, err, errRoutesChanged)
+		assert.Equal(t, routeUpdateAttempts, gets)
+		assert.NotContains(t, admin.writes, serverRoutesPath)
+	})
+
+	t.Run("Reconcile retries", func(t *testing.T) {
+		admin := newFakeCaddyAdmin(t, []Route{})
+		admin.afterGet = func(a *fakeCaddyAdmin) {
+			a.afterGet = nil
+			a.version++
+		}
+
+		drift, err := p.Reconcile([]DesiredSite{{Site: Site{Owner: "shop", Domain: "shop.example.com", Upstream: "10.0.0.1:3000"}}}, nil, true)
+		require.NoError(t, err)
+		assert.Equal(t, []RouteDrift{{Owner: "shop", Domain: "shop.example.com", Kind: DriftMissing}}, drift)
+		assert.Equal(t, http.MethodPatch, admin.writes[serverRoutesPath])
+		assert.Equal(t, []string{"shop"}, admin.owners())
+	})
+}
@@ -0,0 +1,67 @@
+package proxy
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+)
+
+// MaintenanceBypassHeader carries the bypass token of an application in maintenance.
+const MaintenanceBypassHeader = "X-Nixopus-Maintenance-Bypass"
+
+const maintenancePage = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%[1]s is under maintenance</title></head>
+<body style="font-family: sans-serif; text-align: center; padding-top: 10vh">
+<h1>%[1]s is under maintenance</h1>
+<p>We are making some changes and will be back shortly. Please try again in a few minutes.</p>
+</body>
+</html>`
+
+// maintenanceMatch matches the requests that neither come from a bypass address nor carry the
+// bypass token, every request when there is no way to bypass maintenance.
+func (m Middleware) maintenanceMatch() []Match {
+	var bypass []Match
+	if len(m.Maintenance.BypassIPs) > 0 {
+		bypass = append(bypass, Match{RemoteIP: &RemoteIPMatch{Ranges: m.Maintenance.BypassIPs}})
+	}
+	if m.Maintenance.BypassToken != "" {
+		bypass = append(bypass, Match{Header: map[string][]string{MaintenanceBypassHeader: {m.Maintenance.BypassToken}}})
+	}
+	if len(bypass) == 0 {
+		return nil
+	}
+	return []Match{{Not: bypass}}
+}
+
+// maintenanceHandle redirects to the maintenance URL or answers with the maintenance page.
+func (m Middleware) maintenanceHandle(domain string) StaticResponseHandle {
+	if m.Maintenance.RedirectURL != "" {
+		return StaticResponseHandle{
+			Handler:    "static_response",
+			StatusCode: http.StatusFound,
+			Headers: map[string][]string{
+				"Location":      {m.Maintenance.RedirectURL},
+				"Cache-Control": {"no-store"},
+			},
+		}
+	}
+	return StaticResponseHandle{
+		Handler:    "static_response",
+		StatusCode: http.StatusServiceUnavailable,
+		Headers: map[string][]string{
+			"Content-Type":  {"text/html; charset=utf-8"},
+			"Cache-Control": {"no-store"},
+			"Retry-After":   {"300"},
+		},
+		Body: m.maintenanceBody(domain),
+	}
+}
+
+// maintenanceBody returns the custom maintenance page, or the default one naming domain.
+func (m Middleware) maintenanceBody(domain string) string {
+	if m.Maintenance.Page != "" {
+		return m.Maintenance.Page
+	}
+	return fmt.Sprintf(maintenancePage, html.EscapeString(domain))
+}
@@ -0,0 +1,90 @@
+package proxy
+
+import (
+	"testing"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		maintenance shared_types.MaintenanceSettings
+		want        []Match
+	}{
+		{
+			name:        "No bypass",
+			maintenance: shared_types.MaintenanceSettings{Enabled: true},
+			want:        nil,
+		},
+		{
+			name:        "Bypass addresses",
+			maintenance: shared_types.MaintenanceSettings{Enabled: true, BypassIPs: []string{"203.0.113.7", "10.0.0.0/8"}},
+			want:        []Match{{Not: []Match{{RemoteIP: &RemoteIPMatch{Ranges: []string{"203.0.113.7", "10.0.0.0/8"}}}}}},
+		},
+		{
+			name:        "Bypass addresses and token",
+			maintenance: shared_types.MaintenanceSettings{Enabled: true, BypassIPs: []string{"203.0.113.7"}, BypassToken: "let-me-in-please"},
+			want: []Match{{Not: []Match{
+				{RemoteIP: &RemoteIPMatch{Ranges: []string{"203.0.113.7"}}},
+				{Header: map[string][]string{MaintenanceBypassHeader: {"let-me-in-please"}}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Middleware{Maintenance: tt.maintenance}.maintenanceMatch())
+		})
+	}
+}
+
+func TestMaintenanceHandle(t *testing.T) {
+	t.Run("Default page names the domain", func(t *testing.T) {
+		handle := Middleware{}.maintenanceHandle("<b>example.com</b>")
+		assert.Equal(t, 503, handle.StatusCode)
+		assert.Equal(t, []string{"300"}, handle.Headers["Retry-After"])
+		assert.Contains(t, handle.Body, "&lt;b&gt;example.com&lt;/b&gt; is under maintenance")
+	})
+
+	t.Run("Custom page", func(t *testing.T) {
+		m := Middleware{Maintenance: shared_types.MaintenanceSettings{Page: "<h1>Back soon</h1>"}}
+		assert.Equal(t, "<h1>Back soon</h1>", m.maintenanceHandle("example.com").Body)
+	})
+
+	t.Run("Redirect", func(t *testing.T) {
+		m := Middleware{Maintenance: shared_types.MaintenanceSettings{RedirectURL: "https://status.example.com"}}
+		handle := m.maintenanceHandle("example.com")
+		assert.Equal(t, 302, handle.StatusCode)
+		assert.Equal(t, []string{"https://status.example.com"}, handle.Headers["Location"])
+		assert.Empty(t, handle.Body)
+	})
+}
+
+func TestMaintenanceWrap(t *testing.T) {
+	upstream := ReverseProxyHandle{Handler: "reverse_proxy"}
+	m := Middleware{
+		Settings:    shared_types.ProxySettings{WWWRedirect: true, SecurityHeaders: true, Compression: true},
+		Maintenance: shared_types.MaintenanceSettings{Enabled: true, BypassToken: "let-me-in-please"},
+	}
+
+	handles := m.Wrap("example.com", upstream)
+	require.Len(t, handles, 1)
+	routes := handles[0].(SubrouteHandle).Routes
+	require.Len(t, routes, 3)
+
+	// redirects are answered before maintenance
+	assert.Equal(t, []Match{{Host: []string{"www.example.com"}}}, routes[0].Match)
+
+	maintenance := routes[1]
+	assert.Equal(t, m.maintenanceMatch(), maintenance.Match)
+	assert.True(t, maintenance.Terminal)
+	require.Len(t, maintenance.Handle, 2)
+	assert.IsType(t, HeadersHandle{}, maintenance.Handle[0])
+	assert.Equal(t, m.maintenanceHandle("example.com"), maintenance.Handle[1])
+
+	// requests bypassing maintenance reach the application
+	assert.Equal(t, upstream, routes[2].Handle[len(routes[2].Handle)-1])
+}
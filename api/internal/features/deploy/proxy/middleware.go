@@ -0,0 +1,265 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// defaultHSTSMaxAge is the max-age of the Strict-Transport-Security header, one year.
+const defaultHSTSMaxAge = 31536000
+
+// Middleware renders the proxy settings and the domain aliases of an application into the
+// route of its domain.
+type Middleware struct {
+	Settings shared_types.ProxySettings
+	// PasswordHash is the bcrypt hash checked when Settings.BasicAuthUsername is set
+	PasswordHash string
+	Aliases      []Alias
+	Maintenance  shared_types.MaintenanceSettings
+}
+
+// Alias is another host of the route, served like the domain or redirected to it.
+type Alias struct {
+	Host     string
+	Redirect bool
+}
+
+// ApplicationMiddleware returns the middleware configured for an application.
+func ApplicationMiddleware(application shared_types.Application) Middleware {
+	m := Middleware{
+		Settings:     application.ProxySettings,
+		PasswordHash: application.BasicAuthPasswordHash,
+		Maintenance:  application.Maintenance,
+	}
+	for _, domain := range application.Domains {
+		if domain.IsPrimary || strings.EqualFold(domain.Name, application.Domain) {
+			continue
+		}
+		m.Aliases = append(m.Aliases, Alias{
+			Host:     domain.Name,
+			Redirect: domain.Mode == shared_types.DomainModeRedirect,
+		})
+	}
+	return m
+}
+
+// IsZero reports whether the route is a plain route of the domain, one that leaves requests
+// untouched and matches no other host.
+func (m Middleware) IsZero() bool {
+	s := m.Settings
+	return len(s.ResponseHeaders) == 0 && !s.SecurityHeaders && !s.HSTS && !s.HTTPSRedirect &&
+		!s.WWWRedirect && !m.basicAuth() && !s.Compression && s.MaxBodySize == 0 &&
+		s.ConnectTimeout == 0 && s.ResponseTimeout == 0 && len(m.Aliases) == 0 && !m.Maintenance.Enabled
+}
+
+func (m Middleware) basicAuth() bool {
+	return m.Settings.BasicAuthUsername != "" && m.PasswordHash != ""
+}
+
+// Hosts returns the hosts the route of domain matches: the domain, its aliases and, with the
+// www redirect enabled, the variant that is redirected to it.
+func (m Middleware) Hosts(domain string) []string {
+	hosts := []string{domain}
+	for _, alias := range m.Aliases {
+		hosts = appendHost(hosts, alias.Host)
+	}
+	if m.Settings.WWWRedirect {
+		hosts = appendHost(hosts, wwwAlias(domain))
+	}
+	return hosts
+}
+
+// redirectHosts returns the hosts that are redirected to domain.
+func (m Middleware) redirectHosts(domain string) []string {
+	var hosts []string
+	for _, alias := range m.Aliases {
+		if alias.Redirect {
+			hosts = appendHost(hosts, alias.Host)
+		}
+	}
+	if m.Settings.WWWRedirect {
+		www := wwwAlias(domain)
+		if !m.servesAlias(www) {
+			hosts = appendHost(hosts, www)
+		}
+	}
+	return hosts
+}
+
+func (m Middleware) servesAlias(host string) bool {
+	for _, alias := range m.Aliases {
+		if !alias.Redirect && strings.EqualFold(alias.Host, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendHost(hosts []string, host string) []string {
+	for _, existing := range hosts {
+		if strings.EqualFold(existing, host) {
+			return hosts
+		}
+	}
+	return append(hosts, host)
+}
+
+// wwwAlias returns the bare domain for www domains and the www domain otherwise.
+func wwwAlias(domain string) string {
+	if bare, ok := strings.CutPrefix(domain, "www."); ok {
+		return bare
+	}
+	return "www." + domain
+}
+
+// Wrap returns the handles of the route of domain. Redirects are answered first, then requests
+// not bypassing maintenance get the maintenance response. Other requests pass the header,
+// encoding, authentication and body limit handlers before reaching handle, so that headers are
+// also set on the responses of the handlers rejecting a request.
+func (m Middleware) Wrap(domain string, handle interface{}) []interface{} {
+	if m.IsZero() {
+		return []interface{}{handle}
+	}
+	s := m.Settings
+
+	var routes []Route
+	if hosts := m.redirectHosts(domain); len(hosts) > 0 {
+		routes = append(routes, Route{
+			Match:    []Match{{Host: hosts}},
+			Handle:   []interface{}{redirectHandle("https://" + domain + "{http.request.uri}")},
+			Terminal: true,
+		})
+	}
+	if s.HTTPSRedirect {
+		routes = append(routes, Route{
+			Match:    []Match{{Protocol: "http"}},
+			Handle:   []interface{}{redirectHandle("https://{http.request.host}{http.request.uri}")},
+			Terminal: true,
+		})
+	}
+
+	var handles []interface{}
+	if headers := m.responseHeaders(); len(headers) > 0 {
+		handles = append(handles, HeadersHandle{
+			Handler:  "headers",
+			Response: &ResponseHeader{Set: headers, Deferred: true},
+		})
+	}
+	if m.Maintenance.Enabled {
+		routes = append(routes, Route{
+			Match:    m.maintenanceMatch(),
+			Handle:   append(append([]interface{}(nil), handles...), m.maintenanceHandle(domain)),
+			Terminal: true,
+		})
+	}
+	if s.Compression {
+		handles = append(handles, EncodeHandle{
+			Handler:   "encode",
+			Encodings: map[string]struct{}{"zstd": {}, "gzip": {}},
+			Prefer:    []string{"zstd", "gzip"},
+		})
+	}
+	if m.basicAuth() {
+		handles = append(handles, AuthenticationHandle{
+			Handler: "authentication",
+			Providers: AuthProviders{
+				HTTPBasic: &HTTPBasicAuth{
+					// the base64 form is understood by every Caddy version, newer ones also take the hash as is
+					Accounts: []BasicAuthAccount{{
+						Username: s.BasicAuthUsername,
+						Password: base64.StdEncoding.EncodeToString([]byte(m.PasswordHash)),
+					}},
+					Realm: domain,
+				},
+			},
+		})
+	}
+	if s.MaxBodySize > 0 {
+		handles = append(handles, RequestBodyHandle{
+			Handler: "request_body",
+			MaxSize: s.MaxBodySize,
+		})
+	}
+	handles = append(handles, m.withTimeouts(handle))
+
+	routes = append(routes, Route{Handle: handles, Terminal: true})
+	return []interface{}{SubrouteHandle{Handler: "subroute", Routes: routes}}
+}
+
+func (m Middleware) responseHeaders() map[string][]string {
+	s := m.Settings
+	headers := make(map[string][]string)
+	if s.SecurityHeaders {
+		frameOptions := s.FrameOptions
+		if frameOptions == "" {
+			frameOptions = "DENY"
+		}
+		headers["X-Content-Type-Options"] = []string{"nosniff"}
+		headers["X-Frame-Options"] = []string{frameOptions}
+		headers["Referrer-Policy"] = []string{"strict-origin-when-cross-origin"}
+	}
+	if s.HSTS {
+		maxAge := s.HSTSMaxAge
+		if maxAge == 0 {
+			maxAge = defaultHSTSMaxAge
+		}
+		value := "max-age=" + strconv.Itoa(maxAge)
+		if s.HSTSIncludeSubdomains {
+			value += "; includeSubDomains"
+		}
+		if s.HSTSPreload {
+			value += "; preload"
+		}
+		headers["Strict-Transport-Security"] = []string{value}
+	}
+	for name, value := range s.ResponseHeaders {
+		headers[http.CanonicalHeaderKey(name)] = []string{value}
+	}
+	return headers
+}
+
+// withTimeouts sets the upstream timeouts on the reverse proxies of handle. Static sites have
+// no upstream and are returned as is.
+func (m Middleware) withTimeouts(handle interface{}) interface{} {
+	s := m.Settings
+	if s.ConnectTimeout == 0 && s.ResponseTimeout == 0 {
+		return handle
+	}
+
+	switch h := handle.(type) {
+	case ReverseProxyHandle:
+		h.Transport = &HTTPTransport{Protocol: "http"}
+		if s.ConnectTimeout > 0 {
+			h.Transport.DialTimeout = strconv.Itoa(s.ConnectTimeout) + "s"
+		}
+		if s.ResponseTimeout > 0 {
+			h.Transport.ResponseHeaderTimeout = strconv.Itoa(s.ResponseTimeout) + "s"
+		}
+		return h
+	case SubrouteHandle:
+		routes := make([]Route, len(h.Routes))
+		for i, route := range h.Routes {
+			route.Handle = append([]interface{}(nil), route.Handle...)
+			for j, inner := range route.Handle {
+				route.Handle[j] = m.withTimeouts(inner)
+			}
+			routes[i] = route
+		}
+		h.Routes = routes
+		return h
+	default:
+		return handle
+	}
+}
+
+func redirectHandle(location string) StaticResponseHandle {
+	return StaticResponseHandle{
+		Handler:    "static_response",
+		StatusCode: http.StatusPermanentRedirect,
+		Headers:    map[string][]string{"Location": {location}},
+	}
+}
@@ -0,0 +1,215 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"testing"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings shared_types.ProxySettings
+		want     map[string][]string
+	}{
+		{
+			name: "None",
+			want: map[string][]string{},
+		},
+		{
+			name:     "Security headers",
+			settings: shared_types.ProxySettings{SecurityHeaders: true},
+			want: map[string][]string{
+				"X-Content-Type-Options": {"nosniff"},
+				"X-Frame-Options":        {"DENY"},
+				"Referrer-Policy":        {"strict-origin-when-cross-origin"},
+			},
+		},
+		{
+			name:     "Security headers with frame options",
+			settings: shared_types.ProxySettings{SecurityHeaders: true, FrameOptions: "SAMEORIGIN"},
+			want: map[string][]string{
+				"X-Content-Type-Options": {"nosniff"},
+				"X-Frame-Options":        {"SAMEORIGIN"},
+				"Referrer-Policy":        {"strict-origin-when-cross-origin"},
+			},
+		},
+		{
+			name:     "HSTS default max age",
+			settings: shared_types.ProxySettings{HSTS: true},
+			want:     map[string][]string{"Strict-Transport-Security": {"max-age=31536000"}},
+		},
+		{
+			name:     "HSTS with subdomains and preload",
+			settings: shared_types.ProxySettings{HSTS: true, HSTSMaxAge: 600, HSTSIncludeSubdomains: true, HSTSPreload: true},
+			want:     map[string][]string{"Strict-Transport-Security": {"max-age=600; includeSubDomains; preload"}},
+		},
+		{
+			name: "Custom headers are canonicalized and override the presets",
+			settings: shared_types.ProxySettings{
+				SecurityHeaders: true,
+				ResponseHeaders: map[string]string{"x-frame-options": "SAMEORIGIN", "x-powered-by": "nixopus"},
+			},
+			want: map[string][]string{
+				"X-Content-Type-Options": {"nosniff"},
+				"X-Frame-Options":        {"SAMEORIGIN"},
+				"Referrer-Policy":        {"strict-origin-when-cross-origin"},
+				"X-Powered-By":           {"nixopus"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Middleware{Settings: tt.settings}.responseHeaders())
+		})
+	}
+}
+
+func TestMiddlewareWrap(t *testing.T) {
+	upstream := ReverseProxyHandle{Handler: "reverse_proxy", Upstreams: []Upstream{{Dial: "10.0.0.5:3000"}}}
+
+	t.Run("Plain route is not wrapped", func(t *testing.T) {
+		assert.Equal(t, []interface{}{upstream}, Middleware{}.Wrap("example.com", upstream))
+	})
+
+	t.Run("Handlers in order", func(t *testing.T) {
+		m := Middleware{
+			Settings: shared_types.ProxySettings{
+				HSTS:              true,
+				HTTPSRedirect:     true,
+				Compression:       true,
+				BasicAuthUsername: "admin",
+				MaxBodySize:       1024,
+				ConnectTimeout:    5,
+				ResponseTimeout:   60,
+			},
+			PasswordHash: "$2a$10$hash",
+		}
+		handles := m.Wrap("example.com", upstream)
+		require.Len(t, handles, 1)
+		subroute, ok := handles[0].(SubrouteHandle)
+		require.True(t, ok)
+		require.Len(t, subroute.Routes, 2)
+
+		redirect := subroute.Routes[0]
+		assert.Equal(t, []Match{{Protocol: "http"}}, redirect.Match)
+		assert.Equal(t, []interface{}{redirectHandle("https://{http.request.host}{http.request.uri}")}, redirect.Handle)
+		assert.True(t, redirect.Terminal)
+
+		chain := subroute.Routes[1].Handle
+		require.Len(t, chain, 5)
+		headers := chain[0].(HeadersHandle)
+		assert.True(t, headers.Response.Deferred)
+		assert.Equal(t, []string{"max-age=31536000"}, headers.Response.Set["Strict-Transport-Security"])
+		assert.Equal(t, "encode", chain[1].(EncodeHandle).Handler)
+
+		auth := chain[2].(AuthenticationHandle)
+		account := auth.Providers.HTTPBasic.Accounts[0]
+		assert.Equal(t, "admin", account.Username)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("$2a$10$hash")), account.Password)
+		assert.Equal(t, "example.com", auth.Providers.HTTPBasic.Realm)
+
+		assert.Equal(t, int64(1024), chain[3].(RequestBodyHandle).MaxSize)
+
+		proxy := chain[4].(ReverseProxyHandle)
+		assert.Equal(t, &HTTPTransport{Protocol: "http", DialTimeout: "5s", ResponseHeaderTimeout: "60s"}, proxy.Transport)
+		assert.Nil(t, upstream.Transport)
+	})
+
+	t.Run("Basic auth needs a password", func(t *testing.T) {
+		m := Middleware{Settings: shared_types.ProxySettings{BasicAuthUsername: "admin"}}
+		assert.True(t, m.IsZero())
+	})
+}
+
+func TestWithTimeouts(t *testing.T) {
+	m := Middleware{Settings: shared_types.ProxySettings{ResponseTimeout: 30}}
+	proxy := ReverseProxyHandle{Handler: "reverse_proxy"}
+	files := FileServerHandle{Handler: "file_server"}
+
+	assert.Equal(t, files, m.withTimeouts(files))
+
+	nested := SubrouteHandle{Handler: "subroute", Routes: []Route{{Handle: []interface{}{files, proxy}}}}
+	got := m.withTimeouts(nested).(SubrouteHandle)
+	assert.Equal(t, files, got.Routes[0].Handle[0])
+	assert.Equal(t, &HTTPTransport{Protocol: "http", ResponseHeaderTimeout: "30s"}, got.Routes[0].Handle[1].(ReverseProxyHandle).Transport)
+	// the routes of the handle passed in are left untouched
+	assert.Equal(t, proxy, nested.Routes[0].Handle[1])
+}
+
+func TestMiddlewareHosts(t *testing.T) {
+	tests := []struct {
+		name          string
+		domain        string
+		middleware    Middleware
+		wantHosts     []string
+		wantRedirects []string
+	}{
+		{
+			name:      "Domain only",
+			domain:    "example.com",
+			wantHosts: []string{"example.com"},
+		},
+		{
+			name:   "Served and redirected aliases",
+			domain: "example.com",
+			middleware: Middleware{Aliases: []Alias{
+				{Host: "shop.example.com"},
+				{Host: "old.example.com", Redirect: true},
+				{Host: "EXAMPLE.com"},
+			}},
+			wantHosts:     []string{"example.com", "shop.example.com", "old.example.com"},
+			wantRedirects: []string{"old.example.com"},
+		},
+		{
+			name:          "WWW redirect",
+			domain:        "example.com",
+			middleware:    Middleware{Settings: shared_types.ProxySettings{WWWRedirect: true}},
+			wantHosts:     []string{"example.com", "www.example.com"},
+			wantRedirects: []string{"www.example.com"},
+		},
+		{
+			name:          "WWW domain redirects the bare domain",
+			domain:        "www.example.com",
+			middleware:    Middleware{Settings: shared_types.ProxySettings{WWWRedirect: true}},
+			wantHosts:     []string{"www.example.com", "example.com"},
+			wantRedirects: []string{"example.com"},
+		},
+		{
+			name:   "WWW alias served is not redirected",
+			domain: "example.com",
+			middleware: Middleware{
+				Settings: shared_types.ProxySettings{WWWRedirect: true},
+				Aliases:  []Alias{{Host: "www.example.com"}},
+			},
+			wantHosts: []string{"example.com", "www.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantHosts, tt.middleware.Hosts(tt.domain))
+			assert.Equal(t, tt.wantRedirects, tt.middleware.redirectHosts(tt.domain))
+		})
+	}
+}
+
+func TestApplicationMiddleware(t *testing.T) {
+	application := shared_types.Application{
+		Domain: "example.com",
+		Domains: []*shared_types.ApplicationDomain{
+			{Name: "example.com"},
+			{Name: "primary.example.com", IsPrimary: true},
+			{Name: "shop.example.com", Mode: shared_types.DomainModeServe},
+			{Name: "old.example.com", Mode: shared_types.DomainModeRedirect},
+		},
+	}
+	assert.Equal(t, []Alias{
+		{Host: "shop.example.com"},
+		{Host: "old.example.com", Redirect: true},
+	}, ApplicationMiddleware(application).Aliases)
+}
@@ -0,0 +1,451 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+const (
+	defaultNginxConfigDir       = "/etc/nginx/conf.d"
+	defaultNginxCertificatesDir = "/etc/letsencrypt/live"
+	defaultNginxTestCommand     = "nginx -t"
+	defaultNginxReloadCommand   = "nginx -s reload"
+
+	nginxFilePrefix = "nixopus-"
+	// nginxMaintenancePage is the file of the maintenance page in the site directory
+	nginxMaintenancePage = "nixopus-maintenance.html"
+	// nginxDollar stands for a literal dollar sign until render defines the variable holding it
+	nginxDollar = "${nixopus_dollar}"
+)
+
+// ErrPathPrefixUnsupported is returned for sites below a path prefix. Each domain has a single
+// vhost file on Nginx, it cannot be shared by several applications.
+var ErrPathPrefixUnsupported = errors.New("nginx cannot route a path prefix, use caddy to share a domain between applications")
+
+// ErrInvalidHost is returned for sites with a host that is not a plain hostname. Hosts are
+// written into the vhost as is, so anything else could add directives to it.
+var ErrInvalidHost = errors.New("nginx can only route hostnames made of letters, digits, dashes and dots")
+
+// nginxHostPattern matches a hostname, optionally prefixed with a wildcard label.
+var nginxHostPattern = regexp.MustCompile(`^(\*\.)?([A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)*[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// nginxMu serializes the writes of vhost files and the reloads of Nginx.
+var nginxMu sync.Mutex
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-z0-9.-]`)
+
+// Nginx serves sites by writing one vhost file per domain into ConfigDir, which the http
+// block of Nginx is expected to include, and reloading Nginx. Certificates are not obtained
+// by Nginx, a domain is served over HTTPS once CertificatesDir/<domain> holds fullchain.pem
+// and privkey.pem, the layout certbot uses.
+type Nginx struct {
+	Logger          *logger.Logger
+	ConfigDir       string
+	CertificatesDir string
+	TestCommand     string
+	ReloadCommand   string
+}
+
+func NewNginx(logger *logger.Logger) *Nginx {
+	cfg := config.AppConfig.Proxy
+	n := &Nginx{
+		Logger:          logger,
+		ConfigDir:       cfg.NginxConfigDir,
+		CertificatesDir: cfg.NginxCertificatesDir,
+		TestCommand:     cfg.NginxTestCommand,
+		ReloadCommand:   cfg.NginxReloadCommand,
+	}
+	if n.ConfigDir == "" {
+		n.ConfigDir = defaultNginxConfigDir
+	}
+	if n.CertificatesDir == "" {
+		n.CertificatesDir = defaultNginxCertificatesDir
+	}
+	if n.TestCommand == "" {
+		n.TestCommand = defaultNginxTestCommand
+	}
+	if n.ReloadCommand == "" {
+		n.ReloadCommand = defaultNginxReloadCommand
+	}
+	return n
+}
+
+// AddRoute writes the vhost of the site. Nginx has no separate registration step, adding and
+// updating a route are the same.
+func (n *Nginx) AddRoute(site Site) error {
+	return n.UpdateRoute(site)
+}
+
+func (n *Nginx) UpdateRoute(site Site) error {
+	files := make(map[string]string)
+	var location []string
+	switch {
+	case site.Wake != nil:
+		location = []string{
+			fmt.Sprintf("proxy_set_header %s %s;", site.Wake.TokenHeader, nginxQuote(site.Wake.Token)),
+			"proxy_set_header Host $host;",
+			fmt.Sprintf("proxy_pass http://%s%s$request_uri;", site.Wake.APIUpstream, site.Wake.Path),
+		}
+	case site.Stopped != "":
+		files["stopped.html"] = fmt.Sprintf(stoppedPage, html.EscapeString(site.Stopped))
+		location = []string{fmt.Sprintf("return %d;", http.StatusServiceUnavailable)}
+	default:
+		location = n.proxyLocation(site)
+	}
+	return n.writeSite(site, files, func(b *strings.Builder) {
+		if site.Stopped != "" {
+			fmt.Fprintf(b, "    error_page %d /stopped.html;\n", http.StatusServiceUnavailable)
+			// the page is served after an internal redirect, the headers of the stopped
+			// response are set here
+			writeLocation(b, "= /stopped.html", nil, []string{
+				"internal;",
+				`add_header Cache-Control "no-store" always;`,
+				`add_header Retry-After "300" always;`,
+				fmt.Sprintf("root %s;", nginxQuote(n.siteDir(site.Domain))),
+			})
+		}
+		writeLocation(b, "/", n.headerLines(site), location)
+	})
+}
+
+func (n *Nginx) proxyLocation(site Site) []string {
+	s := site.Middleware.Settings
+	lines := []string{
+		"proxy_http_version 1.1;",
+		"proxy_set_header Host $host;",
+		"proxy_set_header X-Real-IP $remote_addr;",
+		"proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;",
+		"proxy_set_header X-Forwarded-Proto $scheme;",
+		"proxy_set_header Upgrade $http_upgrade;",
+		`proxy_set_header Connection "upgrade";`,
+	}
+	if s.ConnectTimeout > 0 {
+		lines = append(lines, fmt.Sprintf("proxy_connect_timeout %ds;", s.ConnectTimeout))
+	}
+	if s.ResponseTimeout > 0 {
+		lines = append(lines, fmt.Sprintf("proxy_read_timeout %ds;", s.ResponseTimeout))
+	}
+	for _, name := range sortedKeys(site.Middleware.responseHeaders()) {
+		// the headers of the application are replaced, not sent twice
+		lines = append(lines, fmt.Sprintf("proxy_hide_header %s;", name))
+	}
+	return append(lines, fmt.Sprintf("proxy_pass http://%s;", site.Upstream))
+}
+
+// ServeFiles serves rootDir like the Caddy file server does: unknown paths fall back to
+// /index.html for single page applications, HTML documents are always revalidated and the
+// not found page keeps the 404 status.
+func (n *Nginx) ServeFiles(site Site, rootDir string, options StaticSiteOptions) error {
+	return n.writeSite(site, nil, func(b *strings.Builder) {
+		fmt.Fprintf(b, "    root %s;\n", nginxQuote(rootDir))
+		b.WriteString("    index index.html;\n")
+		b.WriteString("    location ~ /\\.git {\n        return 404;\n    }\n")
+		if notFound := strings.TrimPrefix(options.NotFoundPage, "/"); notFound != "" {
+			fmt.Fprintf(b, "    error_page 404 %s;\n", nginxQuote(path.Join("/", notFound)))
+		}
+
+		fallback := "=404"
+		if options.SPAFallback {
+			fallback = "/index.html"
+		}
+		var location []string
+		if options.Browse {
+			location = append(location, "autoindex on;")
+		}
+		location = append(location, "try_files $uri $uri/ "+fallback+";")
+
+		headers := n.headerLines(site)
+		if options.CacheControl != "" {
+			writeLocation(b, "~* \\.html?$", append(headers, `add_header Cache-Control "no-cache" always;`), []string{"try_files $uri =404;"})
+			headers = append(headers, fmt.Sprintf("add_header Cache-Control %s always;", nginxQuote(options.CacheControl)))
+		}
+		writeLocation(b, "/", headers, location)
+	})
+}
+
+// RemoveRoute deletes the vhost files of the domain of site and reloads Nginx.
+func (n *Nginx) RemoveRoute(site Site) error {
+	nginxMu.Lock()
+	defer nginxMu.Unlock()
+
+	var errs []error
+	if err := os.Remove(n.vhostPath(site.Domain)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, err)
+	}
+	if err := os.RemoveAll(n.siteDir(site.Domain)); err != nil {
+		errs = append(errs, err)
+	}
+	if err := n.run(n.ReloadCommand); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Status checks that the vhost directory exists and that the configuration of Nginx is valid.
+func (n *Nginx) Status() error {
+	info, err := os.Stat(n.ConfigDir)
+	if err != nil {
+		return fmt.Errorf("nginx config directory is not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("nginx config directory %s is not a directory", n.ConfigDir)
+	}
+	return n.run(n.TestCommand)
+}
+
+// writeSite renders the vhost of site around the locations written by body, tests the
+// configuration and reloads Nginx. The previous vhost is restored when the test fails, so a
+// bad site never keeps Nginx from reloading. Sites with a host that is not a hostname are
+// refused before anything is written, as the test would accept directives injected through it.
+func (n *Nginx) writeSite(site Site, files map[string]string, body func(b *strings.Builder)) error {
+	if site.Middleware.Settings.PathPrefix != "" {
+		return ErrPathPrefixUnsupported
+	}
+	for _, host := range site.Middleware.Hosts(site.Domain) {
+		if len(host) > 253 || !nginxHostPattern.MatchString(host) {
+			return ErrInvalidHost
+		}
+	}
+
+	nginxMu.Lock()
+	defer nginxMu.Unlock()
+
+	siteDir := n.siteDir(site.Domain)
+	if files == nil {
+		files = make(map[string]string)
+	}
+	if site.Middleware.basicAuth() {
+		files["htpasswd"] = site.Middleware.Settings.BasicAuthUsername + ":" + site.Middleware.PasswordHash + "\n"
+	}
+	if site.inMaintenance() && site.Middleware.Maintenance.RedirectURL == "" {
+		files[nginxMaintenancePage] = site.Middleware.maintenanceBody(site.Domain)
+	}
+	if err := os.MkdirAll(siteDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", siteDir, err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(siteDir, name), []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	vhostPath := n.vhostPath(site.Domain)
+	previous, err := os.ReadFile(vhostPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.WriteFile(vhostPath, []byte(n.render(site, body)), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", vhostPath, err)
+	}
+
+	if err := n.run(n.TestCommand); err != nil {
+		if previous != nil {
+			os.WriteFile(vhostPath, previous, 0o644)
+		} else {
+			os.Remove(vhostPath)
+		}
+		return err
+	}
+	if err := n.run(n.ReloadCommand); err != nil {
+		return err
+	}
+
+	n.Logger.Log(logger.Info, "Nginx vhost updated", site.Domain)
+	return nil
+}
+
+// render returns a server block redirecting each redirected host to the domain and one
+// serving the domain and its other hosts.
+func (n *Nginx) render(site Site, body func(b *strings.Builder)) string {
+	m := site.Middleware
+	var b strings.Builder
+
+	redirects := m.redirectHosts(site.Domain)
+	for _, host := range redirects {
+		b.WriteString("server {\n")
+		n.writeListen(&b, host)
+		fmt.Fprintf(&b, "    server_name %s;\n", host)
+		fmt.Fprintf(&b, "    return %d https://%s$request_uri;\n", http.StatusPermanentRedirect, site.Domain)
+		b.WriteString("}\n")
+	}
+
+	var hosts []string
+	for _, host := range m.Hosts(site.Domain) {
+		if !containsFold(redirects, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	b.WriteString("server {\n")
+	tls := n.writeListen(&b, site.Domain)
+	fmt.Fprintf(&b, "    server_name %s;\n", strings.Join(hosts, " "))
+
+	s := m.Settings
+	if s.MaxBodySize > 0 {
+		fmt.Fprintf(&b, "    client_max_body_size %d;\n", s.MaxBodySize)
+	} else {
+		b.WriteString("    client_max_body_size 0;\n")
+	}
+	if s.HTTPSRedirect && tls {
+		fmt.Fprintf(&b, "    if ($scheme = http) {\n        return %d https://$host$request_uri;\n    }\n", http.StatusPermanentRedirect)
+	}
+	if site.inMaintenance() {
+		n.writeMaintenance(&b, site)
+	}
+	if m.basicAuth() {
+		fmt.Fprintf(&b, "    auth_basic %s;\n", nginxQuote(site.Domain))
+		fmt.Fprintf(&b, "    auth_basic_user_file %s;\n", nginxQuote(filepath.Join(n.siteDir(site.Domain), "htpasswd")))
+	}
+	if s.Compression {
+		b.WriteString("    gzip on;\n    gzip_proxied any;\n    gzip_vary on;\n")
+		b.WriteString("    gzip_types text/plain text/css text/xml text/javascript application/javascript application/json application/xml image/svg+xml;\n")
+	}
+	body(&b)
+	b.WriteString("}\n")
+
+	header := "# Managed by Nixopus, changes are overwritten when the application is routed again.\n"
+	if site.inMaintenance() && len(m.Maintenance.BypassIPs) > 0 {
+		header += fmt.Sprintf("geo $%s {\n    default 1;\n", nginxVariable("nixopus_maintenance", site.Domain))
+		for _, ip := range m.Maintenance.BypassIPs {
+			header += fmt.Sprintf("    %s 0;\n", ip)
+		}
+		header += "}\n"
+	}
+	config := b.String()
+	if !strings.Contains(config, nginxDollar) {
+		return header + config
+	}
+	variable := nginxVariable("nixopus_dollar", site.Domain)
+	config = strings.ReplaceAll(config, nginxDollar, "${"+variable+"}")
+	return header + fmt.Sprintf("geo $%s {\n    default \"$\";\n}\n", variable) + config
+}
+
+// writeMaintenance answers the requests that do not bypass maintenance with the maintenance
+// page or redirect. The checks run for every request of the server, so the internal redirect
+// to the page is let through explicitly.
+func (n *Nginx) writeMaintenance(b *strings.Builder, site Site) {
+	maintenance := site.Middleware.Maintenance
+	if len(maintenance.BypassIPs) > 0 {
+		fmt.Fprintf(b, "    set $nixopus_maintenance $%s;\n", nginxVariable("nixopus_maintenance", site.Domain))
+	} else {
+		b.WriteString("    set $nixopus_maintenance 1;\n")
+	}
+	if maintenance.BypassToken != "" {
+		header := "$http_" + strings.ToLower(strings.ReplaceAll(MaintenanceBypassHeader, "-", "_"))
+		fmt.Fprintf(b, "    if (%s = %s) {\n        set $nixopus_maintenance 0;\n    }\n", header, nginxQuote(maintenance.BypassToken))
+	}
+
+	if maintenance.RedirectURL != "" {
+		fmt.Fprintf(b, "    if ($nixopus_maintenance) {\n        return %d %s;\n    }\n", http.StatusFound, nginxQuote(maintenance.RedirectURL))
+		return
+	}
+	fmt.Fprintf(b, "    if ($uri = /%s) {\n        set $nixopus_maintenance 0;\n    }\n", nginxMaintenancePage)
+	fmt.Fprintf(b, "    if ($nixopus_maintenance) {\n        return %d;\n    }\n", http.StatusServiceUnavailable)
+	fmt.Fprintf(b, "    error_page %d /%s;\n", http.StatusServiceUnavailable, nginxMaintenancePage)
+	writeLocation(b, "= /"+nginxMaintenancePage, nil, []string{
+		"internal;",
+		`add_header Cache-Control "no-store" always;`,
+		`add_header Retry-After "300" always;`,
+		fmt.Sprintf("root %s;", nginxQuote(n.siteDir(site.Domain))),
+	})
+}
+
+// writeListen listens on port 80 and, when a certificate is found for host, on port 443.
+func (n *Nginx) writeListen(b *strings.Builder, host string) bool {
+	b.WriteString("    listen 80;\n    listen [::]:80;\n")
+
+	dir := filepath.Join(n.CertificatesDir, strings.ToLower(host))
+	certificate := filepath.Join(dir, "fullchain.pem")
+	key := filepath.Join(dir, "privkey.pem")
+	if _, err := os.Stat(certificate); err != nil {
+		return false
+	}
+	if _, err := os.Stat(key); err != nil {
+		return false
+	}
+	b.WriteString("    listen 443 ssl;\n    listen [::]:443 ssl;\n")
+	fmt.Fprintf(b, "    ssl_certificate %s;\n", nginxQuote(certificate))
+	fmt.Fprintf(b, "    ssl_certificate_key %s;\n", nginxQuote(key))
+	return true
+}
+
+// headerLines returns the add_header directives of the response headers. Nginx only inherits
+// add_header directives into locations that set none, so every location repeats them.
+func (n *Nginx) headerLines(site Site) []string {
+	headers := site.Middleware.responseHeaders()
+	var lines []string
+	for _, name := range sortedKeys(headers) {
+		lines = append(lines, fmt.Sprintf("add_header %s %s always;", name, nginxQuote(headers[name][0])))
+	}
+	return lines
+}
+
+func writeLocation(b *strings.Builder, match string, headers []string, lines []string) {
+	fmt.Fprintf(b, "    location %s {\n", match)
+	for _, line := range append(append([]string(nil), headers...), lines...) {
+		fmt.Fprintf(b, "        %s\n", line)
+	}
+	b.WriteString("    }\n")
+}
+
+func (n *Nginx) vhostPath(domain string) string {
+	return filepath.Join(n.ConfigDir, nginxFilePrefix+nginxFileName(domain)+".conf")
+}
+
+// siteDir holds the files referenced by the vhost of domain. Nginx only includes the .conf
+// files of ConfigDir, the directory is not read as configuration.
+func (n *Nginx) siteDir(domain string) string {
+	return filepath.Join(n.ConfigDir, nginxFilePrefix+nginxFileName(domain))
+}
+
+// run runs the test or reload command of Nginx through the shell. The commands are only taken
+// from NGINX_TEST_COMMAND and NGINX_RELOAD_COMMAND of the server configuration, nothing of a
+// site or a request ever reaches the command line.
+func (n *Nginx) run(command string) error {
+	output, err := exec.Command("sh", "-c", command).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w: %s", command, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// nginxVariable returns the name of the variable of domain. Variables defined by geo are global
+// to the http block, each vhost defines its own.
+func nginxVariable(name string, domain string) string {
+	return name + "_" + strings.NewReplacer(".", "_", "-", "_").Replace(nginxFileName(domain))
+}
+
+func nginxFileName(domain string) string {
+	return unsafeFileNameChars.ReplaceAllString(strings.ToLower(domain), "_")
+}
+
+// nginxQuote quotes s as an Nginx string. Nginx has no escape for $, a variable holding a
+// dollar sign is used instead.
+func nginxQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "$", nginxDollar)
+	return `"` + s + `"`
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,220 @@
+package proxy
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+// newTestNginx returns an Nginx writing into temporary directories whose test and reload
+// commands always succeed. Certificates are found for the given hosts.
+func newTestNginx(t *testing.T, certificates ...string) *Nginx {
+	log := logger.NewLogger()
+	n := &Nginx{
+		Logger:          &log,
+		ConfigDir:       t.TempDir(),
+		CertificatesDir: t.TempDir(),
+		TestCommand:     "true",
+		ReloadCommand:   "true",
+	}
+	for _, host := range certificates {
+		dir := filepath.Join(n.CertificatesDir, host)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "fullchain.pem"), nil, 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "privkey.pem"), nil, 0o644))
+	}
+	return n
+}
+
+// readVhost returns the vhost of domain with the temporary directories replaced by the
+// defaults, so the golden files do not depend on them.
+func readVhost(t *testing.T, n *Nginx, domain string) string {
+	content, err := os.ReadFile(n.vhostPath(domain))
+	require.NoError(t, err)
+	return strings.NewReplacer(
+		n.ConfigDir, defaultNginxConfigDir,
+		n.CertificatesDir, defaultNginxCertificatesDir,
+	).Replace(string(content))
+}
+
+func assertGolden(t *testing.T, name string, got string) {
+	golden := filepath.Join("testdata", "nginx", name+".conf")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, []byte(got), 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), got)
+}
+
+func TestNginxRender(t *testing.T) {
+	tests := []struct {
+		name         string
+		site         Site
+		certificates []string
+		files        []string
+	}{
+		{
+			name: "vhost",
+			site: Site{
+				Domain:   "example.com",
+				Upstream: "10.0.0.5:3000",
+				Middleware: Middleware{
+					Settings: shared_types.ProxySettings{
+						SecurityHeaders:   true,
+						HSTS:              true,
+						HTTPSRedirect:     true,
+						WWWRedirect:       true,
+						Compression:       true,
+						MaxBodySize:       1048576,
+						ConnectTimeout:    5,
+						ResponseTimeout:   60,
+						BasicAuthUsername: "admin",
+						ResponseHeaders:   map[string]string{"x-price": "$5"},
+					},
+					PasswordHash: "$2a$10$hash",
+					Aliases: []Alias{
+						{Host: "app.example.com"},
+						{Host: "old.example.com", Redirect: true},
+					},
+				},
+			},
+			certificates: []string{"example.com"},
+			files:        []string{"htpasswd"},
+		},
+		{
+			name: "maintenance",
+			site: Site{
+				Domain:   "example.com",
+				Upstream: "10.0.0.5:3000",
+				Middleware: Middleware{
+					Maintenance: shared_types.MaintenanceSettings{
+						Enabled:     true,
+						BypassIPs:   []string{"203.0.113.7", "10.0.0.0/8"},
+						BypassToken: "let-me-in",
+					},
+				},
+			},
+			files: []string{nginxMaintenancePage},
+		},
+		{
+			name: "maintenance_redirect",
+			site: Site{
+				Domain:   "example.com",
+				Upstream: "10.0.0.5:3000",
+				Middleware: Middleware{
+					Maintenance: shared_types.MaintenanceSettings{
+						Enabled:     true,
+						RedirectURL: "https://status.example.com",
+					},
+				},
+			},
+		},
+		{
+			name: "stopped",
+			site: Site{
+				Domain:     "example.com",
+				Stopped:    "shop",
+				Middleware: Middleware{Maintenance: shared_types.MaintenanceSettings{Enabled: true}},
+			},
+			files: []string{"stopped.html"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestNginx(t, tt.certificates...)
+			require.NoError(t, n.UpdateRoute(tt.site))
+
+			assertGolden(t, tt.name, readVhost(t, n, tt.site.Domain))
+			for _, file := range tt.files {
+				assert.FileExists(t, filepath.Join(n.siteDir(tt.site.Domain), file))
+			}
+		})
+	}
+}
+
+func TestNginxRejectsPathPrefix(t *testing.T) {
+	n := newTestNginx(t)
+	site := Site{
+		Domain:     "example.com",
+		Upstream:   "10.0.0.5:3000",
+		Middleware: Middleware{Settings: shared_types.ProxySettings{PathPrefix: "/api"}},
+	}
+
+	assert.ErrorIs(t, n.UpdateRoute(site), ErrPathPrefixUnsupported)
+	assert.ErrorIs(t, n.ServeFiles(site, "/srv/site", StaticSiteOptions{}), ErrPathPrefixUnsupported)
+	assert.NoFileExists(t, n.vhostPath(site.Domain))
+}
+
+func TestNginxRestoresVhostWhenTestFails(t *testing.T) {
+	n := newTestNginx(t)
+	site := Site{Domain: "example.com", Upstream: "10.0.0.5:3000"}
+	require.NoError(t, n.UpdateRoute(site))
+	previous := readVhost(t, n, site.Domain)
+
+	n.TestCommand = "false"
+	site.Upstream = "10.0.0.6:3000"
+	assert.Error(t, n.UpdateRoute(site))
+	assert.Equal(t, previous, readVhost(t, n, site.Domain))
+
+	assert.Error(t, n.UpdateRoute(Site{Domain: "new.example.com", Upstream: "10.0.0.6:3000"}))
+	assert.NoFileExists(t, n.vhostPath("new.example.com"))
+}
+
+func TestNginxRejectsHostileHosts(t *testing.T) {
+	injected := "example.com; location /x { alias /etc/; }"
+	tests := []struct {
+		name string
+		site Site
+	}{
+		{name: "Domain with a directive", site: Site{Domain: injected, Upstream: "10.0.0.5:3000"}},
+		{name: "Domain with a block", site: Site{Domain: "example.com{", Upstream: "10.0.0.5:3000"}},
+		{name: "Domain with a newline", site: Site{Domain: "example.com\n    root /", Upstream: "10.0.0.5:3000"}},
+		{name: "Domain with a space", site: Site{Domain: "example.com evil.com", Upstream: "10.0.0.5:3000"}},
+		{
+			name: "Served alias",
+			site: Site{Domain: "example.com", Upstream: "10.0.0.5:3000", Middleware: Middleware{Aliases: []Alias{{Host: injected}}}},
+		},
+		{
+			name: "Redirected alias",
+			site: Site{Domain: "example.com", Upstream: "10.0.0.5:3000", Middleware: Middleware{Aliases: []Alias{{Host: injected, Redirect: true}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestNginx(t)
+			require.NoError(t, n.UpdateRoute(Site{Domain: "example.com", Upstream: "10.0.0.5:3000"}))
+
+			assert.ErrorIs(t, n.UpdateRoute(tt.site), ErrInvalidHost)
+			assert.ErrorIs(t, n.ServeFiles(tt.site, "/srv/site", StaticSiteOptions{}), ErrInvalidHost)
+
+			// the routed vhost is left as it was and nothing is written for the hostile host
+			assertGolden(t, "hostile", readVhost(t, n, "example.com"))
+			entries, err := os.ReadDir(n.ConfigDir)
+			require.NoError(t, err)
+			for _, entry := range entries {
+				content, err := os.ReadFile(filepath.Join(n.ConfigDir, entry.Name()))
+				if err == nil {
+					assert.NotContains(t, string(content), "alias /etc/")
+				}
+			}
+			if tt.site.Domain != "example.com" {
+				assert.NoFileExists(t, n.vhostPath(tt.site.Domain))
+			}
+		})
+	}
+
+	n := newTestNginx(t)
+	assert.NoError(t, n.UpdateRoute(Site{Domain: "*.example.com", Upstream: "10.0.0.5:3000"}))
+}
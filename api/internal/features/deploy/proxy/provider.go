@@ -0,0 +1,156 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/raghavyuva/caddygo"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// Provider is a reverse proxy serving the domains of applications. The backend of an
+// application is chosen by its ProxyServer.
+type Provider interface {
+	// AddRoute routes the domains of site, obtaining certificates for them where the proxy can.
+	AddRoute(site Site) error
+	// UpdateRoute replaces the route of the domains of site.
+	UpdateRoute(site Site) error
+	// RemoveRoute stops serving the domains of site below its path prefix.
+	RemoveRoute(site Site) error
+	// ServeFiles serves the files in rootDir on the domains of site.
+	ServeFiles(site Site, rootDir string, options StaticSiteOptions) error
+	// Status returns an error when the proxy cannot be reached or its configuration is invalid.
+	Status() error
+}
+
+// Site is a route of the domains of an application. Requests are proxied to Upstream, unless
+// Stopped or Wake is set. Owner identifies the application, the route of the same owner is
+// replaced when the path prefix of the application changes.
+type Site struct {
+	Owner      string
+	Domain     string
+	Middleware Middleware
+	Upstream   string
+	// Stopped answers every request with the stopped page of the application with this name
+	Stopped string
+	// Wake forwards requests to the API, which starts the sleeping application
+	Wake *Wake
+}
+
+// inMaintenance reports whether the maintenance response is rendered for site. Stopped and
+// sleeping applications answer with their own route instead.
+func (s Site) inMaintenance() bool {
+	return s.Middleware.Maintenance.Enabled && s.Stopped == "" && s.Wake == nil
+}
+
+// Wake forwards requests for a sleeping application to Path on the Nixopus API. The original
+// URI is appended to Path and Token is set in TokenHeader, so the API can tell the request
+// came through the proxy before starting the application.
+type Wake struct {
+	APIUpstream string
+	Path        string
+	TokenHeader string
+	Token       string
+}
+
+// NewProvider returns the proxy backend for server, Caddy unless Nginx is chosen.
+func NewProvider(server shared_types.ProxyServer, logger *logger.Logger) Provider {
+	if server == shared_types.Nginx {
+		return NewNginx(logger)
+	}
+	return NewCaddyProvider(logger)
+}
+
+var (
+	caddyClientOnce sync.Once
+	caddyClient     *caddygo.Client
+)
+
+// CaddyProvider serves sites through the Caddy admin API. Domains are added with the caddygo
+// client, which sets up automatic TLS, and their routes are then rendered by Caddy.
+type CaddyProvider struct {
+	Logger *logger.Logger
+	client *caddygo.Client
+}
+
+func NewCaddyProvider(logger *logger.Logger) *CaddyProvider {
+	caddyClientOnce.Do(func() {
+		caddyClient = caddygo.NewClient(config.AppConfig.Proxy.CaddyEndpoint)
+	})
+	return &CaddyProvider{
+		Logger: logger,
+		client: caddyClient,
+	}
+}
+
+func (p *CaddyProvider) caddy(site Site, rootDir string, fileServerType FileServerType) *Caddy {
+	c := NewCaddy(p.Logger, rootDir, site.Domain, "", fileServerType)
+	c.Owner = site.Owner
+	c.Upstream = site.Upstream
+	c.Middleware = site.Middleware
+	return c
+}
+
+// AddRoute obtains certificates for the domain on demand and routes it. The caddygo client is
+// not used to add the domain, it replaces every route of the domain, including the routes of
+// other applications sharing it below other paths.
+func (p *CaddyProvider) AddRoute(site Site) error {
+	if err := p.caddy(site, "", ReverseProxy).EnableAutomaticTLS(site.Domain); err != nil {
+		return err
+	}
+	return p.UpdateRoute(site)
+}
+
+func (p *CaddyProvider) UpdateRoute(site Site) error {
+	c := p.caddy(site, "", ReverseProxy)
+	return c.replaceRoute(siteRoute(c, site))
+}
+
+// siteRoute returns the route c renders for site: the wake or stopped route of an application
+// that is not running, or the proxy to its upstream.
+func siteRoute(c *Caddy, site Site) Route {
+	switch {
+	case site.Wake != nil:
+		return c.handleRoute(WakeHandles(site.Wake.APIUpstream, site.Wake.Path, site.Wake.TokenHeader, site.Wake.Token)...)
+	case site.Stopped != "":
+		return c.handleRoute(StoppedHandle(site.Stopped))
+	default:
+		return c.proxyRoute()
+	}
+}
+
+// RemoveRoute deletes the route of site. The TLS connection policies and automation subjects
+// of its hosts are only deleted once no other route serves them.
+func (p *CaddyProvider) RemoveRoute(site Site) error {
+	unused, err := p.caddy(site, "", ReverseProxy).DeleteRoute()
+	if err != nil {
+		return err
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, host := range unused {
+		if err := p.client.DeleteDomain(host); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", host, err))
+		}
+	}
+	p.client.Reload()
+	return errors.Join(errs...)
+}
+
+// ServeFiles replaces the route of the domain with a file server. Like the other routes only the
+// route list is rewritten, loading the whole config again would drop the other apps of Caddy.
+func (p *CaddyProvider) ServeFiles(site Site, rootDir string, options StaticSiteOptions) error {
+	c := p.caddy(site, rootDir, FileServer)
+	c.StaticSite = options
+	return c.replaceRoute(c.route())
+}
+
+func (p *CaddyProvider) Status() error {
+	return NewCaddy(p.Logger, "", "", "", ReverseProxy).checkCaddyRunning()
+}
@@ -0,0 +1,227 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+// DriftKind tells how the route of an application in Caddy differs from the route it renders.
+type DriftKind string
+
+const (
+	// DriftMissing is an application without a route
+	DriftMissing DriftKind = "missing"
+	// DriftChanged is a route that differs from the one the application renders, or an
+	// application with more than one route
+	DriftChanged DriftKind = "changed"
+	// DriftStale is a route of an application that is not routed anymore
+	DriftStale DriftKind = "stale"
+)
+
+// RouteDrift is a route of the nixopus server that does not match the state of its application.
+type RouteDrift struct {
+	Owner  string    `json:"owner"`
+	Domain string    `json:"domain"`
+	Kind   DriftKind `json:"kind"`
+}
+
+// DesiredSite is a site along with what it serves: the files in RootDir when Static is set,
+// the route of the site otherwise.
+type DesiredSite struct {
+	Site    Site
+	RootDir string
+	Static  *StaticSiteOptions
+}
+
+// Reconcile compares the routes of the nixopus server with the routes sites render and reports
+// the drift. Routes of owners that are neither in sites nor in keep are stale, routes without an
+// owner that no site claims, like the routes of Nixopus itself, are left alone.
+//
+// With fix set, drifted routes are replaced by the rendered ones and stale routes are removed in
+// a single update, and the nixopus server is created when Caddy lost it. Automatic TLS is
+// enabled again for the domains of missing routes.
+func (p *CaddyProvider) Reconcile(sites []DesiredSite, keep []string, fix bool) ([]RouteDrift, error) {
+	caddyRoutesMu.Lock()
+	defer caddyRoutesMu.Unlock()
+
+	c := NewCaddy(p.Logger, "", "", "", ReverseProxy)
+	exists, err := c.ensureServer(fix)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		drift, _, err := p.routeDrift(nil, sites, keep)
+		return drift, err
+	}
+
+	var drift []RouteDrift
+	var driftErr error
+	err = c.updateRoutes(func(routes []Route) ([]Route, bool) {
+		var newRoutes []Route
+		drift, newRoutes, driftErr = p.routeDrift(routes, sites, keep)
+		return newRoutes, driftErr == nil && fix && len(drift) > 0
+	})
+	if driftErr != nil {
+		return nil, driftErr
+	}
+	if err != nil {
+		return drift, err
+	}
+	if !fix || len(drift) == 0 {
+		return drift, nil
+	}
+	p.Logger.Log(logger.Info, "Caddy routes reconciled", fmt.Sprintf("%d routes fixed", len(drift)))
+
+	var errs []error
+	for _, d := range drift {
+		if d.Kind == DriftMissing {
+			if err := c.EnableAutomaticTLS(d.Domain); err != nil {
+				errs = append(errs, fmt.Errorf("failed to enable TLS for %s: %w", d.Domain, err))
+			}
+		}
+	}
+	return drift, errors.Join(errs...)
+}
+
+// routeDrift returns the drift between routes and the routes sites render, along with the
+// route list fixing it.
+func (p *CaddyProvider) routeDrift(routes []Route, sites []DesiredSite, keep []string) ([]RouteDrift, []Route, error) {
+	var drift []RouteDrift
+	claimed := make([]bool, len(routes))
+	desired := make([]Route, 0, len(sites))
+	owners := make(map[string]bool, len(sites)+len(keep))
+	for _, owner := range keep {
+		owners[owner] = true
+	}
+
+	for _, site := range sites {
+		owners[site.Site.Owner] = true
+		sc := p.siteCaddy(site)
+		route := p.desiredRoute(sc, site)
+		desired = append(desired, route)
+
+		hosts := sc.Middleware.Hosts(sc.Domain)
+		var owned []Route
+		for i, actual := range routes {
+			if sc.ownsRoute(actual, hosts) {
+				claimed[i] = true
+				owned = append(owned, actual)
+			}
+		}
+
+		switch {
+		case len(owned) == 0:
+			drift = append(drift, RouteDrift{Owner: site.Site.Owner, Domain: site.Site.Domain, Kind: DriftMissing})
+		case len(owned) > 1:
+			drift = append(drift, RouteDrift{Owner: site.Site.Owner, Domain: site.Site.Domain, Kind: DriftChanged})
+		default:
+			previous, err := json.Marshal(owned[0])
+			if err != nil {
+				return nil, nil, err
+			}
+			same, err := sameJSON(previous, route)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !same {
+				drift = append(drift, RouteDrift{Owner: site.Site.Owner, Domain: site.Site.Domain, Kind: DriftChanged})
+			}
+		}
+	}
+
+	var kept []Route
+	for i, route := range routes {
+		if claimed[i] {
+			continue
+		}
+		if owner, _, ok := parseRouteID(route.ID); ok && !owners[owner] {
+			var domain string
+			if hosts := routeHosts([]Route{route}); len(hosts) > 0 {
+				domain = hosts[0]
+			}
+			drift = append(drift, RouteDrift{Owner: owner, Domain: domain, Kind: DriftStale})
+			continue
+		}
+		kept = append(kept, route)
+	}
+
+	newRoutes := append(kept, desired...)
+	sortRoutes(newRoutes)
+	return drift, newRoutes, nil
+}
+
+// siteCaddy returns the Caddy client rendering site.
+func (p *CaddyProvider) siteCaddy(site DesiredSite) *Caddy {
+	if site.Static != nil {
+		c := p.caddy(site.Site, site.RootDir, FileServer)
+		c.StaticSite = *site.Static
+		return c
+	}
+	return p.caddy(site.Site, "", ReverseProxy)
+}
+
+// desiredRoute returns the route ServeFiles or UpdateRoute writes for site.
+func (p *CaddyProvider) desiredRoute(c *Caddy, site DesiredSite) Route {
+	if site.Static != nil {
+		return c.route()
+	}
+	return siteRoute(c, site.Site)
+}
+
+// ensureServer reports whether the nixopus server exists in the config of Caddy. When it does
+// not and create is set, an empty server listening on :443 is added, keeping the rest of the
+// config as it is.
+func (c *Caddy) ensureServer(create bool) (bool, error) {
+	var root map[string]interface{}
+	if _, err := c.getConfigValue("/config/", &root); err != nil {
+		return false, err
+	}
+
+	servers := nestedMap(root, "apps", "http", "servers")
+	if _, ok := servers["nixopus"]; ok {
+		return true, nil
+	}
+	if !create {
+		return false, nil
+	}
+
+	if root == nil {
+		root = make(map[string]interface{})
+	}
+	apps := ensureMap(root, "apps")
+	httpApp := ensureMap(apps, "http")
+	servers = ensureMap(httpApp, "servers")
+	servers["nixopus"] = map[string]interface{}{
+		"listen": []string{":443"},
+		"routes": []Route{},
+	}
+	if err := c.loadConfig(root); err != nil {
+		return false, err
+	}
+	c.Logger.Log(logger.Warning, "Caddy had no nixopus server, it was created again", "")
+	return true, nil
+}
+
+// nestedMap returns the object at keys below m, nil when one of them is not set.
+func nestedMap(m map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, key := range keys {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		m = next
+	}
+	return m
+}
+
+func ensureMap(m map[string]interface{}, key string) map[string]interface{} {
+	next, ok := m[key].(map[string]interface{})
+	if !ok {
+		next = make(map[string]interface{})
+		m[key] = next
+	}
+	return next
+}
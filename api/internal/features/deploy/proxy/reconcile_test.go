@@ -0,0 +1,155 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCaddyAdmin is the part of the admin API of Caddy the route updates use, serving the routes
+// of the nixopus server with an ETag and recording the writes.
+type fakeCaddyAdmin struct {
+	mu     sync.Mutex
+	routes []Route
+	writes map[string]string
+	// version changes with every write of the routes, it is the ETag of the route list
+	version int
+	// afterGet runs once the routes are read, to write them in between like another writer
+	afterGet func(a *fakeCaddyAdmin)
+}
+
+func (a *fakeCaddyAdmin) etag() string {
+	return fmt.Sprintf(`"%s %d"`, serverRoutesPath, a.version)
+}
+
+func newFakeCaddyAdmin(t *testing.T, routes []Route) *fakeCaddyAdmin {
+	admin := &fakeCaddyAdmin{routes: routes, writes: make(map[string]string)}
+	server := httptest.NewServer(admin)
+	t.Cleanup(server.Close)
+
+	previous := config.AppConfig.Proxy.CaddyEndpoint
+	config.AppConfig.Proxy.CaddyEndpoint = server.URL
+	t.Cleanup(func() { config.AppConfig.Proxy.CaddyEndpoint = previous })
+	return admin
+}
+
+func (a *fakeCaddyAdmin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/config/":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"apps": map[string]interface{}{"http": map[string]interface{}{"servers": map[string]interface{}{
+				"nixopus": map[string]interface{}{"listen": []string{":443"}, "routes": a.routes},
+			}}},
+		})
+	case r.Method == http.MethodGet && r.URL.Path == serverRoutesPath:
+		w.Header().Set("Etag", a.etag())
+		json.NewEncoder(w).Encode(a.routes)
+		if a.afterGet != nil {
+			a.afterGet(a)
+		}
+	case r.Method == http.MethodGet:
+		w.Write([]byte("null"))
+	default:
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == serverRoutesPath {
+			if match := r.Header.Get("If-Match"); match != "" && match != a.etag() {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			a.routes = nil
+			json.Unmarshal(body, &a.routes)
+			a.version++
+		}
+		a.writes[r.URL.Path] = r.Method
+	}
+}
+
+func (a *fakeCaddyAdmin) owners() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var owners []string
+	for _, route := range a.routes {
+		owner, _, _ := parseRouteID(route.ID)
+		owners = append(owners, owner)
+	}
+	return owners
+}
+
+func TestReconcile(t *testing.T) {
+	log := logger.NewLogger()
+	p := &CaddyProvider{Logger: &log}
+	site := func(owner string, domain string, upstream string) DesiredSite {
+		return DesiredSite{Site: Site{Owner: owner, Domain: domain, Upstream: upstream}}
+	}
+	rendered := func(s DesiredSite) Route {
+		return p.desiredRoute(p.siteCaddy(s), s)
+	}
+
+	inSync := site("in-sync", "a.example.com", "10.0.0.1:3000")
+	changed := site("changed", "b.example.com", "10.0.0.1:3001")
+	missing := site("missing", "c.example.com", "10.0.0.1:3002")
+	kept := rendered(site("kept", "d.example.com", "10.0.0.1:3003"))
+	stale := rendered(site("stale", "e.example.com", "10.0.0.1:3004"))
+	// a route of Nixopus itself, which has no owner
+	unowned := Route{Match: []Match{{Host: []string{"api.example.com"}}}, Terminal: true}
+
+	actual := []Route{
+		rendered(inSync),
+		rendered(site("changed", "b.example.com", "10.0.0.1:4001")),
+		kept,
+		stale,
+		unowned,
+	}
+	sites := []DesiredSite{inSync, changed, missing}
+	wantDrift := []RouteDrift{
+		{Owner: "changed", Domain: "b.example.com", Kind: DriftChanged},
+		{Owner: "missing", Domain: "c.example.com", Kind: DriftMissing},
+		{Owner: "stale", Domain: "e.example.com", Kind: DriftStale},
+	}
+
+	t.Run("Report", func(t *testing.T) {
+		admin := newFakeCaddyAdmin(t, actual)
+
+		drift, err := p.Reconcile(sites, []string{"kept"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, wantDrift, drift)
+		assert.Empty(t, admin.writes)
+	})
+
+	t.Run("Fix", func(t *testing.T) {
+		admin := newFakeCaddyAdmin(t, actual)
+
+		drift, err := p.Reconcile(sites, []string{"kept"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, wantDrift, drift)
+		assert.Equal(t, http.MethodPatch, admin.writes[serverRoutesPath])
+		assert.Contains(t, admin.writes, tlsAppPath)
+		assert.ElementsMatch(t, []string{"in-sync", "changed", "missing", "kept", ""}, admin.owners())
+
+		// the fixed routes are in sync
+		drift, err = p.Reconcile(sites, []string{"kept"}, false)
+		require.NoError(t, err)
+		assert.Empty(t, drift)
+	})
+
+	t.Run("Skipped applications keep their route", func(t *testing.T) {
+		admin := newFakeCaddyAdmin(t, actual)
+
+		drift, err := p.Reconcile([]DesiredSite{inSync}, []string{"changed", "kept", "stale"}, true)
+		require.NoError(t, err)
+		assert.Empty(t, drift)
+		assert.Empty(t, admin.writes)
+	})
+}
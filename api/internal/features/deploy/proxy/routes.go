@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// routeIDPrefix marks the routes added by Nixopus, the rest of the id is the owner of the route
+// and its priority.
+const routeIDPrefix = "nixopus-route:"
+
+// routeID identifies the route of owner in the Caddy config. The priority is part of the id,
+// Caddy keeps nothing else along with a route and the priorities of the routes of other
+// applications are needed to insert a route at its place.
+func routeID(owner string, priority int) string {
+	return routeIDPrefix + owner + ":" + strconv.Itoa(priority)
+}
+
+// parseRouteID returns the owner and priority of a route added by Nixopus.
+func parseRouteID(id string) (string, int, bool) {
+	rest, ok := strings.CutPrefix(id, routeIDPrefix)
+	if !ok {
+		return "", 0, false
+	}
+	i := strings.LastIndex(rest, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+	priority, err := strconv.Atoi(rest[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return rest[:i], priority, true
+}
+
+// pathMatch returns the paths matching the requests below prefix, none for the whole domain.
+func pathMatch(prefix string) []string {
+	if prefix == "" {
+		return nil
+	}
+	return []string{prefix, prefix + "/*"}
+}
+
+// routePathPrefix returns the path prefix a route matches, empty when it matches every path.
+func routePathPrefix(route Route) string {
+	for _, match := range route.Match {
+		for _, p := range match.Path {
+			if !strings.HasSuffix(p, "*") {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// sortRoutes orders routes by priority and then by the length of their path prefix, so that a
+// route below a path is tried before the route of the whole domain. Routes that compare equal
+// keep their order.
+func sortRoutes(routes []Route) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		_, pi, _ := parseRouteID(routes[i].ID)
+		_, pj, _ := parseRouteID(routes[j].ID)
+		if pi != pj {
+			return pi > pj
+		}
+		return len(routePathPrefix(routes[i])) > len(routePathPrefix(routes[j]))
+	})
+}
+
+// stripPrefix removes prefix from the path of requests before they reach handle.
+func stripPrefix(handle interface{}, prefix string) SubrouteHandle {
+	return SubrouteHandle{
+		Handler: "subroute",
+		Routes: []Route{
+			{
+				Handle: []interface{}{
+					RewriteHandle{
+						Handler:         "rewrite",
+						StripPathPrefix: prefix,
+					},
+					handle,
+				},
+			},
+		},
+	}
+}
+
+func routeMatchesHost(route Route, domains []string) bool {
+	for _, match := range route.Match {
+		for _, host := range match.Host {
+			for _, domain := range domains {
+				if strings.EqualFold(host, domain) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRouteID(t *testing.T) {
+	tests := []struct {
+		id           string
+		wantOwner    string
+		wantPriority int
+		ok           bool
+	}{
+		{id: routeID("6f1c2d4e-8b1a-4f0e-9a53-3c1d7e2b9f10", 0), wantOwner: "6f1c2d4e-8b1a-4f0e-9a53-3c1d7e2b9f10", ok: true},
+		{id: routeID("owner:with:colons", -5), wantOwner: "owner:with:colons", wantPriority: -5, ok: true},
+		{id: "nixopus-route:owner", ok: false},
+		{id: "nixopus-route:owner:high", ok: false},
+		{id: "caddygo-route", ok: false},
+		{id: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			owner, priority, ok := parseRouteID(tt.id)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.wantOwner, owner)
+			assert.Equal(t, tt.wantPriority, priority)
+		})
+	}
+}
+
+func TestSortRoutes(t *testing.T) {
+	route := func(id string, prefix string) Route {
+		return Route{ID: id, Match: []Match{{Host: []string{"example.com"}, Path: pathMatch(prefix)}}}
+	}
+	routes := []Route{
+		route(routeID("site", 0), ""),
+		route("", ""),
+		route(routeID("api", 0), "/api"),
+		route(routeID("docs", 0), "/api/docs"),
+		route(routeID("canary", 10), ""),
+		route(routeID("legacy", -1), "/legacy"),
+	}
+
+	sortRoutes(routes)
+
+	var order []string
+	for _, r := range routes {
+		owner, _, _ := parseRouteID(r.ID)
+		order = append(order, owner)
+	}
+	// the route without an owner has priority 0 and keeps its place among the whole domain routes
+	assert.Equal(t, []string{"canary", "docs", "api", "site", "", "legacy"}, order)
+}
+
+func TestRoutePathPrefix(t *testing.T) {
+	assert.Equal(t, "", routePathPrefix(Route{Match: []Match{{Host: []string{"example.com"}}}}))
+	assert.Equal(t, "/api", routePathPrefix(Route{Match: []Match{{Path: pathMatch("/api")}}}))
+	assert.Nil(t, pathMatch(""))
+}
+
+func TestPathPrefixRoute(t *testing.T) {
+	log := logger.NewLogger()
+	site := func(owner string, settings shared_types.ProxySettings) *Caddy {
+		c := NewCaddy(&log, "", "example.com", "", ReverseProxy)
+		c.Owner = owner
+		c.Upstream = "10.0.0.5:3000"
+		c.Middleware = Middleware{Settings: settings}
+		return c
+	}
+
+	t.Run("Match and strip", func(t *testing.T) {
+		route := site("api", shared_types.ProxySettings{PathPrefix: "/api", StripPathPrefix: true, RoutePriority: 2}).route()
+		assert.Equal(t, routeID("api", 2), route.ID)
+		assert.Equal(t, []Match{{Host: []string{"example.com"}, Path: []string{"/api", "/api/*"}}}, route.Match)
+
+		require.Len(t, route.Handle, 1)
+		strip := route.Handle[0].(SubrouteHandle)
+		rewrite := strip.Routes[0].Handle[0].(RewriteHandle)
+		assert.Equal(t, "/api", rewrite.StripPathPrefix)
+	})
+
+	t.Run("Routes of a shared host belong to their owner", func(t *testing.T) {
+		whole := site("site", shared_types.ProxySettings{})
+		api := site("api", shared_types.ProxySettings{PathPrefix: "/api"})
+		routes := []Route{whole.route(), api.route()}
+
+		other := api.otherRoutes(routes)
+		require.Len(t, other, 1)
+		assert.Equal(t, routes[0].ID, other[0].ID)
+	})
+
+	t.Run("Routes without owner are matched by host and path", func(t *testing.T) {
+		api := site("api", shared_types.ProxySettings{PathPrefix: "/api"})
+		hosts := api.Middleware.Hosts(api.Domain)
+		assert.True(t, api.ownsRoute(Route{Match: []Match{{Host: []string{"EXAMPLE.com"}, Path: pathMatch("/api")}}}, hosts))
+		assert.False(t, api.ownsRoute(Route{Match: []Match{{Host: []string{"example.com"}}}}, hosts))
+	})
+}
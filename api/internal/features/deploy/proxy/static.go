@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"path"
+	"strings"
+)
+
+// staticSiteHandle builds the subroute serving RootDir according to the StaticSite options.
+//
+// The subroute sets cache headers, rewrites unknown paths to /index.html when the SPA fallback
+// is enabled and then hands the request to file_server. A custom 404 page is served from the
+// subroute's error routes so that it keeps the 404 status code.
+func (c *Caddy) staticSiteHandle() SubrouteHandle {
+	var routes []Route
+
+	if c.StaticSite.CacheControl != "" {
+		// the inner handler sets the configured policy on every response, the outer one then
+		// overrides it for HTML documents so that a new release is picked up right away
+		routes = append(routes, Route{
+			Handle: []interface{}{
+				HeadersHandle{
+					Handler: "headers",
+					Response: &ResponseHeader{
+						Set:      map[string][]string{"Cache-Control": {"no-cache"}},
+						Deferred: true,
+						Require:  &ResponseMatch{Headers: map[string][]string{"Content-Type": {"text/html*"}}},
+					},
+				},
+				HeadersHandle{
+					Handler: "headers",
+					Response: &ResponseHeader{
+						Set:      map[string][]string{"Cache-Control": {c.StaticSite.CacheControl}},
+						Deferred: true,
+					},
+				},
+			},
+		})
+	}
+
+	if c.StaticSite.SPAFallback {
+		routes = append(routes, Route{
+			Match: []Match{
+				{
+					File: &FileMatch{
+						Root:     c.RootDir,
+						TryFiles: []string{"{http.request.uri.path}", "{http.request.uri.path}/index.html", "/index.html"},
+					},
+				},
+			},
+			Handle: []interface{}{
+				RewriteHandle{
+					Handler: "rewrite",
+					URI:     "{http.matchers.file.relative}",
+				},
+			},
+		})
+	}
+
+	fileServer := FileServerHandle{
+		Handler: string(FileServer),
+		Root:    c.RootDir,
+		Hide:    []string{".git"},
+	}
+	if c.StaticSite.Browse {
+		fileServer.Browse = &struct{}{}
+	}
+	routes = append(routes, Route{
+		Handle:   []interface{}{fileServer},
+		Terminal: true,
+	})
+
+	handle := SubrouteHandle{
+		Handler: "subroute",
+		Routes:  routes,
+	}
+
+	if notFound := strings.TrimPrefix(c.StaticSite.NotFoundPage, "/"); notFound != "" {
+		handle.Errors = &ErrorsConfig{
+			Routes: []Route{
+				{
+					Match: []Match{
+						{Expression: "{http.error.status_code} == 404"},
+					},
+					Handle: []interface{}{
+						RewriteHandle{
+							Handler: "rewrite",
+							URI:     path.Join("/", notFound),
+						},
+						FileServerHandle{
+							Handler:    string(FileServer),
+							Root:       c.RootDir,
+							StatusCode: "404",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return handle
+}
@@ -0,0 +1,79 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func staticSite(options StaticSiteOptions) SubrouteHandle {
+	c := &Caddy{RootDir: "/srv/static/current"}
+	c.StaticSite = options
+	return c.staticSiteHandle()
+}
+
+func TestStaticSiteHandle(t *testing.T) {
+	fileServer := Route{
+		Handle:   []interface{}{FileServerHandle{Handler: string(FileServer), Root: "/srv/static/current", Hide: []string{".git"}}},
+		Terminal: true,
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		handle := staticSite(StaticSiteOptions{})
+		assert.Equal(t, "subroute", handle.Handler)
+		assert.Equal(t, []Route{fileServer}, handle.Routes)
+		assert.Nil(t, handle.Errors)
+	})
+
+	t.Run("Directory browsing", func(t *testing.T) {
+		handle := staticSite(StaticSiteOptions{Browse: true})
+		require.Len(t, handle.Routes, 1)
+		server := handle.Routes[0].Handle[0].(FileServerHandle)
+		assert.NotNil(t, server.Browse)
+	})
+
+	t.Run("SPA fallback rewrites before serving files", func(t *testing.T) {
+		handle := staticSite(StaticSiteOptions{SPAFallback: true})
+		require.Len(t, handle.Routes, 2)
+		assert.Equal(t, Route{
+			Match: []Match{{File: &FileMatch{
+				Root:     "/srv/static/current",
+				TryFiles: []string{"{http.request.uri.path}", "{http.request.uri.path}/index.html", "/index.html"},
+			}}},
+			Handle: []interface{}{RewriteHandle{Handler: "rewrite", URI: "{http.matchers.file.relative}"}},
+		}, handle.Routes[0])
+		assert.Equal(t, fileServer, handle.Routes[1])
+	})
+
+	t.Run("Cache control revalidates HTML", func(t *testing.T) {
+		handle := staticSite(StaticSiteOptions{CacheControl: "public, max-age=31536000"})
+		require.Len(t, handle.Routes, 2)
+		headers := handle.Routes[0].Handle
+		require.Len(t, headers, 2)
+
+		html := headers[0].(HeadersHandle).Response
+		assert.Equal(t, map[string][]string{"Cache-Control": {"no-cache"}}, html.Set)
+		assert.Equal(t, &ResponseMatch{Headers: map[string][]string{"Content-Type": {"text/html*"}}}, html.Require)
+		assert.True(t, html.Deferred)
+
+		assets := headers[1].(HeadersHandle).Response
+		assert.Equal(t, map[string][]string{"Cache-Control": {"public, max-age=31536000"}}, assets.Set)
+		assert.Nil(t, assets.Require)
+		assert.True(t, assets.Deferred)
+	})
+
+	for _, page := range []string{"404.html", "/404.html"} {
+		t.Run("Not found page "+page, func(t *testing.T) {
+			handle := staticSite(StaticSiteOptions{NotFoundPage: page})
+			require.NotNil(t, handle.Errors)
+			assert.Equal(t, []Route{{
+				Match: []Match{{Expression: "{http.error.status_code} == 404"}},
+				Handle: []interface{}{
+					RewriteHandle{Handler: "rewrite", URI: "/404.html"},
+					FileServerHandle{Handler: string(FileServer), Root: "/srv/static/current", StatusCode: "404"},
+				},
+			}}, handle.Errors.Routes)
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package proxy
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	tlsAppPath                = "/config/apps/tls"
+	tlsConnectionPoliciesPath = "/config/apps/http/servers/nixopus/tls_connection_policies"
+
+	// certificateTagPrefix marks the certificates loaded by Nixopus, the rest of the tag is the domain.
+	certificateTagPrefix = "nixopus-certificate:"
+	// internalPolicySubject identifies the automation policy of the domains using the internal
+	// CA. It keeps the subject list of the policy from ever being empty, which Caddy would
+	// treat as a policy for every domain, when the caddygo client removes a deleted domain.
+	internalPolicySubject = "nixopus-internal-ca.invalid"
+
+	httpsPort        = "443"
+	handshakeTimeout = 10 * time.Second
+)
+
+// ManagedCertificate is how the certificate of a domain is configured in Caddy. ACME
+// certificates need no configuration, Caddy obtains them for the domains of the routes.
+type ManagedCertificate struct {
+	Domain      string
+	Mode        shared_types.CertificateMode
+	Certificate string
+	PrivateKey  string
+}
+
+type loadedCertificate struct {
+	Certificate string   `json:"certificate"`
+	Key         string   `json:"key"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// SyncCertificates rewrites the parts of the TLS configuration of Caddy owned by Nixopus so
+// that custom certificates are loaded and selected for their domain, and domains of the
+// internal mode are issued by the internal CA. Domains missing from certificates go back to
+// ACME. Everything else in the configuration, including the policies of the caddygo client,
+// is kept as is.
+func (c *Caddy) SyncCertificates(certificates []ManagedCertificate) error {
+	var custom []ManagedCertificate
+	var internal []string
+	for _, certificate := range certificates {
+		switch certificate.Mode {
+		case shared_types.CertificateModeCustom:
+			custom = append(custom, certificate)
+		case shared_types.CertificateModeInternal:
+			internal = append(internal, strings.ToLower(certificate.Domain))
+		}
+	}
+
+	var app map[string]json.RawMessage
+	exists, err := c.getConfigValue(tlsAppPath, &app)
+	if err != nil {
+		return err
+	}
+	if app == nil {
+		app = make(map[string]json.RawMessage)
+	}
+	if err := syncLoadedCertificates(app, custom); err != nil {
+		return err
+	}
+	if err := syncInternalPolicy(app, internal); err != nil {
+		return err
+	}
+	if exists || len(app) > 0 {
+		if err := c.setConfigValue(tlsAppPath, exists, app); err != nil {
+			return err
+		}
+	}
+
+	var policies []map[string]json.RawMessage
+	exists, err = c.getConfigValue(tlsConnectionPoliciesPath, &policies)
+	if err != nil {
+		return err
+	}
+	policies, err = syncConnectionPolicies(policies, custom)
+	if err != nil {
+		return err
+	}
+	if !exists && len(policies) == 0 {
+		return nil
+	}
+	return c.setConfigValue(tlsConnectionPoliciesPath, exists, policies)
+}
+
+// EnableAutomaticTLS adds domain to the subjects of the on-demand automation policy, like the
+// caddygo client does when it adds a domain, without replacing the routes of the domain, which
+// other applications may share below other paths.
+func (c *Caddy) EnableAutomaticTLS(domain string) error {
+	var app map[string]json.RawMessage
+	exists, err := c.getConfigValue(tlsAppPath, &app)
+	if err != nil {
+		return err
+	}
+	if app == nil {
+		app = make(map[string]json.RawMessage)
+	}
+	automation := make(map[string]json.RawMessage)
+	if err := decodeIfSet(app["automation"], &automation); err != nil {
+		return err
+	}
+	var policies []map[string]json.RawMessage
+	if err := decodeIfSet(automation["policies"], &policies); err != nil {
+		return err
+	}
+
+	var policy map[string]json.RawMessage
+	for _, p := range policies {
+		var onDemand bool
+		if err := decodeIfSet(p["on_demand"], &onDemand); err != nil {
+			return err
+		}
+		if onDemand {
+			policy = p
+			break
+		}
+	}
+	if policy == nil {
+		policy = map[string]json.RawMessage{
+			"on_demand": json.RawMessage(`true`),
+			"key_type":  json.RawMessage(`"p384"`),
+		}
+		policies = append(policies, policy)
+	}
+
+	var subjects []string
+	if err := decodeIfSet(policy["subjects"], &subjects); err != nil {
+		return err
+	}
+	if containsFold(subjects, domain) {
+		return nil
+	}
+	if err := encodeInto(policy, "subjects", append(subjects, strings.ToLower(domain))); err != nil {
+		return err
+	}
+	if err := encodeInto(automation, "policies", policies); err != nil {
+		return err
+	}
+	if err := encodeInto(app, "automation", automation); err != nil {
+		return err
+	}
+	return c.setConfigValue(tlsAppPath, exists, app)
+}
+
+// syncLoadedCertificates replaces the certificates Nixopus loaded into the tls app with custom.
+func syncLoadedCertificates(app map[string]json.RawMessage, custom []ManagedCertificate) error {
+	loaders := make(map[string]json.RawMessage)
+	if err := decodeIfSet(app["certificates"], &loaders); err != nil {
+		return err
+	}
+	var loaded []loadedCertificate
+	if err := decodeIfSet(loaders["load_pem"], &loaded); err != nil {
+		return err
+	}
+
+	var kept []loadedCertificate
+	for _, certificate := range loaded {
+		if !hasTagPrefix(certificate.Tags, certificateTagPrefix) {
+			kept = append(kept, certificate)
+		}
+	}
+	for _, certificate := range custom {
+		kept = append(kept, loadedCertificate{
+			Certificate: certificate.Certificate,
+			Key:         certificate.PrivateKey,
+			Tags:        []string{certificateTag(certificate.Domain)},
+		})
+	}
+
+	if len(kept) == 0 {
+		delete(loaders, "load_pem")
+	} else if err := encodeInto(loaders, "load_pem", kept); err != nil {
+		return err
+	}
+	if len(loaders) == 0 {
+		delete(app, "certificates")
+		return nil
+	}
+	return encodeInto(app, "certificates", loaders)
+}
+
+// syncInternalPolicy makes the first automation policy issue certificates for internal from
+// the internal CA. Caddy uses the first policy matching a domain, so the policy takes over
+// domains the caddygo client added to its own policies.
+func syncInternalPolicy(app map[string]json.RawMessage, internal []string) error {
+	automation := make(map[string]json.RawMessage)
+	if err := decodeIfSet(app["automation"], &automation); err != nil {
+		return err
+	}
+	var policies []map[string]json.RawMessage
+	if err := decodeIfSet(automation["policies"], &policies); err != nil {
+		return err
+	}
+
+	var kept []map[string]json.RawMessage
+	for _, policy := range policies {
+		var subjects []string
+		if err := decodeIfSet(policy["subjects"], &subjects); err != nil {
+			return err
+		}
+		if !containsFold(subjects, internalPolicySubject) {
+			kept = append(kept, policy)
+		}
+	}
+	if len(internal) > 0 {
+		policy := make(map[string]json.RawMessage)
+		if err := encodeInto(policy, "subjects", append([]string{internalPolicySubject}, internal...)); err != nil {
+			return err
+		}
+		policy["issuers"] = json.RawMessage(`[{"module":"internal"}]`)
+		kept = append([]map[string]json.RawMessage{policy}, kept...)
+	}
+
+	if len(kept) == 0 {
+		delete(automation, "policies")
+	} else if err := encodeInto(automation, "policies", kept); err != nil {
+		return err
+	}
+	if len(automation) == 0 {
+		delete(app, "automation")
+		return nil
+	}
+	return encodeInto(app, "automation", automation)
+}
+
+// syncConnectionPolicies selects the custom certificate of each domain by its tag. A catch-all
+// policy is kept last, once a server has connection policies Caddy refuses handshakes that
+// match none of them.
+func syncConnectionPolicies(policies []map[string]json.RawMessage, custom []ManagedCertificate) ([]map[string]json.RawMessage, error) {
+	var kept []map[string]json.RawMessage
+	hasCatchAll := false
+	for _, policy := range policies {
+		var selection struct {
+			AnyTag []string `json:"any_tag"`
+		}
+		if err := decodeIfSet(policy["certificate_selection"], &selection); err != nil {
+			return nil, err
+		}
+		if hasTagPrefix(selection.AnyTag, certificateTagPrefix) {
+			continue
+		}
+		if _, ok := policy["match"]; !ok {
+			hasCatchAll = true
+		}
+		kept = append(kept, policy)
+	}
+
+	var selected []map[string]json.RawMessage
+	for _, certificate := range custom {
+		policy := make(map[string]json.RawMessage)
+		if err := encodeInto(policy, "match", map[string][]string{"sni": {certificate.Domain}}); err != nil {
+			return nil, err
+		}
+		if err := encodeInto(policy, "certificate_selection", map[string][]string{"any_tag": {certificateTag(certificate.Domain)}}); err != nil {
+			return nil, err
+		}
+		selected = append(selected, policy)
+	}
+	if len(selected) > 0 && !hasCatchAll {
+		kept = append(kept, map[string]json.RawMessage{})
+	}
+	return append(selected, kept...), nil
+}
+
+// ServedCertificate connects to the HTTPS listener of Caddy with c.Domain as server name and
+// returns the certificate chain it presents. The chain is not verified here, so that the
+// certificate can be inspected even when it is self-signed or expired.
+func (c *Caddy) ServedCertificate() ([]*x509.Certificate, error) {
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid caddy endpoint: %w", err)
+	}
+
+	dialer := &net.Dialer{Timeout: handshakeTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(endpoint.Hostname(), httpsPort), &tls.Config{
+		ServerName:         c.Domain,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	chain := conn.ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no certificate is served for %s", c.Domain)
+	}
+	return chain, nil
+}
+
+// getConfigValue decodes the value at path of the Caddy config into v and reports whether it is set.
+func (c *Caddy) getConfigValue(path string, v interface{}) (bool, error) {
+	resp, err := c.client.Get(c.Endpoint + path)
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to get Caddy config %s: %s - %s", path, resp.Status, string(body))
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || string(body) == "null" {
+		return false, nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// setConfigValue replaces the value at path, creating it when it is not set yet.
+func (c *Caddy) setConfigValue(path string, exists bool, v interface{}) error {
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+
+	method := http.MethodPatch
+	if !exists {
+		method = http.MethodPut
+	}
+	req, err := http.NewRequest(method, c.Endpoint+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update Caddy config %s: %s - %s", path, resp.Status, string(body))
+	}
+	return nil
+}
+
+func certificateTag(domain string) string {
+	return certificateTagPrefix + strings.ToLower(domain)
+}
+
+func hasTagPrefix(tags []string, prefix string) bool {
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeIfSet(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func encodeInto(m map[string]json.RawMessage, key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	m[key] = raw
+	return nil
+}
@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncLoadedCertificates(t *testing.T) {
+	app := map[string]json.RawMessage{
+		"certificates": json.RawMessage(`{"load_pem":[
+			{"certificate":"operator","key":"operator-key"},
+			{"certificate":"old","key":"old-key","tags":["nixopus-certificate:old.example.com"]}
+		]}`),
+	}
+	require.NoError(t, syncLoadedCertificates(app, []ManagedCertificate{
+		{Domain: "shop.example.com", Certificate: "shop", PrivateKey: "shop-key"},
+	}))
+
+	var loaders struct {
+		LoadPEM []loadedCertificate `json:"load_pem"`
+	}
+	require.NoError(t, json.Unmarshal(app["certificates"], &loaders))
+	assert.Equal(t, []loadedCertificate{
+		{Certificate: "operator", Key: "operator-key"},
+		{Certificate: "shop", Key: "shop-key", Tags: []string{"nixopus-certificate:shop.example.com"}},
+	}, loaders.LoadPEM)
+
+	t.Run("Loaders are removed once empty", func(t *testing.T) {
+		app := map[string]json.RawMessage{
+			"certificates": json.RawMessage(`{"load_pem":[{"certificate":"old","key":"old-key","tags":["nixopus-certificate:old.example.com"]}]}`),
+		}
+		require.NoError(t, syncLoadedCertificates(app, nil))
+		assert.NotContains(t, app, "certificates")
+	})
+}
+
+func TestSyncInternalPolicy(t *testing.T) {
+	app := map[string]json.RawMessage{
+		"automation": json.RawMessage(`{"policies":[
+			{"subjects":["nixopus-internal-ca.invalid","old.example.com"],"issuers":[{"module":"internal"}]},
+			{"on_demand":true,"subjects":["shop.example.com"]}
+		]}`),
+	}
+	require.NoError(t, syncInternalPolicy(app, []string{"intranet.example.com"}))
+
+	var automation struct {
+		Policies []struct {
+			Subjects []string `json:"subjects"`
+			OnDemand bool     `json:"on_demand"`
+		} `json:"policies"`
+	}
+	require.NoError(t, json.Unmarshal(app["automation"], &automation))
+	require.Len(t, automation.Policies, 2)
+	// the internal policy comes first, Caddy uses the first policy matching a domain
+	assert.Equal(t, []string{internalPolicySubject, "intranet.example.com"}, automation.Policies[0].Subjects)
+	assert.True(t, automation.Policies[1].OnDemand)
+
+	require.NoError(t, syncInternalPolicy(app, nil))
+	require.NoError(t, json.Unmarshal(app["automation"], &automation))
+	assert.Len(t, automation.Policies, 1)
+}
+
+func TestSyncConnectionPolicies(t *testing.T) {
+	custom := []ManagedCertificate{{Domain: "shop.example.com"}}
+	stale := map[string]json.RawMessage{
+		"match":                 json.RawMessage(`{"sni":["old.example.com"]}`),
+		"certificate_selection": json.RawMessage(`{"any_tag":["nixopus-certificate:old.example.com"]}`),
+	}
+
+	policies, err := syncConnectionPolicies([]map[string]json.RawMessage{stale}, custom)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.JSONEq(t, `{"sni":["shop.example.com"]}`, string(policies[0]["match"]))
+	assert.JSONEq(t, `{"any_tag":["nixopus-certificate:shop.example.com"]}`, string(policies[0]["certificate_selection"]))
+	// a catch-all policy keeps the handshakes of the other domains working
+	assert.Empty(t, policies[1])
+
+	t.Run("Existing catch-all is kept", func(t *testing.T) {
+		catchAll := map[string]json.RawMessage{"protocol_min": json.RawMessage(`"tls1.2"`)}
+		policies, err := syncConnectionPolicies([]map[string]json.RawMessage{catchAll}, custom)
+		require.NoError(t, err)
+		require.Len(t, policies, 2)
+		assert.Equal(t, catchAll, policies[1])
+	})
+}
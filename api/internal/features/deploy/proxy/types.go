@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
@@ -12,8 +13,24 @@ type Caddy struct {
 	RootDir        string
 	Domain         string
 	Port           string
+	Upstream       string
+	Owner          string
 	client         *http.Client
 	FileServerType FileServerType
+	StaticSite     StaticSiteOptions
+	Middleware     Middleware
+}
+
+// StaticSiteOptions controls how a file_server route serves a static site.
+type StaticSiteOptions struct {
+	// Browse enables directory listings for directories without an index file.
+	Browse bool
+	// SPAFallback serves /index.html for paths that do not match a file, so client side routers work.
+	SPAFallback bool
+	// NotFoundPage is a page relative to the site root that is served with a 404 status for missing files.
+	NotFoundPage string
+	// CacheControl is the Cache-Control header set on assets. HTML documents are always revalidated.
+	CacheControl string
 }
 
 type FileServerType string
@@ -29,12 +46,16 @@ type CaddyConfig struct {
 			Servers map[string]Server `json:"servers,omitempty"`
 		} `json:"http,omitempty"`
 	} `json:"apps,omitempty"`
+	// Logging is kept as is when the config is loaded again
+	Logging json.RawMessage `json:"logging,omitempty"`
 }
 
 type Server struct {
 	Listen         []string       `json:"listen,omitempty"`
 	Routes         []Route        `json:"routes,omitempty"`
 	AutomaticHTTPS AutomaticHTTPS `json:"automatic_https,omitempty"`
+	// Logs maps the hosts of the server to access loggers, kept as is
+	Logs json.RawMessage `json:"logs,omitempty"`
 }
 
 type AutomaticHTTPS struct {
@@ -43,30 +64,132 @@ type AutomaticHTTPS struct {
 }
 
 type Route struct {
-	Match  []Match       `json:"match,omitempty"`
-	Handle []interface{} `json:"handle,omitempty"`
+	ID       string        `json:"@id,omitempty"`
+	Match    []Match       `json:"match,omitempty"`
+	Handle   []interface{} `json:"handle,omitempty"`
+	Terminal bool          `json:"terminal,omitempty"`
 }
 
 type Match struct {
-	Host []string `json:"host,omitempty"`
+	Host       []string            `json:"host,omitempty"`
+	Path       []string            `json:"path,omitempty"`
+	Protocol   string              `json:"protocol,omitempty"`
+	File       *FileMatch          `json:"file,omitempty"`
+	Expression string              `json:"expression,omitempty"`
+	Header     map[string][]string `json:"header,omitempty"`
+	RemoteIP   *RemoteIPMatch      `json:"remote_ip,omitempty"`
+	// Not matches the requests matching none of its matcher sets
+	Not []Match `json:"not,omitempty"`
+}
+
+type RemoteIPMatch struct {
+	Ranges []string `json:"ranges,omitempty"`
+}
+
+type FileMatch struct {
+	Root     string   `json:"root,omitempty"`
+	TryFiles []string `json:"try_files,omitempty"`
 }
 
 type SubrouteHandle struct {
-	Handler string  `json:"handler,omitempty"`
-	Routes  []Route `json:"routes,omitempty"`
+	Handler string        `json:"handler,omitempty"`
+	Routes  []Route       `json:"routes,omitempty"`
+	Errors  *ErrorsConfig `json:"errors,omitempty"`
+}
+
+type ErrorsConfig struct {
+	Routes []Route `json:"routes,omitempty"`
 }
 
 type FileServerHandle struct {
-	Handler string   `json:"handler,omitempty"`
-	Root    string   `json:"root,omitempty"`
-	Browse  struct{} `json:"browse,omitempty"`
+	Handler    string    `json:"handler,omitempty"`
+	Root       string    `json:"root,omitempty"`
+	Browse     *struct{} `json:"browse,omitempty"`
+	Hide       []string  `json:"hide,omitempty"`
+	StatusCode string    `json:"status_code,omitempty"`
+}
+
+type RewriteHandle struct {
+	Handler         string `json:"handler,omitempty"`
+	URI             string `json:"uri,omitempty"`
+	StripPathPrefix string `json:"strip_path_prefix,omitempty"`
+}
+
+type HeadersHandle struct {
+	Handler  string          `json:"handler,omitempty"`
+	Response *ResponseHeader `json:"response,omitempty"`
+}
+
+type ResponseHeader struct {
+	Set      map[string][]string `json:"set,omitempty"`
+	Deferred bool                `json:"deferred,omitempty"`
+	Require  *ResponseMatch      `json:"require,omitempty"`
+}
+
+type ResponseMatch struct {
+	Headers map[string][]string `json:"headers,omitempty"`
 }
 
 type ReverseProxyHandle struct {
-	Handler   string     `json:"handler,omitempty"`
-	Upstreams []Upstream `json:"upstreams,omitempty"`
+	Handler   string               `json:"handler,omitempty"`
+	Transport *HTTPTransport       `json:"transport,omitempty"`
+	Upstreams []Upstream           `json:"upstreams,omitempty"`
+	Headers   *ReverseProxyHeaders `json:"headers,omitempty"`
+}
+
+// HTTPTransport configures how reverse_proxy connects to its upstreams. Durations are Caddy
+// duration strings such as "30s".
+type HTTPTransport struct {
+	Protocol              string `json:"protocol"`
+	DialTimeout           string `json:"dial_timeout,omitempty"`
+	ResponseHeaderTimeout string `json:"response_header_timeout,omitempty"`
+}
+
+type ReverseProxyHeaders struct {
+	Request *HeaderOps `json:"request,omitempty"`
+}
+
+type HeaderOps struct {
+	Set map[string][]string `json:"set,omitempty"`
+}
+
+type StaticResponseHandle struct {
+	Handler    string              `json:"handler,omitempty"`
+	StatusCode int                 `json:"status_code,omitempty"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
 }
 
 type Upstream struct {
 	Dial string `json:"dial,omitempty"`
 }
+
+type AuthenticationHandle struct {
+	Handler   string        `json:"handler,omitempty"`
+	Providers AuthProviders `json:"providers"`
+}
+
+type AuthProviders struct {
+	HTTPBasic *HTTPBasicAuth `json:"http_basic,omitempty"`
+}
+
+type HTTPBasicAuth struct {
+	Accounts []BasicAuthAccount `json:"accounts,omitempty"`
+	Realm    string             `json:"realm,omitempty"`
+}
+
+type BasicAuthAccount struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type RequestBodyHandle struct {
+	Handler string `json:"handler,omitempty"`
+	MaxSize int64  `json:"max_size,omitempty"`
+}
+
+type EncodeHandle struct {
+	Handler   string              `json:"handler,omitempty"`
+	Encodings map[string]struct{} `json:"encodings,omitempty"`
+	Prefer    []string            `json:"prefer,omitempty"`
+}
@@ -121,6 +121,10 @@ func (s *DeployService) StartDeploymentInBackground(
 		DeploymentType: string(d.deployment.Type),
 		Branch:         d.application.Branch,
 		ApplicationID:  d.application.ID.String(),
+		Submodules:     d.application.GitSubmodules,
+		LFS:            d.application.GitLFS,
+		CloneDepth:     d.application.CloneDepth,
+		CloneFilter:    d.application.CloneFilter,
 	}
 
 	// we will pass the commit hash to the clone repository function for rollback feature
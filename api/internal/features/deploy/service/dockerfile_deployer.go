@@ -2,6 +2,9 @@ package service
 
 import (
 	"fmt"
+	"net"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
@@ -15,12 +18,17 @@ func (s *DeployService) handleDockerfileDeployment(d DeployerConfig) error {
 		return err
 	}
 
-	caddyProxy := proxy.NewCaddy(&s.logger, d.contextPath, d.application.Domain, availablePort, proxy.ReverseProxy)
-	if err := caddyProxy.Serve(); err != nil {
-		s.addLog(d.application.ID, fmt.Sprintf("Failed to start Caddy proxy: %v", err), d.deployment_config.ID)
+	site := proxy.Site{
+		Owner:      d.application.ID.String(),
+		Domain:     d.application.Domain,
+		Middleware: proxy.ApplicationMiddleware(d.application),
+		Upstream:   net.JoinHostPort(config.AppConfig.SSH.Host, availablePort),
+	}
+	if err := proxy.NewProvider(d.application.ProxyServer, &s.logger).AddRoute(site); err != nil {
+		s.addLog(d.application.ID, fmt.Sprintf("Failed to configure the proxy: %v", err), d.deployment_config.ID)
 		return err
 	}
-	s.addLog(d.application.ID, "Caddy proxy started successfully", d.deployment_config.ID)
+	s.addLog(d.application.ID, "Proxy configured successfully", d.deployment_config.ID)
 
 	return nil
 }
@@ -0,0 +1,9 @@
+package service
+
+import (
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (s *DeployService) GetPortAllocations() ([]shared_types.PortAllocation, error) {
+	return s.storage.GetPortAllocations()
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetServerPorts lists the ports published on each server, the HTTP ports the proxy routes to
+// as well as the exposed TCP and UDP ports, ordered by server and published port.
+func (s *DeployService) GetServerPorts() ([]types.ServerPorts, error) {
+	allocations, err := s.storage.GetPortAllocations()
+	if err != nil {
+		return nil, err
+	}
+	exposed, err := s.storage.GetExposedPorts()
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []types.ServerPorts
+	index := make(map[string]int)
+	add := func(serverHost string, port types.PublishedPort) {
+		i, ok := index[serverHost]
+		if !ok {
+			i = len(servers)
+			index[serverHost] = i
+			servers = append(servers, types.ServerPorts{ServerHost: serverHost})
+		}
+		servers[i].Ports = append(servers[i].Ports, port)
+	}
+
+	for _, allocation := range allocations {
+		port := types.PublishedPort{
+			ApplicationID: allocation.ApplicationID,
+			Kind:          types.PublishedPortHTTP,
+			Protocol:      shared_types.PortProtocolTCP,
+			PublishedPort: allocation.Port,
+		}
+		if allocation.Application != nil {
+			port.ApplicationName = allocation.Application.Name
+			port.TargetPort = allocation.Application.Port
+		}
+		add(allocation.ServerHost, port)
+	}
+	for _, exposedPort := range exposed {
+		port := types.PublishedPort{
+			ApplicationID: exposedPort.ApplicationID,
+			Kind:          types.PublishedPortExposed,
+			Protocol:      exposedPort.Protocol,
+			PublishedPort: exposedPort.PublishedPort,
+			TargetPort:    exposedPort.TargetPort,
+			SNI:           exposedPort.SNI,
+		}
+		if exposedPort.Application != nil {
+			port.ApplicationName = exposedPort.Application.Name
+		}
+		add(exposedPort.ServerHost, port)
+	}
+
+	sort.Slice(servers, func(a, b int) bool {
+		return servers[a].ServerHost < servers[b].ServerHost
+	})
+	for i := range servers {
+		ports := servers[i].Ports
+		sort.SliceStable(ports, func(a, b int) bool {
+			return ports[a].PublishedPort < ports[b].PublishedPort
+		})
+	}
+	return servers, nil
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
@@ -12,12 +11,16 @@ import (
 func (s *DeployService) handleStaticDeployment(d DeployerConfig) error {
 	s.addLog(d.application.ID, "Using static file deployment strategy", d.deployment_config.ID)
 
-	caddyProxy := proxy.NewCaddy(&s.logger, d.contextPath, d.application.Domain, strconv.Itoa(443), proxy.FileServer)
-	if err := caddyProxy.Serve(); err != nil {
-		s.addLog(d.application.ID, fmt.Sprintf("Failed to start Caddy proxy: %v", err), d.deployment_config.ID)
+	site := proxy.Site{
+		Owner:      d.application.ID.String(),
+		Domain:     d.application.Domain,
+		Middleware: proxy.ApplicationMiddleware(d.application),
+	}
+	if err := proxy.NewProvider(d.application.ProxyServer, &s.logger).ServeFiles(site, d.contextPath, proxy.StaticSiteOptions{Browse: true}); err != nil {
+		s.addLog(d.application.ID, fmt.Sprintf("Failed to configure the proxy: %v", err), d.deployment_config.ID)
 		return err
 	}
-	s.addLog(d.application.ID, "Caddy proxy started successfully", d.deployment_config.ID)
+	s.addLog(d.application.ID, "Proxy configured successfully", d.deployment_config.ID)
 
 	s.updateStatus(d.deployment_config.ID, shared_types.Deployed, d.appStatus.ID)
 	s.addLog(d.application.ID, types.LogDeploymentCompletedSuccessfully, d.deployment_config.ID)
@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -41,6 +43,36 @@ type DeployRepository interface {
 	GetDeploymentLogs(deploymentID string, page, pageSize int, level string, startTime, endTime time.Time, searchTerm string) ([]shared_types.ApplicationLogs, int, error)
 	GetApplicationByRepositoryID(repositoryID uint64) (shared_types.Application, error)
 	GetApplicationByRepositoryIDAndBranch(repositoryID uint64, branch string) ([]shared_types.Application, error)
+	AddApplicationWithPort(application *shared_types.Application, serverHost string, options types.AllocatePortOptions) (*shared_types.PortAllocation, error)
+	AllocatePort(applicationID uuid.UUID, serverHost string, options types.AllocatePortOptions) (*shared_types.PortAllocation, error)
+	FindPortAllocation(applicationID uuid.UUID, serverHost string) (*shared_types.PortAllocation, error)
+	ReleasePorts(applicationID uuid.UUID) error
+	GetPortAllocations() ([]shared_types.PortAllocation, error)
+	AddApplicationPort(port *shared_types.ApplicationPort) error
+	DeleteApplicationPort(portID uuid.UUID) error
+	GetApplicationPorts(applicationID uuid.UUID) ([]shared_types.ApplicationPort, error)
+	GetSNIPorts(serverHost string) ([]shared_types.ApplicationPort, error)
+	GetExposedPorts() ([]shared_types.ApplicationPort, error)
+	StartDeploymentAttempt(deploymentID uuid.UUID) (shared_types.ApplicationDeployment, error)
+	SetDeploymentPhase(deploymentID uuid.UUID, phase shared_types.DeploymentPhase) error
+	GetGithubDeploymentID(deploymentID uuid.UUID) (int64, error)
+	SetGithubDeploymentID(deploymentID uuid.UUID, githubDeploymentID int64) error
+	FindApplicationByID(applicationID uuid.UUID) (shared_types.Application, error)
+	SetApplicationRunState(applicationID uuid.UUID, state shared_types.RunState) error
+	GetScaleToZeroApplications() ([]shared_types.Application, error)
+	GetLastRequestTimes(applicationIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
+	GetRoutedApplications() ([]shared_types.Application, error)
+	GetApplicationDatabaseLinks(applicationID uuid.UUID) ([]shared_types.ApplicationDatabase, error)
+	UpdateProxySettings(applicationID uuid.UUID, settings shared_types.ProxySettings, passwordHash string) error
+	UpdateMaintenance(applicationID uuid.UUID, settings shared_types.MaintenanceSettings) error
+	GetOrganizationDomain(domainID uuid.UUID, organizationID uuid.UUID) (shared_types.Domain, error)
+	AddApplicationDomain(domain *shared_types.ApplicationDomain) error
+	UpdateApplicationDomainMode(domainID uuid.UUID, mode shared_types.DomainMode) error
+	SetPrimaryApplicationDomain(applicationID uuid.UUID, domainID uuid.UUID) error
+	DeleteApplicationDomain(domainID uuid.UUID) error
+	IsDomainVerifiedByAnotherOrganization(host string, organizationID uuid.UUID) (bool, error)
+	GetOrganizationDomainID(host string, organizationID uuid.UUID) (*uuid.UUID, error)
+	IsRouteTaken(host string, pathPrefix string, applicationID uuid.UUID, organizationID uuid.UUID) (bool, error)
 }
 
 func (s *DeployStorage) IsNameAlreadyTaken(name string) (bool, error) {
@@ -54,12 +86,14 @@ func (s *DeployStorage) IsNameAlreadyTaken(name string) (bool, error) {
 	return count > 0, err
 }
 
+// IsDomainAlreadyTaken reports whether domain is the primary domain or an alias of any application.
 func (s *DeployStorage) IsDomainAlreadyTaken(domain string) (bool, error) {
 	var count int
 	err := s.DB.NewSelect().
 		TableExpr("applications").
 		ColumnExpr("count(*)").
-		Where("domain = ?", domain).
+		Where("LOWER(domain) = LOWER(?)", domain).
+		WhereOr("id IN (SELECT application_id FROM application_domains WHERE LOWER(name) = LOWER(?))", domain).
 		Scan(s.Ctx, &count)
 
 	return count > 0, err
@@ -80,12 +114,90 @@ func (s *DeployStorage) IsDomainValid(domain string) (bool, error) {
 	return true, nil
 }
 
+// AddApplication inserts an application together with the record of its primary domain.
 func (s *DeployStorage) AddApplication(application *shared_types.Application) error {
-	_, err := s.DB.NewInsert().Model(application).Exec(s.Ctx)
+	return s.DB.RunInTx(s.Ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return insertApplication(ctx, tx, application)
+	})
+}
+
+// AddApplicationWithPort inserts an application like AddApplication and allocates its host port
+// on the server in the same transaction, so an application is never left without a port.
+func (s *DeployStorage) AddApplicationWithPort(application *shared_types.Application, serverHost string, options types.AllocatePortOptions) (*shared_types.PortAllocation, error) {
+	var allocation *shared_types.PortAllocation
+	err := s.DB.RunInTx(s.Ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := insertApplication(ctx, tx, application); err != nil {
+			return err
+		}
+		var err error
+		allocation, err = allocatePort(ctx, tx, application.ID, serverHost, options)
+		return err
+	})
 	if err != nil {
+		return nil, err
+	}
+	return allocation, nil
+}
+
+func insertApplication(ctx context.Context, tx bun.Tx, application *shared_types.Application) error {
+	if _, err := tx.NewInsert().Model(application).Exec(ctx); err != nil {
 		return err
 	}
-	return nil
+	if application.Domain == "" {
+		return nil
+	}
+
+	primary := &shared_types.ApplicationDomain{
+		ID:            uuid.New(),
+		ApplicationID: application.ID,
+		Name:          application.Domain,
+		IsPrimary:     true,
+		Mode:          shared_types.DomainModeServe,
+		PathPrefix:    application.ProxySettings.PathPrefix,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	domainID, err := organizationDomainID(ctx, tx, application.Domain, application.OrganizationID)
+	if err != nil {
+		return err
+	}
+	primary.DomainID = domainID
+
+	_, err = tx.NewInsert().Model(primary).Exec(ctx)
+	return err
+}
+
+// domainCoversHost matches domains that are host itself or one of its parents.
+const domainCoversHost = "(LOWER(?) = LOWER(TRIM(LEADING '*.' FROM name)) OR LOWER(?) LIKE '%.' || LOWER(TRIM(LEADING '*.' FROM name)))"
+
+// organizationDomainID returns the most specific verified domain of the organization host
+// belongs to, or nil when the host is not under any of them.
+func organizationDomainID(ctx context.Context, db bun.IDB, host string, organizationID uuid.UUID) (*uuid.UUID, error) {
+	var domain shared_types.Domain
+	err := db.NewSelect().
+		Model(&domain).
+		Column("id").
+		Where("organization_id = ? AND deleted_at IS NULL AND verified_at IS NOT NULL", organizationID).
+		Where(domainCoversHost, host, host).
+		OrderExpr("LENGTH(name) DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ID, nil
+}
+
+// orderDomains lists the primary domain of an application first.
+func orderDomains(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.Order("is_primary DESC", "name ASC")
+}
+
+func orderPorts(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.Order("protocol ASC", "target_port ASC")
 }
 
 func (s *DeployStorage) UpdateApplication(application *shared_types.Application) error {
@@ -114,6 +226,57 @@ func (s *DeployStorage) UpdateApplicationDeployment(deployment *shared_types.App
 	return nil
 }
 
+// StartDeploymentAttempt increments the attempt counter of a deployment and returns the
+// updated record, including the last phase completed by previous attempts.
+func (s *DeployStorage) StartDeploymentAttempt(deploymentID uuid.UUID) (shared_types.ApplicationDeployment, error) {
+	var deployment shared_types.ApplicationDeployment
+	err := s.DB.NewUpdate().
+		Model(&deployment).
+		Set("attempts = attempts + 1").
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", deploymentID).
+		Returning("*").
+		Scan(s.Ctx)
+	if err != nil {
+		return shared_types.ApplicationDeployment{}, err
+	}
+	return deployment, nil
+}
+
+// SetDeploymentPhase records the last phase a deployment completed.
+func (s *DeployStorage) SetDeploymentPhase(deploymentID uuid.UUID, phase shared_types.DeploymentPhase) error {
+	_, err := s.DB.NewUpdate().
+		Table("application_deployment").
+		Set("completed_phase = ?", phase).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", deploymentID).
+		Exec(s.Ctx)
+	return err
+}
+
+// GetGithubDeploymentID returns the GitHub deployment a deployment reports to, 0 if none was created yet.
+func (s *DeployStorage) GetGithubDeploymentID(deploymentID uuid.UUID) (int64, error) {
+	var githubDeploymentID sql.NullInt64
+	err := s.DB.NewSelect().
+		Table("application_deployment").
+		Column("github_deployment_id").
+		Where("id = ?", deploymentID).
+		Scan(s.Ctx, &githubDeploymentID)
+	if err != nil {
+		return 0, err
+	}
+	return githubDeploymentID.Int64, nil
+}
+
+func (s *DeployStorage) SetGithubDeploymentID(deploymentID uuid.UUID, githubDeploymentID int64) error {
+	_, err := s.DB.NewUpdate().
+		Table("application_deployment").
+		Set("github_deployment_id = ?", githubDeploymentID).
+		Where("id = ?", deploymentID).
+		Exec(s.Ctx)
+	return err
+}
+
 func (s *DeployStorage) AddApplicationDeploymentStatus(deployment_status *shared_types.ApplicationDeploymentStatus) error {
 	_, err := s.DB.NewInsert().Model(deployment_status).Exec(s.Ctx)
 	if err != nil {
@@ -171,6 +334,7 @@ func (s *DeployStorage) GetApplications(page, pageSize int, organizationID uuid.
 		Model(&applications).
 		Relation("Status").
 		Relation("Logs").
+		Relation("Domains", orderDomains).
 		Order("created_at DESC").
 		Limit(pageSize).
 		Offset(offset).
@@ -190,6 +354,8 @@ func (s *DeployStorage) GetApplicationById(id string, organizationID uuid.UUID)
 	err := s.DB.NewSelect().
 		Model(&application).
 		Relation("Status").
+		Relation("Domains", orderDomains).
+		Relation("Ports", orderPorts).
 		Where("a.id = ? AND a.organization_id = ?", id, organizationID).
 		Scan(s.Ctx)
 
@@ -260,6 +426,11 @@ func (s *DeployStorage) DeleteDeployment(deployment *types.DeleteDeploymentReque
 		return err
 	}
 
+	err = s.ReleasePorts(deployment.ID)
+	if err != nil {
+		return err
+	}
+
 	_, err = s.DB.NewDelete().
 		Table("applications").
 		Where("id = ?", deployment.ID).
@@ -422,3 +593,540 @@ func (s *DeployStorage) GetApplicationByRepositoryIDAndBranch(repositoryID uint6
 
 	return applications, nil
 }
+
+// AllocatePort reserves a host port on the given server for the application.
+//
+// The allocation runs in a transaction holding an advisory lock scoped to the server,
+// so concurrent deployments cannot pick the same port. If the application already
+// holds a port on the server the existing allocation is returned unchanged. Otherwise the
+// preferred port of options is allocated when it is free, and a random port of the range that
+// is neither allocated, exposed nor in use on the server is picked when it is not. The unique
+// constraints on the table guard against any allocation made outside this method.
+func (s *DeployStorage) AllocatePort(applicationID uuid.UUID, serverHost string, options types.AllocatePortOptions) (*shared_types.PortAllocation, error) {
+	var allocation *shared_types.PortAllocation
+	err := s.DB.RunInTx(s.Ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		allocation, err = allocatePort(ctx, tx, applicationID, serverHost, options)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allocation, nil
+}
+
+func allocatePort(ctx context.Context, tx bun.Tx, applicationID uuid.UUID, serverHost string, options types.AllocatePortOptions) (*shared_types.PortAllocation, error) {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", "port_allocations:"+serverHost); err != nil {
+		return nil, fmt.Errorf("failed to lock port allocations: %w", err)
+	}
+
+	var existing shared_types.PortAllocation
+	err := tx.NewSelect().
+		Model(&existing).
+		Where("application_id = ? AND server_host = ?", applicationID, serverHost).
+		Scan(ctx)
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	port := 0
+	if options.Preferred > 0 {
+		taken, err := portTaken(ctx, tx, serverHost, options.Preferred)
+		if err != nil {
+			return nil, err
+		}
+		if !taken {
+			port = options.Preferred
+		}
+	}
+	if port == 0 {
+		query := tx.NewSelect().
+			TableExpr("generate_series(?, ?) AS p", types.PortRangeStart, types.PortRangeEnd).
+			ColumnExpr("p").
+			Where("p NOT IN (SELECT port FROM port_allocations WHERE server_host = ?)", serverHost).
+			Where("p NOT IN (SELECT published_port FROM application_ports WHERE server_host = ? AND protocol = ?)", serverHost, shared_types.PortProtocolTCP)
+		if len(options.InUse) > 0 {
+			query = query.Where("p NOT IN (?)", bun.In(options.InUse))
+		}
+		err = query.OrderExpr("random()").Limit(1).Scan(ctx, &port)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, types.ErrNoAvailablePorts
+			}
+			return nil, err
+		}
+	}
+
+	allocation := &shared_types.PortAllocation{
+		ID:            uuid.New(),
+		ServerHost:    serverHost,
+		Port:          port,
+		ApplicationID: applicationID,
+		CreatedAt:     time.Now(),
+	}
+	if _, err := tx.NewInsert().Model(allocation).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return allocation, nil
+}
+
+// portTaken reports whether port is allocated to or exposed by an application of the server.
+func portTaken(ctx context.Context, tx bun.Tx, serverHost string, port int) (bool, error) {
+	allocated, err := tx.NewSelect().
+		Model((*shared_types.PortAllocation)(nil)).
+		Where("server_host = ? AND port = ?", serverHost, port).
+		Exists(ctx)
+	if err != nil || allocated {
+		return allocated, err
+	}
+	return tx.NewSelect().
+		Model((*shared_types.ApplicationPort)(nil)).
+		Where("server_host = ? AND protocol = ? AND published_port = ?", serverHost, shared_types.PortProtocolTCP, port).
+		Exists(ctx)
+}
+
+// FindPortAllocation returns the port the application holds on the server, nil when it holds
+// none. Unlike AllocatePort it never allocates one.
+func (s *DeployStorage) FindPortAllocation(applicationID uuid.UUID, serverHost string) (*shared_types.PortAllocation, error) {
+	var allocation shared_types.PortAllocation
+	err := s.DB.NewSelect().
+		Model(&allocation).
+		Where("application_id = ? AND server_host = ?", applicationID, serverHost).
+		Scan(s.Ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &allocation, nil
+}
+
+// ReleasePorts removes every port allocation held by the application.
+func (s *DeployStorage) ReleasePorts(applicationID uuid.UUID) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.PortAllocation)(nil)).
+		Where("application_id = ?", applicationID).
+		Exec(s.Ctx)
+	return err
+}
+
+// GetPortAllocations returns all port allocations across servers, ordered by server and port.
+func (s *DeployStorage) GetPortAllocations() ([]shared_types.PortAllocation, error) {
+	var allocations []shared_types.PortAllocation
+	err := s.DB.NewSelect().
+		Model(&allocations).
+		Relation("Application", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "name", "domain", "port", "organization_id")
+		}).
+		Order("pa.server_host ASC", "pa.port ASC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get port allocations: %w", err)
+	}
+
+	return allocations, nil
+}
+
+// AddApplicationPort exposes a port of an application on its server.
+//
+// Like AllocatePort it holds the advisory lock of the server, so that a fixed port is never
+// published twice and ports routed by SNI are allocated from the range of the HTTP ports
+// without colliding with them.
+func (s *DeployStorage) AddApplicationPort(port *shared_types.ApplicationPort) error {
+	tx, err := s.DB.BeginTx(s.Ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(s.Ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", "port_allocations:"+port.ServerHost); err != nil {
+		return fmt.Errorf("failed to lock port allocations: %w", err)
+	}
+
+	exposed, err := tx.NewSelect().
+		Model((*shared_types.ApplicationPort)(nil)).
+		Where("application_id = ? AND protocol = ? AND target_port = ?", port.ApplicationID, port.Protocol, port.TargetPort).
+		Exists(s.Ctx)
+	if err != nil {
+		return err
+	}
+	if exposed {
+		return types.ErrTargetPortAlreadyExposed
+	}
+
+	if port.SNI != "" {
+		taken, err := tx.NewSelect().
+			Model((*shared_types.ApplicationPort)(nil)).
+			Where("LOWER(sni) = LOWER(?)", port.SNI).
+			Exists(s.Ctx)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return types.ErrSNIAlreadyTaken
+		}
+
+		err = tx.NewSelect().
+			TableExpr("generate_series(?, ?) AS p", types.PortRangeStart, types.PortRangeEnd).
+			ColumnExpr("p").
+			Where("p NOT IN (SELECT port FROM port_allocations WHERE server_host = ?)", port.ServerHost).
+			Where("p NOT IN (SELECT published_port FROM application_ports WHERE server_host = ? AND protocol = ?)", port.ServerHost, port.Protocol).
+			OrderExpr("random()").
+			Limit(1).
+			Scan(s.Ctx, &port.PublishedPort)
+		if errors.Is(err, sql.ErrNoRows) {
+			return types.ErrNoAvailablePorts
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		taken, err := tx.NewSelect().
+			Model((*shared_types.ApplicationPort)(nil)).
+			Where("server_host = ? AND protocol = ? AND published_port = ?", port.ServerHost, port.Protocol, port.PublishedPort).
+			Exists(s.Ctx)
+		if err != nil {
+			return err
+		}
+		if !taken && port.Protocol == shared_types.PortProtocolTCP {
+			taken, err = tx.NewSelect().
+				Model((*shared_types.PortAllocation)(nil)).
+				Where("server_host = ? AND port = ?", port.ServerHost, port.PublishedPort).
+				Exists(s.Ctx)
+			if err != nil {
+				return err
+			}
+		}
+		if taken {
+			return types.ErrPublishedPortTaken
+		}
+	}
+
+	if _, err := tx.NewInsert().Model(port).Exec(s.Ctx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteApplicationPort stops exposing a port, its published port can be reused right away.
+func (s *DeployStorage) DeleteApplicationPort(portID uuid.UUID) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.ApplicationPort)(nil)).
+		Where("id = ?", portID).
+		Exec(s.Ctx)
+	return err
+}
+
+// GetApplicationPorts returns the ports exposed by an application.
+func (s *DeployStorage) GetApplicationPorts(applicationID uuid.UUID) ([]shared_types.ApplicationPort, error) {
+	var ports []shared_types.ApplicationPort
+	err := s.DB.NewSelect().
+		Model(&ports).
+		Where("application_id = ?", applicationID).
+		Order("protocol ASC", "target_port ASC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application ports: %w", err)
+	}
+	return ports, nil
+}
+
+// GetSNIPorts returns the ports of every application of a server that are routed by SNI.
+func (s *DeployStorage) GetSNIPorts(serverHost string) ([]shared_types.ApplicationPort, error) {
+	var ports []shared_types.ApplicationPort
+	err := s.DB.NewSelect().
+		Model(&ports).
+		Where("server_host = ? AND sni <> ''", serverHost).
+		Order("sni ASC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sni ports: %w", err)
+	}
+	return ports, nil
+}
+
+// GetExposedPorts returns the ports exposed by applications across servers, ordered by server
+// and published port.
+func (s *DeployStorage) GetExposedPorts() ([]shared_types.ApplicationPort, error) {
+	var ports []shared_types.ApplicationPort
+	err := s.DB.NewSelect().
+		Model(&ports).
+		Relation("Application", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Column("id", "name", "domain", "organization_id")
+		}).
+		Order("ap.server_host ASC", "ap.published_port ASC", "ap.protocol ASC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exposed ports: %w", err)
+	}
+	return ports, nil
+}
+
+// FindApplicationByID returns an application regardless of the organization it belongs to.
+// It is meant for background work, like waking a sleeping application, that runs without a user.
+func (s *DeployStorage) FindApplicationByID(applicationID uuid.UUID) (shared_types.Application, error) {
+	var application shared_types.Application
+	err := s.DB.NewSelect().
+		Model(&application).
+		Relation("Domains", orderDomains).
+		Where("a.id = ?", applicationID).
+		Scan(s.Ctx)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+	return application, nil
+}
+
+func (s *DeployStorage) SetApplicationRunState(applicationID uuid.UUID, state shared_types.RunState) error {
+	_, err := s.DB.NewUpdate().
+		Table("applications").
+		Set("run_state = ?", state).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", applicationID).
+		Exec(s.Ctx)
+	return err
+}
+
+// GetScaleToZeroApplications returns the running applications that sleep when idle. Only
+// applications served by Caddy are returned, the proxy whose requests are logged.
+func (s *DeployStorage) GetScaleToZeroApplications() ([]shared_types.Application, error) {
+	var applications []shared_types.Application
+	err := s.DB.NewSelect().
+		Model(&applications).
+		Relation("Domains", orderDomains).
+		Where("a.scale_to_zero = ?", true).
+		Where("a.build_pack = ?", shared_types.DockerFile).
+		Where("a.proxy_server = ?", shared_types.Caddy).
+		Where("a.run_state = ?", shared_types.RunStateRunning).
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// GetLastRequestTimes returns the time of the last request the proxy logged for each of the
+// applications. Applications without a logged request are left out.
+func (s *DeployStorage) GetLastRequestTimes(applicationIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	times := make(map[uuid.UUID]time.Time, len(applicationIDs))
+	if len(applicationIDs) == 0 {
+		return times, nil
+	}
+
+	var rows []struct {
+		ApplicationID uuid.UUID `bun:"application_id"`
+		LastRequest   time.Time `bun:"last_request"`
+	}
+	err := s.DB.NewSelect().
+		TableExpr("access_logs").
+		ColumnExpr("application_id, MAX(timestamp) AS last_request").
+		Where("application_id IN (?)", bun.In(applicationIDs)).
+		Group("application_id").
+		Scan(s.Ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		times[row.ApplicationID] = row.LastRequest
+	}
+	return times, nil
+}
+
+// GetRoutedApplications returns the applications with a domain that were deployed at least once,
+// whose domain the proxy routes, along with their domains.
+func (s *DeployStorage) GetRoutedApplications() ([]shared_types.Application, error) {
+	var applications []shared_types.Application
+	err := s.DB.NewSelect().
+		Model(&applications).
+		Relation("Domains", orderDomains).
+		Where("a.domain <> ''").
+		Where("EXISTS (SELECT 1 FROM application_deployment AS d WHERE d.application_id = a.id)").
+		Order("a.created_at").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// GetApplicationDatabaseLinks returns the managed databases linked to an application.
+func (s *DeployStorage) GetApplicationDatabaseLinks(applicationID uuid.UUID) ([]shared_types.ApplicationDatabase, error) {
+	var links []shared_types.ApplicationDatabase
+	err := s.DB.NewSelect().
+		Model(&links).
+		Relation("Database").
+		Where("adb.application_id = ?", applicationID).
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// UpdateProxySettings replaces the proxy settings of an application and its basic auth password
+// hash. The path prefix is mirrored in the domains of the application.
+func (s *DeployStorage) UpdateProxySettings(applicationID uuid.UUID, settings shared_types.ProxySettings, passwordHash string) error {
+	application := &shared_types.Application{
+		ID:                    applicationID,
+		ProxySettings:         settings,
+		BasicAuthPasswordHash: passwordHash,
+		UpdatedAt:             time.Now(),
+	}
+	return s.DB.RunInTx(s.Ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model(application).
+			Column("proxy_settings", "basic_auth_password_hash", "updated_at").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*shared_types.ApplicationDomain)(nil)).
+			Set("path_prefix = ?", settings.PathPrefix).
+			Set("updated_at = ?", application.UpdatedAt).
+			Where("application_id = ? AND path_prefix <> ?", applicationID, settings.PathPrefix).
+			Exec(ctx)
+		return err
+	})
+}
+
+// UpdateMaintenance replaces the maintenance settings of an application.
+func (s *DeployStorage) UpdateMaintenance(applicationID uuid.UUID, settings shared_types.MaintenanceSettings) error {
+	application := &shared_types.Application{
+		ID:          applicationID,
+		Maintenance: settings,
+		UpdatedAt:   time.Now(),
+	}
+	_, err := s.DB.NewUpdate().
+		Model(application).
+		Column("maintenance", "updated_at").
+		WherePK().
+		Exec(s.Ctx)
+	return err
+}
+
+// GetOrganizationDomain returns a domain registered by the organization.
+func (s *DeployStorage) GetOrganizationDomain(domainID uuid.UUID, organizationID uuid.UUID) (shared_types.Domain, error) {
+	var domain shared_types.Domain
+	err := s.DB.NewSelect().
+		Model(&domain).
+		Where("id = ? AND organization_id = ? AND deleted_at IS NULL", domainID, organizationID).
+		Scan(s.Ctx)
+	return domain, err
+}
+
+func (s *DeployStorage) AddApplicationDomain(domain *shared_types.ApplicationDomain) error {
+	_, err := s.DB.NewInsert().Model(domain).Exec(s.Ctx)
+	return err
+}
+
+func (s *DeployStorage) UpdateApplicationDomainMode(domainID uuid.UUID, mode shared_types.DomainMode) error {
+	_, err := s.DB.NewUpdate().
+		Model((*shared_types.ApplicationDomain)(nil)).
+		Set("mode = ?", mode).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", domainID).
+		Exec(s.Ctx)
+	return err
+}
+
+// SetPrimaryApplicationDomain makes a domain of an application its primary domain and mirrors
+// its name in the domain column of the application.
+func (s *DeployStorage) SetPrimaryApplicationDomain(applicationID uuid.UUID, domainID uuid.UUID) error {
+	return s.DB.RunInTx(s.Ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var domain shared_types.ApplicationDomain
+		err := tx.NewSelect().
+			Model(&domain).
+			Where("id = ? AND application_id = ?", domainID, applicationID).
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		_, err = tx.NewUpdate().
+			Model((*shared_types.ApplicationDomain)(nil)).
+			Set("is_primary = FALSE").
+			Set("updated_at = ?", now).
+			Where("application_id = ? AND is_primary", applicationID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*shared_types.ApplicationDomain)(nil)).
+			Set("is_primary = TRUE").
+			Set("mode = ?", shared_types.DomainModeServe).
+			Set("updated_at = ?", now).
+			Where("id = ?", domainID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Table("applications").
+			Set("domain = ?", domain.Name).
+			Set("updated_at = ?", now).
+			Where("id = ?", applicationID).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (s *DeployStorage) DeleteApplicationDomain(domainID uuid.UUID) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.ApplicationDomain)(nil)).
+		Where("id = ? AND NOT is_primary", domainID).
+		Exec(s.Ctx)
+	return err
+}
+
+// IsDomainVerifiedByAnotherOrganization reports whether the most specific verified domain host
+// belongs to was verified by another organization than organizationID.
+func (s *DeployStorage) IsDomainVerifiedByAnotherOrganization(host string, organizationID uuid.UUID) (bool, error) {
+	var domain shared_types.Domain
+	err := s.DB.NewSelect().
+		Model(&domain).
+		Column("organization_id").
+		Where("deleted_at IS NULL AND verified_at IS NOT NULL").
+		Where(domainCoversHost, host, host).
+		OrderExpr("LENGTH(name) DESC").
+		Limit(1).
+		Scan(s.Ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return domain.OrganizationID != organizationID, nil
+}
+
+// GetOrganizationDomainID returns the most specific verified domain of the organization host
+// belongs to, or nil when the host is not under any of them.
+func (s *DeployStorage) GetOrganizationDomainID(host string, organizationID uuid.UUID) (*uuid.UUID, error) {
+	return organizationDomainID(s.Ctx, s.DB, host, organizationID)
+}
+
+// IsRouteTaken reports whether another application serves host below pathPrefix. Applications
+// of other organizations cannot share host at all.
+func (s *DeployStorage) IsRouteTaken(host string, pathPrefix string, applicationID uuid.UUID, organizationID uuid.UUID) (bool, error) {
+	var count int
+	err := s.DB.NewSelect().
+		TableExpr("application_domains AS apd").
+		ColumnExpr("count(*)").
+		Join("JOIN applications AS a ON a.id = apd.application_id").
+		Where("LOWER(apd.name) = LOWER(?)", host).
+		Where("apd.application_id <> ?", applicationID).
+		Where("(apd.path_prefix = ? OR a.organization_id <> ?)", pathPrefix, organizationID).
+		Scan(s.Ctx, &count)
+	return count > 0, err
+}
@@ -39,7 +39,7 @@ func (s *TaskService) BuildImage(b BuildConfig) (string, error) {
 
 	if _, err := os.Stat(buildContextPath); os.IsNotExist(err) {
 		b.TaskContext.LogAndUpdateStatus("Build context path does not exist: "+buildContextPath, shared_types.Failed)
-		return "", fmt.Errorf("build context path does not exist: %s", buildContextPath)
+		return "", fmt.Errorf("%w: %s", types.ErrBuildContextNotFound, buildContextPath)
 	}
 
 	b.TaskContext.AddLog("Creating build context archive...")
@@ -59,7 +59,7 @@ func (s *TaskService) BuildImage(b BuildConfig) (string, error) {
 	b.TaskContext.AddLog("Validating Dockerfile path...")
 	if _, err := os.Stat(dockerfileFullPath); os.IsNotExist(err) {
 		b.TaskContext.LogAndUpdateStatus("Dockerfile not found at path: "+dockerfileFullPath, shared_types.Failed)
-		return "", fmt.Errorf("dockerfile not found at path: %v", err)
+		return "", fmt.Errorf("%w: %s", types.ErrDockerfileNotFound, dockerfileFullPath)
 	}
 	b.TaskContext.AddLog("Dockerfile validation successful")
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
 	github_service "github.com/raghavyuva/nixopus-api/internal/features/github-connector/service"
 	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
 )
@@ -17,7 +18,7 @@ type CloneConfig struct {
 func (t *TaskService) Clone(cloneConfig CloneConfig) (string, error) {
 	repoID, err := strconv.ParseInt(cloneConfig.Application.Repository, 10, 64)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse repository id: %w", err)
+		return "", fmt.Errorf("%w: %s", types.ErrInvalidRepositoryID, cloneConfig.Application.Repository)
 	}
 	cloneRepositoryConfig := github_service.CloneRepositoryConfig{
 		RepoID:         uint64(repoID),
@@ -27,6 +28,10 @@ func (t *TaskService) Clone(cloneConfig CloneConfig) (string, error) {
 		DeploymentType: cloneConfig.DeploymentType,
 		Branch:         cloneConfig.Application.Branch,
 		ApplicationID:  cloneConfig.Application.ID.String(),
+		Submodules:     cloneConfig.Application.GitSubmodules,
+		LFS:            cloneConfig.Application.GitLFS,
+		CloneDepth:     cloneConfig.Application.CloneDepth,
+		CloneFilter:    cloneConfig.Application.CloneFilter,
 	}
 	// we will pass the commit hash to the clone repository function for rollback feature otherwise it will clone the latest commit
 	repoPath, err := t.Github_service.CloneRepository(cloneRepositoryConfig, &cloneConfig.ApplicationDeployment.CommitHash)
@@ -1,48 +1,189 @@
 package tasks
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/google/uuid"
-	"github.com/raghavyuva/nixopus-api/internal/features/ssh"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
 	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
 )
 
-func (s *TaskService) runCommands(applicationID uuid.UUID, deploymentConfigID uuid.UUID, command string, commandType string) error {
-	taskCtx := s.NewTaskContext(shared_types.TaskPayload{
-		Application: shared_types.Application{
-			ID: applicationID,
-		},
-		ApplicationDeployment: shared_types.ApplicationDeployment{
-			ID: deploymentConfigID,
-		},
-	})
-	taskCtx.AddLog(fmt.Sprintf("Running %s commands %v", commandType, command))
+// defaultReleaseCommandTimeout bounds a release command when RELEASE_COMMAND_TIMEOUT is not set.
+const defaultReleaseCommandTimeout = 30 * time.Minute
 
-	if command == "" {
+// runReleaseCommand runs a release phase command (pre run / post run) in a one-off container
+// created from the freshly built application image. The container gets the environment and
+// the networks of the application's service, including its linked databases, its output is
+// streamed to the deployment logs and a non zero exit code is returned as an error so that it
+// can gate the rollout. A command running past the release command timeout is killed.
+func (s *TaskService) runReleaseCommand(d shared_types.TaskPayload, taskCtx *TaskContext, command string, commandType string) error {
+	if strings.TrimSpace(command) == "" {
 		return nil
 	}
 
-	client := ssh.NewSSH()
-	output, err := client.RunCommand(command)
+	taskCtx.AddLog(fmt.Sprintf("Running %s command in release container: %s", commandType, command))
+
+	networks, envVars := s.applicationEnv(d.Application, taskCtx)
+	networkMode := s.releaseNetworkMode(d)
+	if len(networks) > 0 {
+		// the database network is attachable, so the container can reach the linked databases
+		networkMode = networks[0].Target
+	}
+
+	containerConfig := container.Config{
+		Image:      fmt.Sprintf("%s:latest", d.Application.Name),
+		Entrypoint: []string{"/bin/sh", "-c"},
+		Cmd:        []string{command},
+		Env:        envVars,
+		Labels: map[string]string{
+			"com.application.id": d.Application.ID.String(),
+			"com.deployment.id":  d.ApplicationDeployment.ID.String(),
+			"com.release.phase":  commandType,
+		},
+	}
+	hostConfig := container.HostConfig{
+		NetworkMode: container.NetworkMode(networkMode),
+	}
+	networkConfig := network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			networkMode: {},
+		},
+	}
+
+	resp, err := s.DockerRepo.CreateContainer(containerConfig, hostConfig, networkConfig, "")
 	if err != nil {
-		taskCtx.AddLog(fmt.Sprintf("Error while running %s command %v", commandType, output))
-		return err
+		taskCtx.AddLog(fmt.Sprintf("Failed to create %s container: %s", commandType, err.Error()))
+		return fmt.Errorf("%w: %v", types.ErrReleaseCommandFailed, err)
 	}
+	defer func() {
+		if err := s.DockerRepo.RemoveContainer(resp.ID, container.RemoveOptions{Force: true}); err != nil {
+			s.Logger.Log(logger.Error, "Failed to remove release container", err.Error())
+		}
+	}()
+
+	timeout := releaseCommandTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	// wait must be registered before the container starts, otherwise a fast exiting command can be missed
+	waitC, waitErrC := s.DockerRepo.WaitContainer(ctx, resp.ID, container.WaitConditionNextExit)
 
-	if output != "" {
-		taskCtx.AddLog(fmt.Sprintf("%s command resulted in %v", commandType, output))
+	if err := s.DockerRepo.StartContainer(resp.ID, container.StartOptions{}); err != nil {
+		taskCtx.AddLog(fmt.Sprintf("Failed to start %s container: %s", commandType, err.Error()))
+		return fmt.Errorf("%w: %v", types.ErrReleaseCommandFailed, err)
 	}
 
+	s.streamReleaseLogs(ctx, resp.ID, taskCtx, commandType)
+
+	select {
+	case result := <-waitC:
+		if result.Error != nil {
+			taskCtx.AddLog(fmt.Sprintf("Error while waiting for %s container: %s", commandType, result.Error.Message))
+			return fmt.Errorf("%w: %s", types.ErrReleaseCommandFailed, result.Error.Message)
+		}
+		if result.StatusCode != 0 {
+			taskCtx.AddLog(fmt.Sprintf("%s command exited with code %d", commandType, result.StatusCode))
+			return fmt.Errorf("%w: %s command exited with code %d", types.ErrReleaseCommandExited, commandType, result.StatusCode)
+		}
+	case err := <-waitErrC:
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			taskCtx.AddLog(fmt.Sprintf("%s command did not finish within %s and was stopped", commandType, timeout))
+			return fmt.Errorf("%w: %s command ran longer than %s", types.ErrReleaseCommandTimedOut, commandType, timeout)
+		}
+		taskCtx.AddLog(fmt.Sprintf("Error while waiting for %s container: %s", commandType, err.Error()))
+		return fmt.Errorf("%w: %v", types.ErrReleaseCommandFailed, err)
+	}
+
+	taskCtx.AddLog(fmt.Sprintf("%s command completed successfully", commandType))
 	return nil
 }
 
-func (s *TaskService) PrerunCommands(d shared_types.TaskPayload) error {
-	return s.runCommands(d.Application.ID, d.ApplicationDeployment.ID,
-		d.Application.PreRunCommand, "pre run")
+func releaseCommandTimeout() time.Duration {
+	if minutes := config.AppConfig.Deployment.ReleaseCommandTimeout; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return defaultReleaseCommandTimeout
+}
+
+// streamReleaseLogs follows the logs of the release container until it exits and writes every
+// line of stdout and stderr to the deployment logs.
+func (s *TaskService) streamReleaseLogs(ctx context.Context, containerID string, taskCtx *TaskContext, commandType string) {
+	logs, err := s.DockerRepo.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		taskCtx.AddLog(fmt.Sprintf("Failed to attach to %s container logs: %s", commandType, err.Error()))
+		return
+	}
+	defer logs.Close()
+
+	stdout := &releaseLogWriter{taskCtx: taskCtx, prefix: commandType + ": "}
+	stderr := &releaseLogWriter{taskCtx: taskCtx, prefix: commandType + " (stderr): "}
+	if _, err := stdcopy.StdCopy(stdout, stderr, logs); err != nil {
+		s.Logger.Log(logger.Error, "Failed to read release container logs", err.Error())
+	}
+	stdout.Flush()
+	stderr.Flush()
+}
+
+// releaseNetworkMode returns the network the release container should join. The release container
+// shares the first network attached to the application's service, falling back to the default bridge.
+func (s *TaskService) releaseNetworkMode(d shared_types.TaskPayload) string {
+	service, err := s.getExistingService(d, nil)
+	if err != nil || service == nil || len(service.Spec.TaskTemplate.Networks) == 0 {
+		return "bridge"
+	}
+	return service.Spec.TaskTemplate.Networks[0].Target
+}
+
+// releaseLogWriter is an io.Writer that splits the output of a release container into lines
+// and adds each of them to the deployment logs.
+type releaseLogWriter struct {
+	taskCtx *TaskContext
+	prefix  string
+	buffer  []byte
+}
+
+func (w *releaseLogWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+	for {
+		idx := bytes.IndexByte(w.buffer, '\n')
+		if idx == -1 {
+			break
+		}
+		line := strings.TrimRight(string(w.buffer[:idx]), "\r")
+		w.buffer = w.buffer[idx+1:]
+		if line != "" {
+			w.taskCtx.AddLog(w.prefix + line)
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes any remaining partial line to the deployment logs.
+func (w *releaseLogWriter) Flush() {
+	if len(w.buffer) > 0 {
+		w.taskCtx.AddLog(w.prefix + string(w.buffer))
+		w.buffer = nil
+	}
+}
+
+// PrerunCommands runs the application's pre run command inside the newly built image, before the rollout
+func (s *TaskService) PrerunCommands(d shared_types.TaskPayload, taskCtx *TaskContext) error {
+	return s.runReleaseCommand(d, taskCtx, d.Application.PreRunCommand, "pre run")
 }
 
-func (s *TaskService) PostRunCommands(d shared_types.TaskPayload) error {
-	return s.runCommands(d.Application.ID, d.ApplicationDeployment.ID,
-		d.Application.PostRunCommand, "post run")
+// PostRunCommands runs the application's post run command inside the newly built image, after the rollout
+func (s *TaskService) PostRunCommands(d shared_types.TaskPayload, taskCtx *TaskContext) error {
+	return s.runReleaseCommand(d, taskCtx, d.Application.PostRunCommand, "post run")
 }
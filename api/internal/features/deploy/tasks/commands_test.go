@@ -0,0 +1,77 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// logRecorder keeps the deployment logs written through a TaskContext.
+type logRecorder struct {
+	storage.DeployRepository
+	logs []string
+}
+
+func (r *logRecorder) AddApplicationLogs(log *shared_types.ApplicationLogs) error {
+	r.logs = append(r.logs, log.Log)
+	return nil
+}
+
+func TestReleaseLogWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []string
+	}{
+		{
+			name:   "Complete lines",
+			writes: []string{"migrating\nmigrated\n"},
+			want:   []string{"pre run: migrating", "pre run: migrated"},
+		},
+		{
+			name:   "Lines split across writes",
+			writes: []string{"migr", "ating\nmig", "rated\n"},
+			want:   []string{"pre run: migrating", "pre run: migrated"},
+		},
+		{
+			name:   "Carriage returns and empty lines are dropped",
+			writes: []string{"one\r\n\n\ntwo\r\n"},
+			want:   []string{"pre run: one", "pre run: two"},
+		},
+		{
+			name:   "Partial line is flushed",
+			writes: []string{"done\nno newline"},
+			want:   []string{"pre run: done", "pre run: no newline"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &logRecorder{}
+			taskCtx := (&TaskService{Storage: recorder}).NewTaskContext(shared_types.TaskPayload{})
+			w := &releaseLogWriter{taskCtx: taskCtx, prefix: "pre run: "}
+			for _, p := range tt.writes {
+				n, err := w.Write([]byte(p))
+				assert.NoError(t, err)
+				assert.Equal(t, len(p), n)
+			}
+			w.Flush()
+			assert.Equal(t, tt.want, recorder.logs)
+		})
+	}
+}
+
+func TestReleaseCommandTimeout(t *testing.T) {
+	previous := config.AppConfig.Deployment.ReleaseCommandTimeout
+	defer func() { config.AppConfig.Deployment.ReleaseCommandTimeout = previous }()
+
+	config.AppConfig.Deployment.ReleaseCommandTimeout = 0
+	assert.Equal(t, defaultReleaseCommandTimeout, releaseCommandTimeout())
+
+	config.AppConfig.Deployment.ReleaseCommandTimeout = 5
+	assert.Equal(t, 5*time.Minute, releaseCommandTimeout())
+}
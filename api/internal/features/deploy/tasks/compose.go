@@ -0,0 +1,177 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/ssh"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	composeFileName = "docker-compose.yml"
+	composeEnvFile  = ".env"
+)
+
+// ComposeProjectPath returns the directory on the deployment server holding the compose file
+// of an application. Its name is also the compose project name.
+func ComposeProjectPath(application shared_types.Application) string {
+	return filepath.Join(config.AppConfig.Deployment.MountPath, "compose", application.ID.String())
+}
+
+// HandleComposeDeployment writes the compose file of an application created from a template
+// to the deployment server, brings its services up and routes the domain to the published port.
+//
+// Besides the application's environment variables the compose file can use NIXOPUS_PORT, the
+// host port reserved for the application, NIXOPUS_DOMAIN and NIXOPUS_URL.
+func (t *TaskService) HandleComposeDeployment(ctx context.Context, TaskPayload shared_types.TaskPayload) error {
+	taskCtx := t.NewTaskContext(TaskPayload)
+	application := TaskPayload.Application
+
+	if application.ComposeFile == "" {
+		taskCtx.LogAndUpdateStatus(types.LogDockerComposeNotImplemented, shared_types.Failed)
+		return types.ErrDockerComposeNotImplemented
+	}
+
+	taskCtx.LogAndUpdateStatus(types.LogDockerComposeDeploymentStarted, shared_types.Deploying)
+
+	availablePort, err := t.getAvailablePort(TaskPayload)
+	if err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to get available port: "+err.Error(), shared_types.Failed)
+		return err
+	}
+
+	env := GetMapFromString(application.EnvironmentVariables)
+	env["NIXOPUS_PORT"] = availablePort
+	env["NIXOPUS_DOMAIN"] = application.Domain
+	env["NIXOPUS_URL"] = "https://" + application.Domain
+
+	projectPath := ComposeProjectPath(application)
+	if err := writeComposeProject(projectPath, application.ComposeFile, env); err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to write compose project: "+err.Error(), shared_types.Failed)
+		return err
+	}
+	taskCtx.AddLog("Compose project written to " + projectPath)
+
+	taskCtx.AddLog("Starting compose services")
+	if err := t.DockerRepo.ComposeUp(filepath.Join(projectPath, composeFileName), nil); err != nil {
+		taskCtx.LogAndUpdateStatus(fmt.Sprintf(types.LogDockerComposeDeploymentFailed, err.Error()), shared_types.Failed)
+		return fmt.Errorf("%w: %v", types.ErrDockerComposeCommandFailed, err)
+	}
+
+	r := TaskPayload.ApplicationDeployment
+	r.ContainerName = application.ID.String()
+	r.ContainerStatus = "running"
+	taskCtx.UpdateDeployment(&r)
+
+	port, err := strconv.Atoi(availablePort)
+	if err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to convert port to int: "+err.Error(), shared_types.Failed)
+		return err
+	}
+	if err := t.routeDomain(application, port); err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to add domain: "+err.Error(), shared_types.Failed)
+		return err
+	}
+
+	taskCtx.LogAndUpdateStatus(types.LogDockerComposeDeploymentCompleted, shared_types.Deployed)
+	return nil
+}
+
+// RemoveComposeProject takes the services of a compose application down and removes its
+// project directory. Named volumes are kept so that data survives an accidental deletion.
+func (t *TaskService) RemoveComposeProject(application shared_types.Application) error {
+	projectPath := ComposeProjectPath(application)
+	if err := t.DockerRepo.ComposeDown(filepath.Join(projectPath, composeFileName)); err != nil {
+		return err
+	}
+	_, err := ssh.NewSSH().RunCommand("rm -rf " + shellQuote(projectPath))
+	return err
+}
+
+// writeComposeProject uploads the compose file and the .env file it is interpolated from.
+func writeComposeProject(projectPath string, compose string, env map[string]string) error {
+	client, err := ssh.NewSSH().Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect via SSH: %w", err)
+	}
+	defer client.Close()
+
+	sftp, err := client.NewSftp()
+	if err != nil {
+		return fmt.Errorf("failed to create SFTP client: %w", err)
+	}
+	defer sftp.Close()
+
+	if err := sftp.MkdirAll(projectPath); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		composeFileName: compose,
+		composeEnvFile:  composeEnv(env),
+	}
+	for name, content := range files {
+		file, err := sftp.Create(filepath.Join(projectPath, name))
+		if err != nil {
+			return err
+		}
+		_, err = file.Write([]byte(content))
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return sftp.Chmod(filepath.Join(projectPath, composeEnvFile), 0600)
+}
+
+// composeEnv renders variables in the .env format read by docker compose. Values are single
+// quoted so that they are taken literally, values containing a single quote are double quoted.
+func composeEnv(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v := env[k]
+		if !strings.Contains(v, "'") {
+			fmt.Fprintf(&b, "%s='%s'\n", k, v)
+			continue
+		}
+		v = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", "$$").Replace(v)
+		fmt.Fprintf(&b, "%s=\"%s\"\n", k, v)
+	}
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// HandleComposeRestart recreates the services of a compose application.
+func (t *TaskService) HandleComposeRestart(ctx context.Context, TaskPayload shared_types.TaskPayload) error {
+	taskCtx := t.NewTaskContext(TaskPayload)
+	taskCtx.LogAndUpdateStatus("Restarting compose services", shared_types.Deploying)
+
+	composePath := filepath.Join(ComposeProjectPath(TaskPayload.Application), composeFileName)
+	if err := t.DockerRepo.ComposeDown(composePath); err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to stop compose services: "+err.Error(), shared_types.Failed)
+		return err
+	}
+	if err := t.DockerRepo.ComposeUp(composePath, nil); err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to start compose services: "+err.Error(), shared_types.Failed)
+		return err
+	}
+
+	taskCtx.LogAndUpdateStatus("Application service restarted", shared_types.Running)
+	return nil
+}
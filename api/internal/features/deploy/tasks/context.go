@@ -1,9 +1,11 @@
 package tasks
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
 	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
@@ -48,6 +50,8 @@ func (c *ContextTask) GetApplicationData(
 		BuildVariables:       GetStringFromMap(deployment.BuildVariables),
 		EnvironmentVariables: GetStringFromMap(deployment.EnvironmentVariables),
 		Environment:          deployment.Environment,
+		ProxyServer:          deployment.ProxyServer,
+		ProxySettings:        shared_types.ProxySettings{PathPrefix: deployment.PathPrefix, StripPathPrefix: deployment.StripPathPrefix, RoutePriority: deployment.RoutePriority},
 		BuildPack:            deployment.BuildPack,
 		Repository:           deployment.Repository,
 		Branch:               deployment.Branch,
@@ -60,9 +64,34 @@ func (c *ContextTask) GetApplicationData(
 		UpdatedAt:            time.Now(),
 		DockerfilePath:       deployment.DockerfilePath,
 		BasePath:             deployment.BasePath,
+		BuildCommand:         deployment.BuildCommand,
+		BuildImage:           deployment.BuildImage,
+		OutputDirectory:      deployment.OutputDirectory,
+		SpaFallback:          deployment.SpaFallback,
+		NotFoundPage:         deployment.NotFoundPage,
+		CacheControl:         deployment.CacheControl,
+		DirectoryBrowsing:    deployment.DirectoryBrowsing,
+		ScaleToZero:          deployment.ScaleToZero,
+		IdleTimeout:          deployment.IdleTimeout,
+		GitSubmodules:        deployment.GitSubmodules,
+		GitLFS:               deployment.GitLFS,
+		CloneDepth:           deployment.CloneDepth,
+		CloneFilter:          deployment.CloneFilter,
+		ReportGithubStatus:   deployment.ReportGithubStatus,
+		RunState:             shared_types.RunStateRunning,
+		ComposeFile:          deployment.ComposeFile,
+		Template:             deployment.Template,
 		OrganizationID:       c.OrganizationId,
 	}
 
+	if application.BuildImage == "" {
+		application.BuildImage = types.DefaultStaticBuildImage
+	}
+
+	if application.IdleTimeout == 0 {
+		application.IdleTimeout = types.DefaultIdleTimeout
+	}
+
 	return application
 }
 
@@ -109,7 +138,16 @@ func (c *ContextTask) PersistCreateApplicationDeploymentData(application shared_
 	}{
 		{
 			operation: func() error {
-				return c.TaskService.Storage.AddApplication(&application)
+				// static sites are served straight from disk by the proxy and never publish a port
+				if application.BuildPack == shared_types.Static {
+					return c.TaskService.Storage.AddApplication(&application)
+				}
+				options, err := c.TaskService.portAllocationOptions(application)
+				if err != nil {
+					return fmt.Errorf("%s: %w", types.LogFailedToAllocatePort, err)
+				}
+				_, err = c.TaskService.Storage.AddApplicationWithPort(&application, config.AppConfig.SSH.Host, options)
+				return err
 			},
 			errMessage: types.LogFailedToCreateApplicationRecord,
 		},
@@ -206,6 +244,7 @@ func (c *ContextTask) PrepareCreateDeploymentContext() (shared_types.TaskPayload
 func (c *ContextTask) PrepareUpdateDeploymentContext() (shared_types.TaskPayload, error) {
 	application := c.mergeDeploymentUpdates()
 	applicationDeployment := c.GetDeploymentConfig(c.Application.ID)
+	applicationDeployment.CommitHash = c.ContextConfig.(*types.UpdateDeploymentRequest).CommitHash
 	err := c.PersistUpdateApplicationDeploymentData(application, applicationDeployment)
 	if err != nil {
 		return shared_types.TaskPayload{}, err
@@ -266,6 +305,62 @@ func (c *ContextTask) mergeDeploymentUpdates() shared_types.Application {
 		application.BasePath = deployment.BasePath
 	}
 
+	if deployment.BuildCommand != nil {
+		application.BuildCommand = *deployment.BuildCommand
+	}
+
+	if deployment.BuildImage != "" {
+		application.BuildImage = deployment.BuildImage
+	}
+
+	if deployment.OutputDirectory != nil {
+		application.OutputDirectory = *deployment.OutputDirectory
+	}
+
+	if deployment.SpaFallback != nil {
+		application.SpaFallback = *deployment.SpaFallback
+	}
+
+	if deployment.NotFoundPage != nil {
+		application.NotFoundPage = *deployment.NotFoundPage
+	}
+
+	if deployment.CacheControl != nil {
+		application.CacheControl = *deployment.CacheControl
+	}
+
+	if deployment.DirectoryBrowsing != nil {
+		application.DirectoryBrowsing = *deployment.DirectoryBrowsing
+	}
+
+	if deployment.ScaleToZero != nil {
+		application.ScaleToZero = *deployment.ScaleToZero
+	}
+
+	if deployment.IdleTimeout != nil {
+		application.IdleTimeout = *deployment.IdleTimeout
+	}
+
+	if deployment.GitSubmodules != nil {
+		application.GitSubmodules = *deployment.GitSubmodules
+	}
+
+	if deployment.GitLFS != nil {
+		application.GitLFS = *deployment.GitLFS
+	}
+
+	if deployment.CloneDepth != nil {
+		application.CloneDepth = *deployment.CloneDepth
+	}
+
+	if deployment.CloneFilter != nil {
+		application.CloneFilter = *deployment.CloneFilter
+	}
+
+	if deployment.ReportGithubStatus != nil {
+		application.ReportGithubStatus = *deployment.ReportGithubStatus
+	}
+
 	application.UpdatedAt = time.Now()
 
 	return *application
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
 )
 
 // DeleteDeployment deletes a deployment and its associated resources.
@@ -20,8 +21,6 @@ func (s *TaskService) DeleteDeployment(deployment *types.DeleteDeploymentRequest
 		return fmt.Errorf("failed to get application details: %w", err)
 	}
 
-	domain := application.Domain
-
 	services, err := s.DockerRepo.GetClusterServices()
 	if err != nil {
 		s.Logger.Log(logger.Error, "Failed to get services", err.Error())
@@ -61,12 +60,38 @@ func (s *TaskService) DeleteDeployment(deployment *types.DeleteDeploymentRequest
 		s.Logger.Log(logger.Error, "Failed to remove repository", err.Error())
 	}
 
-	client := GetCaddyClient()
-	err = client.DeleteDomain(domain)
-	if err != nil {
+	if application.BuildPack == shared_types.DockerCompose {
+		s.Logger.Log(logger.Info, "Removing compose project", ComposeProjectPath(application))
+		if err := s.RemoveComposeProject(application); err != nil {
+			s.Logger.Log(logger.Error, "Failed to remove compose project", err.Error())
+		}
+	}
+
+	if application.BuildPack == shared_types.Static {
+		sitePath := StaticSitePath(application.ID)
+		s.Logger.Log(logger.Info, "Removing static site releases", sitePath)
+		if err := os.RemoveAll(sitePath); err != nil {
+			s.Logger.Log(logger.Error, "Failed to remove static site releases", err.Error())
+		}
+	}
+
+	if err := s.proxyFor(application).RemoveRoute(applicationSite(application)); err != nil {
 		s.Logger.Log(logger.Error, "Failed to remove domain", err.Error())
 	}
-	client.Reload()
 
-	return s.Storage.DeleteDeployment(deployment, userID)
+	if err := s.Storage.DeleteDeployment(deployment, userID); err != nil {
+		return err
+	}
+
+	for _, port := range application.Ports {
+		if port.SNI != "" {
+			if err := s.syncTLSPassthrough(); err != nil {
+				s.Logger.Log(logger.Error, "Failed to remove sni routes", err.Error())
+			}
+			break
+		}
+	}
+
+	publishApplicationEvent(application, shared_types.WebhookEventApplicationDeleted)
+	return nil
 }
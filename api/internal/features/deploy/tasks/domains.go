@@ -0,0 +1,166 @@
+package tasks
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// requireVerifiedHost checks that host is under a domain the organization verified. A host
+// under a more specific domain verified by another organization belongs to that organization.
+func (t *TaskService) requireVerifiedHost(host string, organizationID uuid.UUID) error {
+	claimed, err := t.Storage.IsDomainVerifiedByAnotherOrganization(host, organizationID)
+	if err != nil {
+		return err
+	}
+	if claimed {
+		return types.ErrDomainOwnedElsewhere
+	}
+	domainID, err := t.Storage.GetOrganizationDomainID(host, organizationID)
+	if err != nil {
+		return err
+	}
+	if domainID == nil {
+		return types.ErrDomainNotVerified
+	}
+	return nil
+}
+
+// AddApplicationDomain assigns a domain registered by the organization, or a subdomain of it,
+// to an application and routes it together with the other domains of the application.
+func (t *TaskService) AddApplicationDomain(request *types.AddApplicationDomainRequest, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(request.ID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	domain, err := t.Storage.GetOrganizationDomain(request.DomainID, organizationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return shared_types.Application{}, types.ErrDomainNotRegistered
+	}
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+	if !domain.IsVerified() {
+		return shared_types.Application{}, types.ErrDomainNotVerified
+	}
+
+	host := strings.ToLower(strings.TrimPrefix(domain.Name, "*."))
+	if request.Subdomain != "" {
+		host = request.Subdomain + "." + host
+	}
+	taken, err := t.Storage.IsRouteTaken(host, application.ProxySettings.PathPrefix, application.ID, organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+	if taken {
+		return shared_types.Application{}, types.ErrDomainAlreadyTaken
+	}
+
+	mode := request.Mode
+	if mode == "" {
+		mode = shared_types.DomainModeServe
+	}
+	record := &shared_types.ApplicationDomain{
+		ID:            uuid.New(),
+		ApplicationID: application.ID,
+		DomainID:      &domain.ID,
+		Name:          host,
+		Mode:          mode,
+		PathPrefix:    application.ProxySettings.PathPrefix,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := t.Storage.AddApplicationDomain(record); err != nil {
+		return shared_types.Application{}, err
+	}
+	if request.IsPrimary {
+		if err := t.Storage.SetPrimaryApplicationDomain(application.ID, record.ID); err != nil {
+			return shared_types.Application{}, err
+		}
+	}
+
+	return t.rerouteDomains(application.ID, organizationID)
+}
+
+// UpdateApplicationDomain changes the mode of a domain of an application or makes it the primary
+// domain. The primary domain is always served, so it cannot be switched to redirect.
+func (t *TaskService) UpdateApplicationDomain(request *types.UpdateApplicationDomainRequest, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(request.ID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	record := findApplicationDomain(application, request.ApplicationDomainID)
+	if record == nil {
+		return shared_types.Application{}, types.ErrApplicationDomainNotFound
+	}
+
+	switch {
+	case request.IsPrimary && !record.IsPrimary:
+		err = t.Storage.SetPrimaryApplicationDomain(application.ID, record.ID)
+	case record.IsPrimary && request.Mode == shared_types.DomainModeRedirect:
+		return shared_types.Application{}, types.ErrPrimaryDomainMustServe
+	case !record.IsPrimary:
+		mode := request.Mode
+		if mode == "" {
+			mode = shared_types.DomainModeServe
+		}
+		err = t.Storage.UpdateApplicationDomainMode(record.ID, mode)
+	}
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	return t.rerouteDomains(application.ID, organizationID)
+}
+
+// RemoveApplicationDomain unassigns a domain from an application. The primary domain can only
+// be removed after another domain was made primary.
+func (t *TaskService) RemoveApplicationDomain(request *types.RemoveApplicationDomainRequest, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(request.ID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	record := findApplicationDomain(application, request.ApplicationDomainID)
+	if record == nil {
+		return shared_types.Application{}, types.ErrApplicationDomainNotFound
+	}
+	if record.IsPrimary {
+		return shared_types.Application{}, types.ErrCannotRemovePrimaryDomain
+	}
+
+	if err := t.Storage.DeleteApplicationDomain(record.ID); err != nil {
+		return shared_types.Application{}, err
+	}
+
+	return t.rerouteDomains(application.ID, organizationID)
+}
+
+// rerouteDomains reloads an application after its domains changed and routes them.
+func (t *TaskService) rerouteDomains(applicationID uuid.UUID, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(applicationID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+	if err := t.refreshRoute(application); err != nil {
+		return application, fmt.Errorf("%w: %v", types.ErrApplyDomains, err)
+	}
+	return application, nil
+}
+
+func findApplicationDomain(application shared_types.Application, id uuid.UUID) *shared_types.ApplicationDomain {
+	for _, domain := range application.Domains {
+		if domain.ID == id {
+			return domain
+		}
+	}
+	return nil
+}
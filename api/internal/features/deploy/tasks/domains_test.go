@@ -0,0 +1,151 @@
+package tasks
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// domainStore serves an application and a domain of its organization. Every route is taken,
+// so domains are never added.
+type domainStore struct {
+	storage.DeployRepository
+	application shared_types.Application
+	domain      shared_types.Domain
+	domainErr   error
+	checkedHost string
+	writes      int
+}
+
+func (s *domainStore) GetApplicationById(string, uuid.UUID) (shared_types.Application, error) {
+	return s.application, nil
+}
+
+func (s *domainStore) GetOrganizationDomain(uuid.UUID, uuid.UUID) (shared_types.Domain, error) {
+	return s.domain, s.domainErr
+}
+
+func (s *domainStore) IsRouteTaken(host string, _ string, _ uuid.UUID, _ uuid.UUID) (bool, error) {
+	s.checkedHost = host
+	return true, nil
+}
+
+func (s *domainStore) SetPrimaryApplicationDomain(uuid.UUID, uuid.UUID) error {
+	s.writes++
+	return nil
+}
+
+func (s *domainStore) UpdateApplicationDomainMode(uuid.UUID, shared_types.DomainMode) error {
+	s.writes++
+	return nil
+}
+
+func (s *domainStore) DeleteApplicationDomain(uuid.UUID) error {
+	s.writes++
+	return nil
+}
+
+func TestAddApplicationDomain(t *testing.T) {
+	verified := time.Now()
+	tests := []struct {
+		name      string
+		domain    shared_types.Domain
+		domainErr error
+		subdomain string
+		wantErr   error
+		wantHost  string
+	}{
+		{name: "Domain of another organization", domainErr: sql.ErrNoRows, wantErr: types.ErrDomainNotRegistered},
+		{name: "Unverified domain", domain: shared_types.Domain{Name: "example.com"}, wantErr: types.ErrDomainNotVerified},
+		{name: "Domain", domain: shared_types.Domain{Name: "Example.com", VerifiedAt: &verified}, wantErr: types.ErrDomainAlreadyTaken, wantHost: "example.com"},
+		{name: "Subdomain of a wildcard domain", domain: shared_types.Domain{Name: "*.example.com", VerifiedAt: &verified}, subdomain: "shop", wantErr: types.ErrDomainAlreadyTaken, wantHost: "shop.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &domainStore{domain: tt.domain, domainErr: tt.domainErr}
+			s := &TaskService{Storage: store}
+			_, err := s.AddApplicationDomain(&types.AddApplicationDomainRequest{ID: uuid.New(), DomainID: uuid.New(), Subdomain: tt.subdomain}, uuid.New())
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantHost, store.checkedHost)
+		})
+	}
+}
+
+func TestPrimaryApplicationDomain(t *testing.T) {
+	primary := &shared_types.ApplicationDomain{ID: uuid.New(), Name: "example.com", IsPrimary: true}
+	application := shared_types.Application{ID: uuid.New(), Domains: []*shared_types.ApplicationDomain{primary}}
+
+	t.Run("Primary domain cannot redirect", func(t *testing.T) {
+		store := &domainStore{application: application}
+		_, err := (&TaskService{Storage: store}).UpdateApplicationDomain(&types.UpdateApplicationDomainRequest{
+			ID: application.ID, ApplicationDomainID: primary.ID, Mode: shared_types.DomainModeRedirect,
+		}, uuid.New())
+		assert.ErrorIs(t, err, types.ErrPrimaryDomainMustServe)
+		assert.Zero(t, store.writes)
+	})
+
+	t.Run("Primary domain cannot be removed", func(t *testing.T) {
+		store := &domainStore{application: application}
+		_, err := (&TaskService{Storage: store}).RemoveApplicationDomain(&types.RemoveApplicationDomainRequest{
+			ID: application.ID, ApplicationDomainID: primary.ID,
+		}, uuid.New())
+		assert.ErrorIs(t, err, types.ErrCannotRemovePrimaryDomain)
+		assert.Zero(t, store.writes)
+	})
+
+	t.Run("Unknown domain", func(t *testing.T) {
+		store := &domainStore{application: application}
+		_, err := (&TaskService{Storage: store}).RemoveApplicationDomain(&types.RemoveApplicationDomainRequest{
+			ID: application.ID, ApplicationDomainID: uuid.New(),
+		}, uuid.New())
+		assert.ErrorIs(t, err, types.ErrApplicationDomainNotFound)
+	})
+}
+
+// hostOwnership answers which organization verified the domain a host is under. Every route is
+// taken, so no application is created.
+type hostOwnership struct {
+	storage.DeployRepository
+	elsewhere bool
+	domainID  *uuid.UUID
+}
+
+func (s *hostOwnership) IsDomainVerifiedByAnotherOrganization(string, uuid.UUID) (bool, error) {
+	return s.elsewhere, nil
+}
+
+func (s *hostOwnership) GetOrganizationDomainID(string, uuid.UUID) (*uuid.UUID, error) {
+	return s.domainID, nil
+}
+
+func (s *hostOwnership) IsRouteTaken(string, string, uuid.UUID, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func TestCreateDeploymentDomain(t *testing.T) {
+	domainID := uuid.New()
+	tests := []struct {
+		name    string
+		store   *hostOwnership
+		wantErr error
+	}{
+		{name: "Host under no verified domain", store: &hostOwnership{}, wantErr: types.ErrDomainNotVerified},
+		{name: "Host under a domain of another organization", store: &hostOwnership{elsewhere: true, domainID: &domainID}, wantErr: types.ErrDomainOwnedElsewhere},
+		{name: "Host under a verified domain", store: &hostOwnership{domainID: &domainID}, wantErr: types.ErrDomainAlreadyTaken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &TaskService{Storage: tt.store}
+			_, err := s.CreateDeploymentTask(&types.CreateDeploymentRequest{Domain: "shop.example.com"}, uuid.New(), uuid.New())
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
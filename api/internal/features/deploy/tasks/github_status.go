@@ -0,0 +1,119 @@
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+
+	github_service "github.com/raghavyuva/nixopus-api/internal/features/github-connector/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// githubReport holds what is needed to report the statuses of a deployment to GitHub.
+type githubReport struct {
+	userID      string
+	repoID      uint64
+	ref         string
+	environment string
+	url         string
+	production  bool
+}
+
+// newGithubReport returns nil unless the application opted in to GitHub deployment statuses
+// and is deployed from a GitHub repository.
+func newGithubReport(payload shared_types.TaskPayload) *githubReport {
+	application := payload.Application
+	if !application.ReportGithubStatus || application.Repository == "" {
+		return nil
+	}
+	repoID, err := strconv.ParseUint(application.Repository, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	ref := payload.ApplicationDeployment.CommitHash
+	if ref == "" {
+		ref = application.Branch
+	}
+
+	report := &githubReport{
+		userID:      application.UserID.String(),
+		repoID:      repoID,
+		ref:         ref,
+		environment: fmt.Sprintf("%s (%s)", application.Name, application.Environment),
+		production:  application.Environment == shared_types.Production,
+	}
+	if application.Domain != "" {
+		report.url = "https://" + application.Domain
+	}
+	return report
+}
+
+// githubDeploymentState maps a deployment status to the state reported to GitHub. Statuses
+// that are not part of a deployment's progress are not reported.
+func githubDeploymentState(status shared_types.Status) (github_service.DeploymentState, string, bool) {
+	switch status {
+	case shared_types.Started:
+		return github_service.DeploymentStateQueued, "Deployment queued", true
+	case shared_types.Cloning:
+		return github_service.DeploymentStateInProgress, "Cloning repository", true
+	case shared_types.Building:
+		return github_service.DeploymentStateInProgress, "Building", true
+	case shared_types.Deploying:
+		return github_service.DeploymentStateInProgress, "Deploying", true
+	case shared_types.Deployed, shared_types.Running:
+		return github_service.DeploymentStateSuccess, "Deployment succeeded", true
+	case shared_types.Failed:
+		return github_service.DeploymentStateFailure, "Deployment failed", true
+	default:
+		return "", "", false
+	}
+}
+
+// reportGithubStatus reports a status change to GitHub, creating the GitHub deployment on the
+// first one. Failures are logged and never fail the deployment.
+func (tc *TaskContext) reportGithubStatus(status shared_types.Status) {
+	if tc.github == nil || tc.service.Github_service == nil {
+		return
+	}
+	state, description, ok := githubDeploymentState(status)
+	if !ok {
+		return
+	}
+
+	githubDeploymentID, err := tc.service.Storage.GetGithubDeploymentID(tc.deploymentID)
+	if err != nil {
+		tc.service.Logger.Log(logger.Error, "Failed to get GitHub deployment: "+err.Error(), "")
+		return
+	}
+
+	if githubDeploymentID == 0 {
+		githubDeploymentID, err = tc.service.Github_service.CreateDeployment(github_service.CreateDeploymentConfig{
+			UserID:      tc.github.userID,
+			RepoID:      tc.github.repoID,
+			Ref:         tc.github.ref,
+			Environment: tc.github.environment,
+			Description: "Deployed by Nixopus",
+			Production:  tc.github.production,
+		})
+		if err != nil {
+			tc.service.Logger.Log(logger.Error, err.Error(), tc.deploymentID.String())
+			return
+		}
+		if err := tc.service.Storage.SetGithubDeploymentID(tc.deploymentID, githubDeploymentID); err != nil {
+			tc.service.Logger.Log(logger.Error, "Failed to save GitHub deployment: "+err.Error(), "")
+		}
+	}
+
+	err = tc.service.Github_service.CreateDeploymentStatus(github_service.DeploymentStatusConfig{
+		UserID:         tc.github.userID,
+		RepoID:         tc.github.repoID,
+		DeploymentID:   githubDeploymentID,
+		State:          state,
+		EnvironmentURL: tc.github.url,
+		Description:    description,
+	})
+	if err != nil {
+		tc.service.Logger.Log(logger.Error, err.Error(), tc.deploymentID.String())
+	}
+}
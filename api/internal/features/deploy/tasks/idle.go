@@ -0,0 +1,99 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// idleCheckInterval is how often the requests of scale-to-zero applications are checked.
+const idleCheckInterval = time.Minute
+
+// StartIdleMonitor puts scale-to-zero applications to sleep once the proxy routed no request to
+// them for their idle timeout. It stops when ctx is done.
+//
+// Requests are read from the access logs of Caddy, so only requests through the proxy keep an
+// application awake, not the traffic of its own connections. Without access logs, turned on by
+// ACCESS_LOG_PORT, applications are never put to sleep.
+func (t *TaskService) StartIdleMonitor(ctx context.Context) {
+	if config.AppConfig.Proxy.AccessLogPort == "" {
+		t.Logger.Log(logger.Warning, "Scale to zero is off, it needs the access logs of the proxy", "set ACCESS_LOG_PORT to turn it on")
+		return
+	}
+
+	go func() {
+		watched := make(map[uuid.UUID]time.Time)
+		ticker := time.NewTicker(idleCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.checkIdleApplications(watched)
+			}
+		}
+	}()
+}
+
+// checkIdleApplications puts the applications to sleep that saw no request for their idle
+// timeout. watched keeps when the monitor first saw each running application, so one that was
+// just deployed or woken gets its full idle timeout before its first request.
+func (t *TaskService) checkIdleApplications(watched map[uuid.UUID]time.Time) {
+	applications, err := t.Storage.GetScaleToZeroApplications()
+	if err != nil {
+		t.Logger.Log(logger.Error, "Failed to list scale-to-zero applications", err.Error())
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(applications))
+	for _, application := range applications {
+		ids = append(ids, application.ID)
+	}
+	lastRequests, err := t.Storage.GetLastRequestTimes(ids)
+	if err != nil {
+		t.Logger.Log(logger.Error, "Failed to read the requests of scale-to-zero applications", err.Error())
+		return
+	}
+
+	now := time.Now()
+	seen := make(map[uuid.UUID]bool, len(applications))
+	for _, application := range applications {
+		seen[application.ID] = true
+		if _, ok := watched[application.ID]; !ok {
+			watched[application.ID] = now
+		}
+
+		if !isIdle(application, watched[application.ID], lastRequests[application.ID], now) {
+			continue
+		}
+
+		t.Logger.Log(logger.Info, "Putting idle application to sleep", application.Name)
+		if err := t.SleepApplication(application); err != nil {
+			t.Logger.Log(logger.Error, "Failed to put idle application to sleep", application.Name+": "+err.Error())
+			continue
+		}
+		delete(watched, application.ID)
+	}
+
+	for id := range watched {
+		if !seen[id] {
+			delete(watched, id)
+		}
+	}
+}
+
+// isIdle reports whether the application received no request for its idle timeout, counting
+// from its last request or from when it was first watched, whichever is later.
+func isIdle(application shared_types.Application, watchedSince time.Time, lastRequest time.Time, now time.Time) bool {
+	lastActive := watchedSince
+	if lastRequest.After(lastActive) {
+		lastActive = lastRequest
+	}
+	return now.Sub(lastActive) >= time.Duration(application.IdleTimeout)*time.Minute
+}
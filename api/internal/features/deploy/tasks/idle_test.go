@@ -0,0 +1,56 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsIdle(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	application := shared_types.Application{IdleTimeout: 15}
+
+	tests := []struct {
+		name         string
+		watchedSince time.Time
+		lastRequest  time.Time
+		want         bool
+	}{
+		{
+			name:         "Just watched without requests",
+			watchedSince: now.Add(-time.Minute),
+			want:         false,
+		},
+		{
+			name:         "Watched for the idle timeout without requests",
+			watchedSince: now.Add(-15 * time.Minute),
+			want:         true,
+		},
+		{
+			name:         "Recent request",
+			watchedSince: now.Add(-time.Hour),
+			lastRequest:  now.Add(-5 * time.Minute),
+			want:         false,
+		},
+		{
+			name:         "Last request older than the idle timeout",
+			watchedSince: now.Add(-time.Hour),
+			lastRequest:  now.Add(-20 * time.Minute),
+			want:         true,
+		},
+		{
+			name:         "Old request before a recent wake",
+			watchedSince: now.Add(-2 * time.Minute),
+			lastRequest:  now.Add(-time.Hour),
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isIdle(application, tt.watchedSince, tt.lastRequest, now))
+		})
+	}
+}
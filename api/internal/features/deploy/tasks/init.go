@@ -6,8 +6,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/raghavyuva/caddygo"
-	"github.com/raghavyuva/nixopus-api/internal/config"
 	types "github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
 	"github.com/raghavyuva/nixopus-api/internal/queue"
 	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
@@ -41,15 +39,6 @@ var (
 	TASK_RESTART            = "task_restart_deployment"
 )
 
-var caddyClient *caddygo.Client
-
-func GetCaddyClient() *caddygo.Client {
-	if caddyClient == nil {
-		caddyClient = caddygo.NewClient(config.AppConfig.Proxy.CaddyEndpoint)
-	}
-	return caddyClient
-}
-
 func (t *TaskService) SetupCreateDeploymentQueue() {
 	onceQueues.Do(func() {
 		CreateDeploymentQueue = queue.RegisterQueue(&taskq.QueueOptions{
@@ -66,9 +55,12 @@ func (t *TaskService) SetupCreateDeploymentQueue() {
 		TaskCreateDeployment = taskq.RegisterTask(&taskq.TaskOptions{
 			Name:       TASK_CREATE_DEPLOYMENT,
 			RetryLimit: 5,
+			FallbackHandler: func(msg *taskq.Message) error {
+				return queue.RecordDeadLetter(QUEUE_CREATE_DEPLOYMENT, msg)
+			},
 			Handler: func(ctx context.Context, data shared_types.TaskPayload) error {
 				fmt.Printf("[%s] start: correlation_id=%s\n", TASK_CREATE_DEPLOYMENT, data.CorrelationID)
-				err := t.BuildPack(ctx, data)
+				err := t.runAttempt(ctx, data, TaskCreateDeployment.Options().RetryLimit, t.BuildPack)
 				if err != nil {
 					fmt.Print("error handling create deployment: ", err)
 					return err
@@ -92,9 +84,12 @@ func (t *TaskService) SetupCreateDeploymentQueue() {
 		TaskUpdateDeployment = taskq.RegisterTask(&taskq.TaskOptions{
 			Name:       TASK_UPDATE_DEPLOYMENT,
 			RetryLimit: 5,
+			FallbackHandler: func(msg *taskq.Message) error {
+				return queue.RecordDeadLetter(QUEUE_UPDATE_DEPLOYMENT, msg)
+			},
 			Handler: func(ctx context.Context, data shared_types.TaskPayload) error {
 				fmt.Println("Updating deployment")
-				err := t.HandleUpdateDeployment(ctx, data)
+				err := t.runAttempt(ctx, data, TaskUpdateDeployment.Options().RetryLimit, t.HandleUpdateDeployment)
 				if err != nil {
 					return err
 				}
@@ -117,9 +112,12 @@ func (t *TaskService) SetupCreateDeploymentQueue() {
 		TaskReDeploy = taskq.RegisterTask(&taskq.TaskOptions{
 			Name:       TASK_REDEPLOYMENT,
 			RetryLimit: 5,
+			FallbackHandler: func(msg *taskq.Message) error {
+				return queue.RecordDeadLetter(QUEUE_REDEPLOYMENT, msg)
+			},
 			Handler: func(ctx context.Context, data shared_types.TaskPayload) error {
 				fmt.Println("Redeploying application")
-				err := t.HandleReDeploy(ctx, data)
+				err := t.runAttempt(ctx, data, TaskReDeploy.Options().RetryLimit, t.HandleReDeploy)
 				if err != nil {
 					return err
 				}
@@ -142,9 +140,12 @@ func (t *TaskService) SetupCreateDeploymentQueue() {
 		TaskRollback = taskq.RegisterTask(&taskq.TaskOptions{
 			Name:       TASK_ROLLBACK,
 			RetryLimit: 10,
+			FallbackHandler: func(msg *taskq.Message) error {
+				return queue.RecordDeadLetter(QUEUE_ROLLBACK, msg)
+			},
 			Handler: func(ctx context.Context, data shared_types.TaskPayload) error {
 				fmt.Println("Rolling back deployment")
-				err := t.HandleRollback(ctx, data)
+				err := t.runAttempt(ctx, data, TaskRollback.Options().RetryLimit, t.HandleRollback)
 				if err != nil {
 					return err
 				}
@@ -167,9 +168,13 @@ func (t *TaskService) SetupCreateDeploymentQueue() {
 		TaskRestart = taskq.RegisterTask(&taskq.TaskOptions{
 			Name:       TASK_RESTART,
 			RetryLimit: 5,
+			FallbackHandler: func(msg *taskq.Message) error {
+				return queue.RecordDeadLetter(QUEUE_RESTART, msg)
+			},
 			Handler: func(ctx context.Context, data shared_types.TaskPayload) error {
 				fmt.Println("Restarting deployment")
-				err := t.HandleRestart(ctx, data)
+				// a restart is no deployment, it is retried without counting attempts or sending deployment events
+				err := classifyError(t.HandleRestart(ctx, data))
 				if err != nil {
 					return err
 				}
@@ -187,18 +192,7 @@ func (t *TaskService) BuildPack(ctx context.Context, d shared_types.TaskPayload)
 	var err error
 	switch d.Application.BuildPack {
 	case shared_types.DockerFile:
-		err = t.PrerunCommands(d)
-		if err != nil {
-			return err
-		}
 		err = t.HandleCreateDockerfileDeployment(ctx, d)
-		if err != nil {
-			return err
-		}
-		err = t.PostRunCommands(d)
-		if err != nil {
-			return err
-		}
 	case shared_types.DockerCompose:
 		err = t.HandleCreateDockerComposeDeployment(ctx, d)
 	case shared_types.Static:
@@ -0,0 +1,250 @@
+package tasks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"golang.org/x/sync/singleflight"
+)
+
+// wakeGroup makes concurrent requests to the same sleeping application wait for a single start.
+var wakeGroup singleflight.Group
+
+// WakePath is the API path, relative to the API version prefix, that wakes sleeping applications.
+func WakePath(applicationID uuid.UUID) string {
+	return "/wake/" + applicationID.String()
+}
+
+// WakeToken returns the token the proxy sends along with requests for a sleeping application.
+func WakeToken(applicationID uuid.UUID) string {
+	mac := hmac.New(sha256.New, shared_types.JWTSecretKey)
+	mac.Write([]byte("wake:" + applicationID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidWakeToken reports whether token was issued by WakeToken for the application.
+func ValidWakeToken(applicationID uuid.UUID, token string) bool {
+	return hmac.Equal([]byte(token), []byte(WakeToken(applicationID)))
+}
+
+// StopApplication scales the service of the application to zero and serves a "stopped" page on its domain.
+func (t *TaskService) StopApplication(applicationID uuid.UUID, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(applicationID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	if application.BuildPack == shared_types.Static {
+		return shared_types.Application{}, types.ErrStaticSiteCannotBeStopped
+	}
+
+	if application.BuildPack == shared_types.DockerCompose {
+		return shared_types.Application{}, types.ErrComposeCannotBeStopped
+	}
+
+	if application.RunState == shared_types.RunStateStopped {
+		return shared_types.Application{}, types.ErrApplicationAlreadyStopped
+	}
+
+	site := applicationSite(application)
+	site.Stopped = application.Name
+	if err := t.scaleToZero(application, site); err != nil {
+		return shared_types.Application{}, err
+	}
+
+	application.RunState = shared_types.RunStateStopped
+	if err := t.Storage.SetApplicationRunState(application.ID, application.RunState); err != nil {
+		return shared_types.Application{}, err
+	}
+
+	return application, nil
+}
+
+// StartApplication scales a stopped or sleeping application back up and routes its domain to it
+// once the service is healthy.
+func (t *TaskService) StartApplication(ctx context.Context, applicationID uuid.UUID, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(applicationID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	if application.RunState == shared_types.RunStateRunning {
+		return shared_types.Application{}, types.ErrApplicationNotStopped
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, types.WakeTimeout)
+	defer cancel()
+
+	if err := t.startApplication(ctx, &application); err != nil {
+		return shared_types.Application{}, err
+	}
+
+	return application, nil
+}
+
+// SleepApplication scales an idle application to zero. Its domain is routed to the API, which
+// wakes the application on the next request.
+func (t *TaskService) SleepApplication(application shared_types.Application) error {
+	if err := t.scaleToZero(application, sleepingSite(application)); err != nil {
+		return err
+	}
+
+	return t.Storage.SetApplicationRunState(application.ID, shared_types.RunStateSleeping)
+}
+
+// sleepingSite returns the site of a sleeping application, routing its domain to the API.
+func sleepingSite(application shared_types.Application) proxy.Site {
+	site := applicationSite(application)
+	site.Wake = &proxy.Wake{
+		APIUpstream: config.AppConfig.SSH.Host + ":" + config.AppConfig.Server.Port,
+		Path:        "/api/v1" + WakePath(application.ID),
+		TokenHeader: types.WakeTokenHeader,
+		Token:       WakeToken(application.ID),
+	}
+	return site
+}
+
+// WakeApplication starts a sleeping application and returns it once it can serve requests.
+// Applications that are already running are returned right away.
+func (t *TaskService) WakeApplication(ctx context.Context, applicationID uuid.UUID) (shared_types.Application, error) {
+	result, err, _ := wakeGroup.Do(applicationID.String(), func() (interface{}, error) {
+		application, err := t.Storage.FindApplicationByID(applicationID)
+		if err != nil {
+			return shared_types.Application{}, err
+		}
+
+		switch application.RunState {
+		case shared_types.RunStateRunning:
+			return application, nil
+		case shared_types.RunStateStopped:
+			return shared_types.Application{}, types.ErrApplicationAlreadyStopped
+		}
+
+		// the wake must not be cancelled when the request that triggered it goes away,
+		// other visitors are waiting on the same start
+		wakeCtx, cancel := context.WithTimeout(context.Background(), types.WakeTimeout)
+		defer cancel()
+
+		t.Logger.Log(logger.Info, "Waking sleeping application", application.Name)
+		if err := t.startApplication(wakeCtx, &application); err != nil {
+			return shared_types.Application{}, err
+		}
+		return application, nil
+	})
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	return result.(shared_types.Application), nil
+}
+
+// ApplicationUpstream returns the address the proxy dials to reach the application.
+func (t *TaskService) ApplicationUpstream(application shared_types.Application) (string, error) {
+	port, err := t.getAvailablePort(shared_types.TaskPayload{Application: application})
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(config.AppConfig.SSH.Host, port), nil
+}
+
+// scaleToZero routes the domain of the application to site and then scales its service to zero,
+// so that visitors never reach an upstream that is shutting down.
+func (t *TaskService) scaleToZero(application shared_types.Application, site proxy.Site) error {
+	service, err := t.findApplicationService(application)
+	if err != nil {
+		return err
+	}
+
+	if err := t.proxyFor(application).UpdateRoute(site); err != nil {
+		return err
+	}
+
+	return t.DockerRepo.ScaleService(service.ID, 0, "")
+}
+
+// startApplication scales the service of the application to one replica, waits until it accepts
+// connections and routes the domain back to it.
+func (t *TaskService) startApplication(ctx context.Context, application *shared_types.Application) error {
+	service, err := t.findApplicationService(*application)
+	if err != nil {
+		return err
+	}
+
+	if err := t.DockerRepo.ScaleService(service.ID, 1, ""); err != nil {
+		return err
+	}
+
+	availablePort, err := t.getAvailablePort(shared_types.TaskPayload{Application: *application})
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(availablePort)
+	if err != nil {
+		return err
+	}
+
+	upstream := net.JoinHostPort(config.AppConfig.SSH.Host, availablePort)
+	if err := t.waitForService(ctx, service.ID, upstream); err != nil {
+		return err
+	}
+
+	if err := t.routeDomain(*application, port); err != nil {
+		return err
+	}
+
+	application.RunState = shared_types.RunStateRunning
+	return t.Storage.SetApplicationRunState(application.ID, application.RunState)
+}
+
+// waitForService polls the service until a replica is running and its published port accepts connections.
+func (t *TaskService) waitForService(ctx context.Context, serviceID string, upstream string) error {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		service, err := t.DockerRepo.GetServiceByID(serviceID)
+		if err == nil {
+			running, _, err := t.DockerRepo.GetServiceHealth(service)
+			if err == nil && running > 0 {
+				conn, err := net.DialTimeout("tcp", upstream, time.Second)
+				if err == nil {
+					conn.Close()
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return types.ErrServiceNotHealthy
+		case <-ticker.C:
+		}
+	}
+}
+
+// findApplicationService returns the swarm service running the application.
+func (t *TaskService) findApplicationService(application shared_types.Application) (*swarm.Service, error) {
+	services, err := t.DockerRepo.GetClusterServices()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range services {
+		if service.Spec.Annotations.Name == application.Name {
+			return &service, nil
+		}
+	}
+	return nil, types.ErrServiceNotFound
+}
@@ -0,0 +1,111 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	audit_service "github.com/raghavyuva/nixopus-api/internal/features/audit/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// UpdateMaintenance stores the maintenance settings of an application and applies them to the
+// route of its domain right away. The containers keep running either way. The change is
+// recorded in the audit log on behalf of userID.
+func (t *TaskService) UpdateMaintenance(request *types.UpdateMaintenanceRequest, userID uuid.UUID, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(request.ID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	previous := application.Maintenance
+	application.Maintenance = request.MaintenanceSettings
+	if err := t.Storage.UpdateMaintenance(application.ID, application.Maintenance); err != nil {
+		return shared_types.Application{}, err
+	}
+	t.auditMaintenance(application, previous, userID, map[string]any{"trigger": "api"})
+
+	if err := t.refreshRoute(application); err != nil {
+		return application, fmt.Errorf("%w: %v", types.ErrApplyMaintenance, err)
+	}
+	return application, nil
+}
+
+// beginReleaseMaintenance puts the application of a deployment in maintenance while its release
+// commands run, when it asks for it and is not in maintenance already. The application of the
+// payload keeps maintenance on, so the route rendered by the rollout serves it as well.
+//
+// The returned function takes the application out of maintenance again, unless it was turned on
+// through the API in the meantime. It must run once the post run command finished or the
+// deployment failed.
+func (t *TaskService) beginReleaseMaintenance(payload *shared_types.TaskPayload, taskCtx *TaskContext) func() {
+	application := &payload.Application
+	if !application.Maintenance.DuringReleaseCommands || application.Maintenance.Enabled ||
+		(strings.TrimSpace(application.PreRunCommand) == "" && strings.TrimSpace(application.PostRunCommand) == "") {
+		return func() {}
+	}
+
+	previous := application.Maintenance
+	application.Maintenance.Enabled = true
+	if err := t.refreshRoute(*application); err != nil {
+		taskCtx.AddLog("Failed to turn on maintenance mode: " + err.Error())
+	} else {
+		taskCtx.AddLog("Maintenance mode turned on for the release commands")
+	}
+	metadata := map[string]any{
+		"trigger":       "release_commands",
+		"deployment_id": payload.ApplicationDeployment.ID.String(),
+	}
+	t.auditMaintenance(*application, previous, application.UserID, metadata)
+
+	return func() {
+		current, err := t.Storage.FindApplicationByID(application.ID)
+		if err != nil {
+			taskCtx.AddLog("Failed to turn off maintenance mode: " + err.Error())
+			return
+		}
+		if current.Maintenance.Enabled {
+			taskCtx.AddLog("Maintenance mode was turned on meanwhile, leaving it on")
+			return
+		}
+		if err := t.refreshRoute(current); err != nil {
+			taskCtx.AddLog("Failed to turn off maintenance mode: " + err.Error())
+			return
+		}
+		taskCtx.AddLog("Maintenance mode turned off")
+		t.auditMaintenance(current, application.Maintenance, application.UserID, metadata)
+	}
+}
+
+// auditMaintenance records a change of the maintenance mode of an application in the audit log.
+// The bypass token is left out of the recorded values.
+func (t *TaskService) auditMaintenance(application shared_types.Application, previous shared_types.MaintenanceSettings, userID uuid.UUID, metadata map[string]any) {
+	request := &audit_service.AuditLogRequest{
+		UserID:         userID,
+		OrganizationID: application.OrganizationID,
+		Action:         shared_types.AuditActionUpdate,
+		ResourceType:   shared_types.AuditResourceApplication,
+		ResourceID:     application.ID,
+		OldValues:      maintenanceAuditValues(previous),
+		NewValues:      maintenanceAuditValues(application.Maintenance),
+		Metadata:       metadata,
+		RequestID:      uuid.New(),
+	}
+	if err := audit_service.NewAuditService(t.Store.DB, context.Background(), t.Logger).LogAction(request); err != nil {
+		t.Logger.Log(logger.Warning, "Failed to audit maintenance mode", err.Error())
+	}
+}
+
+func maintenanceAuditValues(settings shared_types.MaintenanceSettings) map[string]any {
+	return map[string]any{
+		"maintenance_enabled":     settings.Enabled,
+		"redirect_url":            settings.RedirectURL,
+		"custom_page":             settings.Page != "",
+		"bypass_ips":              settings.BypassIPs,
+		"bypass_token":            settings.BypassToken != "",
+		"during_release_commands": settings.DuringReleaseCommands,
+	}
+}
@@ -0,0 +1,59 @@
+package tasks
+
+import (
+	"testing"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeginReleaseMaintenanceSkipped(t *testing.T) {
+	tests := []struct {
+		name        string
+		application shared_types.Application
+	}{
+		{
+			name:        "Not asked for",
+			application: shared_types.Application{PreRunCommand: "migrate"},
+		},
+		{
+			name: "Already in maintenance",
+			application: shared_types.Application{
+				PreRunCommand: "migrate",
+				Maintenance:   shared_types.MaintenanceSettings{Enabled: true, DuringReleaseCommands: true},
+			},
+		},
+		{
+			name: "No release commands",
+			application: shared_types.Application{
+				PreRunCommand: "  ",
+				Maintenance:   shared_types.MaintenanceSettings{DuringReleaseCommands: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := shared_types.TaskPayload{Application: tt.application}
+			// the route is never refreshed, so no proxy or storage is needed
+			end := (&TaskService{}).beginReleaseMaintenance(&payload, nil)
+			end()
+			assert.Equal(t, tt.application.Maintenance, payload.Application.Maintenance)
+		})
+	}
+}
+
+func TestMaintenanceAuditValues(t *testing.T) {
+	values := maintenanceAuditValues(shared_types.MaintenanceSettings{
+		Enabled:     true,
+		Page:        "<h1>Back soon</h1>",
+		BypassIPs:   []string{"203.0.113.7"},
+		BypassToken: "let-me-in-please",
+	})
+	assert.Equal(t, true, values["maintenance_enabled"])
+	assert.Equal(t, true, values["custom_page"])
+	// the token itself is never recorded
+	assert.Equal(t, true, values["bypass_token"])
+	assert.NotContains(t, values, "page")
+	assert.Equal(t, []string{"203.0.113.7"}, values["bypass_ips"])
+}
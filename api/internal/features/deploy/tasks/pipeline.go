@@ -0,0 +1,125 @@
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// DockerfileDeployment describes a Dockerfile deployment run by runDockerfileDeployment.
+type DockerfileDeployment struct {
+	DeploymentType string
+	StartMessage   string
+	DoneMessage    string
+}
+
+// runDockerfileDeployment clones and builds the application image, runs the release commands,
+// rolls out the swarm service and routes the domain to it.
+//
+// Phases completed by a previous attempt of the same deployment are skipped, so a retry after a
+// failed rollout neither rebuilds the image nor runs the pre run command a second time.
+func (s *TaskService) runDockerfileDeployment(TaskPayload shared_types.TaskPayload, d DockerfileDeployment) error {
+	taskCtx := s.NewTaskContext(TaskPayload)
+
+	if taskCtx.PhaseCompleted(shared_types.PhaseBuilt) {
+		taskCtx.LogAndUpdateStatus("Image was built by a previous attempt, skipping clone and build", shared_types.Deploying)
+	} else {
+		taskCtx.LogAndUpdateStatus(d.StartMessage, shared_types.Cloning)
+
+		repoPath, err := s.Clone(CloneConfig{
+			TaskPayload:    TaskPayload,
+			DeploymentType: d.DeploymentType,
+			TaskContext:    taskCtx,
+		})
+		if err != nil {
+			taskCtx.LogAndUpdateStatus("Failed to clone repository: "+err.Error(), shared_types.Failed)
+			return err
+		}
+
+		taskCtx.LogAndUpdateStatus("Repository cloned successfully", shared_types.Building)
+		taskCtx.AddLog("Building image from Dockerfile " + repoPath + " for application " + TaskPayload.Application.Name)
+		buildImageResult, err := s.BuildImage(BuildConfig{
+			TaskPayload:       TaskPayload,
+			ContextPath:       repoPath,
+			Force:             TaskPayload.UpdateOptions.Force,
+			ForceWithoutCache: TaskPayload.UpdateOptions.ForceWithoutCache,
+			TaskContext:       taskCtx,
+		})
+		if err != nil {
+			taskCtx.LogAndUpdateStatus("Failed to build image: "+err.Error(), shared_types.Failed)
+			return err
+		}
+
+		taskCtx.AddLog("Image built successfully: " + buildImageResult + " for application " + TaskPayload.Application.Name)
+		taskCtx.CompletePhase(shared_types.PhaseBuilt)
+	}
+
+	taskCtx.UpdateStatus(shared_types.Deploying)
+
+	endMaintenance := s.beginReleaseMaintenance(&TaskPayload, taskCtx)
+	defer endMaintenance()
+
+	if !taskCtx.PhaseCompleted(shared_types.PhasePreRun) {
+		err := s.PrerunCommands(TaskPayload, taskCtx)
+		if err != nil {
+			taskCtx.LogAndUpdateStatus("Pre run command failed, aborting rollout: "+err.Error(), shared_types.Failed)
+			return err
+		}
+		taskCtx.CompletePhase(shared_types.PhasePreRun)
+	}
+
+	var availablePort string
+	if taskCtx.PhaseCompleted(shared_types.PhaseRolledOut) {
+		taskCtx.AddLog("Service was rolled out by a previous attempt")
+		port, err := s.getAvailablePort(TaskPayload)
+		if err != nil {
+			taskCtx.LogAndUpdateStatus("Failed to get available port: "+err.Error(), shared_types.Failed)
+			return err
+		}
+		availablePort = port
+	} else {
+		containerResult, err := s.AtomicUpdateContainer(TaskPayload, taskCtx)
+		if err != nil {
+			taskCtx.LogAndUpdateStatus("Failed to update container: "+err.Error(), shared_types.Failed)
+			return err
+		}
+
+		taskCtx.AddLog("Container updated successfully for application " + TaskPayload.Application.Name + " with container id " + containerResult.ContainerID)
+		taskCtx.CompletePhase(shared_types.PhaseRolledOut)
+		availablePort = containerResult.AvailablePort
+	}
+
+	taskCtx.LogAndUpdateStatus(d.DoneMessage, shared_types.Deployed)
+
+	port, err := strconv.Atoi(availablePort)
+	if err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to convert port to int: "+err.Error(), shared_types.Failed)
+		return err
+	}
+
+	err = s.routeDomain(TaskPayload.Application, port)
+	if err != nil {
+		fmt.Println("Failed to add domain: ", err)
+		taskCtx.LogAndUpdateStatus("Failed to add domain: "+err.Error(), shared_types.Failed)
+		return err
+	}
+
+	// a deployment brings a stopped or sleeping application back up
+	if TaskPayload.Application.RunState != shared_types.RunStateRunning {
+		if err := s.Storage.SetApplicationRunState(TaskPayload.Application.ID, shared_types.RunStateRunning); err != nil {
+			taskCtx.AddLog("Failed to update application run state: " + err.Error())
+		}
+	}
+
+	if !taskCtx.PhaseCompleted(shared_types.PhasePostRun) {
+		err = s.PostRunCommands(TaskPayload, taskCtx)
+		if err != nil {
+			taskCtx.LogAndUpdateStatus("Post run command failed: "+err.Error(), shared_types.Failed)
+			return err
+		}
+		taskCtx.CompletePhase(shared_types.PhasePostRun)
+	}
+
+	return nil
+}
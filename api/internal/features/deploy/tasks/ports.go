@@ -0,0 +1,318 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/ssh"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// AddApplicationPort exposes a TCP or UDP port of an application. A fixed published port must
+// be free on the server, ports routed by SNI are published on an allocated port that the layer4
+// server of Caddy forwards the TLS connections for the server name to. The server name must be
+// under a domain the organization verified.
+func (t *TaskService) AddApplicationPort(request *types.AddApplicationPortRequest, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(request.ID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+	if application.BuildPack != shared_types.DockerFile {
+		return shared_types.Application{}, types.ErrPortsNeedDockerfile
+	}
+
+	if request.SNI != "" {
+		if config.AppConfig.Proxy.Layer4Listen == "" {
+			return shared_types.Application{}, types.ErrSNIRoutingDisabled
+		}
+		// like the domains of applications, server names are limited to verified domains
+		if err := t.requireVerifiedHost(request.SNI, organizationID); err != nil {
+			return shared_types.Application{}, err
+		}
+	} else {
+		// reserved ports of the server are rejected by the validator
+		taken, err := t.publishedPortTaken(application, request.Protocol, request.PublishedPort)
+		if err != nil {
+			return shared_types.Application{}, err
+		}
+		if taken {
+			return shared_types.Application{}, types.ErrPublishedPortTaken
+		}
+	}
+
+	record := &shared_types.ApplicationPort{
+		ID:            uuid.New(),
+		ApplicationID: application.ID,
+		ServerHost:    config.AppConfig.SSH.Host,
+		Protocol:      request.Protocol,
+		TargetPort:    request.TargetPort,
+		PublishedPort: request.PublishedPort,
+		SNI:           request.SNI,
+		CreatedAt:     time.Now(),
+	}
+	if err := t.Storage.AddApplicationPort(record); err != nil {
+		return shared_types.Application{}, err
+	}
+
+	return t.republishPorts(application.ID, organizationID, record.SNI != "")
+}
+
+// RemoveApplicationPort stops exposing a port of an application.
+func (t *TaskService) RemoveApplicationPort(request *types.RemoveApplicationPortRequest, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(request.ID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	var record *shared_types.ApplicationPort
+	for _, port := range application.Ports {
+		if port.ID == request.ApplicationPortID {
+			record = port
+			break
+		}
+	}
+	if record == nil {
+		return shared_types.Application{}, types.ErrApplicationPortNotFound
+	}
+
+	if err := t.Storage.DeleteApplicationPort(record.ID); err != nil {
+		return shared_types.Application{}, err
+	}
+
+	return t.republishPorts(application.ID, organizationID, record.SNI != "")
+}
+
+// republishPorts reloads an application after its ports changed and publishes them on its
+// service. The routes of the layer4 server are only rewritten when a port routed by SNI changed.
+func (t *TaskService) republishPorts(applicationID uuid.UUID, organizationID uuid.UUID, passthrough bool) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(applicationID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+	if err := t.publishPorts(application); err != nil {
+		return application, fmt.Errorf("%w: %v", types.ErrApplyPorts, err)
+	}
+	if passthrough {
+		if err := t.syncTLSPassthrough(); err != nil {
+			return application, fmt.Errorf("%w: %v", types.ErrApplyPorts, err)
+		}
+	}
+	return application, nil
+}
+
+// publishPorts replaces the published ports of the service of the application. The spec of a
+// stopped or sleeping service is updated as well, so the ports are there once it scales up.
+// Applications that were never deployed publish them with their first deployment.
+func (t *TaskService) publishPorts(application shared_types.Application) error {
+	service, err := t.findApplicationService(application)
+	if errors.Is(err, types.ErrServiceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	availablePort, err := t.getAvailablePort(shared_types.TaskPayload{Application: application})
+	if err != nil {
+		return err
+	}
+	httpPort, err := strconv.Atoi(availablePort)
+	if err != nil {
+		return err
+	}
+	ports, err := t.servicePorts(application, httpPort)
+	if err != nil {
+		return err
+	}
+
+	spec := service.Spec
+	if spec.EndpointSpec == nil {
+		spec.EndpointSpec = &swarm.EndpointSpec{Mode: swarm.ResolutionModeVIP}
+	}
+	spec.EndpointSpec.Ports = ports
+	return t.DockerRepo.UpdateService(service.ID, spec, "")
+}
+
+// servicePorts returns the ports the service of the application publishes on its server: the
+// HTTP port the proxy routes its domains to, followed by the exposed ports.
+func (t *TaskService) servicePorts(application shared_types.Application, httpPort int) ([]swarm.PortConfig, error) {
+	exposed, err := t.Storage.GetApplicationPorts(application.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := []swarm.PortConfig{
+		{
+			Protocol:      swarm.PortConfigProtocolTCP,
+			TargetPort:    uint32(application.Port),
+			PublishedPort: uint32(httpPort),
+			PublishMode:   swarm.PortConfigPublishModeHost,
+		},
+	}
+	for _, port := range exposed {
+		protocol := swarm.PortConfigProtocolTCP
+		if port.Protocol == shared_types.PortProtocolUDP {
+			protocol = swarm.PortConfigProtocolUDP
+		}
+		ports = append(ports, swarm.PortConfig{
+			Protocol:      protocol,
+			TargetPort:    uint32(port.TargetPort),
+			PublishedPort: uint32(port.PublishedPort),
+			PublishMode:   swarm.PortConfigPublishModeHost,
+		})
+	}
+	return ports, nil
+}
+
+// syncTLSPassthrough routes the ports of the server that are routed by SNI through the layer4
+// server of Caddy. Nothing is done while SNI routing is turned off.
+func (t *TaskService) syncTLSPassthrough() error {
+	listen := config.AppConfig.Proxy.Layer4Listen
+	if listen == "" {
+		return nil
+	}
+
+	host := config.AppConfig.SSH.Host
+	ports, err := t.Storage.GetSNIPorts(host)
+	if err != nil {
+		return err
+	}
+	routes := make([]proxy.PassthroughRoute, 0, len(ports))
+	for _, port := range ports {
+		routes = append(routes, proxy.PassthroughRoute{
+			ServerName: port.SNI,
+			Upstream:   net.JoinHostPort(host, strconv.Itoa(port.PublishedPort)),
+		})
+	}
+	return proxy.NewCaddy(&t.Logger, "", "", "", proxy.ReverseProxy).SyncTLSPassthrough(listen, routes)
+}
+
+// publishedPortTaken reports whether port is already used on the server: published by a swarm
+// service other than the one of the application, like databases or services created outside of
+// Nixopus, or listened on by a process of the host.
+func (t *TaskService) publishedPortTaken(application shared_types.Application, protocol shared_types.PortProtocol, port int) (bool, error) {
+	services, err := t.DockerRepo.GetClusterServices()
+	if err != nil {
+		return false, err
+	}
+	listening, err := hostListeningPorts(protocol)
+	if err != nil {
+		return false, fmt.Errorf("failed to list the ports in use on the server: %w", err)
+	}
+	return portTaken(services, listening, application.Name, protocol, port), nil
+}
+
+// portTaken reports whether port is published by a service other than the application's own one,
+// or held by a listener of the host. The ports of the application's own service are listened on
+// by Docker on its behalf, so they do not count as taken.
+func portTaken(services []swarm.Service, listening []int, applicationName string, protocol shared_types.PortProtocol, port int) bool {
+	ownPublished := false
+	for _, service := range services {
+		own := service.Spec.Annotations.Name == applicationName
+		for _, published := range servicePublishedPorts(service) {
+			if int(published.PublishedPort) != port || string(published.Protocol) != string(protocol) {
+				continue
+			}
+			if !own {
+				return true
+			}
+			ownPublished = true
+		}
+	}
+	return !ownPublished && slices.Contains(listening, port)
+}
+
+// portAllocationOptions returns how a host port is picked for an application without one: the
+// port its service already publishes is kept, and ports published by other services or held by
+// listeners on the server are left out.
+func (t *TaskService) portAllocationOptions(application shared_types.Application) (types.AllocatePortOptions, error) {
+	var options types.AllocatePortOptions
+	services, err := t.DockerRepo.GetClusterServices()
+	if err != nil {
+		return options, err
+	}
+	for _, service := range services {
+		own := service.Spec.Annotations.Name == application.Name
+		for _, published := range servicePublishedPorts(service) {
+			if published.Protocol != swarm.PortConfigProtocolTCP || published.PublishedPort == 0 {
+				continue
+			}
+			if own && int(published.TargetPort) == application.Port && options.Preferred == 0 {
+				options.Preferred = int(published.PublishedPort)
+				continue
+			}
+			options.InUse = append(options.InUse, int(published.PublishedPort))
+		}
+	}
+
+	listening, err := hostListeningPorts(shared_types.PortProtocolTCP)
+	if err != nil {
+		return options, fmt.Errorf("failed to list the ports in use on the server: %w", err)
+	}
+	for _, port := range listening {
+		if port != options.Preferred {
+			options.InUse = append(options.InUse, port)
+		}
+	}
+	return options, nil
+}
+
+// servicePublishedPorts returns the ports a service publishes, both those of its spec and
+// those the swarm assigned to its endpoint.
+func servicePublishedPorts(service swarm.Service) []swarm.PortConfig {
+	var ports []swarm.PortConfig
+	if service.Spec.EndpointSpec != nil {
+		ports = append(ports, service.Spec.EndpointSpec.Ports...)
+	}
+	return append(ports, service.Endpoint.Ports...)
+}
+
+// hostListeningPorts returns the TCP or UDP ports listened on on the server.
+func hostListeningPorts(protocol shared_types.PortProtocol) ([]int, error) {
+	client, err := ssh.NewSSH().Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	command := "ss -Htln 2>/dev/null || netstat -tln"
+	if protocol == shared_types.PortProtocolUDP {
+		command = "ss -Huln 2>/dev/null || netstat -uln"
+	}
+	output, err := client.Run(command)
+	if err != nil {
+		return nil, err
+	}
+	return parseListeningPorts(string(output)), nil
+}
+
+// parseListeningPorts reads the ports of the local addresses in the output of `ss -H` or
+// `netstat`, where the local address is the fourth column. Header lines are skipped.
+func parseListeningPorts(output string) []int {
+	seen := make(map[int]bool)
+	var ports []int
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		address := fields[3]
+		port, err := strconv.Atoi(address[strings.LastIndex(address, ":")+1:])
+		if err != nil || port <= 0 || port > 65535 || seen[port] {
+			continue
+		}
+		seen[port] = true
+		ports = append(ports, port)
+	}
+	return ports
+}
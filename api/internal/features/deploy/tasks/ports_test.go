@@ -0,0 +1,129 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseListeningPorts(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []int
+	}{
+		{
+			name: "ss",
+			output: "LISTEN 0      4096         0.0.0.0:22         0.0.0.0:*\n" +
+				"LISTEN 0      4096       127.0.0.1:2019       0.0.0.0:*\n" +
+				"LISTEN 0      4096            [::]:22            [::]:*\n" +
+				"LISTEN 0      4096               *:49200            *:*\n",
+			want: []int{22, 2019, 49200},
+		},
+		{
+			name: "netstat with headers",
+			output: "Active Internet connections (only servers)\n" +
+				"Proto Recv-Q Send-Q Local Address           Foreign Address         State\n" +
+				"tcp        0      0 0.0.0.0:5432            0.0.0.0:*               LISTEN\n" +
+				"tcp6       0      0 :::8443                 :::*                    LISTEN\n",
+			want: []int{5432, 8443},
+		},
+		{
+			name:   "empty",
+			output: "",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseListeningPorts(tt.output))
+		})
+	}
+}
+
+func TestPortTaken(t *testing.T) {
+	service := func(name string, protocol swarm.PortConfigProtocol, published uint32) swarm.Service {
+		s := swarm.Service{Endpoint: swarm.Endpoint{Ports: []swarm.PortConfig{{Protocol: protocol, PublishedPort: published, TargetPort: 1}}}}
+		s.Spec.Annotations.Name = name
+		return s
+	}
+	services := []swarm.Service{
+		service("app", swarm.PortConfigProtocolTCP, 1883),
+		service("postgres", swarm.PortConfigProtocolTCP, 15432),
+		service("dns", swarm.PortConfigProtocolUDP, 5353),
+	}
+
+	tests := []struct {
+		name      string
+		listening []int
+		protocol  shared_types.PortProtocol
+		port      int
+		want      bool
+	}{
+		{name: "Free", protocol: shared_types.PortProtocolTCP, port: 9000},
+		{name: "Published by another service", protocol: shared_types.PortProtocolTCP, port: 15432, want: true},
+		{name: "Published by another service on the other protocol", protocol: shared_types.PortProtocolTCP, port: 5353},
+		{name: "Published by another service over udp", protocol: shared_types.PortProtocolUDP, port: 5353, want: true},
+		{name: "Listened on by the host", listening: []int{25, 9000}, protocol: shared_types.PortProtocolTCP, port: 9000, want: true},
+		{name: "Listened on by Docker for the own service", listening: []int{1883}, protocol: shared_types.PortProtocolTCP, port: 1883},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, portTaken(services, tt.listening, "app", tt.protocol, tt.port))
+		})
+	}
+}
+
+// sniApplication serves a Dockerfile application whose server names are checked by hostOwnership.
+type sniApplication struct {
+	hostOwnership
+	added int
+}
+
+func (s *sniApplication) GetApplicationById(string, uuid.UUID) (shared_types.Application, error) {
+	return shared_types.Application{ID: uuid.New(), BuildPack: shared_types.DockerFile}, nil
+}
+
+func (s *sniApplication) AddApplicationPort(*shared_types.ApplicationPort) error {
+	s.added++
+	return errors.New("stop after the port is recorded")
+}
+
+func TestAddApplicationPortSNI(t *testing.T) {
+	previous := config.AppConfig.Proxy.Layer4Listen
+	defer func() { config.AppConfig.Proxy.Layer4Listen = previous }()
+	config.AppConfig.Proxy.Layer4Listen = ":8443"
+
+	domainID := uuid.New()
+	tests := []struct {
+		name      string
+		store     hostOwnership
+		wantErr   error
+		wantAdded int
+	}{
+		{name: "Server name under no verified domain", store: hostOwnership{}, wantErr: types.ErrDomainNotVerified},
+		{name: "Server name under a domain of another organization", store: hostOwnership{elsewhere: true, domainID: &domainID}, wantErr: types.ErrDomainOwnedElsewhere},
+		{name: "Server name under a verified domain", store: hostOwnership{domainID: &domainID}, wantAdded: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &sniApplication{hostOwnership: tt.store}
+			_, err := (&TaskService{Storage: store}).AddApplicationPort(&types.AddApplicationPortRequest{
+				ID: uuid.New(), Protocol: shared_types.PortProtocolTCP, TargetPort: 8883, SNI: "mqtt.example.com",
+			}, uuid.New())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantAdded, store.added)
+		})
+	}
+}
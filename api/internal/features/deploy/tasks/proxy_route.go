@@ -0,0 +1,123 @@
+package tasks
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// routeDomain routes the domain of the application to port on the deployment server, through
+// the proxy backend chosen by the application.
+func (t *TaskService) routeDomain(application shared_types.Application, port int) error {
+	site := applicationSite(application)
+	site.Upstream = net.JoinHostPort(config.AppConfig.SSH.Host, strconv.Itoa(port))
+	return t.proxyFor(application).AddRoute(site)
+}
+
+// proxyFor returns the proxy backend serving the domains of the application.
+func (t *TaskService) proxyFor(application shared_types.Application) proxy.Provider {
+	return proxy.NewProvider(application.ProxyServer, &t.Logger)
+}
+
+// applicationSite returns the site of the domain of the application, rendering its proxy settings
+// and matching its aliases below its path prefix.
+func applicationSite(application shared_types.Application) proxy.Site {
+	return proxy.Site{
+		Owner:      application.ID.String(),
+		Domain:     application.Domain,
+		Middleware: proxy.ApplicationMiddleware(application),
+	}
+}
+
+// UpdateProxySettings stores the proxy settings of an application and applies them to the route
+// of its domain right away. Stopped and sleeping applications get them when they are started,
+// applications that were never deployed with their first deployment.
+func (t *TaskService) UpdateProxySettings(request *types.UpdateProxySettingsRequest, organizationID uuid.UUID) (shared_types.Application, error) {
+	application, err := t.Storage.GetApplicationById(request.ID.String(), organizationID)
+	if err != nil {
+		return shared_types.Application{}, err
+	}
+
+	passwordHash := application.BasicAuthPasswordHash
+	switch {
+	case request.BasicAuthUsername == "":
+		passwordHash = ""
+	case request.BasicAuthPassword != "":
+		hash, err := bcrypt.GenerateFromPassword([]byte(request.BasicAuthPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return shared_types.Application{}, err
+		}
+		passwordHash = string(hash)
+	case passwordHash == "":
+		return shared_types.Application{}, types.ErrMissingBasicAuthPassword
+	}
+
+	if request.PathPrefix != application.ProxySettings.PathPrefix {
+		if err := t.checkPathPrefix(application, request.PathPrefix, organizationID); err != nil {
+			return shared_types.Application{}, err
+		}
+	}
+
+	application.ProxySettings = request.ProxySettings
+	application.BasicAuthPasswordHash = passwordHash
+	if err := t.Storage.UpdateProxySettings(application.ID, application.ProxySettings, passwordHash); err != nil {
+		return shared_types.Application{}, err
+	}
+
+	if err := t.refreshRoute(application); err != nil {
+		return application, fmt.Errorf("%w: %v", types.ErrApplyProxySettings, err)
+	}
+	return application, nil
+}
+
+// checkPathPrefix checks that the domains of the application can be routed below pathPrefix.
+// Only Caddy routes paths, the vhost of a domain on Nginx cannot be shared.
+func (t *TaskService) checkPathPrefix(application shared_types.Application, pathPrefix string, organizationID uuid.UUID) error {
+	if pathPrefix != "" && application.ProxyServer == shared_types.Nginx {
+		return types.ErrPathPrefixNeedsCaddy
+	}
+	for _, domain := range application.Domains {
+		taken, err := t.Storage.IsRouteTaken(domain.Name, pathPrefix, application.ID, organizationID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return types.ErrDomainAlreadyTaken
+		}
+	}
+	return nil
+}
+
+// refreshRoute renders the route of a running application again, after its proxy settings or
+// domains changed.
+func (t *TaskService) refreshRoute(application shared_types.Application) error {
+	if application.RunState != shared_types.RunStateRunning {
+		return nil
+	}
+
+	deployments, err := t.Storage.GetApplicationDeployments(application.ID)
+	if err != nil || len(deployments) == 0 {
+		return err
+	}
+
+	if application.BuildPack == shared_types.Static {
+		return t.serveStaticSite(application)
+	}
+
+	availablePort, err := t.getAvailablePort(shared_types.TaskPayload{Application: application})
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(availablePort)
+	if err != nil {
+		return err
+	}
+	return t.routeDomain(application, port)
+}
@@ -0,0 +1,149 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/queue"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// queueInspectLimit caps the number of tasks read from each state of a queue.
+const queueInspectLimit = 200
+
+// DeploymentQueues lists the queues the deployment tasks run on.
+var DeploymentQueues = []string{
+	QUEUE_CREATE_DEPLOYMENT,
+	QUEUE_UPDATE_DEPLOYMENT,
+	QUEUE_REDEPLOYMENT,
+	QUEUE_ROLLBACK,
+	QUEUE_RESTART,
+}
+
+// QueuedDeploymentTask is a queued task together with the deployment it works on.
+type QueuedDeploymentTask struct {
+	queue.QueuedMessage
+	CorrelationID   string    `json:"correlation_id"`
+	ApplicationID   uuid.UUID `json:"application_id"`
+	ApplicationName string    `json:"application_name"`
+	OrganizationID  uuid.UUID `json:"organization_id"`
+	DeploymentID    uuid.UUID `json:"deployment_id"`
+}
+
+// DeploymentQueueStatus summarizes the tasks of a single deployment queue.
+type DeploymentQueueStatus struct {
+	Queue    string                 `json:"queue"`
+	Waiting  int                    `json:"waiting"`
+	InFlight int                    `json:"in_flight"`
+	Retrying int                    `json:"retrying"`
+	Dead     int                    `json:"dead"`
+	Tasks    []QueuedDeploymentTask `json:"tasks"`
+}
+
+// GetQueueStatus returns the waiting, in-flight and retrying tasks of every deployment queue.
+func (t *TaskService) GetQueueStatus(ctx context.Context) ([]DeploymentQueueStatus, error) {
+	statuses := make([]DeploymentQueueStatus, 0, len(DeploymentQueues))
+	for _, name := range DeploymentQueues {
+		messages, err := queue.Inspect(ctx, name, queueInspectLimit)
+		if err != nil {
+			return nil, err
+		}
+		dead, err := queue.DeadLetters(ctx, name, queueInspectLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		status := DeploymentQueueStatus{
+			Queue: name,
+			Dead:  len(dead),
+			Tasks: make([]QueuedDeploymentTask, 0, len(messages)),
+		}
+		for _, msg := range messages {
+			switch msg.State {
+			case queue.TaskStateWaiting:
+				status.Waiting++
+			case queue.TaskStateInFlight:
+				status.InFlight++
+			case queue.TaskStateRetrying:
+				status.Retrying++
+			}
+			status.Tasks = append(status.Tasks, toDeploymentTask(msg))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// GetDeadLetters returns the tasks of a deployment queue that ran out of retries.
+func (t *TaskService) GetDeadLetters(ctx context.Context, queueName string) ([]QueuedDeploymentTask, error) {
+	if !isDeploymentQueue(queueName) {
+		return nil, types.ErrUnknownQueue
+	}
+
+	messages, err := queue.DeadLetters(ctx, queueName, queueInspectLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]QueuedDeploymentTask, 0, len(messages))
+	for _, msg := range messages {
+		tasks = append(tasks, toDeploymentTask(msg))
+	}
+	return tasks, nil
+}
+
+// ReprioritizeTask moves a waiting task to the front or the back of its queue,
+// or makes a retrying task due right away.
+func (t *TaskService) ReprioritizeTask(ctx context.Context, request *types.ReprioritizeQueueTaskRequest) error {
+	if !isDeploymentQueue(request.Queue) {
+		return types.ErrUnknownQueue
+	}
+	return queue.MoveTask(ctx, request.Queue, request.TaskID, queue.Position(request.Position))
+}
+
+// PurgeQueueTasks removes a single task when a task id is given, otherwise every waiting
+// and retrying task of the queue, or every dead-lettered one. It returns the number of
+// removed tasks.
+func (t *TaskService) PurgeQueueTasks(ctx context.Context, request *types.PurgeQueueRequest) (int, error) {
+	if !isDeploymentQueue(request.Queue) {
+		return 0, types.ErrUnknownQueue
+	}
+
+	if request.TaskID != "" {
+		if err := queue.RemoveTask(ctx, request.Queue, request.TaskID); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	return queue.PurgeQueue(ctx, request.Queue, queue.TaskState(request.State))
+}
+
+func isDeploymentQueue(name string) bool {
+	for _, q := range DeploymentQueues {
+		if q == name {
+			return true
+		}
+	}
+	return false
+}
+
+// toDeploymentTask decodes the task payload carried by a queued message.
+func toDeploymentTask(msg queue.QueuedMessage) QueuedDeploymentTask {
+	task := QueuedDeploymentTask{QueuedMessage: msg}
+
+	var args []shared_types.TaskPayload
+	if err := msgpack.Unmarshal(msg.Args, &args); err != nil || len(args) == 0 {
+		return task
+	}
+
+	payload := args[0]
+	task.CorrelationID = payload.CorrelationID
+	task.ApplicationID = payload.Application.ID
+	task.ApplicationName = payload.Application.Name
+	task.OrganizationID = payload.Application.OrganizationID
+	task.DeploymentID = payload.ApplicationDeployment.ID
+	return task
+}
@@ -0,0 +1,177 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// proxyReconcileInterval is how often the routes of Caddy are compared with the applications.
+const proxyReconcileInterval = 5 * time.Minute
+
+// StartProxyReconciler compares the routes of Caddy with the deployed applications at startup
+// and every proxyReconcileInterval. PROXY_RECONCILE_MODE decides what happens to the drift:
+// "report", the default, only logs it, "fix" repairs it and "off" turns the reconciler off. It
+// stops when ctx is done.
+func (t *TaskService) StartProxyReconciler(ctx context.Context) {
+	mode := config.AppConfig.Proxy.ReconcileMode
+	if mode == "off" {
+		return
+	}
+	fix := mode == "fix"
+
+	go func() {
+		t.reconcileProxy(fix)
+
+		ticker := time.NewTicker(proxyReconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.reconcileProxy(fix)
+			}
+		}
+	}()
+}
+
+func (t *TaskService) reconcileProxy(fix bool) {
+	result, err := t.ReconcileProxy(fix)
+	if err != nil {
+		t.Logger.Log(logger.Error, "Failed to reconcile proxy routes", err.Error())
+	}
+	for _, skipped := range result.Skipped {
+		t.Logger.Log(logger.Warning, "Skipped the route of an application while reconciling", skipped.Name+": "+skipped.Error)
+	}
+	if len(result.Drift) == 0 || result.Fixed {
+		return
+	}
+	drift := make([]string, 0, len(result.Drift))
+	for _, d := range result.Drift {
+		drift = append(drift, fmt.Sprintf("%s %s (%s)", d.Kind, d.Domain, d.Owner))
+	}
+	t.Logger.Log(logger.Warning, "Proxy routes drifted from the applications", strings.Join(drift, ", "))
+}
+
+// ReconcileProxy compares the routes of Caddy with the routes the deployed applications render:
+// running applications are routed to their service or serve their files, stopped applications
+// serve the stopped page and sleeping applications are routed to the API to be woken. With fix
+// set the drift is repaired, otherwise it is only reported.
+//
+// Applications on Nginx are not reconciled, their vhosts are files rendered on deployment.
+// Applications whose route cannot be rendered, like a running application without a port, are
+// skipped and keep the route they have.
+func (t *TaskService) ReconcileProxy(fix bool) (types.ProxyReconciliation, error) {
+	result := types.ProxyReconciliation{CheckedAt: time.Now()}
+	applications, err := t.Storage.GetRoutedApplications()
+	if err != nil {
+		return result, err
+	}
+
+	var sites []proxy.DesiredSite
+	var keep []string
+	for _, application := range applications {
+		if application.ProxyServer == shared_types.Nginx {
+			continue
+		}
+		site, err := t.desiredSite(application)
+		if err != nil {
+			keep = append(keep, application.ID.String())
+			result.Skipped = append(result.Skipped, types.SkippedRoute{
+				ApplicationID: application.ID,
+				Name:          application.Name,
+				Error:         err.Error(),
+			})
+			continue
+		}
+		sites = append(sites, site)
+	}
+
+	result.Drift, err = proxy.NewCaddyProvider(&t.Logger).Reconcile(sites, keep, fix)
+	if err != nil {
+		return result, err
+	}
+	result.Fixed = fix && len(result.Drift) > 0
+	return result, nil
+}
+
+// desiredSite returns the site the application renders in its current run state.
+func (t *TaskService) desiredSite(application shared_types.Application) (proxy.DesiredSite, error) {
+	switch application.RunState {
+	case shared_types.RunStateStopped:
+		site := applicationSite(application)
+		site.Stopped = application.Name
+		return proxy.DesiredSite{Site: site}, nil
+	case shared_types.RunStateSleeping:
+		return proxy.DesiredSite{Site: sleepingSite(application)}, nil
+	}
+
+	if application.BuildPack == shared_types.Static {
+		options := staticSiteOptions(application)
+		return proxy.DesiredSite{
+			Site:    applicationSite(application),
+			RootDir: filepath.Join(StaticSitePath(application.ID), staticCurrentLink),
+			Static:  &options,
+		}, nil
+	}
+
+	port, err := t.routedPort(application)
+	if err != nil {
+		return proxy.DesiredSite{}, err
+	}
+	site := applicationSite(application)
+	site.Upstream = net.JoinHostPort(config.AppConfig.SSH.Host, strconv.Itoa(port))
+	return proxy.DesiredSite{Site: site}, nil
+}
+
+// routedPort returns the host port the application is routed to without allocating one, which
+// would route it to a port its service does not publish: the port allocated to it or, for
+// applications deployed before ports were allocated, the port its service publishes.
+func (t *TaskService) routedPort(application shared_types.Application) (int, error) {
+	allocation, err := t.Storage.FindPortAllocation(application.ID, config.AppConfig.SSH.Host)
+	if err != nil {
+		return 0, err
+	}
+	if allocation != nil {
+		return allocation.Port, nil
+	}
+
+	services, err := t.DockerRepo.GetClusterServices()
+	if err != nil {
+		return 0, err
+	}
+	if port := publishedHTTPPort(services, application); port != 0 {
+		return port, nil
+	}
+	return 0, types.ErrNoRoutedPort
+}
+
+// publishedHTTPPort returns the TCP port the service of the application publishes for the port
+// the application listens on, 0 when it publishes none.
+func publishedHTTPPort(services []swarm.Service, application shared_types.Application) int {
+	for _, service := range services {
+		if service.Spec.Annotations.Name != application.Name {
+			continue
+		}
+		for _, published := range servicePublishedPorts(service) {
+			if published.Protocol == swarm.PortConfigProtocolTCP && published.PublishedPort != 0 &&
+				int(published.TargetPort) == application.Port {
+				return int(published.PublishedPort)
+			}
+		}
+	}
+	return 0
+}
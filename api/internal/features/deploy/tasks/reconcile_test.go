@@ -0,0 +1,101 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allocationLookup answers FindPortAllocation and fails the test when a port is allocated.
+type allocationLookup struct {
+	storage.DeployRepository
+	t          *testing.T
+	allocation *shared_types.PortAllocation
+}
+
+func (s *allocationLookup) FindPortAllocation(uuid.UUID, string) (*shared_types.PortAllocation, error) {
+	return s.allocation, nil
+}
+
+func (s *allocationLookup) AllocatePort(uuid.UUID, string, types.AllocatePortOptions) (*shared_types.PortAllocation, error) {
+	s.t.Fatal("reconciling allocated a port")
+	return nil, nil
+}
+
+type clusterServices struct {
+	docker.DockerRepository
+	services []swarm.Service
+}
+
+func (d *clusterServices) GetClusterServices() ([]swarm.Service, error) {
+	return d.services, nil
+}
+
+func publishingService(name string, ports ...swarm.PortConfig) swarm.Service {
+	var s swarm.Service
+	s.Spec.Annotations.Name = name
+	s.Endpoint.Ports = ports
+	return s
+}
+
+func TestRoutedPort(t *testing.T) {
+	application := shared_types.Application{ID: uuid.New(), Name: "shop", Port: 3000}
+	tcp := func(target uint32, published uint32) swarm.PortConfig {
+		return swarm.PortConfig{Protocol: swarm.PortConfigProtocolTCP, TargetPort: target, PublishedPort: published}
+	}
+
+	tests := []struct {
+		name       string
+		allocation *shared_types.PortAllocation
+		services   []swarm.Service
+		want       int
+		wantErr    error
+	}{
+		{
+			name:       "Allocated port",
+			allocation: &shared_types.PortAllocation{Port: 49200},
+			services:   []swarm.Service{publishingService("shop", tcp(3000, 8080))},
+			want:       49200,
+		},
+		{
+			name:     "Port published by the service of a legacy application",
+			services: []swarm.Service{publishingService("other", tcp(3000, 9000)), publishingService("shop", tcp(9229, 9229), tcp(3000, 8080))},
+			want:     8080,
+		},
+		{
+			name: "UDP port is not routed",
+			services: []swarm.Service{publishingService("shop", swarm.PortConfig{
+				Protocol: swarm.PortConfigProtocolUDP, TargetPort: 3000, PublishedPort: 8080,
+			})},
+			wantErr: types.ErrNoRoutedPort,
+		},
+		{
+			name:     "No service",
+			services: []swarm.Service{publishingService("other", tcp(3000, 9000))},
+			wantErr:  types.ErrNoRoutedPort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &TaskService{
+				Storage:    &allocationLookup{t: t, allocation: tt.allocation},
+				DockerRepo: &clusterServices{services: tt.services},
+			}
+			port, err := s.routedPort(application)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, port)
+		})
+	}
+}
@@ -41,6 +41,10 @@ func (t *TaskService) RestartDeployment(request *types.RestartDeploymentRequest,
 
 // HandleRestart restarts currently running swarm service for the application and updates status/logs
 func (s *TaskService) HandleRestart(ctx context.Context, TaskPayload shared_types.TaskPayload) error {
+	if TaskPayload.Application.BuildPack == shared_types.DockerCompose {
+		return s.HandleComposeRestart(ctx, TaskPayload)
+	}
+
 	taskCtx := s.NewTaskContext(TaskPayload)
 
 	taskCtx.LogAndUpdateStatus("Restarting application service", shared_types.Deploying)
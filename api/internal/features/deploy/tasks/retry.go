@@ -0,0 +1,174 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// deploymentPhases lists the phases of a deployment in the order they complete.
+var deploymentPhases = []shared_types.DeploymentPhase{
+	shared_types.PhaseBuilt,
+	shared_types.PhasePreRun,
+	shared_types.PhaseRolledOut,
+	shared_types.PhasePostRun,
+}
+
+// phaseIndex returns the position of the phase in deploymentPhases, or -1 when no phase completed.
+func phaseIndex(phase shared_types.DeploymentPhase) int {
+	for i, p := range deploymentPhases {
+		if p == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+// permanentErrors fail the same way on every attempt, so retrying them only repeats the work.
+// Release commands are only permanent once they ran: a command that exited non-zero or hung
+// until its timeout, not a container the daemon failed to create or start.
+var permanentErrors = []error{
+	types.ErrInvalidBuildPack,
+	types.ErrInvalidRepositoryID,
+	types.ErrBuildContextNotFound,
+	types.ErrDockerfileNotFound,
+	types.ErrMissingImageName,
+	types.ErrReleaseCommandExited,
+	types.ErrReleaseCommandTimedOut,
+	types.ErrStaticBuildFailed,
+	types.ErrContainerNotRunning,
+}
+
+// PermanentError wraps a deployment failure that must not be retried.
+//
+// taskq stops retrying a message as soon as its handler returns an error whose Delay is zero.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PermanentError) Delay() time.Duration {
+	return 0
+}
+
+// IsRetryable reports whether a failed deployment task may succeed when run again.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	for _, target := range permanentErrors {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	var streamErr *jsonmessage.JSONError
+	if errors.As(err, &streamErr) {
+		return retryableStreamError(streamErr)
+	}
+	return true
+}
+
+// retryableStreamError tells apart the errors reported through the build and pull output
+// streams. A failing build step reports the exit code of its command, which fails again on the
+// next attempt, and so do registry errors like a missing image or denied access. Errors without
+// a code, like a registry connection that timed out, as well as rate limits and server errors
+// of the registry are worth another attempt.
+func retryableStreamError(err *jsonmessage.JSONError) bool {
+	switch {
+	case err.Code == 0:
+		return true
+	case err.Code < 400:
+		return false
+	case err.Code == 408 || err.Code == 429 || err.Code >= 500:
+		return true
+	default:
+		return false
+	}
+}
+
+// classifyError wraps errors that are not retryable in a PermanentError.
+func classifyError(err error) error {
+	if err == nil || IsRetryable(err) {
+		return err
+	}
+
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return err
+	}
+	return &PermanentError{Err: err}
+}
+
+// runAttempt runs a deployment task handler as the next attempt of the deployment in the payload.
+//
+// Every attempt is counted on the deployment record and its logs are tagged with the attempt
+// number, so retries show up in the deployment timeline. The payload is refreshed with the
+// phases completed by earlier attempts, which lets the handler resume instead of starting over.
+func (t *TaskService) runAttempt(
+	ctx context.Context,
+	payload shared_types.TaskPayload,
+	retryLimit int,
+	handler func(context.Context, shared_types.TaskPayload) error,
+) error {
+	deployment, err := t.Storage.StartDeploymentAttempt(payload.ApplicationDeployment.ID)
+	if err != nil {
+		return err
+	}
+
+	payload.ApplicationDeployment.Attempts = deployment.Attempts
+	payload.ApplicationDeployment.CompletedPhase = deployment.CompletedPhase
+	if deployment.CommitHash != "" {
+		payload.ApplicationDeployment.CommitHash = deployment.CommitHash
+	}
+
+	taskCtx := t.NewTaskContext(payload)
+	if deployment.Attempts > 1 {
+		message := fmt.Sprintf("Retrying deployment, attempt %d of %d", deployment.Attempts, retryLimit)
+		if deployment.CompletedPhase != "" {
+			message += ", resuming after phase " + string(deployment.CompletedPhase)
+		}
+		taskCtx.AddLog(message)
+	}
+
+	if deployment.Attempts == 1 {
+		publishDeploymentEvent(payload, shared_types.WebhookEventDeploymentStarted, nil)
+	}
+
+	err = classifyError(handler(ctx, payload))
+	if err == nil {
+		publishDeploymentEvent(payload, shared_types.WebhookEventDeploymentSucceeded, nil)
+		return nil
+	}
+
+	switch {
+	case !IsRetryable(err):
+		taskCtx.AddLog("Deployment failed with an error that will not be fixed by retrying: " + err.Error())
+		publishDeploymentEvent(payload, shared_types.WebhookEventDeploymentFailed, err)
+	case deployment.Attempts < retryLimit:
+		taskCtx.AddLog(fmt.Sprintf("Attempt %d failed and will be retried: %s", deployment.Attempts, err.Error()))
+	default:
+		taskCtx.AddLog(fmt.Sprintf("Deployment failed after %d attempts: %s", deployment.Attempts, err.Error()))
+		publishDeploymentEvent(payload, shared_types.WebhookEventDeploymentFailed, err)
+	}
+
+	return err
+}
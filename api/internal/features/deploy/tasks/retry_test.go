@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "No error", err: nil, want: false},
+		{name: "Unknown error", err: errors.New("connection reset by peer"), want: true},
+		{name: "Context deadline", err: context.DeadlineExceeded, want: true},
+		{name: "Permanent error", err: &PermanentError{Err: errors.New("bad input")}, want: false},
+		{name: "Wrapped permanent error", err: fmt.Errorf("deploy: %w", &PermanentError{Err: errors.New("bad input")}), want: false},
+		{name: "Invalid build pack", err: types.ErrInvalidBuildPack, want: false},
+		{name: "Missing Dockerfile", err: fmt.Errorf("build: %w", types.ErrDockerfileNotFound), want: false},
+		{name: "Release command exited non-zero", err: fmt.Errorf("%w: pre run command exited with code 1", types.ErrReleaseCommandExited), want: false},
+		{name: "Release command timed out", err: fmt.Errorf("%w: pre run command ran longer than 30m0s", types.ErrReleaseCommandTimedOut), want: false},
+		{name: "Release container could not be created", err: fmt.Errorf("%w: Cannot connect to the Docker daemon", types.ErrReleaseCommandFailed), want: true},
+		{name: "Build step exited non-zero", err: &jsonmessage.JSONError{Code: 1, Message: "The command '/bin/sh -c npm ci' returned a non-zero code: 1"}, want: false},
+		{name: "Registry timeout without a code", err: &jsonmessage.JSONError{Message: "net/http: TLS handshake timeout"}, want: true},
+		{name: "Registry server error", err: &jsonmessage.JSONError{Code: 503, Message: "received unexpected HTTP status: 503 Service Unavailable"}, want: true},
+		{name: "Registry rate limit", err: &jsonmessage.JSONError{Code: 429, Message: "toomanyrequests"}, want: true},
+		{name: "Registry image not found", err: &jsonmessage.JSONError{Code: 404, Message: "manifest unknown"}, want: false},
+		{name: "Registry access denied", err: &jsonmessage.JSONError{Code: 401, Message: "unauthorized"}, want: false},
+		{name: "Wrapped build stream error", err: fmt.Errorf("build failed: %w", &jsonmessage.JSONError{Code: 2}), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	assert.NoError(t, classifyError(nil))
+
+	transient := errors.New("connection refused")
+	assert.Same(t, transient, classifyError(transient))
+
+	var permanent *PermanentError
+	err := classifyError(types.ErrDockerfileNotFound)
+	assert.ErrorAs(t, err, &permanent)
+	assert.ErrorIs(t, err, types.ErrDockerfileNotFound)
+	assert.Zero(t, permanent.Delay())
+
+	wrapped := &PermanentError{Err: transient}
+	assert.Same(t, wrapped, classifyError(wrapped))
+}
+
+func TestPhaseIndex(t *testing.T) {
+	tests := []struct {
+		phase shared_types.DeploymentPhase
+		want  int
+	}{
+		{phase: "", want: -1},
+		{phase: shared_types.PhaseBuilt, want: 0},
+		{phase: shared_types.PhasePreRun, want: 1},
+		{phase: shared_types.PhaseRolledOut, want: 2},
+		{phase: shared_types.PhasePostRun, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.phase), func(t *testing.T) {
+			assert.Equal(t, tt.want, phaseIndex(tt.phase))
+		})
+	}
+}
@@ -21,6 +21,10 @@ func (t *TaskService) RollbackDeployment(request *types.RollbackDeploymentReques
 		return err
 	}
 
+	if app.BuildPack == shared_types.DockerCompose {
+		return types.ErrComposeRollbackUnsupported
+	}
+
 	ctxTask := ContextTask{
 		TaskService:    t,
 		ContextConfig:  request,
@@ -39,8 +43,13 @@ func (t *TaskService) RollbackDeployment(request *types.RollbackDeploymentReques
 	return RollbackQueue.Add(TaskRollback.WithArgs(context.Background(), payload))
 }
 
-// HandleRollback uses Docker Swarm's native rollback capability for instant rollback
+// HandleRollback uses Docker Swarm's native rollback capability for instant rollback,
+// static sites switch back to a previously built release instead
 func (s *TaskService) HandleRollback(ctx context.Context, TaskPayload shared_types.TaskPayload) error {
+	if TaskPayload.Application.BuildPack == shared_types.Static {
+		return s.HandleStaticRollback(ctx, TaskPayload)
+	}
+
 	taskCtx := s.NewTaskContext(TaskPayload)
 
 	taskCtx.LogAndUpdateStatus("Starting native swarm rollback", shared_types.Deploying)
@@ -51,16 +60,22 @@ func (s *TaskService) HandleRollback(ctx context.Context, TaskPayload shared_typ
 		return types.ErrContainerNotRunning
 	}
 
-	taskCtx.AddLog("Rolling back service " + serviceID + " using Docker Swarm native rollback")
+	// rolling back a second time would undo the first rollback, so retries only recheck the service health
+	if taskCtx.PhaseCompleted(shared_types.PhaseRolledOut) {
+		taskCtx.AddLog("Service " + serviceID + " was rolled back by a previous attempt, checking its health")
+	} else {
+		taskCtx.AddLog("Rolling back service " + serviceID + " using Docker Swarm native rollback")
 
-	err := s.DockerRepo.RollbackService(serviceID)
-	if err != nil {
-		taskCtx.LogAndUpdateStatus("Failed to rollback service: "+err.Error(), shared_types.Failed)
-		return err
-	}
+		err := s.DockerRepo.RollbackService(serviceID)
+		if err != nil {
+			taskCtx.LogAndUpdateStatus("Failed to rollback service: "+err.Error(), shared_types.Failed)
+			return err
+		}
+		taskCtx.CompletePhase(shared_types.PhaseRolledOut)
 
-	// Wait for rollback to complete
-	time.Sleep(time.Second * 5)
+		// Wait for rollback to complete
+		time.Sleep(time.Second * 5)
+	}
 
 	serviceInfo, err := s.DockerRepo.GetServiceByID(serviceID)
 	if err != nil {
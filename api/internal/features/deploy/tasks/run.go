@@ -7,9 +7,10 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	database_types "github.com/raghavyuva/nixopus-api/internal/features/database/types"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
-	"github.com/raghavyuva/nixopus-api/internal/features/ssh"
 	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
 )
 
@@ -52,31 +53,27 @@ func (s *TaskService) sanitizeEnvVars(envVars map[string]string) []string {
 	return logEnvVars
 }
 
-func (s *TaskService) getAvailablePort() (string, error) {
-	ssh := ssh.NewSSH()
-	client, err := ssh.Connect()
+// getAvailablePort returns the host port reserved for the application on the deployment server.
+// Applications created before ports were tracked in the database get a port allocated on first
+// use, the one their service already publishes when it is free.
+func (s *TaskService) getAvailablePort(r shared_types.TaskPayload) (string, error) {
+	host := config.AppConfig.SSH.Host
+	allocation, err := s.Storage.FindPortAllocation(r.Application.ID, host)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to allocate port: %w", err)
 	}
-	defer client.Close()
-
-	generatePorts := "seq 49152 65535"
-
-	getUsedPorts := "command -v ss >/dev/null 2>&1 && ss -tan | awk '{print $4}' | cut -d':' -f2 | grep '[0-9]\\{1,5\\}' | sort -u || netstat -tan | awk '{print $4}' | grep ':[0-9]' | cut -d':' -f2 | sort -u"
-
-	cmd := fmt.Sprintf("comm -23 <(%s) <(%s) | sort -R | head -n 1 | tr -d '\\n'", generatePorts, getUsedPorts)
-
-	output, err := client.Run(cmd)
-	if err != nil {
-		return "", fmt.Errorf("failed to find available port: %w", err)
-	}
-
-	port := string(output)
-	if port == "" {
-		return "", fmt.Errorf("no available ports found in range 49152-65535")
+	if allocation == nil {
+		options, err := s.portAllocationOptions(r.Application)
+		if err != nil {
+			return "", fmt.Errorf("failed to allocate port: %w", err)
+		}
+		allocation, err = s.Storage.AllocatePort(r.Application.ID, host, options)
+		if err != nil {
+			return "", fmt.Errorf("failed to allocate port: %w", err)
+		}
 	}
 
-	return port, nil
+	return strconv.Itoa(allocation.Port), nil
 }
 
 // AtomicUpdateContainer performs a zero-downtime update of a running container
@@ -93,7 +90,7 @@ func (s *TaskService) AtomicUpdateContainer(r shared_types.TaskPayload, taskCont
 	// Check if service already exists
 	existingService, err := s.getExistingService(r, taskContext)
 	if err != nil {
-		s.formatLog(taskContext, "No existing service found, creating new service", "")
+		s.formatLog(taskContext, "No existing service found, creating new service")
 	}
 
 	// Create service spec
@@ -182,20 +179,23 @@ func (s *TaskService) getExistingService(r shared_types.TaskPayload, taskContext
 
 // createServiceSpec creates a swarm service specification
 func (s *TaskService) createServiceSpec(r shared_types.TaskPayload, taskContext *TaskContext) (swarm.ServiceSpec, string) {
-	availablePort, err := s.getAvailablePort()
+	availablePort, err := s.getAvailablePort(r)
 	if err != nil {
 		taskContext.LogAndUpdateStatus("Failed to get available port: "+err.Error(), shared_types.Failed)
 		return swarm.ServiceSpec{}, ""
 	}
 
-	var env_vars []string
-	for k, v := range GetMapFromString(r.Application.EnvironmentVariables) {
-		env_vars = append(env_vars, fmt.Sprintf("%s=%s", k, v))
-	}
+	networks, env_vars := s.applicationEnv(r.Application, taskContext)
 
 	replicas := uint64(1)
 	port, _ := strconv.Atoi(availablePort)
 
+	ports, err := s.servicePorts(r.Application, port)
+	if err != nil {
+		taskContext.LogAndUpdateStatus("Failed to load exposed ports: "+err.Error(), shared_types.Failed)
+		return swarm.ServiceSpec{}, ""
+	}
+
 	serviceSpec := swarm.ServiceSpec{
 		Annotations: swarm.Annotations{
 			Name: r.Application.Name,
@@ -211,28 +211,58 @@ func (s *TaskService) createServiceSpec(r shared_types.TaskPayload, taskContext
 				Env:   env_vars,
 				Labels: map[string]string{
 					"com.application.id": r.Application.ID.String(),
+					"com.deployment.id":  r.ApplicationDeployment.ID.String(),
 				},
 			},
 			RestartPolicy: &swarm.RestartPolicy{
 				Condition: swarm.RestartPolicyConditionAny,
 			},
+			Networks: networks,
 		},
 		EndpointSpec: &swarm.EndpointSpec{
-			Mode: swarm.ResolutionModeVIP,
-			Ports: []swarm.PortConfig{
-				{
-					Protocol:      swarm.PortConfigProtocolTCP,
-					TargetPort:    uint32(r.Application.Port),
-					PublishedPort: uint32(port),
-					PublishMode:   swarm.PortConfigPublishModeHost,
-				},
-			},
+			Mode:  swarm.ResolutionModeVIP,
+			Ports: ports,
 		},
 	}
 
 	return serviceSpec, availablePort
 }
 
+// applicationEnv returns the environment the containers of an application run with, its own
+// variables and the connection strings of its linked databases, along with the networks the
+// containers need to reach those databases.
+func (s *TaskService) applicationEnv(application shared_types.Application, taskContext *TaskContext) ([]swarm.NetworkAttachmentConfig, []string) {
+	definedEnv := GetMapFromString(application.EnvironmentVariables)
+	var env []string
+	for k, v := range definedEnv {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return s.linkDatabases(application, definedEnv, env, taskContext)
+}
+
+// linkDatabases injects the connection strings of the databases linked to an application into
+// its environment and returns the networks the service needs to reach them.
+func (s *TaskService) linkDatabases(application shared_types.Application, definedEnv map[string]string, env []string, taskContext *TaskContext) ([]swarm.NetworkAttachmentConfig, []string) {
+	links, err := s.Storage.GetApplicationDatabaseLinks(application.ID)
+	if err != nil {
+		s.formatLog(taskContext, "Failed to load linked databases: %s", err.Error())
+		return nil, env
+	}
+	if len(links) == 0 {
+		return nil, env
+	}
+
+	networkID, err := s.DockerRepo.CreateNetwork(database_types.NetworkName(application.OrganizationID), database_types.NetworkOptions())
+	if err != nil {
+		s.formatLog(taskContext, "Failed to create database network: %s", err.Error())
+		return nil, env
+	}
+
+	s.formatLog(taskContext, "Linking %d database(s)", len(links))
+	return database_types.ApplyLinkNetwork(nil, application.OrganizationID, networkID, true),
+		database_types.ApplyLinkEnv(env, definedEnv, links)
+}
+
 // getServiceInfo retrieves service information
 func (s *TaskService) getServiceInfo(r shared_types.TaskPayload, taskContext *TaskContext) (swarm.Service, error) {
 	services, err := s.DockerRepo.GetClusterServices()
@@ -0,0 +1,350 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// Static sites are deployed as immutable releases:
+//
+//	<MOUNT_PATH>/static/<application id>/releases/<deployment id>/
+//	<MOUNT_PATH>/static/<application id>/current -> releases/<deployment id>
+//
+// Caddy serves the current symlink, so activating a release (or rolling back to an older one)
+// is a single atomic rename and never exposes a half written site.
+const (
+	staticReleasesDir   = "releases"
+	staticCurrentLink   = "current"
+	staticWorkspacePath = "/workspace"
+)
+
+// StaticSitePath returns the directory holding the releases of a static site.
+func StaticSitePath(applicationID uuid.UUID) string {
+	return filepath.Join(os.Getenv("MOUNT_PATH"), "static", applicationID.String())
+}
+
+func staticReleasePath(applicationID uuid.UUID, deploymentID uuid.UUID) string {
+	return filepath.Join(StaticSitePath(applicationID), staticReleasesDir, deploymentID.String())
+}
+
+// HandleCreateStaticDeployment deploys a new static site
+func (t *TaskService) HandleCreateStaticDeployment(ctx context.Context, TaskPayload shared_types.TaskPayload) error {
+	return t.HandleStaticDeployment(ctx, TaskPayload, shared_types.DeploymentTypeCreate)
+}
+
+// HandleStaticDeployment clones the repository, builds the site when a build command is set,
+// publishes the output as a new release and points the proxy at it.
+func (t *TaskService) HandleStaticDeployment(ctx context.Context, TaskPayload shared_types.TaskPayload, deploymentType string) error {
+	taskCtx := t.NewTaskContext(TaskPayload)
+
+	releasePath := staticReleasePath(TaskPayload.Application.ID, TaskPayload.ApplicationDeployment.ID)
+	if _, err := os.Stat(releasePath); err == nil && taskCtx.PhaseCompleted(shared_types.PhaseBuilt) {
+		taskCtx.AddLog("Release was built by a previous attempt, skipping clone and build")
+	} else {
+		taskCtx.LogAndUpdateStatus("Starting static site deployment", shared_types.Cloning)
+
+		repoPath, err := t.Clone(CloneConfig{
+			TaskPayload:    TaskPayload,
+			DeploymentType: deploymentType,
+			TaskContext:    taskCtx,
+		})
+		if err != nil {
+			taskCtx.LogAndUpdateStatus("Failed to clone repository: "+err.Error(), shared_types.Failed)
+			return err
+		}
+
+		taskCtx.LogAndUpdateStatus("Repository cloned successfully", shared_types.Building)
+
+		sourcePath := repoPath
+		if TaskPayload.Application.BasePath != "" && TaskPayload.Application.BasePath != "/" {
+			sourcePath = filepath.Join(repoPath, TaskPayload.Application.BasePath)
+		}
+
+		if err := t.buildStaticRelease(TaskPayload, taskCtx, sourcePath, releasePath); err != nil {
+			os.RemoveAll(releasePath)
+			taskCtx.LogAndUpdateStatus("Failed to build static site: "+err.Error(), shared_types.Failed)
+			return err
+		}
+		taskCtx.CompletePhase(shared_types.PhaseBuilt)
+	}
+
+	taskCtx.UpdateStatus(shared_types.Deploying)
+
+	if err := activateStaticRelease(TaskPayload.Application.ID, TaskPayload.ApplicationDeployment.ID); err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to activate release: "+err.Error(), shared_types.Failed)
+		return err
+	}
+	taskCtx.AddLog("Release " + TaskPayload.ApplicationDeployment.ID.String() + " activated")
+	taskCtx.CompletePhase(shared_types.PhaseRolledOut)
+
+	if err := t.serveStaticSite(TaskPayload.Application); err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to configure proxy: "+err.Error(), shared_types.Failed)
+		return err
+	}
+
+	if err := pruneStaticReleases(TaskPayload.Application.ID, TaskPayload.ApplicationDeployment.ID, types.StaticReleasesToKeep); err != nil {
+		t.Logger.Log(logger.Error, "Failed to prune static releases", err.Error())
+	}
+
+	taskCtx.LogAndUpdateStatus("Deployment completed successfully", shared_types.Deployed)
+	taskCtx.AddLog(fmt.Sprintf("Application %s is available at %s", TaskPayload.Application.Name, TaskPayload.Application.Domain))
+	return nil
+}
+
+// HandleStaticRollback switches the site back to the release built for the target commit.
+// Releases are kept on disk, so this is instant; when the release has already been pruned
+// the commit is cloned and built again.
+func (t *TaskService) HandleStaticRollback(ctx context.Context, TaskPayload shared_types.TaskPayload) error {
+	taskCtx := t.NewTaskContext(TaskPayload)
+
+	taskCtx.LogAndUpdateStatus("Starting static site rollback", shared_types.Deploying)
+
+	deployments, err := t.Storage.GetApplicationDeployments(TaskPayload.Application.ID)
+	if err != nil {
+		taskCtx.LogAndUpdateStatus("Failed to get application deployments: "+err.Error(), shared_types.Failed)
+		return err
+	}
+	sort.Slice(deployments, func(i, j int) bool {
+		return deployments[i].CreatedAt.After(deployments[j].CreatedAt)
+	})
+
+	for _, dep := range deployments {
+		if dep.ID == TaskPayload.ApplicationDeployment.ID || dep.CommitHash != TaskPayload.ApplicationDeployment.CommitHash {
+			continue
+		}
+		if _, err := os.Stat(staticReleasePath(TaskPayload.Application.ID, dep.ID)); err != nil {
+			continue
+		}
+
+		if err := activateStaticRelease(TaskPayload.Application.ID, dep.ID); err != nil {
+			taskCtx.LogAndUpdateStatus("Failed to activate release: "+err.Error(), shared_types.Failed)
+			return err
+		}
+		taskCtx.AddLog("Switched to release " + dep.ID.String())
+		taskCtx.LogAndUpdateStatus("Rollback completed successfully", shared_types.Deployed)
+		return nil
+	}
+
+	taskCtx.AddLog(types.ErrStaticReleaseNotFound.Error() + ", rebuilding commit " + TaskPayload.ApplicationDeployment.CommitHash)
+	return t.HandleStaticDeployment(ctx, TaskPayload, shared_types.DeploymentTypeRollback)
+}
+
+// buildStaticRelease writes the site files into releasePath. Without a build command the output
+// directory of the repository is published as is, otherwise the build runs in an isolated
+// builder container and only its output directory is copied back.
+func (t *TaskService) buildStaticRelease(d shared_types.TaskPayload, taskCtx *TaskContext, sourcePath string, releasePath string) error {
+	if _, err := os.Stat(sourcePath); err != nil {
+		return fmt.Errorf("source path does not exist: %s", sourcePath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(releasePath), 0755); err != nil {
+		return err
+	}
+	os.RemoveAll(releasePath)
+
+	if strings.TrimSpace(d.Application.BuildCommand) == "" {
+		outputPath := filepath.Join(sourcePath, d.Application.OutputDirectory)
+		taskCtx.AddLog("No build command configured, publishing " + outputPath)
+		return copyStaticFiles(outputPath, releasePath)
+	}
+
+	return t.runStaticBuild(d, taskCtx, sourcePath, releasePath)
+}
+
+// runStaticBuild runs the build command in a throwaway container created from the builder image.
+// The source is copied in rather than bind mounted, so the build cannot touch the host.
+func (t *TaskService) runStaticBuild(d shared_types.TaskPayload, taskCtx *TaskContext, sourcePath string, releasePath string) error {
+	builderImage := d.Application.BuildImage
+	if builderImage == "" {
+		builderImage = types.DefaultStaticBuildImage
+	}
+
+	taskCtx.AddLog("Pulling builder image " + builderImage)
+	if err := t.DockerRepo.PullImage(builderImage); err != nil {
+		// the image may still be available locally
+		taskCtx.AddLog("Failed to pull builder image: " + err.Error())
+	}
+
+	var envVars []string
+	for k, v := range GetMapFromString(d.Application.BuildVariables) {
+		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	containerConfig := container.Config{
+		Image:      builderImage,
+		WorkingDir: staticWorkspacePath,
+		Entrypoint: []string{"/bin/sh", "-c"},
+		Cmd:        []string{d.Application.BuildCommand},
+		Env:        envVars,
+		Labels: map[string]string{
+			"com.application.id": d.Application.ID.String(),
+			"com.deployment.id":  d.ApplicationDeployment.ID.String(),
+			"com.build.phase":    "static",
+		},
+	}
+	hostConfig := container.HostConfig{
+		NetworkMode: "bridge",
+		SecurityOpt: []string{"no-new-privileges:true"},
+	}
+
+	resp, err := t.DockerRepo.CreateContainer(containerConfig, hostConfig, network.NetworkingConfig{}, "")
+	if err != nil {
+		return fmt.Errorf("%w: failed to create builder container: %v", types.ErrStaticBuildFailed, err)
+	}
+	defer func() {
+		if err := t.DockerRepo.RemoveContainer(resp.ID, container.RemoveOptions{Force: true}); err != nil {
+			t.Logger.Log(logger.Error, "Failed to remove builder container", err.Error())
+		}
+	}()
+
+	source, err := archive.TarWithOptions(sourcePath, &archive.TarOptions{ExcludePatterns: []string{".git"}})
+	if err != nil {
+		return fmt.Errorf("%w: failed to archive source: %v", types.ErrStaticBuildFailed, err)
+	}
+	defer source.Close()
+
+	if err := t.DockerRepo.CopyToContainer(resp.ID, staticWorkspacePath, source); err != nil {
+		return fmt.Errorf("%w: failed to copy source to builder: %v", types.ErrStaticBuildFailed, err)
+	}
+
+	ctx := context.Background()
+	waitC, waitErrC := t.DockerRepo.WaitContainer(ctx, resp.ID, container.WaitConditionNextExit)
+
+	taskCtx.AddLog("Running build command: " + d.Application.BuildCommand)
+	if err := t.DockerRepo.StartContainer(resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("%w: failed to start builder container: %v", types.ErrStaticBuildFailed, err)
+	}
+
+	t.streamReleaseLogs(ctx, resp.ID, taskCtx, "build")
+
+	select {
+	case result := <-waitC:
+		if result.Error != nil {
+			return fmt.Errorf("%w: %s", types.ErrStaticBuildFailed, result.Error.Message)
+		}
+		if result.StatusCode != 0 {
+			return fmt.Errorf("%w: build command exited with code %d", types.ErrStaticBuildFailed, result.StatusCode)
+		}
+	case err := <-waitErrC:
+		return fmt.Errorf("%w: %v", types.ErrStaticBuildFailed, err)
+	}
+
+	outputPath := path.Join(staticWorkspacePath, filepath.ToSlash(d.Application.OutputDirectory))
+	taskCtx.AddLog("Copying build output " + outputPath)
+
+	output, err := t.DockerRepo.CopyFromContainer(resp.ID, outputPath)
+	if err != nil {
+		return fmt.Errorf("%w: failed to copy build output: %v", types.ErrStaticBuildFailed, err)
+	}
+	defer output.Close()
+
+	// the archive holds a single top level directory named after the output directory
+	stagingPath := releasePath + ".tmp"
+	os.RemoveAll(stagingPath)
+	defer os.RemoveAll(stagingPath)
+
+	if err := archive.Untar(output, stagingPath, &archive.TarOptions{NoLchown: true}); err != nil {
+		return fmt.Errorf("%w: failed to extract build output: %v", types.ErrStaticBuildFailed, err)
+	}
+
+	return os.Rename(filepath.Join(stagingPath, path.Base(outputPath)), releasePath)
+}
+
+// copyStaticFiles copies the site files from src into dst, leaving out the git metadata.
+func copyStaticFiles(src string, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("output directory does not exist: %s", src)
+	}
+
+	files, err := archive.TarWithOptions(src, &archive.TarOptions{ExcludePatterns: []string{".git"}})
+	if err != nil {
+		return err
+	}
+	defer files.Close()
+
+	return archive.Untar(files, dst, &archive.TarOptions{NoLchown: true})
+}
+
+// activateStaticRelease atomically points the current symlink of the site at the given release.
+func activateStaticRelease(applicationID uuid.UUID, deploymentID uuid.UUID) error {
+	sitePath := StaticSitePath(applicationID)
+	tmpLink := filepath.Join(sitePath, staticCurrentLink+".tmp")
+
+	os.Remove(tmpLink)
+	if err := os.Symlink(filepath.Join(staticReleasesDir, deploymentID.String()), tmpLink); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpLink, filepath.Join(sitePath, staticCurrentLink))
+}
+
+// pruneStaticReleases removes the oldest releases of a site, keeping the given number of
+// releases for rollback. The active release is never removed.
+func pruneStaticReleases(applicationID uuid.UUID, activeID uuid.UUID, keep int) error {
+	releasesPath := filepath.Join(StaticSitePath(applicationID), staticReleasesDir)
+	entries, err := os.ReadDir(releasesPath)
+	if err != nil {
+		return err
+	}
+
+	type release struct {
+		name    string
+		modTime int64
+	}
+	var releases []release
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == activeID.String() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		releases = append(releases, release{name: entry.Name(), modTime: info.ModTime().UnixNano()})
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].modTime > releases[j].modTime
+	})
+
+	// the active release counts towards the releases to keep
+	if keep < 1 || len(releases) < keep {
+		return nil
+	}
+	for _, r := range releases[keep-1:] {
+		if err := os.RemoveAll(filepath.Join(releasesPath, r.name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serveStaticSite configures the proxy to serve the current release of the site.
+func (t *TaskService) serveStaticSite(application shared_types.Application) error {
+	rootDir := filepath.Join(StaticSitePath(application.ID), staticCurrentLink)
+	return t.proxyFor(application).ServeFiles(applicationSite(application), rootDir, staticSiteOptions(application))
+}
+
+func staticSiteOptions(application shared_types.Application) proxy.StaticSiteOptions {
+	return proxy.StaticSiteOptions{
+		Browse:       application.DirectoryBrowsing,
+		SPAFallback:  application.SpaFallback,
+		NotFoundPage: application.NotFoundPage,
+		CacheControl: application.CacheControl,
+	}
+}
@@ -0,0 +1,136 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createStaticReleases creates a release for every id, the first one being the oldest.
+func createStaticReleases(t *testing.T, applicationID uuid.UUID, ids ...uuid.UUID) {
+	t.Helper()
+	now := time.Now()
+	for i, id := range ids {
+		releasePath := staticReleasePath(applicationID, id)
+		require.NoError(t, os.MkdirAll(releasePath, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(releasePath, "index.html"), []byte(id.String()), 0o644))
+		modTime := now.Add(time.Duration(i-len(ids)) * time.Minute)
+		require.NoError(t, os.Chtimes(releasePath, modTime, modTime))
+	}
+}
+
+func staticReleaseNames(t *testing.T, applicationID uuid.UUID) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(StaticSitePath(applicationID), staticReleasesDir))
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedNames(ids ...uuid.UUID) []string {
+	var names []string
+	for _, id := range ids {
+		names = append(names, id.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestActivateStaticRelease(t *testing.T) {
+	t.Setenv("MOUNT_PATH", t.TempDir())
+	applicationID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	createStaticReleases(t, applicationID, first, second)
+
+	current := filepath.Join(StaticSitePath(applicationID), staticCurrentLink)
+	for _, id := range []uuid.UUID{first, second, first} {
+		require.NoError(t, activateStaticRelease(applicationID, id))
+
+		target, err := os.Readlink(current)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(staticReleasesDir, id.String()), target, "the link is relative so the site can be moved")
+
+		content, err := os.ReadFile(filepath.Join(current, "index.html"))
+		require.NoError(t, err)
+		assert.Equal(t, id.String(), string(content))
+	}
+
+	_, err := os.Lstat(filepath.Join(StaticSitePath(applicationID), staticCurrentLink+".tmp"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestPruneStaticReleases(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+
+	tests := []struct {
+		name   string
+		active uuid.UUID
+		keep   int
+		want   []string
+	}{
+		{name: "Keeps the newest releases", active: ids[3], keep: 2, want: sortedNames(ids[2], ids[3])},
+		{name: "Keeps the active release when it is old", active: ids[0], keep: 2, want: sortedNames(ids[0], ids[3])},
+		{name: "Keeps only the active release", active: ids[3], keep: 1, want: sortedNames(ids[3])},
+		{name: "Fewer releases than kept", active: ids[3], keep: 10, want: sortedNames(ids...)},
+		{name: "Pruning disabled", active: ids[3], keep: 0, want: sortedNames(ids...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MOUNT_PATH", t.TempDir())
+			applicationID := uuid.New()
+			createStaticReleases(t, applicationID, ids...)
+
+			require.NoError(t, pruneStaticReleases(applicationID, tt.active, tt.keep))
+			assert.Equal(t, tt.want, staticReleaseNames(t, applicationID))
+		})
+	}
+}
+
+func TestCopyStaticFiles(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "assets"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, ".git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "index.html"), []byte("<h1>hi</h1>"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "assets", "app.js"), []byte("console.log(1)"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0o644))
+
+	dst := filepath.Join(t.TempDir(), "release")
+	require.NoError(t, copyStaticFiles(src, dst))
+
+	content, err := os.ReadFile(filepath.Join(dst, "index.html"))
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>hi</h1>", string(content))
+	assert.FileExists(t, filepath.Join(dst, "assets", "app.js"))
+	assert.NoDirExists(t, filepath.Join(dst, ".git"))
+
+	assert.Error(t, copyStaticFiles(filepath.Join(src, "dist"), dst))
+}
+
+func TestStaticSiteOptions(t *testing.T) {
+	application := shared_types.Application{
+		DirectoryBrowsing: true,
+		SpaFallback:       true,
+		NotFoundPage:      "404.html",
+		CacheControl:      "public, max-age=3600",
+	}
+
+	assert.Equal(t, proxy.StaticSiteOptions{
+		Browse:       true,
+		SPAFallback:  true,
+		NotFoundPage: "404.html",
+		CacheControl: "public, max-age=3600",
+	}, staticSiteOptions(application))
+}
@@ -32,10 +32,13 @@ func GetMapFromString(s string) map[string]string {
 }
 
 type TaskContext struct {
-	service       *TaskService
-	applicationID uuid.UUID
-	deploymentID  uuid.UUID
-	statusID      uuid.UUID
+	service        *TaskService
+	applicationID  uuid.UUID
+	deploymentID   uuid.UUID
+	statusID       uuid.UUID
+	attempt        int
+	completedPhase shared_types.DeploymentPhase
+	github         *githubReport
 }
 
 func (s *TaskService) NewTaskContext(result shared_types.TaskPayload) *TaskContext {
@@ -47,10 +50,13 @@ func (s *TaskService) NewTaskContext(result shared_types.TaskPayload) *TaskConte
 	}
 
 	return &TaskContext{
-		service:       s,
-		applicationID: result.Application.ID,
-		deploymentID:  result.ApplicationDeployment.ID,
-		statusID:      statusID,
+		service:        s,
+		applicationID:  result.Application.ID,
+		deploymentID:   result.ApplicationDeployment.ID,
+		statusID:       statusID,
+		attempt:        result.ApplicationDeployment.Attempts,
+		completedPhase: result.ApplicationDeployment.CompletedPhase,
+		github:         newGithubReport(result),
 	}
 }
 
@@ -73,6 +79,8 @@ func (tc *TaskContext) UpdateStatus(status shared_types.Status) {
 	if err != nil {
 		tc.service.Logger.Log(logger.Error, "Failed to update application deployment status: "+err.Error(), "")
 	}
+
+	tc.reportGithubStatus(status)
 }
 
 func (tc *TaskContext) AddLog(logMessage string) {
@@ -83,6 +91,7 @@ func (tc *TaskContext) AddLog(logMessage string) {
 		CreatedAt:               time.Now(),
 		UpdatedAt:               time.Now(),
 		ApplicationDeploymentID: tc.deploymentID,
+		Attempt:                 tc.attempt,
 	}
 
 	err := tc.service.Storage.AddApplicationLogs(&appLog)
@@ -96,6 +105,24 @@ func (tc *TaskContext) LogAndUpdateStatus(message string, status shared_types.St
 	tc.UpdateStatus(status)
 }
 
+// PhaseCompleted reports whether a previous attempt of the deployment already completed the phase.
+func (tc *TaskContext) PhaseCompleted(phase shared_types.DeploymentPhase) bool {
+	return phaseIndex(tc.completedPhase) >= phaseIndex(phase)
+}
+
+// CompletePhase records that the deployment completed the phase, so retries resume after it.
+func (tc *TaskContext) CompletePhase(phase shared_types.DeploymentPhase) {
+	tc.completedPhase = phase
+	err := tc.service.Storage.SetDeploymentPhase(tc.deploymentID, phase)
+	if err != nil {
+		tc.service.Logger.Log(logger.Error, "Failed to record deployment phase: "+err.Error(), "")
+	}
+}
+
+func (tc *TaskContext) GetAttempt() int {
+	return tc.attempt
+}
+
 func (tc *TaskContext) GetApplicationID() uuid.UUID {
 	return tc.applicationID
 }
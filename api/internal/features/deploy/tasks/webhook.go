@@ -41,6 +41,7 @@ func (t *TaskService) EnqueueWebhookTask(payload shared_types.WebhookPayload) er
 			Port:                 application.Port,
 			DockerfilePath:       application.DockerfilePath,
 			BasePath:             application.BasePath,
+			CommitHash:           payload.After,
 		}
 
 		_, err := t.UpdateDeployment(deployment, application.UserID, application.OrganizationID)
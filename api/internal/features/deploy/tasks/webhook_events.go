@@ -0,0 +1,37 @@
+package tasks
+
+import (
+	webhook_service "github.com/raghavyuva/nixopus-api/internal/features/webhooks/service"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// publishDeploymentEvent sends a deployment event to the webhook subscriptions of the
+// organization the application belongs to.
+func publishDeploymentEvent(payload shared_types.TaskPayload, eventType shared_types.WebhookEventType, err error) {
+	data := shared_types.WebhookDeploymentData{
+		ApplicationID:   payload.Application.ID,
+		ApplicationName: payload.Application.Name,
+		Domain:          payload.Application.Domain,
+		Environment:     payload.Application.Environment,
+		DeploymentID:    payload.ApplicationDeployment.ID,
+		CommitHash:      payload.ApplicationDeployment.CommitHash,
+		Attempt:         payload.ApplicationDeployment.Attempts,
+	}
+	if err != nil {
+		data.Error = err.Error()
+	}
+	webhook_service.Publish(payload.Application.OrganizationID, eventType, data)
+}
+
+// publishApplicationEvent sends an application.created or application.deleted event.
+func publishApplicationEvent(application shared_types.Application, eventType shared_types.WebhookEventType) {
+	webhook_service.Publish(application.OrganizationID, eventType, shared_types.WebhookApplicationData{
+		ApplicationID: application.ID,
+		Name:          application.Name,
+		Domain:        application.Domain,
+		Environment:   application.Environment,
+		BuildPack:     application.BuildPack,
+		Branch:        application.Branch,
+		UserID:        application.UserID,
+	})
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/testutils"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serverHost = "10.0.0.1"
+
+func newApplication(t *testing.T, deployStorage *storage.DeployStorage, user *shared_types.User, org *shared_types.Organization, name string) *shared_types.Application {
+	application := &shared_types.Application{
+		ID:             uuid.New(),
+		Name:           name,
+		Port:           3000,
+		Environment:    shared_types.Development,
+		BuildPack:      shared_types.DockerFile,
+		UserID:         user.ID,
+		OrganizationID: org.ID,
+	}
+	require.NoError(t, deployStorage.AddApplication(application))
+	return application
+}
+
+func TestPortAllocationStorage(t *testing.T) {
+	setup := testutils.NewTestSetup()
+	deployStorage := &storage.DeployStorage{DB: setup.DB, Ctx: setup.Ctx}
+
+	user, org, err := setup.CreateTestUserAndOrg()
+	require.NoError(t, err)
+
+	t.Run("ReuseExistingAllocation", func(t *testing.T) {
+		application := newApplication(t, deployStorage, user, org, "reuse")
+
+		first, err := deployStorage.AllocatePort(application.ID, serverHost, types.AllocatePortOptions{})
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, first.Port, types.PortRangeStart)
+		assert.LessOrEqual(t, first.Port, types.PortRangeEnd)
+
+		second, err := deployStorage.AllocatePort(application.ID, serverHost, types.AllocatePortOptions{Preferred: 50000})
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, first.Port, second.Port)
+
+		found, err := deployStorage.FindPortAllocation(application.ID, serverHost)
+		require.NoError(t, err)
+		assert.Equal(t, first.Port, found.Port)
+	})
+
+	t.Run("FindWithoutAllocationDoesNotAllocate", func(t *testing.T) {
+		application := newApplication(t, deployStorage, user, org, "lookup")
+
+		found, err := deployStorage.FindPortAllocation(application.ID, serverHost)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		found, err = deployStorage.FindPortAllocation(application.ID, serverHost)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("PreferredPortIsKeptWhenFree", func(t *testing.T) {
+		legacy := newApplication(t, deployStorage, user, org, "legacy")
+		other := newApplication(t, deployStorage, user, org, "other")
+
+		allocation, err := deployStorage.AllocatePort(legacy.ID, serverHost, types.AllocatePortOptions{Preferred: 51000})
+		require.NoError(t, err)
+		assert.Equal(t, 51000, allocation.Port)
+
+		allocation, err = deployStorage.AllocatePort(other.ID, serverHost, types.AllocatePortOptions{Preferred: 51000})
+		require.NoError(t, err)
+		assert.NotEqual(t, 51000, allocation.Port)
+	})
+
+	t.Run("PortsInUseAreSkipped", func(t *testing.T) {
+		application := newApplication(t, deployStorage, user, org, "in-use")
+
+		inUse := make([]int, 0, types.PortRangeEnd-types.PortRangeStart)
+		for port := types.PortRangeStart; port < types.PortRangeEnd; port++ {
+			inUse = append(inUse, port)
+		}
+		allocation, err := deployStorage.AllocatePort(application.ID, "10.0.0.2", types.AllocatePortOptions{InUse: inUse})
+		require.NoError(t, err)
+		assert.Equal(t, types.PortRangeEnd, allocation.Port)
+	})
+
+	t.Run("ConcurrentAllocationsGetDistinctPorts", func(t *testing.T) {
+		const count = 20
+		applications := make([]*shared_types.Application, count)
+		for i := range applications {
+			applications[i] = newApplication(t, deployStorage, user, org, fmt.Sprintf("concurrent-%d", i))
+		}
+
+		ports := make([]int, count)
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		for i, application := range applications {
+			wg.Add(1)
+			go func(i int, id uuid.UUID) {
+				defer wg.Done()
+				// every application prefers the same port, only one of them may get it
+				allocation, err := deployStorage.AllocatePort(id, "10.0.0.3", types.AllocatePortOptions{Preferred: 52000})
+				errs[i] = err
+				if err == nil {
+					ports[i] = allocation.Port
+				}
+			}(i, application.ID)
+		}
+		wg.Wait()
+
+		seen := make(map[int]bool, count)
+		for i := range applications {
+			require.NoError(t, errs[i])
+			assert.False(t, seen[ports[i]], "port %d allocated twice", ports[i])
+			seen[ports[i]] = true
+		}
+		assert.True(t, seen[52000])
+	})
+
+	t.Run("AddApplicationWithPort", func(t *testing.T) {
+		application := &shared_types.Application{
+			ID:             uuid.New(),
+			Name:           "with-port",
+			Port:           3000,
+			Environment:    shared_types.Development,
+			BuildPack:      shared_types.DockerFile,
+			UserID:         user.ID,
+			OrganizationID: org.ID,
+		}
+		allocation, err := deployStorage.AddApplicationWithPort(application, serverHost, types.AllocatePortOptions{})
+		require.NoError(t, err)
+
+		found, err := deployStorage.FindPortAllocation(application.ID, serverHost)
+		require.NoError(t, err)
+		assert.Equal(t, allocation.Port, found.Port)
+	})
+}
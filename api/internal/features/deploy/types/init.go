@@ -2,8 +2,10 @@ package types
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
 	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
 )
 
@@ -37,6 +39,27 @@ type CreateDeploymentRequest struct {
 	Port                 int                      `json:"port"`
 	DockerfilePath       string                   `json:"dockerfile_path,omitempty"`
 	BasePath             string                   `json:"base_path,omitempty"`
+	BuildCommand         string                   `json:"build_command,omitempty"`
+	BuildImage           string                   `json:"build_image,omitempty"`
+	OutputDirectory      string                   `json:"output_directory,omitempty"`
+	SpaFallback          bool                     `json:"spa_fallback,omitempty"`
+	NotFoundPage         string                   `json:"not_found_page,omitempty"`
+	CacheControl         string                   `json:"cache_control,omitempty"`
+	DirectoryBrowsing    bool                     `json:"directory_browsing,omitempty"`
+	ScaleToZero          bool                     `json:"scale_to_zero,omitempty"`
+	IdleTimeout          int                      `json:"idle_timeout,omitempty"`
+	GitSubmodules        bool                     `json:"git_submodules,omitempty"`
+	GitLFS               bool                     `json:"git_lfs,omitempty"`
+	CloneDepth           int                      `json:"clone_depth,omitempty"`
+	CloneFilter          string                   `json:"clone_filter,omitempty"`
+	ReportGithubStatus   bool                     `json:"report_github_status,omitempty"`
+	ProxyServer          shared_types.ProxyServer `json:"proxy_server,omitempty"`
+	PathPrefix           string                   `json:"path_prefix,omitempty"`
+	StripPathPrefix      bool                     `json:"strip_path_prefix,omitempty"`
+	RoutePriority        int                      `json:"route_priority,omitempty"`
+	// ComposeFile and Template are set when an application is created from the template catalog
+	ComposeFile string `json:"-"`
+	Template    string `json:"-"`
 }
 
 type UpdateDeploymentRequest struct {
@@ -50,6 +73,34 @@ type UpdateDeploymentRequest struct {
 	Force                bool              `json:"force,omitempty"`
 	DockerfilePath       string            `json:"dockerfile_path,omitempty"`
 	BasePath             string            `json:"base_path,omitempty"`
+	BuildCommand         *string           `json:"build_command,omitempty"`
+	BuildImage           string            `json:"build_image,omitempty"`
+	OutputDirectory      *string           `json:"output_directory,omitempty"`
+	SpaFallback          *bool             `json:"spa_fallback,omitempty"`
+	NotFoundPage         *string           `json:"not_found_page,omitempty"`
+	CacheControl         *string           `json:"cache_control,omitempty"`
+	DirectoryBrowsing    *bool             `json:"directory_browsing,omitempty"`
+	ScaleToZero          *bool             `json:"scale_to_zero,omitempty"`
+	IdleTimeout          *int              `json:"idle_timeout,omitempty"`
+	GitSubmodules        *bool             `json:"git_submodules,omitempty"`
+	GitLFS               *bool             `json:"git_lfs,omitempty"`
+	CloneDepth           *int              `json:"clone_depth,omitempty"`
+	CloneFilter          *string           `json:"clone_filter,omitempty"`
+	ReportGithubStatus   *bool             `json:"report_github_status,omitempty"`
+	// CommitHash is set by push webhooks to the commit that triggered the deployment
+	CommitHash string `json:"-"`
+}
+
+type ReprioritizeQueueTaskRequest struct {
+	Queue    string `json:"queue"`
+	TaskID   string `json:"task_id"`
+	Position string `json:"position"`
+}
+
+type PurgeQueueRequest struct {
+	Queue  string `json:"queue"`
+	TaskID string `json:"task_id,omitempty"`
+	State  string `json:"state,omitempty"`
 }
 
 type DeleteDeploymentRequest struct {
@@ -70,11 +121,123 @@ type RestartDeploymentRequest struct {
 	ID uuid.UUID `json:"id"`
 }
 
+type StopApplicationRequest struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type StartApplicationRequest struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// UpdateProxySettingsRequest replaces the proxy settings of an application. The basic auth
+// password is only needed when basic auth is turned on or its password is changed.
+type UpdateProxySettingsRequest struct {
+	ID uuid.UUID `json:"id"`
+	shared_types.ProxySettings
+	BasicAuthPassword string `json:"basic_auth_password,omitempty"`
+}
+
+// UpdateMaintenanceRequest replaces the maintenance settings of an application, turning
+// maintenance on or off.
+type UpdateMaintenanceRequest struct {
+	ID uuid.UUID `json:"id"`
+	shared_types.MaintenanceSettings
+}
+
+// AddApplicationDomainRequest assigns a domain of the organization, or a subdomain of it,
+// to an application.
+type AddApplicationDomainRequest struct {
+	ID        uuid.UUID               `json:"id"`
+	DomainID  uuid.UUID               `json:"domain_id"`
+	Subdomain string                  `json:"subdomain,omitempty"`
+	Mode      shared_types.DomainMode `json:"mode"`
+	IsPrimary bool                    `json:"is_primary"`
+}
+
+// UpdateApplicationDomainRequest changes how a domain of an application is served or makes it
+// the primary domain.
+type UpdateApplicationDomainRequest struct {
+	ID                  uuid.UUID               `json:"id"`
+	ApplicationDomainID uuid.UUID               `json:"application_domain_id"`
+	Mode                shared_types.DomainMode `json:"mode"`
+	IsPrimary           bool                    `json:"is_primary"`
+}
+
+type RemoveApplicationDomainRequest struct {
+	ID                  uuid.UUID `json:"id"`
+	ApplicationDomainID uuid.UUID `json:"application_domain_id"`
+}
+
+// AddApplicationPortRequest exposes a TCP or UDP port of an application on a fixed port of its
+// server, or routes the TLS connections for SNI to it through the proxy.
+type AddApplicationPortRequest struct {
+	ID            uuid.UUID                 `json:"id"`
+	Protocol      shared_types.PortProtocol `json:"protocol"`
+	TargetPort    int                       `json:"target_port"`
+	PublishedPort int                       `json:"published_port,omitempty"`
+	SNI           string                    `json:"sni,omitempty"`
+}
+
+type RemoveApplicationPortRequest struct {
+	ID                uuid.UUID `json:"id"`
+	ApplicationPortID uuid.UUID `json:"application_port_id"`
+}
+
+// ReconcileProxyRequest compares the routes of Caddy with the applications, repairing the drift
+// when Fix is set and only reporting it otherwise.
+type ReconcileProxyRequest struct {
+	Fix bool `json:"fix"`
+}
+
+// ProxyReconciliation is the drift found between the routes of Caddy and the applications.
+// Applications whose route could not be rendered are listed in Skipped, their routes are left
+// as they are.
+type ProxyReconciliation struct {
+	Fixed     bool               `json:"fixed"`
+	Drift     []proxy.RouteDrift `json:"drift"`
+	Skipped   []SkippedRoute     `json:"skipped,omitempty"`
+	CheckedAt time.Time          `json:"checked_at"`
+}
+
+type SkippedRoute struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	Name          string    `json:"name"`
+	Error         string    `json:"error"`
+}
+
+// ServerPorts lists the ports published on a server, the HTTP ports routed by the proxy as
+// well as the exposed TCP and UDP ports.
+type ServerPorts struct {
+	ServerHost string          `json:"server_host"`
+	Ports      []PublishedPort `json:"ports"`
+}
+
+// PublishedPort is a port of an application published on its server.
+type PublishedPort struct {
+	ApplicationID   uuid.UUID                 `json:"application_id"`
+	ApplicationName string                    `json:"application_name"`
+	Kind            PublishedPortKind         `json:"kind"`
+	Protocol        shared_types.PortProtocol `json:"protocol"`
+	PublishedPort   int                       `json:"published_port"`
+	TargetPort      int                       `json:"target_port"`
+	SNI             string                    `json:"sni,omitempty"`
+}
+
+type PublishedPortKind string
+
+const (
+	// PublishedPortHTTP is the port the proxy routes the domains of an application to
+	PublishedPortHTTP PublishedPortKind = "http"
+	// PublishedPortExposed is a port exposed with AddApplicationPortRequest
+	PublishedPortExposed PublishedPortKind = "exposed"
+)
+
 var (
 	ErrMissingID                    = errors.New("id is required")
 	ErrInvalidRequestType           = errors.New("invalid request type")
 	ErrMissingName                  = errors.New("name is required")
 	ErrMissingDomain                = errors.New("domain is required")
+	ErrInvalidDomain                = errors.New("domain must be a hostname such as app.example.com")
 	ErrMissingRepository            = errors.New("repository is required")
 	ErrMissingBranch                = errors.New("branch is required")
 	ErrMissingPort                  = errors.New("port is required")
@@ -95,6 +258,116 @@ var (
 	ErrDockerComposeCommandFailed   = errors.New("docker-compose command failed")
 	ErrDockerComposeInvalidConfig   = errors.New("invalid docker-compose configuration")
 	ErrFailedToGetAvailablePort     = errors.New("failed to get available port")
+	ErrNoRoutedPort                 = errors.New("application has no allocated port and its service publishes none")
+	ErrReleaseCommandFailed         = errors.New("release command failed")
+	ErrReleaseCommandExited         = errors.New("release command exited with a non-zero code")
+	ErrReleaseCommandTimedOut       = errors.New("release command timed out")
+	ErrNoAvailablePorts             = errors.New("no available ports found in range 49152-65535")
+	ErrStaticBuildFailed            = errors.New("static site build failed")
+	ErrStaticReleaseNotFound        = errors.New("static site release not found")
+	ErrUnknownQueue                 = errors.New("unknown deployment queue")
+	ErrBuildContextNotFound         = errors.New("build context path does not exist")
+	ErrDockerfileNotFound           = errors.New("dockerfile not found")
+	ErrInvalidRepositoryID          = errors.New("invalid repository id")
+	ErrApplicationNotStopped        = errors.New("application is not stopped")
+	ErrApplicationAlreadyStopped    = errors.New("application is already stopped")
+	ErrStaticSiteCannotBeStopped    = errors.New("static sites have no service to stop")
+	ErrServiceNotFound              = errors.New("no service found for application")
+	ErrServiceNotHealthy            = errors.New("service did not become healthy in time")
+	ErrInvalidWakeToken             = errors.New("invalid wake token")
+	ErrComposeCannotBeStopped       = errors.New("docker compose applications cannot be stopped")
+	ErrComposeRollbackUnsupported   = errors.New("docker compose applications cannot be rolled back, redeploy them instead")
+	ErrInvalidCloneDepth            = errors.New("clone_depth must be 0 for the full history or a positive number of commits")
+	ErrInvalidCloneFilter           = errors.New("clone_filter must be blob:none or tree:0")
+	ErrInvalidProxyHeader           = errors.New("response header names must be valid HTTP header names and values must not contain line breaks")
+	ErrTooManyProxyHeaders          = errors.New("at most 50 response headers can be set")
+	ErrInvalidFrameOptions          = errors.New("frame_options must be DENY or SAMEORIGIN")
+	ErrInvalidHSTSMaxAge            = errors.New("hsts_max_age must not be negative")
+	ErrInvalidBasicAuthUsername     = errors.New("basic_auth_username must not contain a colon")
+	ErrMissingBasicAuthPassword     = errors.New("basic_auth_password is required to turn on basic auth")
+	ErrBasicAuthPasswordTooShort    = errors.New("basic_auth_password must be at least 8 characters")
+	ErrInvalidMaxBodySize           = errors.New("max_body_size must not be negative")
+	ErrInvalidProxyTimeout          = errors.New("proxy timeouts must be between 0 and 3600 seconds")
+	ErrInvalidProxyServer           = errors.New("proxy_server must be caddy or nginx")
+	ErrInvalidPathPrefix            = errors.New("path_prefix must be a path such as /api, without a trailing slash or wildcards")
+	ErrInvalidRoutePriority         = errors.New("route_priority must be between -1000 and 1000")
+	ErrPathPrefixNeedsCaddy         = errors.New("path_prefix is only supported on applications served by caddy")
+	ErrScaleToZeroNeedsCaddy        = errors.New("scale_to_zero is only supported on applications served by caddy")
+	ErrScaleToZeroNeedsAccessLogs   = errors.New("scale_to_zero needs the access logs of the proxy, set ACCESS_LOG_PORT to turn them on")
+	ErrApplyProxySettings           = errors.New("proxy settings were saved but could not be applied, they take effect with the next deployment")
+	ErrInvalidMaintenanceRedirect   = errors.New("redirect_url must be an absolute http or https URL")
+	ErrMaintenancePageAndRedirect   = errors.New("page and redirect_url cannot both be set")
+	ErrMaintenancePageTooLarge      = errors.New("page must not be larger than 64 KiB")
+	ErrInvalidBypassIP              = errors.New("bypass_ips must be IP addresses or CIDR ranges, at most 100")
+	ErrInvalidBypassToken           = errors.New("bypass_token must be 16 to 128 letters, digits, dots, dashes, underscores or tildes")
+	ErrApplyMaintenance             = errors.New("maintenance settings were saved but could not be applied, they take effect with the next deployment")
+	ErrMissingDomainID              = errors.New("domain_id is required")
+	ErrMissingApplicationDomainID   = errors.New("application_domain_id is required")
+	ErrInvalidSubdomain             = errors.New("subdomain must be one or more DNS labels")
+	ErrInvalidDomainMode            = errors.New("mode must be serve or redirect")
+	ErrPrimaryDomainMustServe       = errors.New("the primary domain must be served, it cannot redirect")
+	ErrDomainAlreadyTaken           = errors.New("domain is already used by another application below the same path")
+	ErrDomainNotVerified            = errors.New("domain is not verified, publish its TXT record and verify it first")
+	ErrDomainOwnedElsewhere         = errors.New("domain belongs to a domain verified by another organization")
+	ErrDomainNotRegistered          = errors.New("domain is not registered in the organization")
+	ErrApplicationDomainNotFound    = errors.New("domain is not assigned to the application")
+	ErrCannotRemovePrimaryDomain    = errors.New("the primary domain cannot be removed, make another domain primary first")
+	ErrApplyDomains                 = errors.New("domains were saved but could not be routed, they take effect with the next deployment")
+	ErrMissingApplicationPortID     = errors.New("application_port_id is required")
+	ErrInvalidPortProtocol          = errors.New("protocol must be tcp or udp")
+	ErrInvalidTargetPort            = errors.New("target_port must be between 1 and 65535")
+	ErrInvalidPublishedPort         = errors.New("published_port must be between 1 and 65535")
+	ErrInvalidSNI                   = errors.New("sni must be a host name")
+	ErrSNIRequiresTCP               = errors.New("sni routing only works for tcp ports serving TLS")
+	ErrSNIWithPublishedPort         = errors.New("ports routed by sni are published on an allocated port, published_port must be empty")
+	ErrSNIRoutingDisabled           = errors.New("sni routing is turned off, set LAYER4_LISTEN and run Caddy with the layer4 module")
+	ErrPortsNeedDockerfile          = errors.New("only applications deployed from a Dockerfile can expose ports")
+	ErrPublishedPortReserved        = errors.New("published_port is reserved on the server")
+	ErrPublishedPortTaken           = errors.New("published_port is already used on the server")
+	ErrTargetPortAlreadyExposed     = errors.New("target_port is already exposed with this protocol")
+	ErrSNIAlreadyTaken              = errors.New("sni is already routed to another port")
+	ErrApplicationPortNotFound      = errors.New("port is not exposed by the application")
+	ErrApplyPorts                   = errors.New("ports were saved but could not be published, they take effect with the next deployment")
+)
+
+// Partial clone filters an application can be cloned with.
+const (
+	// CloneFilterBlobless fetches file contents only when they are checked out.
+	CloneFilterBlobless = "blob:none"
+	// CloneFilterTreeless also fetches directories lazily, best suited to single builds.
+	CloneFilterTreeless = "tree:0"
+)
+
+// Host ports published by application services are allocated from this range.
+const (
+	PortRangeStart = 49152
+	PortRangeEnd   = 65535
+)
+
+// AllocatePortOptions tune how a host port is picked for an application.
+type AllocatePortOptions struct {
+	// Preferred is allocated when no other application holds it, like the port the service of
+	// an application created before ports were tracked already publishes.
+	Preferred int
+	// InUse are the ports of the server taken outside of the allocations, by listeners on the
+	// host or by services published outside of Nixopus. They are never allocated.
+	InUse []int
+}
+
+const (
+	// DefaultStaticBuildImage is the builder image used for static sites when none is configured.
+	DefaultStaticBuildImage = "node:lts-alpine"
+	// StaticReleasesToKeep is the number of static site releases kept on disk for instant rollback.
+	StaticReleasesToKeep = 5
+)
+
+const (
+	// DefaultIdleTimeout is the number of minutes without traffic after which a scale-to-zero application sleeps.
+	DefaultIdleTimeout = 15
+	// WakeTimeout bounds how long a request to a sleeping application is held while its service starts.
+	WakeTimeout = 2 * time.Minute
+	// WakeTokenHeader carries the token that proves a wake request was forwarded by the proxy.
+	WakeTokenHeader = "X-Nixopus-Wake-Token"
 )
 
 const (
@@ -113,6 +386,7 @@ const (
 	LogFailedToCreateApplicationStatus           = "Failed to create application status: %s"
 	LogFailedToCreateApplicationDeployment       = "Failed to create application deployment: %s"
 	LogFailedToCreateApplicationDeploymentStatus = "Failed to create application deployment status: %s"
+	LogFailedToAllocatePort                      = "Failed to allocate port: %s"
 	LogFailedToCreateApplicationLogs             = "Failed to create application logs: %s"
 	LogFailedToUpdateApplicationRecord           = "Failed to update application record"
 	LogFailedToUpdateApplicationDeployment       = "Failed to update application deployment"
@@ -3,11 +3,20 @@ package validation
 import (
 	"encoding/json"
 	"io"
+	"net"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
 )
 
 type Validator struct {
@@ -35,6 +44,31 @@ func (v *Validator) ValidateRequest(req interface{}) error {
 		return validateRollbackDeploymentRequest(*r)
 	case *types.RestartDeploymentRequest:
 		return validateRestartDeploymentRequest(*r)
+	case *types.StopApplicationRequest:
+		return validateStopApplicationRequest(*r)
+	case *types.StartApplicationRequest:
+		return validateStartApplicationRequest(*r)
+	case *types.UpdateProxySettingsRequest:
+		return validateUpdateProxySettingsRequest(r)
+	case *types.UpdateMaintenanceRequest:
+		return validateUpdateMaintenanceRequest(r)
+	case *types.AddApplicationDomainRequest:
+		return validateAddApplicationDomainRequest(*r)
+	case *types.UpdateApplicationDomainRequest:
+		return validateUpdateApplicationDomainRequest(*r)
+	case *types.RemoveApplicationDomainRequest:
+		return validateRemoveApplicationDomainRequest(*r)
+	case *types.AddApplicationPortRequest:
+		return validateAddApplicationPortRequest(r)
+	case *types.RemoveApplicationPortRequest:
+		return validateRemoveApplicationPortRequest(*r)
+	case *types.ReprioritizeQueueTaskRequest:
+		return validateReprioritizeQueueTaskRequest(*r)
+	case *types.PurgeQueueRequest:
+		return validatePurgeQueueRequest(*r)
+	case *types.ReconcileProxyRequest:
+		// fix is the only field and both values are valid
+		return nil
 	default:
 		return types.ErrInvalidRequestType
 	}
@@ -47,6 +81,9 @@ func validateDeploymentRequest(req *types.CreateDeploymentRequest) error {
 	if req.Domain == "" {
 		return errors.New("domain is required")
 	}
+	if len(req.Domain) > 253 || !subdomainPattern.MatchString(strings.ToLower(req.Domain)) {
+		return types.ErrInvalidDomain
+	}
 	if req.Environment == "" {
 		return errors.New("environment is required")
 	}
@@ -59,7 +96,7 @@ func validateDeploymentRequest(req *types.CreateDeploymentRequest) error {
 	if req.Branch == "" {
 		return errors.New("branch is required")
 	}
-	if req.Port == 0 {
+	if req.Port == 0 && req.BuildPack != shared_types.Static {
 		return errors.New("port is required")
 	}
 	if req.BasePath == "" {
@@ -67,7 +104,38 @@ func validateDeploymentRequest(req *types.CreateDeploymentRequest) error {
 	} else if req.BasePath[0] != '/' {
 		req.BasePath = "/" + req.BasePath
 	}
-	return nil
+	if err := validateRelativePath(req.OutputDirectory, "output_directory"); err != nil {
+		return err
+	}
+	if err := validateRelativePath(req.NotFoundPage, "not_found_page"); err != nil {
+		return err
+	}
+	if req.ScaleToZero && req.BuildPack == shared_types.Static {
+		return types.ErrStaticSiteCannotBeStopped
+	}
+	if req.IdleTimeout < 0 {
+		return errors.New("idle_timeout must be a positive number of minutes")
+	}
+	switch req.ProxyServer {
+	case "":
+		req.ProxyServer = shared_types.Caddy
+	case shared_types.Caddy, shared_types.Nginx:
+	default:
+		return types.ErrInvalidProxyServer
+	}
+	if err := validatePathRoute(&req.PathPrefix, req.RoutePriority); err != nil {
+		return err
+	}
+	if req.PathPrefix != "" && req.ProxyServer != shared_types.Caddy {
+		return types.ErrPathPrefixNeedsCaddy
+	}
+	if req.ScaleToZero && req.ProxyServer != shared_types.Caddy {
+		return types.ErrScaleToZeroNeedsCaddy
+	}
+	if req.ScaleToZero && config.AppConfig.Proxy.AccessLogPort == "" {
+		return types.ErrScaleToZeroNeedsAccessLogs
+	}
+	return validateCloneOptions(req.CloneDepth, req.CloneFilter)
 }
 
 func validateUpdateDeploymentRequest(req *types.UpdateDeploymentRequest) error {
@@ -86,6 +154,59 @@ func validateUpdateDeploymentRequest(req *types.UpdateDeploymentRequest) error {
 			req.BasePath = "/" + req.BasePath
 		}
 	}
+	if req.OutputDirectory != nil {
+		if err := validateRelativePath(*req.OutputDirectory, "output_directory"); err != nil {
+			return err
+		}
+	}
+	if req.NotFoundPage != nil {
+		if err := validateRelativePath(*req.NotFoundPage, "not_found_page"); err != nil {
+			return err
+		}
+	}
+	if req.ScaleToZero != nil && *req.ScaleToZero && config.AppConfig.Proxy.AccessLogPort == "" {
+		return types.ErrScaleToZeroNeedsAccessLogs
+	}
+	if req.IdleTimeout != nil && *req.IdleTimeout < 1 {
+		return errors.New("idle_timeout must be a positive number of minutes")
+	}
+	if req.CloneDepth != nil {
+		if err := validateCloneOptions(*req.CloneDepth, ""); err != nil {
+			return err
+		}
+	}
+	if req.CloneFilter != nil {
+		if err := validateCloneOptions(0, *req.CloneFilter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCloneOptions checks the clone depth, 0 being the full history, and the partial
+// clone filter.
+func validateCloneOptions(depth int, filter string) error {
+	if depth < 0 {
+		return types.ErrInvalidCloneDepth
+	}
+	switch filter {
+	case "", types.CloneFilterBlobless, types.CloneFilterTreeless:
+		return nil
+	default:
+		return types.ErrInvalidCloneFilter
+	}
+}
+
+// validateRelativePath makes sure a path inside the repository or build output
+// cannot point outside of it.
+func validateRelativePath(path string, field string) error {
+	if path == "" {
+		return nil
+	}
+	cleaned := filepath.Clean(strings.TrimPrefix(path, "/"))
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return errors.New(field + " must be inside the repository")
+	}
 	return nil
 }
 
@@ -117,3 +238,267 @@ func validateRestartDeploymentRequest(req types.RestartDeploymentRequest) error
 	}
 	return nil
 }
+
+func validateStopApplicationRequest(req types.StopApplicationRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	return nil
+}
+
+func validateStartApplicationRequest(req types.StartApplicationRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	return nil
+}
+
+// maxProxyTimeout bounds the connect and response timeouts of the proxy, in seconds.
+const maxProxyTimeout = 3600
+
+// headerNamePattern matches the token characters allowed in HTTP header names.
+var headerNamePattern = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+func validateUpdateProxySettingsRequest(req *types.UpdateProxySettingsRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	if len(req.ResponseHeaders) > 50 {
+		return types.ErrTooManyProxyHeaders
+	}
+	for name, value := range req.ResponseHeaders {
+		if !headerNamePattern.MatchString(name) || strings.ContainsAny(value, "\r\n") {
+			return types.ErrInvalidProxyHeader
+		}
+	}
+	switch req.FrameOptions {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		return types.ErrInvalidFrameOptions
+	}
+	if req.HSTSMaxAge < 0 {
+		return types.ErrInvalidHSTSMaxAge
+	}
+	if strings.Contains(req.BasicAuthUsername, ":") {
+		return types.ErrInvalidBasicAuthUsername
+	}
+	if req.BasicAuthPassword != "" && len(req.BasicAuthPassword) < 8 {
+		return types.ErrBasicAuthPasswordTooShort
+	}
+	if req.MaxBodySize < 0 {
+		return types.ErrInvalidMaxBodySize
+	}
+	for _, timeout := range []int{req.ConnectTimeout, req.ResponseTimeout} {
+		if timeout < 0 || timeout > maxProxyTimeout {
+			return types.ErrInvalidProxyTimeout
+		}
+	}
+	return validatePathRoute(&req.PathPrefix, req.RoutePriority)
+}
+
+// pathPrefixPattern matches one or more path segments, each starting with a slash.
+var pathPrefixPattern = regexp.MustCompile(`^(/[A-Za-z0-9._~@-]+)+$`)
+
+// validatePathRoute normalizes the path prefix of a route, a trailing slash is dropped and the
+// root path routes the whole domain.
+func validatePathRoute(prefix *string, priority int) error {
+	*prefix = strings.TrimRight(strings.TrimSpace(*prefix), "/")
+	if *prefix != "" && !pathPrefixPattern.MatchString(*prefix) {
+		return types.ErrInvalidPathPrefix
+	}
+	if priority < -1000 || priority > 1000 {
+		return types.ErrInvalidRoutePriority
+	}
+	return nil
+}
+
+// bypassTokenPattern matches tokens that can be sent in a header and written into proxy
+// configurations without quoting.
+var bypassTokenPattern = regexp.MustCompile(`^[A-Za-z0-9._~-]{16,128}$`)
+
+// validateUpdateMaintenanceRequest trims the bypass addresses, which are written into the proxy
+// configuration as they are.
+func validateUpdateMaintenanceRequest(req *types.UpdateMaintenanceRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	if req.RedirectURL != "" {
+		u, err := url.Parse(req.RedirectURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return types.ErrInvalidMaintenanceRedirect
+		}
+		if req.Page != "" {
+			return types.ErrMaintenancePageAndRedirect
+		}
+	}
+	if len(req.Page) > 64*1024 {
+		return types.ErrMaintenancePageTooLarge
+	}
+	if len(req.BypassIPs) > 100 {
+		return types.ErrInvalidBypassIP
+	}
+	for i, ip := range req.BypassIPs {
+		ip = strings.TrimSpace(ip)
+		if net.ParseIP(ip) == nil {
+			if _, _, err := net.ParseCIDR(ip); err != nil {
+				return types.ErrInvalidBypassIP
+			}
+		}
+		req.BypassIPs[i] = ip
+	}
+	if req.BypassToken != "" && !bypassTokenPattern.MatchString(req.BypassToken) {
+		return types.ErrInvalidBypassToken
+	}
+	return nil
+}
+
+// subdomainPattern matches one or more DNS labels separated by dots.
+var subdomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+func validateAddApplicationDomainRequest(req types.AddApplicationDomainRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	if req.DomainID == uuid.Nil {
+		return types.ErrMissingDomainID
+	}
+	if req.Subdomain != "" && !subdomainPattern.MatchString(req.Subdomain) {
+		return types.ErrInvalidSubdomain
+	}
+	return validateDomainMode(req.Mode, req.IsPrimary)
+}
+
+func validateUpdateApplicationDomainRequest(req types.UpdateApplicationDomainRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	if req.ApplicationDomainID == uuid.Nil {
+		return types.ErrMissingApplicationDomainID
+	}
+	return validateDomainMode(req.Mode, req.IsPrimary)
+}
+
+func validateRemoveApplicationDomainRequest(req types.RemoveApplicationDomainRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	if req.ApplicationDomainID == uuid.Nil {
+		return types.ErrMissingApplicationDomainID
+	}
+	return nil
+}
+
+// validateDomainMode accepts an empty mode, which serves the domain.
+func validateDomainMode(mode shared_types.DomainMode, isPrimary bool) error {
+	switch mode {
+	case "", shared_types.DomainModeServe:
+		return nil
+	case shared_types.DomainModeRedirect:
+		if isPrimary {
+			return types.ErrPrimaryDomainMustServe
+		}
+		return nil
+	default:
+		return types.ErrInvalidDomainMode
+	}
+}
+
+// validateAddApplicationPortRequest defaults the protocol to tcp and lowercases the SNI.
+func validateAddApplicationPortRequest(req *types.AddApplicationPortRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	switch req.Protocol {
+	case "":
+		req.Protocol = shared_types.PortProtocolTCP
+	case shared_types.PortProtocolTCP, shared_types.PortProtocolUDP:
+	default:
+		return types.ErrInvalidPortProtocol
+	}
+	if req.TargetPort < 1 || req.TargetPort > 65535 {
+		return types.ErrInvalidTargetPort
+	}
+
+	req.SNI = strings.ToLower(strings.TrimSpace(req.SNI))
+	if req.SNI == "" {
+		if req.PublishedPort < 1 || req.PublishedPort > 65535 {
+			return types.ErrInvalidPublishedPort
+		}
+		if reservedPort(req.Protocol, req.PublishedPort) {
+			return types.ErrPublishedPortReserved
+		}
+		return nil
+	}
+	if !subdomainPattern.MatchString(req.SNI) {
+		return types.ErrInvalidSNI
+	}
+	if req.Protocol != shared_types.PortProtocolTCP {
+		return types.ErrSNIRequiresTCP
+	}
+	if req.PublishedPort != 0 {
+		return types.ErrSNIWithPublishedPort
+	}
+	return nil
+}
+
+// reservedPort reports whether port is used by the server itself: SSH, the HTTP and HTTPS
+// listeners of the proxy, HTTP/3 on UDP, the Caddy admin API, the API and its access log
+// listener, PostgreSQL, Redis and the layer4 listener. Well known defaults are reserved even
+// when the configuration points elsewhere, as the services usually still run on the server.
+func reservedPort(protocol shared_types.PortProtocol, port int) bool {
+	if protocol == shared_types.PortProtocolUDP {
+		return port == 443
+	}
+
+	reserved := []string{"22", "80", "443", "2019", "5432", "6379",
+		config.AppConfig.Server.Port,
+		config.AppConfig.Database.Port,
+		config.AppConfig.Proxy.AccessLogPort,
+	}
+	if config.AppConfig.SSH.Port != 0 {
+		reserved = append(reserved, strconv.Itoa(int(config.AppConfig.SSH.Port)))
+	}
+	if endpoint, err := url.Parse(config.AppConfig.Proxy.CaddyEndpoint); err == nil && endpoint.Port() != "" {
+		reserved = append(reserved, endpoint.Port())
+	}
+	if redis, err := url.Parse(config.AppConfig.Redis.URL); err == nil && redis.Port() != "" {
+		reserved = append(reserved, redis.Port())
+	}
+	if _, listenPort, err := net.SplitHostPort(config.AppConfig.Proxy.Layer4Listen); err == nil {
+		reserved = append(reserved, listenPort)
+	}
+	return slices.Contains(reserved, strconv.Itoa(port))
+}
+
+func validateRemoveApplicationPortRequest(req types.RemoveApplicationPortRequest) error {
+	if req.ID == uuid.Nil {
+		return types.ErrMissingID
+	}
+	if req.ApplicationPortID == uuid.Nil {
+		return types.ErrMissingApplicationPortID
+	}
+	return nil
+}
+
+func validateReprioritizeQueueTaskRequest(req types.ReprioritizeQueueTaskRequest) error {
+	if req.Queue == "" {
+		return errors.New("queue is required")
+	}
+	if req.TaskID == "" {
+		return errors.New("task_id is required")
+	}
+	if req.Position != "first" && req.Position != "last" {
+		return errors.New("position must be first or last")
+	}
+	return nil
+}
+
+func validatePurgeQueueRequest(req types.PurgeQueueRequest) error {
+	if req.Queue == "" {
+		return errors.New("queue is required")
+	}
+	if req.State != "" && req.State != "dead" {
+		return errors.New("state must be empty or dead")
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddApplicationPortRequest(t *testing.T) {
+	previous := config.AppConfig
+	defer func() { config.AppConfig = previous }()
+	config.AppConfig.Server.Port = "8080"
+	config.AppConfig.SSH.Port = 2222
+	config.AppConfig.Database.Port = "5433"
+	config.AppConfig.Redis.URL = "redis://localhost:6380"
+	config.AppConfig.Proxy.CaddyEndpoint = "http://localhost:2020"
+	config.AppConfig.Proxy.Layer4Listen = ":8443"
+	config.AppConfig.Proxy.AccessLogPort = "9400"
+
+	id := uuid.New()
+	tests := []struct {
+		name    string
+		request types.AddApplicationPortRequest
+		wantErr error
+	}{
+		{name: "Free tcp port", request: types.AddApplicationPortRequest{ID: id, TargetPort: 1883, PublishedPort: 1883}},
+		{name: "Free udp port", request: types.AddApplicationPortRequest{ID: id, Protocol: shared_types.PortProtocolUDP, TargetPort: 53, PublishedPort: 53}},
+		{name: "Missing id", request: types.AddApplicationPortRequest{TargetPort: 1883, PublishedPort: 1883}, wantErr: types.ErrMissingID},
+		{name: "Unknown protocol", request: types.AddApplicationPortRequest{ID: id, Protocol: "sctp", TargetPort: 1, PublishedPort: 1}, wantErr: types.ErrInvalidPortProtocol},
+		{name: "Invalid target port", request: types.AddApplicationPortRequest{ID: id, TargetPort: 70000, PublishedPort: 1883}, wantErr: types.ErrInvalidTargetPort},
+		{name: "Missing published port", request: types.AddApplicationPortRequest{ID: id, TargetPort: 1883}, wantErr: types.ErrInvalidPublishedPort},
+		{name: "SSH", request: types.AddApplicationPortRequest{ID: id, TargetPort: 22, PublishedPort: 22}, wantErr: types.ErrPublishedPortReserved},
+		{name: "Configured SSH port", request: types.AddApplicationPortRequest{ID: id, TargetPort: 22, PublishedPort: 2222}, wantErr: types.ErrPublishedPortReserved},
+		{name: "HTTP", request: types.AddApplicationPortRequest{ID: id, TargetPort: 80, PublishedPort: 80}, wantErr: types.ErrPublishedPortReserved},
+		{name: "HTTPS", request: types.AddApplicationPortRequest{ID: id, TargetPort: 443, PublishedPort: 443}, wantErr: types.ErrPublishedPortReserved},
+		{name: "HTTP/3", request: types.AddApplicationPortRequest{ID: id, Protocol: shared_types.PortProtocolUDP, TargetPort: 443, PublishedPort: 443}, wantErr: types.ErrPublishedPortReserved},
+		{name: "Caddy admin API", request: types.AddApplicationPortRequest{ID: id, TargetPort: 2020, PublishedPort: 2020}, wantErr: types.ErrPublishedPortReserved},
+		{name: "API", request: types.AddApplicationPortRequest{ID: id, TargetPort: 8080, PublishedPort: 8080}, wantErr: types.ErrPublishedPortReserved},
+		{name: "Access logs", request: types.AddApplicationPortRequest{ID: id, TargetPort: 9400, PublishedPort: 9400}, wantErr: types.ErrPublishedPortReserved},
+		{name: "PostgreSQL", request: types.AddApplicationPortRequest{ID: id, TargetPort: 5432, PublishedPort: 5432}, wantErr: types.ErrPublishedPortReserved},
+		{name: "Configured database port", request: types.AddApplicationPortRequest{ID: id, TargetPort: 5432, PublishedPort: 5433}, wantErr: types.ErrPublishedPortReserved},
+		{name: "Redis", request: types.AddApplicationPortRequest{ID: id, TargetPort: 6379, PublishedPort: 6379}, wantErr: types.ErrPublishedPortReserved},
+		{name: "Configured Redis port", request: types.AddApplicationPortRequest{ID: id, TargetPort: 6379, PublishedPort: 6380}, wantErr: types.ErrPublishedPortReserved},
+		{name: "Layer4 listener", request: types.AddApplicationPortRequest{ID: id, TargetPort: 8443, PublishedPort: 8443}, wantErr: types.ErrPublishedPortReserved},
+		{name: "Reserved tcp port is free over udp", request: types.AddApplicationPortRequest{ID: id, Protocol: shared_types.PortProtocolUDP, TargetPort: 22, PublishedPort: 22}},
+		{name: "SNI", request: types.AddApplicationPortRequest{ID: id, TargetPort: 8883, SNI: " MQTT.example.com "}},
+		{name: "SNI over udp", request: types.AddApplicationPortRequest{ID: id, Protocol: shared_types.PortProtocolUDP, TargetPort: 8883, SNI: "mqtt.example.com"}, wantErr: types.ErrSNIRequiresTCP},
+		{name: "SNI with published port", request: types.AddApplicationPortRequest{ID: id, TargetPort: 8883, PublishedPort: 8883, SNI: "mqtt.example.com"}, wantErr: types.ErrSNIWithPublishedPort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validateAddApplicationPortRequest(&tt.request))
+		})
+	}
+}
+
+func TestValidateUpdateProxySettingsRequest(t *testing.T) {
+	id := uuid.New()
+	settings := func(s shared_types.ProxySettings) types.UpdateProxySettingsRequest {
+		return types.UpdateProxySettingsRequest{ID: id, ProxySettings: s}
+	}
+	tooManyHeaders := make(map[string]string)
+	for i := 0; i < 51; i++ {
+		tooManyHeaders[fmt.Sprintf("X-Header-%d", i)] = "value"
+	}
+
+	tests := []struct {
+		name    string
+		request types.UpdateProxySettingsRequest
+		wantErr error
+	}{
+		{name: "Empty settings", request: settings(shared_types.ProxySettings{})},
+		{
+			name: "Every setting",
+			request: types.UpdateProxySettingsRequest{
+				ID: id,
+				ProxySettings: shared_types.ProxySettings{
+					ResponseHeaders:   map[string]string{"X-Powered-By": "nixopus"},
+					SecurityHeaders:   true,
+					FrameOptions:      "SAMEORIGIN",
+					HSTS:              true,
+					HSTSMaxAge:        600,
+					BasicAuthUsername: "admin",
+					MaxBodySize:       1024,
+					ConnectTimeout:    5,
+					ResponseTimeout:   3600,
+				},
+				BasicAuthPassword: "correct horse",
+			},
+		},
+		{name: "Missing id", request: types.UpdateProxySettingsRequest{}, wantErr: types.ErrMissingID},
+		{name: "Too many headers", request: settings(shared_types.ProxySettings{ResponseHeaders: tooManyHeaders}), wantErr: types.ErrTooManyProxyHeaders},
+		{name: "Header name with a space", request: settings(shared_types.ProxySettings{ResponseHeaders: map[string]string{"X Powered": "a"}}), wantErr: types.ErrInvalidProxyHeader},
+		{name: "Header value with a newline", request: settings(shared_types.ProxySettings{ResponseHeaders: map[string]string{"X-A": "a\r\nSet-Cookie: b"}}), wantErr: types.ErrInvalidProxyHeader},
+		{name: "Unknown frame options", request: settings(shared_types.ProxySettings{FrameOptions: "ALLOW-FROM example.com"}), wantErr: types.ErrInvalidFrameOptions},
+		{name: "Negative HSTS max age", request: settings(shared_types.ProxySettings{HSTSMaxAge: -1}), wantErr: types.ErrInvalidHSTSMaxAge},
+		{name: "Username with a colon", request: settings(shared_types.ProxySettings{BasicAuthUsername: "ad:min"}), wantErr: types.ErrInvalidBasicAuthUsername},
+		{name: "Short password", request: types.UpdateProxySettingsRequest{ID: id, BasicAuthPassword: "short"}, wantErr: types.ErrBasicAuthPasswordTooShort},
+		{name: "Negative body size", request: settings(shared_types.ProxySettings{MaxBodySize: -1}), wantErr: types.ErrInvalidMaxBodySize},
+		{name: "Negative timeout", request: settings(shared_types.ProxySettings{ConnectTimeout: -1}), wantErr: types.ErrInvalidProxyTimeout},
+		{name: "Timeout over an hour", request: settings(shared_types.ProxySettings{ResponseTimeout: 3601}), wantErr: types.ErrInvalidProxyTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validateUpdateProxySettingsRequest(&tt.request))
+		})
+	}
+}
+
+func TestValidateApplicationDomainRequests(t *testing.T) {
+	id := uuid.New()
+	domainID := uuid.New()
+
+	addTests := []struct {
+		name    string
+		request types.AddApplicationDomainRequest
+		wantErr error
+	}{
+		{name: "Domain", request: types.AddApplicationDomainRequest{ID: id, DomainID: domainID}},
+		{name: "Subdomain", request: types.AddApplicationDomainRequest{ID: id, DomainID: domainID, Subdomain: "api.eu-1"}},
+		{name: "Redirect", request: types.AddApplicationDomainRequest{ID: id, DomainID: domainID, Mode: shared_types.DomainModeRedirect}},
+		{name: "Missing id", request: types.AddApplicationDomainRequest{DomainID: domainID}, wantErr: types.ErrMissingID},
+		{name: "Missing domain", request: types.AddApplicationDomainRequest{ID: id}, wantErr: types.ErrMissingDomainID},
+		{name: "Uppercase subdomain", request: types.AddApplicationDomainRequest{ID: id, DomainID: domainID, Subdomain: "API"}, wantErr: types.ErrInvalidSubdomain},
+		{name: "Subdomain with a trailing dot", request: types.AddApplicationDomainRequest{ID: id, DomainID: domainID, Subdomain: "api."}, wantErr: types.ErrInvalidSubdomain},
+		{name: "Subdomain starting with a hyphen", request: types.AddApplicationDomainRequest{ID: id, DomainID: domainID, Subdomain: "-api"}, wantErr: types.ErrInvalidSubdomain},
+		{name: "Unknown mode", request: types.AddApplicationDomainRequest{ID: id, DomainID: domainID, Mode: "proxy"}, wantErr: types.ErrInvalidDomainMode},
+		{name: "Primary redirect", request: types.AddApplicationDomainRequest{ID: id, DomainID: domainID, Mode: shared_types.DomainModeRedirect, IsPrimary: true}, wantErr: types.ErrPrimaryDomainMustServe},
+	}
+	for _, tt := range addTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validateAddApplicationDomainRequest(tt.request))
+		})
+	}
+
+	assert.Equal(t, types.ErrMissingApplicationDomainID, validateUpdateApplicationDomainRequest(types.UpdateApplicationDomainRequest{ID: id}))
+	assert.Equal(t, types.ErrPrimaryDomainMustServe, validateUpdateApplicationDomainRequest(types.UpdateApplicationDomainRequest{
+		ID: id, ApplicationDomainID: domainID, Mode: shared_types.DomainModeRedirect, IsPrimary: true,
+	}))
+	assert.Equal(t, types.ErrMissingApplicationDomainID, validateRemoveApplicationDomainRequest(types.RemoveApplicationDomainRequest{ID: id}))
+	assert.NoError(t, validateRemoveApplicationDomainRequest(types.RemoveApplicationDomainRequest{ID: id, ApplicationDomainID: domainID}))
+}
+
+func TestValidatePathRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		priority int
+		want     string
+		wantErr  error
+	}{
+		{name: "Whole domain", prefix: "", want: ""},
+		{name: "Root path", prefix: "/", want: ""},
+		{name: "Prefix", prefix: "/api", want: "/api"},
+		{name: "Nested prefix with trailing slash", prefix: " /api/v1/ ", want: "/api/v1"},
+		{name: "Priority", prefix: "/api", priority: -1000, want: "/api"},
+		{name: "Relative path", prefix: "api", wantErr: types.ErrInvalidPathPrefix},
+		{name: "Wildcard", prefix: "/api/*", wantErr: types.ErrInvalidPathPrefix},
+		{name: "Empty segment", prefix: "/api//v1", wantErr: types.ErrInvalidPathPrefix},
+		{name: "Query", prefix: "/api?v=1", wantErr: types.ErrInvalidPathPrefix},
+		{name: "Priority out of range", prefix: "/api", priority: 1001, wantErr: types.ErrInvalidRoutePriority},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := tt.prefix
+			err := validatePathRoute(&prefix, tt.priority)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, prefix)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateMaintenanceRequest(t *testing.T) {
+	id := uuid.New()
+	maintenance := func(s shared_types.MaintenanceSettings) types.UpdateMaintenanceRequest {
+		return types.UpdateMaintenanceRequest{ID: id, MaintenanceSettings: s}
+	}
+	tooManyIPs := make([]string, 101)
+	for i := range tooManyIPs {
+		tooManyIPs[i] = "10.0.0.1"
+	}
+
+	tests := []struct {
+		name    string
+		request types.UpdateMaintenanceRequest
+		wantIPs []string
+		wantErr error
+	}{
+		{name: "Off", request: maintenance(shared_types.MaintenanceSettings{})},
+		{name: "Page", request: maintenance(shared_types.MaintenanceSettings{Enabled: true, Page: "<h1>Back soon</h1>"})},
+		{name: "Redirect", request: maintenance(shared_types.MaintenanceSettings{Enabled: true, RedirectURL: "https://status.example.com"})},
+		{
+			name:    "Bypass addresses are trimmed",
+			request: maintenance(shared_types.MaintenanceSettings{Enabled: true, BypassIPs: []string{" 203.0.113.7", "10.0.0.0/8 ", "2001:db8::/32"}}),
+			wantIPs: []string{"203.0.113.7", "10.0.0.0/8", "2001:db8::/32"},
+		},
+		{name: "Bypass token", request: maintenance(shared_types.MaintenanceSettings{Enabled: true, BypassToken: "let-me-in-please"})},
+		{name: "Missing id", request: types.UpdateMaintenanceRequest{}, wantErr: types.ErrMissingID},
+		{name: "Redirect without scheme", request: maintenance(shared_types.MaintenanceSettings{RedirectURL: "status.example.com"}), wantErr: types.ErrInvalidMaintenanceRedirect},
+		{name: "Redirect to javascript", request: maintenance(shared_types.MaintenanceSettings{RedirectURL: "javascript:alert(1)"}), wantErr: types.ErrInvalidMaintenanceRedirect},
+		{name: "Redirect and page", request: maintenance(shared_types.MaintenanceSettings{RedirectURL: "https://status.example.com", Page: "<h1>Back soon</h1>"}), wantErr: types.ErrMaintenancePageAndRedirect},
+		{name: "Page over 64 KiB", request: maintenance(shared_types.MaintenanceSettings{Page: strings.Repeat("a", 64*1024+1)}), wantErr: types.ErrMaintenancePageTooLarge},
+		{name: "Invalid bypass address", request: maintenance(shared_types.MaintenanceSettings{BypassIPs: []string{"10.0.0.300"}}), wantErr: types.ErrInvalidBypassIP},
+		{name: "Bypass address with a directive", request: maintenance(shared_types.MaintenanceSettings{BypassIPs: []string{"10.0.0.1 0;\n}"}}), wantErr: types.ErrInvalidBypassIP},
+		{name: "Too many bypass addresses", request: maintenance(shared_types.MaintenanceSettings{BypassIPs: tooManyIPs}), wantErr: types.ErrInvalidBypassIP},
+		{name: "Short bypass token", request: maintenance(shared_types.MaintenanceSettings{BypassToken: "short"}), wantErr: types.ErrInvalidBypassToken},
+		{name: "Bypass token with a quote", request: maintenance(shared_types.MaintenanceSettings{BypassToken: `let-me-in-please"`}), wantErr: types.ErrInvalidBypassToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validateUpdateMaintenanceRequest(&tt.request))
+			if tt.wantIPs != nil {
+				assert.Equal(t, tt.wantIPs, tt.request.BypassIPs)
+			}
+		})
+	}
+}
+
+func TestValidateDeploymentDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		wantErr error
+	}{
+		{name: "Hostname", domain: "app.example.com"},
+		{name: "Upper case", domain: "App.Example.com"},
+		{name: "Directive", domain: "example.com; location /x { alias /etc/; }", wantErr: types.ErrInvalidDomain},
+		{name: "Block", domain: "example.com{", wantErr: types.ErrInvalidDomain},
+		{name: "Newline", domain: "example.com\nroot /", wantErr: types.ErrInvalidDomain},
+		{name: "Space", domain: "example.com evil.com", wantErr: types.ErrInvalidDomain},
+		{name: "Scheme", domain: "https://example.com", wantErr: types.ErrInvalidDomain},
+		{name: "Wildcard", domain: "*.example.com", wantErr: types.ErrInvalidDomain},
+		{name: "Too long", domain: strings.Repeat("a.", 127) + "com", wantErr: types.ErrInvalidDomain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := types.CreateDeploymentRequest{
+				Name:        "shop",
+				Domain:      tt.domain,
+				Environment: shared_types.Production,
+				BuildPack:   shared_types.DockerFile,
+				Repository:  "123",
+				Branch:      "main",
+				Port:        3000,
+			}
+			assert.Equal(t, tt.wantErr, validateDeploymentRequest(&req))
+		})
+	}
+}
+
+func TestValidateScaleToZero(t *testing.T) {
+	previous := config.AppConfig.Proxy.AccessLogPort
+	defer func() { config.AppConfig.Proxy.AccessLogPort = previous }()
+
+	enabled, disabled := true, false
+	tests := []struct {
+		name          string
+		accessLogPort string
+		buildPack     shared_types.BuildPack
+		proxyServer   shared_types.ProxyServer
+		wantErr       error
+	}{
+		{name: "Access logs on", accessLogPort: "9400", buildPack: shared_types.DockerFile},
+		{name: "Access logs off", buildPack: shared_types.DockerFile, wantErr: types.ErrScaleToZeroNeedsAccessLogs},
+		{name: "Nginx", accessLogPort: "9400", buildPack: shared_types.DockerFile, proxyServer: shared_types.Nginx, wantErr: types.ErrScaleToZeroNeedsCaddy},
+		{name: "Static site", accessLogPort: "9400", buildPack: shared_types.Static, wantErr: types.ErrStaticSiteCannotBeStopped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig.Proxy.AccessLogPort = tt.accessLogPort
+			req := types.CreateDeploymentRequest{
+				Name:        "shop",
+				Domain:      "shop.example.com",
+				Environment: shared_types.Production,
+				BuildPack:   tt.buildPack,
+				Repository:  "123",
+				Branch:      "main",
+				Port:        3000,
+				ProxyServer: tt.proxyServer,
+				ScaleToZero: true,
+			}
+			assert.Equal(t, tt.wantErr, validateDeploymentRequest(&req))
+		})
+	}
+
+	t.Run("Update with access logs off", func(t *testing.T) {
+		config.AppConfig.Proxy.AccessLogPort = ""
+		assert.Equal(t, types.ErrScaleToZeroNeedsAccessLogs, validateUpdateDeploymentRequest(&types.UpdateDeploymentRequest{ScaleToZero: &enabled}))
+		assert.NoError(t, validateUpdateDeploymentRequest(&types.UpdateDeploymentRequest{ScaleToZero: &disabled}))
+		assert.NoError(t, validateUpdateDeploymentRequest(&types.UpdateDeploymentRequest{}))
+	})
+
+	t.Run("Update with access logs on", func(t *testing.T) {
+		config.AppConfig.Proxy.AccessLogPort = "9400"
+		assert.NoError(t, validateUpdateDeploymentRequest(&types.UpdateDeploymentRequest{ScaleToZero: &enabled}))
+	})
+}
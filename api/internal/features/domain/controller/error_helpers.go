@@ -1,6 +1,10 @@
 package controller
 
-import "github.com/raghavyuva/nixopus-api/internal/features/domain/types"
+import (
+	"net/http"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/domain/types"
+)
 
 func isInvalidDomainError(err error) bool {
 	switch err {
@@ -25,3 +29,14 @@ func isPermissionError(err error) bool {
 		return false
 	}
 }
+
+func verificationErrorStatus(err error) int {
+	switch err {
+	case types.ErrDomainNotFound:
+		return http.StatusNotFound
+	case types.ErrDomainVerifiedByAnotherOrganization:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
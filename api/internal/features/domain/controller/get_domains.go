@@ -114,6 +114,14 @@ func (c *DomainsController) GenerateRandomSubDomain(f fuego.ContextNoBody) (*sha
 		}
 	}
 
+	verified := domains[:0]
+	for _, domain := range domains {
+		if domain.IsVerified() {
+			verified = append(verified, domain)
+		}
+	}
+	domains = verified
+
 	if len(domains) == 0 {
 		c.logger.Log(logger.Error, "no domains available for subdomain generation", "")
 		return nil, fuego.HTTPError{
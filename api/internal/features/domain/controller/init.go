@@ -29,10 +29,13 @@ func NewDomainsController(
 	notificationManager *notification.NotificationManager,
 ) *DomainsController {
 	storage := storage.DomainStorage{DB: store.DB, Ctx: ctx}
+	domainsService := service.NewDomainsService(store, ctx, l, &storage)
+	domainsService.StartVerificationChecks(ctx)
+
 	return &DomainsController{
 		store:        store,
 		validator:    validation.NewValidator(&storage),
-		service:      service.NewDomainsService(store, ctx, l, &storage),
+		service:      domainsService,
 		ctx:          ctx,
 		logger:       l,
 		notification: notificationManager,
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/domain/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// VerifyDomain checks the verification TXT record of a domain right away.
+func (c *DomainsController) VerifyDomain(f fuego.ContextWithBody[types.VerifyDomainRequest]) (*shared_types.Response, error) {
+	domainRequest, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	w, r := f.Response(), f.Request()
+	if !c.parseAndValidate(w, r, &domainRequest) {
+		return nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(r)
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    types.ErrMissingID,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	status, err := c.service.VerifyDomain(domainRequest.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, err.Error(), "")
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: verificationErrorStatus(err),
+		}
+	}
+
+	message := "Domain verified successfully"
+	if !status.Verified {
+		message = types.ErrVerificationRecordNotFound.Error()
+	}
+	return &shared_types.Response{
+		Status:  "success",
+		Message: message,
+		Data:    status,
+	}, nil
+}
+
+// GetDomainStatus shows the A, AAAA, CNAME and verification TXT records of a domain as seen by
+// the resolver of the server and public resolvers.
+func (c *DomainsController) GetDomainStatus(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	r := f.Request()
+
+	domainRequest := types.VerifyDomainRequest{ID: f.QueryParam("id")}
+	if err := c.validator.ValidateRequest(&domainRequest); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(r)
+	if organizationID == uuid.Nil {
+		return nil, fuego.HTTPError{
+			Err:    types.ErrMissingID,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	status, err := c.service.GetDomainStatus(domainRequest.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, err.Error(), "")
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: verificationErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Domain status fetched successfully",
+		Data:    status,
+	}, nil
+}
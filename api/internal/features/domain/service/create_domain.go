@@ -16,7 +16,7 @@ import (
 // It takes a CreateDomainRequest, which contains the domain name, and a user ID.
 // The user ID is used to associate the domain with a user.
 //
-// It returns a CreateDomainResponse, which is always empty, and an error.
+// It returns a CreateDomainResponse, holding the ID of the domain and the TXT record verifying it, and an error.
 // The error is either ErrDomainAlreadyExists, or any error that occurred
 // while creating the domain in the storage layer.
 func (s *DomainsService) CreateDomain(req types.CreateDomainRequest, userID string) (types.CreateDomainResponse, error) {
@@ -73,14 +73,21 @@ func (s *DomainsService) CreateDomain(req types.CreateDomainRequest, userID stri
 		return types.CreateDomainResponse{}, types.ErrDomainAlreadyExists
 	}
 
+	token, err := newVerificationToken()
+	if err != nil {
+		s.logger.Log(logger.Error, "failed to generate verification token", err.Error())
+		return types.CreateDomainResponse{}, types.ErrFailedToCreateDomain
+	}
+
 	domain := &shared_types.Domain{
-		ID:             uuid.New(),
-		UserID:         uuid.MustParse(userID),
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-		DeletedAt:      nil,
-		Name:           req.Name,
-		OrganizationID: req.OrganizationID,
+		ID:                uuid.New(),
+		UserID:            uuid.MustParse(userID),
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+		DeletedAt:         nil,
+		Name:              req.Name,
+		OrganizationID:    req.OrganizationID,
+		VerificationToken: token,
 	}
 
 	if err := txStorage.CreateDomain(domain); err != nil {
@@ -93,5 +100,5 @@ func (s *DomainsService) CreateDomain(req types.CreateDomainRequest, userID stri
 		return types.CreateDomainResponse{}, types.ErrFailedToCreateDomain
 	}
 
-	return types.CreateDomainResponse{ID: domain.ID.String()}, nil
+	return types.CreateDomainResponse{ID: domain.ID.String(), Record: verificationRecord(*domain)}, nil
 }
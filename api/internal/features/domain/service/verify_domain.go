@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/domain/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/domain/validation"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	// verificationCheckInterval is how often unverified domains are checked in the background.
+	verificationCheckInterval = 5 * time.Minute
+	// verificationCheckWindow is how long after being added a domain is checked in the background,
+	// later checks have to be triggered.
+	verificationCheckWindow = 7 * 24 * time.Hour
+	dnsLookupTimeout        = 10 * time.Second
+)
+
+// publicResolvers are asked besides the resolver of the server, to show whether records have
+// propagated beyond it.
+var publicResolvers = []string{"1.1.1.1:53", "8.8.8.8:53", "9.9.9.9:53"}
+
+func newVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// verificationRecord returns the TXT record that verifies domain. Wildcard domains are verified
+// on the domain they are a wildcard of.
+func verificationRecord(domain shared_types.Domain) types.VerificationRecord {
+	return types.VerificationRecord{
+		Type:  "TXT",
+		Name:  types.VerificationRecordPrefix + strings.TrimPrefix(domain.Name, "*."),
+		Value: types.VerificationValuePrefix + domain.VerificationToken,
+	}
+}
+
+// GetDomainStatus looks up the DNS records of a domain of the organization without changing
+// its verification state.
+func (s *DomainsService) GetDomainStatus(domainID string, organizationID uuid.UUID) (types.DomainStatus, error) {
+	domain, err := s.organizationDomain(domainID, organizationID)
+	if err != nil {
+		return types.DomainStatus{}, err
+	}
+	return s.checkDNS(*domain), nil
+}
+
+// VerifyDomain checks the verification record of a domain of the organization and marks the
+// domain verified once any resolver returns it. The returned status tells whether it was found.
+func (s *DomainsService) VerifyDomain(domainID string, organizationID uuid.UUID) (types.DomainStatus, error) {
+	domain, err := s.organizationDomain(domainID, organizationID)
+	if err != nil {
+		return types.DomainStatus{}, err
+	}
+	return s.verify(*domain)
+}
+
+func (s *DomainsService) organizationDomain(domainID string, organizationID uuid.UUID) (*shared_types.Domain, error) {
+	domain, err := s.storage.GetDomain(domainID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.OrganizationID != organizationID {
+		return nil, types.ErrDomainNotFound
+	}
+	return domain, nil
+}
+
+func (s *DomainsService) verify(domain shared_types.Domain) (types.DomainStatus, error) {
+	status := s.checkDNS(domain)
+	if status.Verified {
+		return status, nil
+	}
+
+	found := false
+	for _, result := range status.Resolvers {
+		found = found || result.TXTFound
+	}
+	if found {
+		taken, err := s.storage.IsDomainVerifiedElsewhere(domain.Name, domain.OrganizationID)
+		if err != nil {
+			return status, err
+		}
+		if taken {
+			return status, types.ErrDomainVerifiedByAnotherOrganization
+		}
+	}
+
+	now := time.Now()
+	if err := s.storage.SetDomainChecked(domain.ID.String(), now, found); err != nil {
+		return status, err
+	}
+	status.LastCheckedAt = &now
+	if found {
+		status.Verified = true
+		status.VerifiedAt = &now
+		s.logger.Log(logger.Info, "domain verified", fmt.Sprintf("domain_name=%s, organization_id=%s", domain.Name, domain.OrganizationID))
+	}
+	return status, nil
+}
+
+// StartVerificationChecks checks the verification records of recently added domains in the
+// background until ctx is done, so that domains get verified once their records propagated.
+func (s *DomainsService) StartVerificationChecks(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(verificationCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkUnverifiedDomains()
+			}
+		}
+	}()
+}
+
+func (s *DomainsService) checkUnverifiedDomains() {
+	domains, err := s.storage.GetUnverifiedDomains(time.Now().Add(-verificationCheckWindow))
+	if err != nil {
+		s.logger.Log(logger.Error, "failed to get unverified domains", err.Error())
+		return
+	}
+
+	for _, domain := range domains {
+		if domain.LastCheckedAt != nil && time.Since(*domain.LastCheckedAt) < verificationCheckInterval {
+			continue
+		}
+		if _, err := s.verify(domain); err != nil {
+			s.logger.Log(logger.Error, "failed to verify domain", fmt.Sprintf("domain_name=%s, error=%s", domain.Name, err.Error()))
+		}
+	}
+}
+
+// checkDNS asks the resolver of the server and the public resolvers for the records of domain.
+func (s *DomainsService) checkDNS(domain shared_types.Domain) types.DomainStatus {
+	status := types.DomainStatus{
+		ID:            domain.ID.String(),
+		Name:          domain.Name,
+		Verified:      domain.IsVerified(),
+		VerifiedAt:    domain.VerifiedAt,
+		LastCheckedAt: domain.LastCheckedAt,
+		Record:        verificationRecord(domain),
+		ServerIPs:     []string{},
+	}
+
+	serverIPs, err := validation.ServerIPs()
+	if err != nil {
+		s.logger.Log(logger.Error, "failed to resolve server addresses", err.Error())
+	}
+	for _, ip := range serverIPs {
+		status.ServerIPs = append(status.ServerIPs, ip.String())
+	}
+
+	resolvers := append([]string{"system"}, publicResolvers...)
+	status.Resolvers = make([]types.ResolverResult, len(resolvers))
+
+	var wg sync.WaitGroup
+	for i, name := range resolvers {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			resolver := net.DefaultResolver
+			if name != "system" {
+				resolver = publicResolver(name)
+			}
+			status.Resolvers[i] = lookupRecords(resolver, name, domain, status.Record, serverIPs)
+		}(i, name)
+	}
+	wg.Wait()
+
+	return status
+}
+
+func publicResolver(address string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: dnsLookupTimeout}
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// lookupRecords looks up the A, AAAA and CNAME records of the domain and the TXT records at the
+// name of its verification record. Missing records are not reported as errors.
+func lookupRecords(resolver *net.Resolver, name string, domain shared_types.Domain, record types.VerificationRecord, serverIPs []net.IP) types.ResolverResult {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsLookupTimeout)
+	defer cancel()
+
+	host := strings.TrimPrefix(domain.Name, "*.")
+	result := types.ResolverResult{Resolver: name, A: []string{}, AAAA: []string{}, TXT: []string{}}
+	var lookupErrors []string
+	collect := func(err error) {
+		var dnsErr *net.DNSError
+		if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+			lookupErrors = append(lookupErrors, err.Error())
+		}
+	}
+
+	for _, network := range []string{"ip4", "ip6"} {
+		ips, err := resolver.LookupIP(ctx, network, host)
+		collect(err)
+		for _, ip := range ips {
+			if network == "ip4" {
+				result.A = append(result.A, ip.String())
+			} else {
+				result.AAAA = append(result.AAAA, ip.String())
+			}
+			for _, serverIP := range serverIPs {
+				result.PointsToServer = result.PointsToServer || ip.Equal(serverIP)
+			}
+		}
+	}
+
+	cname, err := resolver.LookupCNAME(ctx, host)
+	collect(err)
+	if cname = strings.TrimSuffix(cname, "."); cname != "" && !strings.EqualFold(cname, host) {
+		result.CNAME = cname
+	}
+
+	txt, err := resolver.LookupTXT(ctx, record.Name)
+	collect(err)
+	for _, value := range txt {
+		result.TXT = append(result.TXT, value)
+		result.TXTFound = result.TXTFound || strings.TrimSpace(value) == record.Value
+	}
+
+	result.Error = strings.Join(lookupErrors, "; ")
+	return result
+}
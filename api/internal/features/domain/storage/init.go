@@ -27,6 +27,9 @@ type DomainStorageInterface interface {
 	GetDomainByName(name string, organizationID uuid.UUID) (*shared_types.Domain, error)
 	IsDomainExists(ID string) (bool, error)
 	GetDomainOwnerByID(ID string) (string, error)
+	SetDomainChecked(ID string, checkedAt time.Time, verified bool) error
+	IsDomainVerifiedElsewhere(name string, organizationID uuid.UUID) (bool, error)
+	GetUnverifiedDomains(createdAfter time.Time) ([]shared_types.Domain, error)
 	BeginTx() (bun.Tx, error)
 	WithTx(tx bun.Tx) DomainStorageInterface
 }
@@ -76,6 +79,11 @@ func (s *DomainStorage) UpdateDomain(ID string, Name string) error {
 	if err != nil {
 		return err
 	}
+	if domain.Name != Name {
+		// the verification record of the old name says nothing about the new one
+		domain.VerifiedAt = nil
+		domain.LastCheckedAt = nil
+	}
 	domain.Name = Name
 	domain.UpdatedAt = time.Now()
 	_, err = s.getDB().NewUpdate().Model(&domain).Where("id = ? AND deleted_at IS NULL", ID).Exec(s.Ctx)
@@ -153,3 +161,38 @@ func (s *DomainStorage) GetDomainOwnerByID(ID string) (string, error) {
 	}
 	return domain.UserID.String(), nil
 }
+
+// SetDomainChecked records a DNS check of a domain and, when the verification record was found,
+// marks the domain verified. A domain that is already verified keeps its verification time.
+func (s *DomainStorage) SetDomainChecked(ID string, checkedAt time.Time, verified bool) error {
+	query := s.getDB().NewUpdate().
+		Model((*shared_types.Domain)(nil)).
+		Set("last_checked_at = ?", checkedAt).
+		Where("id = ? AND deleted_at IS NULL", ID)
+	if verified {
+		query = query.Set("verified_at = COALESCE(verified_at, ?)", checkedAt)
+	}
+	_, err := query.Exec(s.Ctx)
+	return err
+}
+
+// IsDomainVerifiedElsewhere reports whether another organization verified a domain with name.
+func (s *DomainStorage) IsDomainVerifiedElsewhere(name string, organizationID uuid.UUID) (bool, error) {
+	return s.getDB().NewSelect().
+		Model((*shared_types.Domain)(nil)).
+		Where("LOWER(name) = LOWER(?)", name).
+		Where("organization_id <> ?", organizationID).
+		Where("verified_at IS NOT NULL AND deleted_at IS NULL").
+		Exists(s.Ctx)
+}
+
+// GetUnverifiedDomains returns the unverified domains added after createdAfter.
+func (s *DomainStorage) GetUnverifiedDomains(createdAfter time.Time) ([]shared_types.Domain, error) {
+	var domains []shared_types.Domain
+	err := s.getDB().NewSelect().
+		Model(&domains).
+		Where("verified_at IS NULL AND deleted_at IS NULL").
+		Where("created_at > ?", createdAfter).
+		Scan(s.Ctx)
+	return domains, err
+}
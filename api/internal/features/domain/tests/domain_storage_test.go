@@ -213,4 +213,77 @@ func TestDomainStorage(t *testing.T) {
 		_, err = domainStorage.GetDomain(domain.ID.String())
 		assert.Error(t, err)
 	})
+
+	t.Run("SetDomainChecked", func(t *testing.T) {
+		domain := &shared_types.Domain{
+			ID:             uuid.New(),
+			Name:           "verify.com",
+			UserID:         testUser.ID,
+			OrganizationID: testOrg.ID,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		}
+
+		err := domainStorage.CreateDomain(domain)
+		assert.NoError(t, err)
+
+		err = domainStorage.SetDomainChecked(domain.ID.String(), time.Now(), false)
+		assert.NoError(t, err)
+
+		checked, err := domainStorage.GetDomain(domain.ID.String())
+		assert.NoError(t, err)
+		assert.NotNil(t, checked.LastCheckedAt)
+		assert.False(t, checked.IsVerified())
+
+		unverified, err := domainStorage.GetUnverifiedDomains(time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Contains(t, domainNames(unverified), "verify.com")
+
+		err = domainStorage.SetDomainChecked(domain.ID.String(), time.Now(), true)
+		assert.NoError(t, err)
+
+		verified, err := domainStorage.GetDomain(domain.ID.String())
+		assert.NoError(t, err)
+		assert.True(t, verified.IsVerified())
+
+		elsewhere, err := domainStorage.IsDomainVerifiedElsewhere("verify.com", uuid.New())
+		assert.NoError(t, err)
+		assert.True(t, elsewhere)
+
+		elsewhere, err = domainStorage.IsDomainVerifiedElsewhere("verify.com", testOrg.ID)
+		assert.NoError(t, err)
+		assert.False(t, elsewhere)
+	})
+
+	t.Run("UpdateDomainResetsVerification", func(t *testing.T) {
+		domain := &shared_types.Domain{
+			ID:             uuid.New(),
+			Name:           "rename.com",
+			UserID:         testUser.ID,
+			OrganizationID: testOrg.ID,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		}
+
+		err := domainStorage.CreateDomain(domain)
+		assert.NoError(t, err)
+
+		err = domainStorage.SetDomainChecked(domain.ID.String(), time.Now(), true)
+		assert.NoError(t, err)
+
+		err = domainStorage.UpdateDomain(domain.ID.String(), "renamed.com")
+		assert.NoError(t, err)
+
+		renamed, err := domainStorage.GetDomain(domain.ID.String())
+		assert.NoError(t, err)
+		assert.False(t, renamed.IsVerified())
+	})
+}
+
+func domainNames(domains []shared_types.Domain) []string {
+	names := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		names = append(names, domain.Name)
+	}
+	return names
 }
@@ -2,6 +2,7 @@ package types
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -30,8 +31,16 @@ var (
 	ErrFailedToDeleteDomain                    = errors.New("failed to delete domain")
 	ErrFailedToUpdateDomain                    = errors.New("failed to update domain")
 	ErrDomainDoesNotBelongToServer             = errors.New("domain does not belong to current server")
+	ErrDomainVerifiedByAnotherOrganization     = errors.New("domain is already verified by another organization")
+	ErrVerificationRecordNotFound              = errors.New("verification TXT record not found, DNS changes can take a while to propagate")
 )
 
+// VerificationRecordPrefix is prepended to a domain to get the name of its verification TXT record.
+const VerificationRecordPrefix = "_nixopus-challenge."
+
+// VerificationValuePrefix is prepended to the token of a domain in its verification TXT record.
+const VerificationValuePrefix = "nixopus-verification="
+
 type CreateDomainRequest struct {
 	Name           string    `json:"name"`
 	OrganizationID uuid.UUID `json:"organization_id"`
@@ -46,11 +55,49 @@ type DeleteDomainRequest struct {
 	ID string `json:"id"`
 }
 
-type CreateDomainResponse struct {
+type VerifyDomainRequest struct {
 	ID string `json:"id"`
 }
 
+type CreateDomainResponse struct {
+	ID     string             `json:"id"`
+	Record VerificationRecord `json:"record"`
+}
+
 type RandomSubdomainResponse struct {
 	Subdomain string `json:"subdomain"`
 	Domain    string `json:"domain"`
 }
+
+// VerificationRecord is the TXT record proving control over a domain.
+type VerificationRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ResolverResult holds the records of a domain as answered by one resolver.
+type ResolverResult struct {
+	Resolver string   `json:"resolver"`
+	A        []string `json:"a"`
+	AAAA     []string `json:"aaaa"`
+	CNAME    string   `json:"cname,omitempty"`
+	TXT      []string `json:"txt"`
+	// TXTFound reports whether the verification record was among the TXT records
+	TXTFound bool `json:"txt_found"`
+	// PointsToServer reports whether one of the A or AAAA records is an address of the server
+	PointsToServer bool   `json:"points_to_server"`
+	Error          string `json:"error,omitempty"`
+}
+
+// DomainStatus is the verification state of a domain and the propagation of its DNS records.
+type DomainStatus struct {
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	Verified      bool               `json:"verified"`
+	VerifiedAt    *time.Time         `json:"verified_at,omitempty"`
+	LastCheckedAt *time.Time         `json:"last_checked_at,omitempty"`
+	Record        VerificationRecord `json:"record"`
+	ServerIPs     []string           `json:"server_ips"`
+	Resolvers     []ResolverResult   `json:"resolvers"`
+}
@@ -69,6 +69,8 @@ func (v *Validator) ValidateRequest(req interface{}) error {
 		return v.ValidateUpdateDomainRequest(*r)
 	case *types.DeleteDomainRequest:
 		return v.ValidateDeleteDomainRequest(*r)
+	case *types.VerifyDomainRequest:
+		return v.ValidateID(r.ID)
 	default:
 		return types.ErrInvalidRequestType
 	}
@@ -119,15 +121,6 @@ func (v *Validator) ValidateDomainBelongsToServer(domainName string) error {
 		return nil
 	}
 
-	serverHost := config.AppConfig.SSH.Host
-	if serverHost == "" {
-		var err error
-		serverHost, err = os.Hostname()
-		if err != nil {
-			return types.ErrDomainDoesNotBelongToServer
-		}
-	}
-
 	// Handle wildcard domains by extracting main domain
 	mainDomain := domainName
 	if strings.HasPrefix(domainName, "*.") {
@@ -139,7 +132,7 @@ func (v *Validator) ValidateDomainBelongsToServer(domainName string) error {
 		return types.ErrDomainDoesNotBelongToServer
 	}
 
-	serverIPs, err := net.LookupIP(serverHost)
+	serverIPs, err := ServerIPs()
 	if err != nil {
 		return types.ErrDomainDoesNotBelongToServer
 	}
@@ -154,3 +147,16 @@ func (v *Validator) ValidateDomainBelongsToServer(domainName string) error {
 
 	return types.ErrDomainDoesNotBelongToServer
 }
+
+// ServerIPs resolves the addresses of the server applications are deployed to.
+func ServerIPs() ([]net.IP, error) {
+	serverHost := config.AppConfig.SSH.Host
+	if serverHost == "" {
+		var err error
+		serverHost, err = os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return net.LookupIP(serverHost)
+}
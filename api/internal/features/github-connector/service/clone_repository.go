@@ -15,6 +15,10 @@ type CloneRepositoryConfig struct {
 	DeploymentType string
 	Branch         string
 	ApplicationID  string
+	Submodules     bool
+	LFS            bool
+	CloneDepth     int
+	CloneFilter    string
 }
 
 // CloneRepository clones the specified repository for the given user and environment.
@@ -80,6 +84,15 @@ func (s *GithubConnectorService) CloneRepository(c CloneRepositoryConfig, commit
 		return "", err
 	}
 
+	opts := CloneOptions{
+		Branch:      c.Branch,
+		Depth:       c.CloneDepth,
+		Filter:      c.CloneFilter,
+		Submodules:  c.Submodules,
+		LFS:         c.LFS,
+		AccessToken: accessToken,
+	}
+
 	clonePath, should_pull, err := s.GetClonePath(c.UserID, c.Environment, c.ApplicationID)
 
 	s.logger.Log(logger.Info, fmt.Sprintf("Clone path: %s", clonePath), "")
@@ -91,7 +104,14 @@ func (s *GithubConnectorService) CloneRepository(c CloneRepositoryConfig, commit
 
 	if c.DeploymentType == shared_types.DeploymentTypeRollback {
 		s.logger.Log(logger.Info, "Rolling back repository", c.UserID)
-		err = s.gitClient.SetHeadToCommitHash(authenticatedURL, clonePath, latestCommitHash)
+		if !should_pull {
+			// the clone was removed since the deployment, start from a fresh one
+			if err := s.gitClient.Clone(authenticatedURL, clonePath, opts); err != nil {
+				s.logger.Log(logger.Error, fmt.Sprintf("Failed to clone repository: %s", err.Error()), "")
+				return "", err
+			}
+		}
+		err = s.gitClient.SetHeadToCommitHash(authenticatedURL, clonePath, latestCommitHash, opts)
 		if err != nil {
 			s.logger.Log(logger.Error, fmt.Sprintf("Failed to rollback repository: %s", err.Error()), "")
 			return "", err
@@ -99,13 +119,13 @@ func (s *GithubConnectorService) CloneRepository(c CloneRepositoryConfig, commit
 	} else {
 		if !should_pull {
 			s.logger.Log(logger.Info, "Cloning repository", c.UserID)
-			err = s.gitClient.Clone(authenticatedURL, clonePath)
+			err = s.gitClient.Clone(authenticatedURL, clonePath, opts)
 			if err != nil {
 				s.logger.Log(logger.Error, fmt.Sprintf("Failed to clone repository: %s", err.Error()), "")
 				return "", err
 			}
 		} else {
-			if err := s.handleGitPull(authenticatedURL, clonePath, c.UserID); err != nil {
+			if err := s.handleGitPull(authenticatedURL, clonePath, c.UserID, opts); err != nil {
 				return "", err
 			}
 		}
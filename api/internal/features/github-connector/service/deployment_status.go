@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DeploymentState is the state of a GitHub deployment status.
+type DeploymentState string
+
+const (
+	DeploymentStateQueued     DeploymentState = "queued"
+	DeploymentStateInProgress DeploymentState = "in_progress"
+	DeploymentStateSuccess    DeploymentState = "success"
+	DeploymentStateFailure    DeploymentState = "failure"
+	DeploymentStateError      DeploymentState = "error"
+	DeploymentStateInactive   DeploymentState = "inactive"
+)
+
+// maxDescriptionLength is the longest description GitHub accepts on deployments and statuses.
+const maxDescriptionLength = 140
+
+type CreateDeploymentConfig struct {
+	UserID string
+	RepoID uint64
+	// Ref is the commit hash or branch that is deployed
+	Ref         string
+	Environment string
+	Description string
+	Production  bool
+}
+
+type DeploymentStatusConfig struct {
+	UserID         string
+	RepoID         uint64
+	DeploymentID   int64
+	State          DeploymentState
+	EnvironmentURL string
+	Description    string
+}
+
+// CreateDeployment creates a GitHub deployment for a repository of the user's installation
+// and returns its ID. Status checks of the ref are not required, the deployment has already
+// been decided when it is reported.
+func (s *GithubConnectorService) CreateDeployment(c CreateDeploymentConfig) (int64, error) {
+	accessToken, repo, err := s.repositoryAccess(c.UserID, c.RepoID)
+	if err != nil {
+		return 0, err
+	}
+
+	body := map[string]interface{}{
+		"ref":                    c.Ref,
+		"environment":            c.Environment,
+		"description":            truncateDescription(c.Description),
+		"auto_merge":             false,
+		"required_contexts":      []string{},
+		"production_environment": c.Production,
+	}
+
+	var deployment struct {
+		ID int64 `json:"id"`
+	}
+	url := fmt.Sprintf("%s/repos/%s/deployments", githubAPIBaseURL, repo)
+	if err := githubPost(url, accessToken, body, &deployment); err != nil {
+		return 0, fmt.Errorf("failed to create GitHub deployment: %w", err)
+	}
+	return deployment.ID, nil
+}
+
+// CreateDeploymentStatus adds a status to a GitHub deployment. Previous deployments of the
+// same environment are marked inactive by GitHub once one succeeds.
+func (s *GithubConnectorService) CreateDeploymentStatus(c DeploymentStatusConfig) error {
+	accessToken, repo, err := s.repositoryAccess(c.UserID, c.RepoID)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{
+		"state":         c.State,
+		"description":   truncateDescription(c.Description),
+		"auto_inactive": true,
+	}
+	if c.EnvironmentURL != "" {
+		body["environment_url"] = c.EnvironmentURL
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/deployments/%d/statuses", githubAPIBaseURL, repo, c.DeploymentID)
+	if err := githubPost(url, accessToken, body, nil); err != nil {
+		return fmt.Errorf("failed to create GitHub deployment status: %w", err)
+	}
+	return nil
+}
+
+// repositoryAccess returns an installation token of the user's connector and the full name
+// of the repository.
+func (s *GithubConnectorService) repositoryAccess(userID string, repoID uint64) (string, string, error) {
+	connectors, err := s.storage.GetAllConnectors(userID)
+	if err != nil {
+		return "", "", err
+	}
+	if len(connectors) == 0 {
+		return "", "", fmt.Errorf("no connectors found for user")
+	}
+
+	jwt := GenerateJwt(&connectors[0])
+	if jwt == "" {
+		return "", "", fmt.Errorf("failed to generate app JWT")
+	}
+
+	accessToken, err := s.getInstallationToken(jwt, connectors[0].InstallationID)
+	if err != nil {
+		return "", "", err
+	}
+
+	var repo struct {
+		FullName string `json:"full_name"`
+	}
+	if err := githubGet(fmt.Sprintf("%s/repositories/%d", githubAPIBaseURL, repoID), accessToken, &repo); err != nil {
+		return "", "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	return accessToken, repo.FullName, nil
+}
+
+func githubGet(url string, accessToken string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	return githubDo(req, accessToken, http.StatusOK, out)
+}
+
+func githubPost(url string, accessToken string, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return githubDo(req, accessToken, http.StatusCreated, out)
+}
+
+func githubDo(req *http.Request, accessToken string, expected int, out interface{}) error {
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", accessToken))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", "nixopus")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API error: %s - %s", resp.Status, string(bodyBytes))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func truncateDescription(description string) string {
+	runes := []rune(description)
+	if len(runes) <= maxDescriptionLength {
+		return description
+	}
+	return string(runes[:maxDescriptionLength-3]) + "..."
+}
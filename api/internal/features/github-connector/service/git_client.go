@@ -14,10 +14,10 @@ import (
 
 // GitClient defines the interface for git operations
 type GitClient interface {
-	Clone(repoURL, destinationPath string) error
-	Pull(repoURL, destinationPath string) error
+	Clone(repoURL, destinationPath string, opts CloneOptions) error
+	Pull(repoURL, destinationPath string, opts CloneOptions) error
 	GetLatestCommitHash(repoURL string, accessToken string) (string, error)
-	SetHeadToCommitHash(repoURL, destinationPath, commitHash string) error
+	SetHeadToCommitHash(repoURL, destinationPath, commitHash string, opts CloneOptions) error
 	SwitchBranch(destinationPath, branch string) error
 	HasUncommittedChanges(destinationPath string) (bool, error)
 	Stash(destinationPath string) (string, error)
@@ -26,6 +26,22 @@ type GitClient interface {
 	RemoveRepository(repoPath string) error
 }
 
+// CloneOptions controls how much of a repository is fetched and what is fetched with it.
+type CloneOptions struct {
+	// Branch is cloned instead of the default branch when set
+	Branch string
+	// Depth limits the history to the last Depth commits, 0 fetches the full history
+	Depth int
+	// Filter is a partial clone filter such as blob:none
+	Filter string
+	// Submodules clones submodules recursively with the same credentials
+	Submodules bool
+	// LFS downloads Git LFS objects, otherwise only their pointer files are checked out
+	LFS bool
+	// AccessToken is used to authenticate submodules hosted on GitHub
+	AccessToken string
+}
+
 // DefaultGitClient is the default implementation of GitClient
 type DefaultGitClient struct {
 	logger logger.Logger
@@ -41,14 +57,14 @@ func NewDefaultGitClient(logger logger.Logger, ssh *ssh.SSH) *DefaultGitClient {
 }
 
 // Clone clones a git repository to the specified path
-func (g *DefaultGitClient) Clone(repoURL, destinationPath string) error {
+func (g *DefaultGitClient) Clone(repoURL, destinationPath string, opts CloneOptions) error {
 	client, err := g.ssh.Connect()
 	if err != nil {
 		return fmt.Errorf("failed to connect via SSH: %w", err)
 	}
 	defer client.Close()
 
-	cmd := fmt.Sprintf("git clone %s %s", repoURL, destinationPath)
+	cmd := BuildCloneCommand(repoURL, destinationPath, opts)
 	output, err := client.Run(cmd)
 	if err != nil {
 		return fmt.Errorf("git clone failed: %s, output: %s", err.Error(), output)
@@ -59,14 +75,14 @@ func (g *DefaultGitClient) Clone(repoURL, destinationPath string) error {
 }
 
 // Pull updates a git repository from remote
-func (g *DefaultGitClient) Pull(repoURL, destinationPath string) error {
+func (g *DefaultGitClient) Pull(repoURL, destinationPath string, opts CloneOptions) error {
 	client, err := g.ssh.Connect()
 	if err != nil {
 		return fmt.Errorf("failed to connect via SSH: %w", err)
 	}
 	defer client.Close()
 
-	cmd := fmt.Sprintf("cd %s && git pull %s", destinationPath, repoURL)
+	cmd := BuildPullCommand(repoURL, destinationPath, opts)
 	output, err := client.Run(cmd)
 	if err != nil {
 		return fmt.Errorf("git pull failed: %s, output: %s", err.Error(), output)
@@ -123,7 +139,7 @@ func (g *DefaultGitClient) GetLatestCommitHash(repoURL string, accessToken strin
 }
 
 // SetHeadToCommitHash sets the HEAD of the repository to a specific commit hash
-func (g *DefaultGitClient) SetHeadToCommitHash(repoURL, destinationPath, commitHash string) error {
+func (g *DefaultGitClient) SetHeadToCommitHash(repoURL, destinationPath, commitHash string, opts CloneOptions) error {
 	// Connect to SSH
 	client, err := g.ssh.Connect()
 	if err != nil {
@@ -131,7 +147,7 @@ func (g *DefaultGitClient) SetHeadToCommitHash(repoURL, destinationPath, commitH
 	}
 	defer client.Close()
 
-	cmd := fmt.Sprintf("cd %s && git checkout %s", destinationPath, commitHash)
+	cmd := BuildCheckoutCommand(repoURL, destinationPath, commitHash, opts)
 	output, err := client.Run(cmd)
 	if err != nil {
 		return fmt.Errorf("git checkout failed: %s, output: %s", err.Error(), output)
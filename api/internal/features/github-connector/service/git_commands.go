@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildCloneCommand returns the command cloning a repository with the given options.
+func BuildCloneCommand(repoURL, destinationPath string, opts CloneOptions) string {
+	args := []string{"clone"}
+	if opts.Branch != "" {
+		args = append(args, "--branch", shellQuote(opts.Branch))
+	}
+	if opts.Depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", opts.Depth))
+	}
+	if opts.Filter != "" {
+		args = append(args, "--filter="+shellQuote(opts.Filter))
+	}
+	if opts.Submodules {
+		args = append(args, "--recurse-submodules")
+		if opts.Depth > 0 {
+			args = append(args, "--shallow-submodules")
+		}
+	}
+	args = append(args, repoURL, destinationPath)
+
+	cmd := opts.git(args...)
+	if opts.LFS {
+		cmd += fmt.Sprintf(" && cd %s && %s", destinationPath, lfsPullCommand)
+	}
+	return cmd
+}
+
+// BuildPullCommand returns the command updating a clone to the latest commit of the remote.
+// Shallow clones fetch the last Depth commits and are reset to them, since merging into a
+// truncated history fails as soon as the local head falls outside of it.
+func BuildPullCommand(repoURL, destinationPath string, opts CloneOptions) string {
+	cmds := []string{"cd " + destinationPath, "git remote set-url origin " + repoURL}
+	if opts.Depth > 0 {
+		args := []string{"fetch", fmt.Sprintf("--depth=%d", opts.Depth)}
+		if opts.Filter != "" {
+			args = append(args, "--filter="+shellQuote(opts.Filter))
+		}
+		args = append(args, "origin")
+		if opts.Branch != "" {
+			args = append(args, shellQuote(opts.Branch))
+		}
+		cmds = append(cmds, opts.git(args...), opts.git("reset", "--hard", "FETCH_HEAD"))
+	} else {
+		cmds = append(cmds, opts.git("pull", repoURL))
+	}
+	return strings.Join(append(cmds, opts.syncCommands()...), " && ")
+}
+
+// BuildCheckoutCommand returns the command checking out a commit. When the commit is not in
+// the clone, as happens with shallow clones, just that commit is fetched.
+func BuildCheckoutCommand(repoURL, destinationPath, commitHash string, opts CloneOptions) string {
+	fetch := opts.git("fetch", "origin", shellQuote(commitHash))
+	if opts.Depth > 0 {
+		fetch = opts.git("fetch", "--depth=1", "origin", shellQuote(commitHash))
+	}
+	cmds := []string{
+		"cd " + destinationPath,
+		"git remote set-url origin " + repoURL,
+		fmt.Sprintf("{ git cat-file -e %s 2>/dev/null || %s; }", shellQuote(commitHash+"^{commit}"), fetch),
+		opts.git("checkout", shellQuote(commitHash)),
+	}
+	return strings.Join(append(cmds, opts.syncCommands()...), " && ")
+}
+
+const lfsPullCommand = "git lfs install --local && git lfs pull"
+
+// syncCommands brings submodules and LFS objects in line with the checked out commit.
+func (o CloneOptions) syncCommands() []string {
+	var cmds []string
+	if o.Submodules {
+		args := []string{"submodule", "update", "--init", "--recursive"}
+		if o.Depth > 0 {
+			args = append(args, "--depth=1")
+		}
+		cmds = append(cmds, o.git(args...))
+	}
+	if o.LFS {
+		cmds = append(cmds, lfsPullCommand)
+	}
+	return cmds
+}
+
+// git returns a git command. GitHub URLs are rewritten to carry the access token so that
+// submodules of the same installation can be cloned, the rewrite only applies to this command
+// and is not written to the repository config. Without LFS, pointer files are checked out
+// even if git-lfs is installed on the server.
+func (o CloneOptions) git(args ...string) string {
+	var b strings.Builder
+	if !o.LFS {
+		b.WriteString("GIT_LFS_SKIP_SMUDGE=1 ")
+	}
+	b.WriteString("git")
+	if o.Submodules && o.AccessToken != "" {
+		authenticated := fmt.Sprintf("https://oauth2:%s@github.com/", o.AccessToken)
+		for _, prefix := range []string{"https://github.com/", "git@github.com:"} {
+			b.WriteString(" -c " + shellQuote(fmt.Sprintf("url.%s.insteadOf=%s", authenticated, prefix)))
+		}
+	}
+	for _, arg := range args {
+		b.WriteString(" " + arg)
+	}
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
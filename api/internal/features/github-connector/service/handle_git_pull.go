@@ -6,7 +6,7 @@ import (
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
 )
 
-func (s *GithubConnectorService) handleGitPull(authenticatedURL, clonePath string, userID string) error {
+func (s *GithubConnectorService) handleGitPull(authenticatedURL, clonePath string, userID string, opts CloneOptions) error {
 	hasChanges, err := s.gitClient.HasUncommittedChanges(clonePath)
 	if err != nil {
 		s.logger.Log(logger.Error, fmt.Sprintf("Failed to check for uncommitted changes: %s", err.Error()), userID)
@@ -22,7 +22,7 @@ func (s *GithubConnectorService) handleGitPull(authenticatedURL, clonePath strin
 	}
 
 	s.logger.Log(logger.Info, "Pulling latest changes", userID)
-	if err := s.gitClient.Pull(authenticatedURL, clonePath); err != nil {
+	if err := s.gitClient.Pull(authenticatedURL, clonePath, opts); err != nil {
 		s.logger.Log(logger.Error, fmt.Sprintf("Failed to pull repository: %s", err.Error()), userID)
 		return err
 	}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/github-connector/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeploymentStatusServer(t *testing.T, deployments *[]map[string]interface{}, statuses *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/app/installations/67890/access_tokens" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]string{"token": "test-access-token"})
+		case r.URL.Path == "/repositories/42" && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 42, "full_name": "test-user/test-repo"})
+		case r.URL.Path == "/repos/test-user/test-repo/deployments" && r.Method == http.MethodPost:
+			assert.Equal(t, "token test-access-token", r.Header.Get("Authorization"))
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			*deployments = append(*deployments, body)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 1001})
+		case r.URL.Path == "/repos/test-user/test-repo/deployments/1001/statuses" && r.Method == http.MethodPost:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			*statuses = append(*statuses, body)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 1})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDeploymentStatusReporting(t *testing.T) {
+	userID := uuid.New().String()
+	connector := shared_types.GithubConnector{
+		ID:             uuid.New(),
+		AppID:          "12345",
+		Pem:            generateTestPrivateKey(),
+		InstallationID: "67890",
+		UserID:         uuid.MustParse(userID),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	var deployments, statuses []map[string]interface{}
+	mockServer := newDeploymentStatusServer(t, &deployments, &statuses)
+	defer mockServer.Close()
+
+	defer service.SetGithubAPIBaseURL("https://api.github.com")
+	service.SetGithubAPIBaseURL(mockServer.URL)
+
+	mockStorage := NewMockGithubConnectorStorage()
+	mockStorage.On("GetAllConnectors", userID).Return([]shared_types.GithubConnector{connector}, nil)
+
+	svc := service.NewGithubConnectorService(nil, context.Background(), logger.NewLogger(), mockStorage)
+
+	id, err := svc.CreateDeployment(service.CreateDeploymentConfig{
+		UserID:      userID,
+		RepoID:      42,
+		Ref:         "0123456789abcdef0123456789abcdef01234567",
+		Environment: "web (production)",
+		Description: "Deployed by Nixopus",
+		Production:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1001), id)
+	require.Len(t, deployments, 1)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", deployments[0]["ref"])
+	assert.Equal(t, "web (production)", deployments[0]["environment"])
+	assert.Equal(t, false, deployments[0]["auto_merge"])
+	assert.Equal(t, []interface{}{}, deployments[0]["required_contexts"])
+	assert.Equal(t, true, deployments[0]["production_environment"])
+
+	err = svc.CreateDeploymentStatus(service.DeploymentStatusConfig{
+		UserID:         userID,
+		RepoID:         42,
+		DeploymentID:   id,
+		State:          service.DeploymentStateSuccess,
+		EnvironmentURL: "https://web.example.com",
+		Description:    "Deployment succeeded",
+	})
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "success", statuses[0]["state"])
+	assert.Equal(t, "https://web.example.com", statuses[0]["environment_url"])
+}
+
+func TestDeploymentStatusReportingErrors(t *testing.T) {
+	userID := uuid.New().String()
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer mockServer.Close()
+
+	defer service.SetGithubAPIBaseURL("https://api.github.com")
+	service.SetGithubAPIBaseURL(mockServer.URL)
+
+	mockStorage := NewMockGithubConnectorStorage()
+	mockStorage.On("GetAllConnectors", userID).Return([]shared_types.GithubConnector{}, nil)
+
+	svc := service.NewGithubConnectorService(nil, context.Background(), logger.NewLogger(), mockStorage)
+
+	_, err := svc.CreateDeployment(service.CreateDeploymentConfig{UserID: userID, RepoID: 42, Ref: "main"})
+	assert.ErrorContains(t, err, "no connectors found for user")
+}
@@ -60,6 +60,10 @@ func (m *NotificationManager) Start() {
 					}
 				case NotificationCategoryOrganization:
 					m.SendOrganizationNotification(payload)
+				case NotificationCategoryBackup:
+					m.SendBackupNotification(payload)
+				case NotificationCategoryCertificate:
+					m.SendCertificateNotification(payload)
 				}
 			case <-m.ctx.Done():
 				return
@@ -192,6 +196,69 @@ func (m *NotificationManager) SendOrganizationNotification(payload NotificationP
 	}
 }
 
+// SendBackupNotification alerts the owner of a backup schedule that a backup or restore failed
+func (m *NotificationManager) SendBackupNotification(payload NotificationPayload) {
+	data, ok := payload.Data.(BackupFailedData)
+	if !ok {
+		return
+	}
+
+	shouldSend, err := m.prefManager.CheckUserNotificationPreferences(payload.UserID, string(SecurityCategory), "security-alerts")
+	if err != nil {
+		log.Printf("Failed to check notification preferences: %s", err)
+		return
+	}
+
+	if !shouldSend {
+		return
+	}
+
+	err = m.emailManager.SendEmailWithTemplate(payload.UserID, email.EmailData{
+		Subject:     fmt.Sprintf("%s of %s %s failed", data.Operation, data.ResourceType, data.ResourceName),
+		Template:    "backup_failed.html",
+		Data:        data,
+		Type:        "security-alerts",
+		ContentType: "text/html; charset=UTF-8",
+		Category:    string(shared_types.SecurityCategory),
+	})
+	if err != nil {
+		log.Printf("Failed to send backup failure email: %s", err)
+	}
+	m.sendWebhookNotification(payload.UserID, fmt.Sprintf("%s of %s %s failed: %s", data.Operation, data.ResourceType, data.ResourceName, data.Error))
+}
+
+// SendCertificateNotification warns the owner of an application that the certificate of one
+// of its domains expires soon
+func (m *NotificationManager) SendCertificateNotification(payload NotificationPayload) {
+	data, ok := payload.Data.(CertificateExpiringData)
+	if !ok {
+		return
+	}
+
+	shouldSend, err := m.prefManager.CheckUserNotificationPreferences(payload.UserID, string(SecurityCategory), "security-alerts")
+	if err != nil {
+		log.Printf("Failed to check notification preferences: %s", err)
+		return
+	}
+
+	if !shouldSend {
+		return
+	}
+
+	err = m.emailManager.SendEmailWithTemplate(payload.UserID, email.EmailData{
+		Subject:     fmt.Sprintf("Certificate of %s expires in %d days", data.Domain, data.DaysLeft),
+		Template:    "certificate_expiring.html",
+		Data:        data,
+		Type:        "security-alerts",
+		ContentType: "text/html; charset=UTF-8",
+		Category:    string(shared_types.SecurityCategory),
+	})
+	if err != nil {
+		log.Printf("Failed to send certificate expiry email: %s", err)
+	}
+	m.sendWebhookNotification(payload.UserID, fmt.Sprintf("Certificate of %s expires on %s", data.Domain, data.ExpiresAt.Format("2006-01-02")))
+}
+
 func (m *NotificationManager) GetWebhookURL(userID string, webhookType string) (string, error) {
 	var config shared_types.WebhookConfig
 
@@ -92,6 +92,24 @@ type NotificationManager struct {
 	discordManager *discord.DiscordManager
 }
 
+type BackupFailedData struct {
+	Operation    string
+	ResourceType string
+	ResourceName string
+	BackupID     string
+	Error        string
+	StartedAt    time.Time
+}
+
+type CertificateExpiringData struct {
+	Domain    string
+	Mode      string
+	Issuer    string
+	ExpiresAt time.Time
+	DaysLeft  int
+	LastError string
+}
+
 type NotificationPasswordResetData struct {
 	NotificationBaseData
 	Email string
@@ -124,11 +142,22 @@ const (
 	NotificationPayloadTypeUpdateOrganization          NotificationPayloadType = "update_organization"
 )
 
+const (
+	NotificationPayloadTypeBackupFailed  NotificationPayloadType = "backup_failed"
+	NotificationPayloadTypeRestoreFailed NotificationPayloadType = "restore_failed"
+)
+
+const (
+	NotificationPayloadTypeCertificateExpiring NotificationPayloadType = "certificate_expiring"
+)
+
 type NotificationCategory string
 
 const (
 	NotificationCategoryAuthentication NotificationCategory = "authentication"
 	NotificationCategoryOrganization   NotificationCategory = "organization"
+	NotificationCategoryBackup         NotificationCategory = "backup"
+	NotificationCategoryCertificate    NotificationCategory = "certificate"
 )
 
 type NotificationPayload struct {
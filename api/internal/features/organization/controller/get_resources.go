@@ -21,6 +21,10 @@ func (c *OrganizationsController) GetResources(f fuego.ContextNoBody) (*shared_t
 		types.ResourceTypeFileManager,
 		types.ResourceTypeDeploy,
 		types.ResourceTypeAudit,
+		types.ResourceTypeDatabase,
+		types.ResourceTypeBackup,
+		types.ResourceTypeTemplate,
+		types.ResourceTypeWebhook,
 	}
 
 	return &shared_types.Response{
@@ -20,6 +20,10 @@ const (
 	ResourceTypeFileManager     ResourceType = "file-manager"
 	ResourceTypeDeploy          ResourceType = "deploy"
 	ResourceTypeAudit           ResourceType = "audit"
+	ResourceTypeDatabase        ResourceType = "database"
+	ResourceTypeBackup          ResourceType = "backup"
+	ResourceTypeTemplate        ResourceType = "template"
+	ResourceTypeWebhook         ResourceType = "webhook"
 )
 
 type CreateOrganizationRequest struct {
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *TemplateController) CreateTemplate(f fuego.ContextWithBody[types.CreateTemplateRequest]) (*shared_types.Response, error) {
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := c.service.CreateTemplate(request, user.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to create template", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: templateErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Template created successfully",
+		Data:    template,
+	}, nil
+}
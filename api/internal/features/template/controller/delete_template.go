@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *TemplateController) DeleteTemplate(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	slug := f.PathParam("slug")
+	if err := c.validator.ValidateSlug(slug); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	_, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.service.DeleteTemplate(slug, organizationID); err != nil {
+		c.logger.Log(logger.Error, "failed to delete template", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: templateErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Template deleted successfully",
+	}, nil
+}
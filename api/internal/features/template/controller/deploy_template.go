@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *TemplateController) DeployTemplate(f fuego.ContextWithBody[types.DeployTemplateRequest]) (*shared_types.Response, error) {
+	slug := f.PathParam("slug")
+	if err := c.validator.ValidateSlug(slug); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := c.service.DeployTemplate(slug, request, user.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to deploy template", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: templateErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Template deployment started",
+		Data:    deployment,
+	}, nil
+}
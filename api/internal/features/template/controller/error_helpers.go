@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	deploy_types "github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// templateErrorStatus maps errors of the template service to HTTP statuses.
+func templateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrTemplateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrTemplateAlreadyExists),
+		errors.Is(err, types.ErrApplicationNameTaken),
+		errors.Is(err, types.ErrDomainAlreadyTaken),
+		errors.Is(err, deploy_types.ErrDomainOwnedElsewhere):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrBuiltinTemplateReadOnly):
+		return http.StatusForbidden
+	case errors.Is(err, types.ErrMissingTemplateVariable),
+		errors.Is(err, types.ErrNoDomainAvailable),
+		errors.Is(err, deploy_types.ErrDomainNotVerified),
+		errors.Is(err, types.ErrUnsafeCompose):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// requestUser returns the user and organization of the request or an unauthorized error.
+func requestUser(w http.ResponseWriter, r *http.Request) (*shared_types.User, uuid.UUID, error) {
+	user := utils.GetUser(w, r)
+	if user == nil {
+		return nil, uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(r)
+	if organizationID == uuid.Nil {
+		return nil, uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+	return user, organizationID, nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *TemplateController) GetTemplates(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	_, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := c.service.GetTemplates(organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get templates", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: templateErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Templates fetched successfully",
+		Data:    templates,
+	}, nil
+}
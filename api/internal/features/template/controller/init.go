@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	deploy_storage "github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/tasks"
+	github_service "github.com/raghavyuva/nixopus-api/internal/features/github-connector/service"
+	github_storage "github.com/raghavyuva/nixopus-api/internal/features/github-connector/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/notification"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/validation"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type TemplateController struct {
+	store        *shared_storage.Store
+	validator    *validation.Validator
+	service      *service.TemplateService
+	ctx          context.Context
+	logger       logger.Logger
+	notification *notification.NotificationManager
+}
+
+func NewTemplateController(
+	store *shared_storage.Store,
+	ctx context.Context,
+	l logger.Logger,
+	notificationManager *notification.NotificationManager,
+) *TemplateController {
+	storage := storage.TemplateStorage{DB: store.DB, Ctx: ctx}
+	github_service := github_service.NewGithubConnectorService(store, ctx, l, &github_storage.GithubConnectorStorage{DB: store.DB, Ctx: ctx})
+	taskService := tasks.NewTaskService(&deploy_storage.DeployStorage{DB: store.DB, Ctx: ctx}, l, docker.NewDockerService(), github_service, store)
+	catalog := service.LoadCatalog(config.AppConfig.Deployment.TemplatesPath, l)
+
+	return &TemplateController{
+		store:        store,
+		validator:    validation.NewValidator(),
+		service:      service.NewTemplateService(store, ctx, l, &storage, taskService, catalog),
+		ctx:          ctx,
+		logger:       l,
+		notification: notificationManager,
+	}
+}
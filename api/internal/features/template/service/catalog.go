@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/validation"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	manifestFileName = "template.yaml"
+	composeFileName  = "docker-compose.yml"
+)
+
+// LoadCatalog reads the built-in templates from dir. Every subdirectory holding a
+// template.yaml and a docker-compose.yml is a template named after the directory. Invalid
+// templates are logged and skipped so that one broken entry does not hide the others.
+func LoadCatalog(dir string, l logger.Logger) []shared_types.Template {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			l.Log(logger.Error, "failed to read templates directory", err.Error())
+		}
+		return nil
+	}
+
+	var templates []shared_types.Template
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		template, err := loadTemplate(filepath.Join(dir, entry.Name()), entry.Name())
+		if err != nil {
+			l.Log(logger.Error, fmt.Sprintf("skipping template %s", entry.Name()), err.Error())
+			continue
+		}
+		templates = append(templates, *template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return strings.ToLower(templates[i].Name) < strings.ToLower(templates[j].Name) })
+	return templates
+}
+
+func loadTemplate(dir string, slug string) (*shared_types.Template, error) {
+	if err := validation.NewValidator().ValidateSlug(slug); err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, types.ErrTemplateManifestNotFound
+		}
+		return nil, err
+	}
+	var manifest types.Manifest
+	if err := yaml.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", types.ErrFailedToLoadTemplate, err)
+	}
+
+	compose, err := os.ReadFile(filepath.Join(dir, composeFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	template := &shared_types.Template{
+		Slug:        slug,
+		Name:        manifest.Name,
+		Description: manifest.Description,
+		Category:    manifest.Category,
+		Website:     manifest.Website,
+		Port:        manifest.Port,
+		Compose:     string(compose),
+		Env:         manifest.Env,
+		Volumes:     manifest.Volumes,
+		Builtin:     true,
+	}
+	if template.Env == nil {
+		template.Env = []shared_types.TemplateEnv{}
+	}
+	if template.Volumes == nil {
+		template.Volumes = []string{}
+	}
+	if err := validation.ValidateTemplate(*template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) builtin(slug string) *shared_types.Template {
+	for i := range s.catalog {
+		if s.catalog[i].Slug == slug {
+			template := s.catalog[i]
+			return &template
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/validation"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// DeployTemplate creates an application from a template and queues its deployment. Variables
+// that are not given take the template default or a generated secret, the domain defaults
+// to a random subdomain of one of the organization's domains.
+func (s *TemplateService) DeployTemplate(slug string, req types.DeployTemplateRequest, userID uuid.UUID, organizationID uuid.UUID) (*types.DeployTemplateResponse, error) {
+	template, err := s.GetTemplate(slug, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	// checked again for templates saved before the compose restrictions existed
+	if !template.Builtin {
+		if err := validation.ValidateOrganizationCompose(template.Compose); err != nil {
+			return nil, err
+		}
+	}
+
+	env, generated, err := resolveEnv(template.Env, req.EnvironmentVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.applicationName(template.Slug, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err := s.allocateDomain(template.Slug, req.Domain, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := req.Environment
+	if environment == "" {
+		environment = shared_types.Production
+	}
+
+	s.tasks.SetupCreateDeploymentQueue()
+	application, err := s.tasks.CreateDeploymentTask(types.DeploymentRequest(*template, name, domain, environment, env), userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DeployTemplateResponse{Application: application, Generated: generated}, nil
+}
+
+// resolveEnv returns the variables of a deployment and the names of the ones that were
+// generated. Variables that the template does not declare are passed through.
+func resolveEnv(declared []shared_types.TemplateEnv, given map[string]string) (map[string]string, []string, error) {
+	env := make(map[string]string, len(declared)+len(given))
+	for name, value := range given {
+		env[name] = value
+	}
+
+	generated := []string{}
+	for _, variable := range declared {
+		if value, ok := env[variable.Name]; ok && value != "" {
+			continue
+		}
+		switch {
+		case variable.Default != "":
+			env[variable.Name] = variable.Default
+		case variable.Generate != "":
+			value, err := generateSecret(variable.Generate)
+			if err != nil {
+				return nil, nil, err
+			}
+			env[variable.Name] = value
+			generated = append(generated, variable.Name)
+		case variable.Required:
+			return nil, nil, fmt.Errorf("%w: %s", types.ErrMissingTemplateVariable, variable.Name)
+		}
+	}
+	return env, generated, nil
+}
+
+// applicationName returns the requested name, or the slug with a short random suffix.
+func (s *TemplateService) applicationName(slug string, requested string) (string, error) {
+	if requested != "" {
+		taken, err := s.tasks.Storage.IsNameAlreadyTaken(requested)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", types.ErrApplicationNameTaken
+		}
+		return requested, nil
+	}
+
+	for i := 0; i < 5; i++ {
+		suffix, err := randomLabel(6)
+		if err != nil {
+			return "", err
+		}
+		name := slug + "-" + suffix
+		taken, err := s.tasks.Storage.IsNameAlreadyTaken(name)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return name, nil
+		}
+	}
+	return "", types.ErrApplicationNameTaken
+}
+
+// allocateDomain returns the requested domain if it is free, or a random subdomain of the
+// first domain of the organization.
+func (s *TemplateService) allocateDomain(slug string, requested string, organizationID uuid.UUID) (string, error) {
+	if requested != "" {
+		requested = strings.ToLower(strings.TrimSpace(requested))
+		taken, err := s.tasks.Storage.IsDomainAlreadyTaken(requested)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", types.ErrDomainAlreadyTaken
+		}
+		return requested, nil
+	}
+
+	domains, err := s.storage.GetDomains(organizationID)
+	if err != nil {
+		return "", err
+	}
+	if len(domains) == 0 {
+		return "", types.ErrNoDomainAvailable
+	}
+	base := strings.TrimPrefix(domains[0].Name, "*.")
+
+	for i := 0; i < 5; i++ {
+		label, err := randomLabel(8)
+		if err != nil {
+			return "", err
+		}
+		domain := slug + "-" + label + "." + base
+		taken, err := s.tasks.Storage.IsDomainAlreadyTaken(domain)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return domain, nil
+		}
+	}
+	return "", types.ErrDomainAlreadyTaken
+}
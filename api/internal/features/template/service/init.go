@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/tasks"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/storage"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+type TemplateService struct {
+	storage storage.TemplateStorageInterface
+	tasks   *tasks.TaskService
+	Ctx     context.Context
+	store   *shared_storage.Store
+	logger  logger.Logger
+	// catalog holds the built-in templates, sorted by name
+	catalog []shared_types.Template
+}
+
+func NewTemplateService(store *shared_storage.Store, ctx context.Context, logger logger.Logger, template_repo storage.TemplateStorageInterface, taskService *tasks.TaskService, catalog []shared_types.Template) *TemplateService {
+	return &TemplateService{
+		storage: template_repo,
+		tasks:   taskService,
+		store:   store,
+		Ctx:     ctx,
+		logger:  logger,
+		catalog: catalog,
+	}
+}
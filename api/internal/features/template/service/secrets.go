@@ -0,0 +1,56 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	alphanumeric   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	lowercase      = "abcdefghijklmnopqrstuvwxyz0123456789"
+	passwordLength = 24
+	secretBytes    = 32
+)
+
+// generateSecret returns a random value of the given kind. None of the alphabets contain
+// whitespace or '=', which the application environment variables cannot hold.
+func generateSecret(kind shared_types.TemplateSecretKind) (string, error) {
+	switch kind {
+	case shared_types.TemplateSecretPassword:
+		return randomString(alphanumeric, passwordLength)
+	case shared_types.TemplateSecretHex, shared_types.TemplateSecretBase64:
+		buf := make([]byte, secretBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		if kind == shared_types.TemplateSecretHex {
+			return hex.EncodeToString(buf), nil
+		}
+		return base64.RawURLEncoding.EncodeToString(buf), nil
+	default:
+		return "", types.ErrInvalidSecretKind
+	}
+}
+
+// randomLabel returns a random lowercase string usable in a domain name.
+func randomLabel(length int) (string, error) {
+	return randomString(lowercase, length)
+}
+
+func randomString(alphabet string, length int) (string, error) {
+	max := big.NewInt(int64(len(alphabet)))
+	out := make([]byte, length)
+	for i := range out {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		out[i] = alphabet[n.Int64()]
+	}
+	return string(out), nil
+}
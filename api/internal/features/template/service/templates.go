@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetTemplates returns the built-in templates followed by the ones of the organization.
+func (s *TemplateService) GetTemplates(organizationID uuid.UUID) ([]shared_types.Template, error) {
+	custom, err := s.storage.GetTemplates(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(custom, func(i, j int) bool { return strings.ToLower(custom[i].Name) < strings.ToLower(custom[j].Name) })
+
+	templates := make([]shared_types.Template, 0, len(s.catalog)+len(custom))
+	templates = append(templates, s.catalog...)
+	return append(templates, custom...), nil
+}
+
+// GetTemplate looks a template up by slug, built-in templates first.
+func (s *TemplateService) GetTemplate(slug string, organizationID uuid.UUID) (*shared_types.Template, error) {
+	if template := s.builtin(slug); template != nil {
+		return template, nil
+	}
+	return s.storage.GetTemplate(slug, organizationID)
+}
+
+func (s *TemplateService) CreateTemplate(req types.CreateTemplateRequest, userID uuid.UUID, organizationID uuid.UUID) (*shared_types.Template, error) {
+	if s.builtin(req.Slug) != nil {
+		return nil, types.ErrTemplateAlreadyExists
+	}
+	if _, err := s.storage.GetTemplate(req.Slug, organizationID); err == nil {
+		return nil, types.ErrTemplateAlreadyExists
+	} else if !errors.Is(err, types.ErrTemplateNotFound) {
+		return nil, err
+	}
+
+	template := &shared_types.Template{
+		ID:             uuid.New(),
+		Slug:           req.Slug,
+		Name:           req.Name,
+		Description:    req.Description,
+		Category:       req.Category,
+		Website:        req.Website,
+		Port:           req.Port,
+		Compose:        req.Compose,
+		Env:            req.Env,
+		Volumes:        req.Volumes,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if template.Env == nil {
+		template.Env = []shared_types.TemplateEnv{}
+	}
+	if template.Volumes == nil {
+		template.Volumes = []string{}
+	}
+	if err := s.storage.CreateTemplate(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) UpdateTemplate(slug string, req types.UpdateTemplateRequest, organizationID uuid.UUID) (*shared_types.Template, error) {
+	if s.builtin(slug) != nil {
+		return nil, types.ErrBuiltinTemplateReadOnly
+	}
+	template, err := s.storage.GetTemplate(slug, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = req.Name
+	template.Description = req.Description
+	template.Category = req.Category
+	template.Website = req.Website
+	template.Port = req.Port
+	template.Compose = req.Compose
+	template.Env = req.Env
+	template.Volumes = req.Volumes
+	if template.Env == nil {
+		template.Env = []shared_types.TemplateEnv{}
+	}
+	if template.Volumes == nil {
+		template.Volumes = []string{}
+	}
+	if err := s.storage.UpdateTemplate(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate removes a template of the organization. Applications deployed from it keep
+// their own copy of the compose file.
+func (s *TemplateService) DeleteTemplate(slug string, organizationID uuid.UUID) error {
+	if s.builtin(slug) != nil {
+		return types.ErrBuiltinTemplateReadOnly
+	}
+	template, err := s.storage.GetTemplate(slug, organizationID)
+	if err != nil {
+		return err
+	}
+	return s.storage.DeleteTemplate(template.ID)
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/uptrace/bun"
+)
+
+type TemplateStorage struct {
+	DB  *bun.DB
+	Ctx context.Context
+}
+
+type TemplateStorageInterface interface {
+	GetTemplates(organizationID uuid.UUID) ([]shared_types.Template, error)
+	GetTemplate(slug string, organizationID uuid.UUID) (*shared_types.Template, error)
+	CreateTemplate(template *shared_types.Template) error
+	UpdateTemplate(template *shared_types.Template) error
+	DeleteTemplate(id uuid.UUID) error
+	GetDomains(organizationID uuid.UUID) ([]shared_types.Domain, error)
+}
+
+func (s *TemplateStorage) GetTemplates(organizationID uuid.UUID) ([]shared_types.Template, error) {
+	var templates []shared_types.Template
+	err := s.DB.NewSelect().
+		Model(&templates).
+		Where("organization_id = ?", organizationID).
+		Order("name ASC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (s *TemplateStorage) GetTemplate(slug string, organizationID uuid.UUID) (*shared_types.Template, error) {
+	var template shared_types.Template
+	err := s.DB.NewSelect().
+		Model(&template).
+		Where("slug = ? AND organization_id = ?", slug, organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (s *TemplateStorage) CreateTemplate(template *shared_types.Template) error {
+	_, err := s.DB.NewInsert().Model(template).Exec(s.Ctx)
+	return err
+}
+
+func (s *TemplateStorage) UpdateTemplate(template *shared_types.Template) error {
+	template.UpdatedAt = time.Now()
+	_, err := s.DB.NewUpdate().
+		Model(template).
+		Column("name", "description", "category", "website", "port", "compose", "env", "volumes", "updated_at").
+		WherePK().
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *TemplateStorage) DeleteTemplate(id uuid.UUID) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.Template)(nil)).
+		Where("id = ?", id).
+		Exec(s.Ctx)
+	return err
+}
+
+// GetDomains returns the verified domains of an organization subdomains of deployed templates
+// are allocated under.
+func (s *TemplateStorage) GetDomains(organizationID uuid.UUID) ([]shared_types.Domain, error) {
+	var domains []shared_types.Domain
+	err := s.DB.NewSelect().
+		Model(&domains).
+		Where("organization_id = ? AND deleted_at IS NULL AND verified_at IS NOT NULL", organizationID).
+		Order("created_at ASC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+var (
+	ErrInvalidRequestType       = errors.New("invalid request type")
+	ErrTemplateNotFound         = errors.New("template not found")
+	ErrTemplateAlreadyExists    = errors.New("a template with this slug already exists")
+	ErrBuiltinTemplateReadOnly  = errors.New("built-in templates cannot be changed")
+	ErrInvalidSlug              = errors.New("invalid slug, use lowercase letters, digits and dashes")
+	ErrMissingTemplateName      = errors.New("template name is required")
+	ErrInvalidTemplatePort      = errors.New("port must be between 1 and 65535")
+	ErrInvalidCompose           = errors.New("compose must be a valid compose file with at least one service")
+	ErrComposeMissingPort       = errors.New("compose must publish a port on ${NIXOPUS_PORT}")
+	ErrUnsafeCompose            = errors.New("compose gives access to the host, which only built-in templates may do")
+	ErrInvalidEnvName           = errors.New("invalid environment variable name")
+	ErrDuplicateEnvName         = errors.New("environment variable is declared more than once")
+	ErrInvalidSecretKind        = errors.New("invalid generate value, expected password, hex or base64")
+	ErrInvalidEnvValue          = errors.New("environment variable values cannot contain whitespace or '='")
+	ErrReservedEnvName          = errors.New("variables starting with NIXOPUS_ are reserved")
+	ErrMissingTemplateVariable  = errors.New("required template variable is missing")
+	ErrInvalidApplicationName   = errors.New("application name must be at least 3 characters")
+	ErrApplicationNameTaken     = errors.New("an application with this name already exists")
+	ErrDomainAlreadyTaken       = errors.New("domain is already used by another application")
+	ErrNoDomainAvailable        = errors.New("no domain is configured for this organization, add one or pass a domain")
+	ErrFailedToLoadTemplate     = errors.New("failed to load template")
+	ErrInvalidEnvironment       = errors.New("invalid environment, expected development, staging or production")
+	ErrTemplateManifestNotFound = errors.New("template.yaml not found")
+)
+
+// Manifest is the template.yaml file describing a template of the catalog directory. The
+// compose file sits next to it as docker-compose.yml.
+type Manifest struct {
+	Name        string                     `yaml:"name"`
+	Description string                     `yaml:"description"`
+	Category    string                     `yaml:"category"`
+	Website     string                     `yaml:"website"`
+	Port        int                        `yaml:"port"`
+	Env         []shared_types.TemplateEnv `yaml:"env"`
+	Volumes     []string                   `yaml:"volumes"`
+}
+
+type CreateTemplateRequest struct {
+	Slug        string                     `json:"slug"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description,omitempty"`
+	Category    string                     `json:"category,omitempty"`
+	Website     string                     `json:"website,omitempty"`
+	Port        int                        `json:"port"`
+	Compose     string                     `json:"compose"`
+	Env         []shared_types.TemplateEnv `json:"env,omitempty"`
+	Volumes     []string                   `json:"volumes,omitempty"`
+}
+
+type UpdateTemplateRequest struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description,omitempty"`
+	Category    string                     `json:"category,omitempty"`
+	Website     string                     `json:"website,omitempty"`
+	Port        int                        `json:"port"`
+	Compose     string                     `json:"compose"`
+	Env         []shared_types.TemplateEnv `json:"env,omitempty"`
+	Volumes     []string                   `json:"volumes,omitempty"`
+}
+
+// DeployTemplateRequest instantiates a template. Without a domain a random subdomain of one
+// of the organization's domains is allocated, without a name one is derived from the slug.
+type DeployTemplateRequest struct {
+	Name                 string                   `json:"name,omitempty"`
+	Domain               string                   `json:"domain,omitempty"`
+	Environment          shared_types.Environment `json:"environment,omitempty"`
+	EnvironmentVariables map[string]string        `json:"environment_variables,omitempty"`
+}
+
+type DeployTemplateResponse struct {
+	Application shared_types.Application `json:"application"`
+	// Generated lists the variables that were given a random value
+	Generated []string `json:"generated"`
+}
+
+// DeploymentRequest builds the request creating the application of a template.
+func DeploymentRequest(template shared_types.Template, name string, domain string, environment shared_types.Environment, env map[string]string) *types.CreateDeploymentRequest {
+	return &types.CreateDeploymentRequest{
+		Name:                 name,
+		Domain:               domain,
+		Environment:          environment,
+		BuildPack:            shared_types.DockerCompose,
+		Port:                 template.Port,
+		EnvironmentVariables: env,
+		BasePath:             "/",
+		DockerfilePath:       "Dockerfile",
+		ComposeFile:          template.Compose,
+		Template:             template.Slug,
+	}
+}
@@ -0,0 +1,217 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	"gopkg.in/yaml.v3"
+)
+
+// namespaceKeys share a namespace of the host or another container when set to host or
+// container:<name>.
+var namespaceKeys = []string{"network_mode", "pid", "ipc", "uts", "userns_mode", "cgroup"}
+
+// hostAccessKeys give a service access to the host whatever their value is.
+var hostAccessKeys = []string{"cap_add", "devices", "device_cgroup_rules", "security_opt", "volumes_from", "build", "extends"}
+
+// ValidateOrganizationCompose rejects compose files that could reach into the host. Templates
+// of the catalog directory are trusted, the ones added by organizations run on the same
+// server as everyone else's applications, so they are limited to volumes and networks of
+// their own project, the host port reserved for them and the default isolation of their
+// containers.
+func ValidateOrganizationCompose(compose string) error {
+	var file map[string]interface{}
+	if err := yaml.Unmarshal([]byte(compose), &file); err != nil {
+		return types.ErrInvalidCompose
+	}
+
+	for _, key := range []string{"include", "secrets", "configs"} {
+		if _, ok := file[key]; ok {
+			return unsafeCompose("top level", key)
+		}
+	}
+	if volumes, ok := file["volumes"].(map[string]interface{}); ok {
+		for name, volume := range volumes {
+			v, _ := volume.(map[string]interface{})
+			if v["driver"] != nil || v["driver_opts"] != nil {
+				return unsafeCompose("volume "+name, "driver_opts")
+			}
+			if err := validateProjectResource("volume "+name, v); err != nil {
+				return err
+			}
+		}
+	}
+	if networks, ok := file["networks"].(map[string]interface{}); ok {
+		for name, network := range networks {
+			n, _ := network.(map[string]interface{})
+			if err := validateProjectResource("network "+name, n); err != nil {
+				return err
+			}
+		}
+	}
+
+	services, ok := file["services"].(map[string]interface{})
+	if !ok {
+		return types.ErrInvalidCompose
+	}
+	for name, raw := range services {
+		service, ok := raw.(map[string]interface{})
+		if !ok {
+			return types.ErrInvalidCompose
+		}
+		if err := validateComposeService(name, service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateProjectResource rejects top level volumes and networks that are not created for the
+// compose project. An external resource or a fixed name could be one of another application,
+// a managed database or Nixopus itself.
+func validateProjectResource(resource string, definition map[string]interface{}) error {
+	if external, ok := definition["external"]; ok && external != false {
+		return unsafeCompose(resource, "external")
+	}
+	if _, ok := definition["name"]; ok {
+		return unsafeCompose(resource, "name")
+	}
+	return nil
+}
+
+func validateComposeService(name string, service map[string]interface{}) error {
+	if privileged, ok := service["privileged"]; ok && privileged != false {
+		return unsafeCompose(name, "privileged")
+	}
+	for _, key := range hostAccessKeys {
+		if _, ok := service[key]; ok {
+			return unsafeCompose(name, key)
+		}
+	}
+	for _, key := range namespaceKeys {
+		value, ok := service[key]
+		if !ok {
+			continue
+		}
+		mode := fmt.Sprint(value)
+		// variables are rejected too, their values come from the deployment request
+		if mode == "host" || strings.HasPrefix(mode, "container:") || strings.Contains(mode, "$") {
+			return unsafeCompose(name, key)
+		}
+	}
+
+	if envFiles, ok := service["env_file"]; ok {
+		for _, envFile := range stringList(envFiles) {
+			if !localComposePath(envFile) {
+				return unsafeCompose(name, "env_file")
+			}
+		}
+	}
+
+	volumes, _ := service["volumes"].([]interface{})
+	for _, volume := range volumes {
+		if isBindMount(volume) {
+			return unsafeCompose(name, "bind mount")
+		}
+	}
+
+	if ports, ok := service["ports"]; ok {
+		list, ok := ports.([]interface{})
+		if !ok {
+			return unsafeCompose(name, "ports")
+		}
+		for _, port := range list {
+			if !publishesReservedPort(port) {
+				return unsafeCompose(name, "ports")
+			}
+		}
+	}
+	return nil
+}
+
+// reservedPortVariables are the ways of referencing NIXOPUS_PORT, the host port reserved for
+// the application.
+var reservedPortVariables = []string{"${NIXOPUS_PORT}", "$NIXOPUS_PORT"}
+
+// publishesReservedPort reports whether a port of a service is published on the host port
+// reserved for the application. Any other host port, including one picked by Docker, is not
+// in the port reservations and could take the port of another application.
+func publishesReservedPort(port interface{}) bool {
+	switch p := port.(type) {
+	case string:
+		parts := strings.Split(p, ":")
+		return len(parts) == 2 && slices.Contains(reservedPortVariables, parts[0]) && parts[1] != ""
+	case map[string]interface{}:
+		published, _ := p["published"].(string)
+		_, hostIP := p["host_ip"]
+		return slices.Contains(reservedPortVariables, published) && p["target"] != nil && !hostIP
+	default:
+		return false
+	}
+}
+
+// isBindMount reports whether a volume of a service mounts a path of the host. Only named
+// volumes and tmpfs mounts are allowed.
+func isBindMount(volume interface{}) bool {
+	switch v := volume.(type) {
+	case string:
+		source, _, found := strings.Cut(v, ":")
+		if !found {
+			// anonymous volume
+			return false
+		}
+		return !namedVolume(source)
+	case map[string]interface{}:
+		switch fmt.Sprint(v["type"]) {
+		case "volume":
+			source, _ := v["source"].(string)
+			return source != "" && !namedVolume(source)
+		case "tmpfs":
+			return false
+		default:
+			return true
+		}
+	default:
+		return true
+	}
+}
+
+// namedVolume reports whether the source of a volume is a volume name rather than a path or a
+// variable that could expand into one.
+func namedVolume(source string) bool {
+	return source != "" && !strings.HasPrefix(source, ".") && !strings.HasPrefix(source, "~") &&
+		!strings.ContainsAny(source, "/\\$")
+}
+
+// localComposePath reports whether a file referenced by the compose file stays inside the
+// compose project directory.
+func localComposePath(path string) bool {
+	return path != "" && !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "~") &&
+		!strings.Contains(path, "..") && !strings.Contains(path, "$")
+}
+
+// stringList returns the entries of a compose key that takes a string or a list. Entries of
+// the long syntax are returned by their path.
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				item = m["path"]
+			}
+			list = append(list, fmt.Sprint(item))
+		}
+		return list
+	default:
+		return []string{""}
+	}
+}
+
+func unsafeCompose(service string, key string) error {
+	return fmt.Errorf("%w: %s uses %s", types.ErrUnsafeCompose, service, key)
+}
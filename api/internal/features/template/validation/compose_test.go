@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOrganizationCompose(t *testing.T) {
+	tests := []struct {
+		name    string
+		compose string
+		wantErr error
+	}{
+		{
+			name: "Named volumes and published port",
+			compose: `services:
+  app:
+    image: ghost:5
+    ports: ["${NIXOPUS_PORT}:2368"]
+    env_file: .env
+    volumes:
+      - content:/var/lib/ghost/content
+      - /tmp/cache
+      - type: tmpfs
+        target: /run
+volumes:
+  content: {}
+`,
+		},
+		{name: "Privileged", compose: "services:\n  app:\n    image: alpine\n    privileged: true\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Privileged explicitly off", compose: "services:\n  app:\n    image: alpine\n    privileged: false\n"},
+		{name: "Host network", compose: "services:\n  app:\n    image: alpine\n    network_mode: host\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Network of another container", compose: "services:\n  app:\n    image: alpine\n    network_mode: container:nixopus-api\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Network mode from a variable", compose: "services:\n  app:\n    image: alpine\n    network_mode: ${MODE}\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Host pid", compose: "services:\n  app:\n    image: alpine\n    pid: host\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Capabilities", compose: "services:\n  app:\n    image: alpine\n    cap_add: [SYS_ADMIN]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Devices", compose: "services:\n  app:\n    image: alpine\n    devices: [/dev/sda]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Security options", compose: "services:\n  app:\n    image: alpine\n    security_opt: [seccomp:unconfined]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Build context", compose: "services:\n  app:\n    build: /\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Host root bind mount", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"/:/host\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Docker socket", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"/var/run/docker.sock:/var/run/docker.sock\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Relative bind mount", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"./data:/data\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Home bind mount", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"~/.ssh:/ssh\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Bind mount from a variable", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"${DATA}:/data\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Long syntax bind mount", compose: "services:\n  app:\n    image: alpine\n    volumes:\n      - type: bind\n        source: /etc\n        target: /host-etc\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Local volume driver binding a host path", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"data:/data\"]\nvolumes:\n  data:\n    driver_opts: {type: none, o: bind, device: /}\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Host env file", compose: "services:\n  app:\n    image: alpine\n    env_file: /etc/nixopus/.env\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Env file outside the project", compose: "services:\n  app:\n    image: alpine\n    env_file: [../../.env]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "File secrets", compose: "services:\n  app:\n    image: alpine\nsecrets:\n  key:\n    file: /etc/shadow\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Included files", compose: "include: [/etc/compose.yml]\nservices:\n  app:\n    image: alpine\n", wantErr: types.ErrUnsafeCompose},
+		{name: "External volume", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"data:/data\"]\nvolumes:\n  data:\n    external: true\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Volume of a database", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"data:/data\"]\nvolumes:\n  data:\n    name: nixopus-db-orders-data\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Volume not external", compose: "services:\n  app:\n    image: alpine\n    volumes: [\"data:/data\"]\nvolumes:\n  data:\n    external: false\n"},
+		{name: "External network", compose: "services:\n  app:\n    image: alpine\nnetworks:\n  databases:\n    external: true\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Named network", compose: "services:\n  app:\n    image: alpine\nnetworks:\n  databases:\n    name: nixopus-databases-1\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Project network", compose: "services:\n  app:\n    image: alpine\n    networks: [backend]\nnetworks:\n  backend: {}\n"},
+		{name: "Reserved port without braces", compose: "services:\n  app:\n    image: alpine\n    ports: [\"$NIXOPUS_PORT:80/tcp\"]\n"},
+		{name: "Long syntax reserved port", compose: "services:\n  app:\n    image: alpine\n    ports:\n      - target: 80\n        published: \"${NIXOPUS_PORT}\"\n"},
+		{name: "Fixed host port", compose: "services:\n  app:\n    image: alpine\n    ports: [\"22:22\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Second service on a fixed port", compose: "services:\n  app:\n    image: alpine\n    ports: [\"${NIXOPUS_PORT}:80\"]\n  db:\n    image: postgres\n    ports: [\"5432:5432\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Port picked by docker", compose: "services:\n  app:\n    image: alpine\n    ports: [\"80\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Port as a number", compose: "services:\n  app:\n    image: alpine\n    ports: [80]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Host address", compose: "services:\n  app:\n    image: alpine\n    ports: [\"0.0.0.0:${NIXOPUS_PORT}:80\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Port with a default", compose: "services:\n  app:\n    image: alpine\n    ports: [\"${NIXOPUS_PORT:-22}:80\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Long syntax fixed port", compose: "services:\n  app:\n    image: alpine\n    ports:\n      - target: 80\n        published: 8080\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Long syntax host address", compose: "services:\n  app:\n    image: alpine\n    ports:\n      - target: 80\n        published: \"${NIXOPUS_PORT}\"\n        host_ip: 0.0.0.0\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Port range", compose: "services:\n  app:\n    image: alpine\n    ports: [\"1000-2000:1000-2000\"]\n", wantErr: types.ErrUnsafeCompose},
+		{name: "Not a compose file", compose: "services: [app]\n", wantErr: types.ErrInvalidCompose},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOrganizationCompose(tt.compose)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/template/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	slugPattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+	envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Validator handles template validation logic
+type Validator struct{}
+
+// NewValidator creates a new validator instance
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateSlug checks a template slug taken from the request path
+func (v *Validator) ValidateSlug(slug string) error {
+	if !slugPattern.MatchString(slug) {
+		return types.ErrInvalidSlug
+	}
+	return nil
+}
+
+// ValidateRequest validates different template request types
+func (v *Validator) ValidateRequest(req interface{}) error {
+	switch r := req.(type) {
+	case *types.CreateTemplateRequest:
+		if err := v.ValidateSlug(r.Slug); err != nil {
+			return err
+		}
+		if err := ValidateTemplate(shared_types.Template{Name: r.Name, Port: r.Port, Compose: r.Compose, Env: r.Env}); err != nil {
+			return err
+		}
+		return ValidateOrganizationCompose(r.Compose)
+	case *types.UpdateTemplateRequest:
+		if err := ValidateTemplate(shared_types.Template{Name: r.Name, Port: r.Port, Compose: r.Compose, Env: r.Env}); err != nil {
+			return err
+		}
+		return ValidateOrganizationCompose(r.Compose)
+	case *types.DeployTemplateRequest:
+		return v.validateDeployTemplateRequest(*r)
+	default:
+		return types.ErrInvalidRequestType
+	}
+}
+
+func (v *Validator) validateDeployTemplateRequest(req types.DeployTemplateRequest) error {
+	if req.Name != "" && len(req.Name) < 3 {
+		return types.ErrInvalidApplicationName
+	}
+	switch req.Environment {
+	case "", shared_types.Development, shared_types.Staging, shared_types.Production:
+	default:
+		return types.ErrInvalidEnvironment
+	}
+	for name, value := range req.EnvironmentVariables {
+		if !envNamePattern.MatchString(name) {
+			return types.ErrInvalidEnvName
+		}
+		if err := ValidateEnvValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateTemplate checks a template of the catalog directory or one added by an organization.
+func ValidateTemplate(template shared_types.Template) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return types.ErrMissingTemplateName
+	}
+	if template.Port < 1 || template.Port > 65535 {
+		return types.ErrInvalidTemplatePort
+	}
+	if err := validateCompose(template.Compose); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(template.Env))
+	for _, env := range template.Env {
+		if !envNamePattern.MatchString(env.Name) {
+			return types.ErrInvalidEnvName
+		}
+		if strings.HasPrefix(env.Name, "NIXOPUS_") {
+			return types.ErrReservedEnvName
+		}
+		if seen[env.Name] {
+			return types.ErrDuplicateEnvName
+		}
+		seen[env.Name] = true
+
+		switch env.Generate {
+		case "", shared_types.TemplateSecretPassword, shared_types.TemplateSecretHex, shared_types.TemplateSecretBase64:
+		default:
+			return types.ErrInvalidSecretKind
+		}
+		if err := ValidateEnvValue(env.Default); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateEnvValue rejects values that would not survive the way application environment
+// variables are stored.
+func ValidateEnvValue(value string) error {
+	if strings.ContainsAny(value, " \t\r\n=") {
+		return types.ErrInvalidEnvValue
+	}
+	return nil
+}
+
+// validateCompose checks that the compose file has services and publishes the port the
+// application is routed to.
+func validateCompose(compose string) error {
+	var file struct {
+		Services map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal([]byte(compose), &file); err != nil || len(file.Services) == 0 {
+		return types.ErrInvalidCompose
+	}
+	if !strings.Contains(compose, "${NIXOPUS_PORT}") && !strings.Contains(compose, "$NIXOPUS_PORT") {
+		return types.ErrComposeMissingPort
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package terminal
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/gorilla/websocket"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+)
+
+// DefaultShell is the shell opened in a container when the client does not ask for one.
+const DefaultShell = "/bin/sh"
+
+// writeTimeout bounds a single write of terminal output to the websocket.
+const writeTimeout = 10 * time.Second
+
+// ContainerTerminal is a shell running in a container through docker exec, with a TTY so that
+// interactive programs and resizing work like on the host terminal.
+type ContainerTerminal struct {
+	docker *docker.DockerService
+	conn   *websocket.Conn
+	log    logger.Logger
+	// wsLock is the write lock of the connection, shared with its other writers
+	wsLock *sync.Mutex
+
+	ready     chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	execID    string
+	stream    types.HijackedResponse
+	err       error
+
+	TerminalId  string
+	ContainerID string
+	Shell       string
+	StartedAt   time.Time
+}
+
+func NewContainerTerminal(conn *websocket.Conn, writeLock *sync.Mutex, log *logger.Logger, dockerService *docker.DockerService, terminalId, containerID, shell string) *ContainerTerminal {
+	if shell == "" {
+		shell = DefaultShell
+	}
+	return &ContainerTerminal{
+		docker:      dockerService,
+		conn:        conn,
+		wsLock:      writeLock,
+		log:         *log,
+		ready:       make(chan struct{}),
+		done:        make(chan struct{}),
+		TerminalId:  terminalId,
+		ContainerID: containerID,
+		Shell:       shell,
+		StartedAt:   time.Now(),
+	}
+}
+
+// Start runs the shell in the container and streams its output to the websocket until it exits.
+// Once it exited an "exit" message carrying its exit code is sent.
+func (t *ContainerTerminal) Start() {
+	env := []string{"TERM=xterm-256color", "COLORTERM=truecolor", "LANG=C.UTF-8"}
+	execID, stream, err := t.docker.ExecTTY(t.ContainerID, []string{t.Shell}, env, 40, 100)
+	t.execID, t.stream, t.err = execID, stream, err
+	close(t.ready)
+	if err != nil {
+		t.log.Log(logger.Error, "Failed to exec into container", err.Error())
+		t.send(TerminalMessage{TerminalId: t.TerminalId, Type: "error", Data: "Failed to start shell: " + err.Error()})
+		t.Close()
+		return
+	}
+	select {
+	case <-t.done:
+		// closed while the shell was starting
+		stream.Close()
+		return
+	default:
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := stream.Reader.Read(buf)
+		if n > 0 {
+			if err := t.send(TerminalMessage{TerminalId: t.TerminalId, Type: "stdout", Data: string(buf[:n])}); err != nil {
+				t.log.Log(logger.Error, "Error writing to websocket", err.Error())
+				break
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				t.log.Log(logger.Error, "Error reading from container", err.Error())
+			}
+			break
+		}
+	}
+
+	select {
+	case <-t.done:
+		// closed by the server, the client is gone
+	default:
+		exitCode := -1
+		if inspect, err := t.docker.InspectExec(execID); err == nil && !inspect.Running {
+			exitCode = inspect.ExitCode
+		}
+		t.send(TerminalMessage{TerminalId: t.TerminalId, Type: "exit", Data: strconv.Itoa(exitCode)})
+	}
+	t.Close()
+}
+
+// Done is closed once the shell exited or the terminal was closed.
+func (t *ContainerTerminal) Done() <-chan struct{} {
+	return t.done
+}
+
+// WriteMessage writes input to the shell, waiting for it to be started.
+func (t *ContainerTerminal) WriteMessage(message string) error {
+	if err := t.wait(); err != nil {
+		return err
+	}
+	_, err := t.stream.Conn.Write([]byte(message))
+	return err
+}
+
+// ResizeTerminal changes the size of the TTY of the shell.
+func (t *ContainerTerminal) ResizeTerminal(rows, cols uint16) error {
+	if err := t.wait(); err != nil {
+		return err
+	}
+	return t.docker.ResizeExec(t.execID, uint(rows), uint(cols))
+}
+
+// Close ends the session. The shell gets a hangup as its TTY is closed, the websocket stays open
+// for the other terminals of the connection.
+func (t *ContainerTerminal) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.done)
+		select {
+		case <-t.ready:
+			if t.err == nil {
+				t.stream.Close()
+			}
+		default:
+		}
+	})
+	return nil
+}
+
+func (t *ContainerTerminal) wait() error {
+	select {
+	case <-t.ready:
+	case <-t.done:
+		return errors.New("terminal already closed")
+	}
+	if t.err != nil {
+		return t.err
+	}
+	select {
+	case <-t.done:
+		return errors.New("terminal already closed")
+	default:
+		return nil
+	}
+}
+
+func (t *ContainerTerminal) send(msg TerminalMessage) error {
+	t.wsLock.Lock()
+	defer t.wsLock.Unlock()
+	return writeJSON(t.conn, msg)
+}
+
+// writeJSON writes a message to the websocket with a deadline, so a client that stopped reading
+// does not hold the write lock of its connection forever. The caller holds the write lock.
+func writeJSON(conn *websocket.Conn, msg TerminalMessage) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	err := conn.WriteJSON(msg)
+	_ = conn.SetWriteDeadline(time.Time{})
+	return err
+}
@@ -26,6 +26,15 @@ type TerminalMessage struct {
 	Size       *TermSize `json:"size,omitempty"`
 }
 
+// Session is a shell attached to a websocket connection, opened on the host over SSH by
+// Terminal or inside a container by ContainerTerminal.
+type Session interface {
+	Start()
+	WriteMessage(message string) error
+	ResizeTerminal(rows, cols uint16) error
+	Close() error
+}
+
 type Terminal struct {
 	ssh        *sshpkg.SSH
 	conn       *websocket.Conn
@@ -34,7 +43,9 @@ type Terminal struct {
 	bufferTime time.Duration
 	bufferTick *time.Ticker
 	log        logger.Logger
-	wsLock     sync.Mutex
+	// wsLock is the write lock of the connection, shared with its other writers. It also
+	// guards outputBuf.
+	wsLock *sync.Mutex
 
 	client  *goph.Client
 	session *ssh.Session
@@ -43,11 +54,12 @@ type Terminal struct {
 	TerminalId string
 }
 
-func NewTerminal(conn *websocket.Conn, log *logger.Logger, terminalId string) (*Terminal, error) {
+func NewTerminal(conn *websocket.Conn, writeLock *sync.Mutex, log *logger.Logger, terminalId string) (*Terminal, error) {
 	ssh_client := sshpkg.NewSSH()
 	terminal := &Terminal{
 		ssh:        ssh_client,
 		conn:       conn,
+		wsLock:     writeLock,
 		done:       make(chan struct{}),
 		outputBuf:  make([]byte, 0, 4096),
 		bufferTime: 10 * time.Millisecond,
@@ -173,7 +185,7 @@ func (t *Terminal) readOutput(r io.Reader) {
 				Data:       string(buf[:n]),
 			}
 			t.wsLock.Lock()
-			err = t.conn.WriteJSON(msg)
+			err = writeJSON(t.conn, msg)
 			t.wsLock.Unlock()
 
 			if err != nil {
@@ -208,7 +220,7 @@ func (t *Terminal) flushBuffer() {
 			Type:       "stdout",
 			Data:       string(t.outputBuf),
 		}
-		err := t.conn.WriteJSON(msg)
+		err := writeJSON(t.conn, msg)
 		if err != nil {
 			t.log.Log(logger.Error, "Error writing websocket message", err.Error())
 		}
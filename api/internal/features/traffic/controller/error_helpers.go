@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// trafficErrorStatus maps errors of the traffic service to HTTP statuses.
+func trafficErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrApplicationNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// requestOrganization returns the organization of the request or an unauthorized error.
+func requestOrganization(w http.ResponseWriter, r *http.Request) (uuid.UUID, error) {
+	user := utils.GetUser(w, r)
+	if user == nil {
+		return uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(r)
+	if organizationID == uuid.Nil {
+		return uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+	return organizationID, nil
+}
+
+// parseQuery reads the application and time range shared by the traffic queries. Missing
+// values are left zero for the validator to fill in.
+func parseQuery(f fuego.ContextNoBody) (uuid.UUID, time.Time, time.Time, error) {
+	var applicationID uuid.UUID
+	if id := f.QueryParam("application_id"); id != "" {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return uuid.Nil, time.Time{}, time.Time{}, types.ErrInvalidApplicationID
+		}
+		applicationID = parsed
+	}
+
+	var times [2]time.Time
+	for i, name := range []string{"start_time", "end_time"} {
+		value := f.QueryParam(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return uuid.Nil, time.Time{}, time.Time{}, types.ErrInvalidTime
+		}
+		times[i] = parsed
+	}
+	return applicationID, times[0], times[1], nil
+}
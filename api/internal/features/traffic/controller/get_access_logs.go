@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetAccessLogs searches the access logs of an application that are still retained.
+func (c *TrafficController) GetAccessLogs(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	applicationID, startTime, endTime, err := parseQuery(f)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+	page, _ := strconv.Atoi(f.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(f.QueryParam("page_size"))
+	request := types.GetAccessLogsRequest{
+		ApplicationID: applicationID,
+		StartTime:     startTime,
+		EndTime:       endTime,
+		Status:        f.QueryParam("status"),
+		Method:        f.QueryParam("method"),
+		SearchTerm:    f.QueryParam("search_term"),
+		Page:          page,
+		PageSize:      pageSize,
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID, err := requestOrganization(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := c.service.GetAccessLogs(&request, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get access logs", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: trafficErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Access logs fetched successfully",
+		Data:    logs,
+	}, nil
+}
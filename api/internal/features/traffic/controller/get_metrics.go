@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetMetrics returns the requests, status classes and latency percentiles of an application
+// per step of minutes, for charts.
+func (c *TrafficController) GetMetrics(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	applicationID, startTime, endTime, err := parseQuery(f)
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+	request := types.GetMetricsRequest{
+		ApplicationID: applicationID,
+		StartTime:     startTime,
+		EndTime:       endTime,
+	}
+	if step := f.QueryParam("step"); step != "" {
+		request.Step, err = strconv.Atoi(step)
+		if err != nil {
+			return nil, fuego.HTTPError{
+				Err:    types.ErrInvalidStep,
+				Status: http.StatusBadRequest,
+			}
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	organizationID, err := requestOrganization(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := c.service.GetMetrics(&request, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get HTTP metrics", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: trafficErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "HTTP metrics fetched successfully",
+		Data: map[string]interface{}{
+			"start_time": request.StartTime,
+			"end_time":   request.EndTime,
+			"step":       request.Step,
+			"metrics":    metrics,
+		},
+	}, nil
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/validation"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type TrafficController struct {
+	store     *shared_storage.Store
+	validator *validation.Validator
+	service   *service.TrafficService
+	ctx       context.Context
+	logger    logger.Logger
+}
+
+func NewTrafficController(
+	store *shared_storage.Store,
+	ctx context.Context,
+	l logger.Logger,
+) *TrafficController {
+	storage := storage.TrafficStorage{DB: store.DB, Ctx: ctx}
+	trafficService := service.NewTrafficService(store, ctx, l, &storage)
+	trafficService.StartIngestion(ctx)
+
+	return &TrafficController{
+		store:     store,
+		validator: validation.NewValidator(),
+		service:   trafficService,
+		ctx:       ctx,
+		logger:    l,
+	}
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/proxy"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	// housekeepingInterval is how often the access logger of the proxy is synced with the
+	// domains, metrics are rolled up and expired data is deleted.
+	housekeepingInterval = time.Minute
+	// rollUpWindow is how far back metrics are rolled up again, so requests logged late are
+	// counted in their minute.
+	rollUpWindow = 5 * time.Minute
+	// ingestQueueSize is how many access logs wait to be written before new ones are dropped,
+	// so a slow database never blocks the proxy sending them.
+	ingestQueueSize     = 10000
+	ingestBatchSize     = 500
+	ingestFlushInterval = 2 * time.Second
+	// maxAccessLogLine is the longest access log line accepted, longer lines are skipped.
+	maxAccessLogLine = 1 << 20
+
+	defaultAccessLogRetentionHours  = 72
+	defaultHTTPMetricsRetentionDays = 30
+)
+
+// caddyAccessLog is the part of an access log entry of Caddy that is kept.
+type caddyAccessLog struct {
+	Timestamp float64 `json:"ts"`
+	Request   struct {
+		RemoteIP string              `json:"remote_ip"`
+		ClientIP string              `json:"client_ip"`
+		Proto    string              `json:"proto"`
+		Method   string              `json:"method"`
+		Host     string              `json:"host"`
+		URI      string              `json:"uri"`
+		Headers  map[string][]string `json:"headers"`
+	} `json:"request"`
+	Duration float64 `json:"duration"`
+	Size     int64   `json:"size"`
+	Status   int     `json:"status"`
+}
+
+// StartIngestion accepts the access logs Caddy sends to ACCESS_LOG_PORT and keeps the access
+// logger of Caddy in sync with the domains of the applications. Every minute the metrics of
+// the last minutes are rolled up from the access logs and expired logs and metrics are deleted.
+// It stops when ctx is done.
+//
+// The access logs are not authenticated, so the listener binds to ACCESS_LOG_LISTEN_HOST and
+// only accepts connections from Caddy, see accessLogPeers.
+func (s *TrafficService) StartIngestion(ctx context.Context) {
+	port := config.AppConfig.Proxy.AccessLogPort
+	if port == "" {
+		// remove the logger in case access logs were turned off
+		go s.syncProxy()
+		return
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(config.AppConfig.Proxy.AccessLogListenHost, port))
+	if err != nil {
+		s.logger.Log(logger.Error, "Failed to listen for access logs", err.Error())
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	queue := make(chan shared_types.AccessLog, ingestQueueSize)
+	var dropped atomic.Int64
+	go s.acceptAccessLogs(listener, queue, &dropped)
+	go s.writeAccessLogs(ctx, queue)
+
+	go func() {
+		s.housekeeping(time.Now().Add(-time.Hour), &dropped)
+
+		ticker := time.NewTicker(housekeepingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.housekeeping(time.Now().Add(-rollUpWindow), &dropped)
+			}
+		}
+	}()
+}
+
+func (s *TrafficService) acceptAccessLogs(listener net.Listener, queue chan<- shared_types.AccessLog, dropped *atomic.Int64) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			s.logger.Log(logger.Error, "Failed to accept access log connection", err.Error())
+			continue
+		}
+		if !s.acceptedPeer(conn.RemoteAddr()) {
+			conn.Close()
+			continue
+		}
+		go s.readAccessLogs(conn, queue, dropped)
+	}
+}
+
+// acceptedPeer reports whether access logs are accepted from addr. Peers are resolved for each
+// connection, as the address of Caddy changes when its container is recreated.
+func (s *TrafficService) acceptedPeer(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	peers, err := accessLogPeers(config.AppConfig.Proxy.CaddyEndpoint, config.AppConfig.Proxy.AccessLogAllowedIPs)
+	if err != nil {
+		s.logger.Log(logger.Warning, "Failed to resolve the addresses of the proxy", err.Error())
+	}
+	for _, peer := range peers {
+		if peer.Contains(tcp.IP) {
+			return true
+		}
+	}
+	s.logger.Log(logger.Warning, "Refused access logs from an address that is not the proxy", tcp.IP.String())
+	return false
+}
+
+// accessLogPeers returns the networks access logs are accepted from: the addresses of the host
+// of the Caddy admin endpoint and the addresses and ranges of allowed. Anyone else could forge
+// the traffic of applications, and keep applications scaled to zero awake.
+func accessLogPeers(caddyEndpoint string, allowed string) ([]*net.IPNet, error) {
+	var peers []*net.IPNet
+	for _, entry := range strings.Split(allowed, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				peers = append(peers, hostNetwork(ip))
+			}
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			peers = append(peers, network)
+		}
+	}
+
+	endpoint, err := url.Parse(caddyEndpoint)
+	if err != nil || endpoint.Hostname() == "" {
+		return peers, err
+	}
+	ips, err := net.LookupIP(endpoint.Hostname())
+	for _, ip := range ips {
+		peers = append(peers, hostNetwork(ip))
+	}
+	return peers, err
+}
+
+func hostNetwork(ip net.IP) *net.IPNet {
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// readAccessLogs reads the JSON lines of a connection of the proxy. Requests that cannot be
+// attributed to an application, like those of the API itself, are skipped.
+func (s *TrafficService) readAccessLogs(conn net.Conn, queue chan<- shared_types.AccessLog, dropped *atomic.Int64) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAccessLogLine)
+	for scanner.Scan() {
+		var entry caddyAccessLog
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		log, ok := s.accessLog(entry)
+		if !ok {
+			continue
+		}
+		select {
+		case queue <- log:
+		default:
+			dropped.Add(1)
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		s.logger.Log(logger.Warning, "Access log connection closed", err.Error())
+	}
+}
+
+func (s *TrafficService) accessLog(entry caddyAccessLog) (shared_types.AccessLog, bool) {
+	s.routesMu.RLock()
+	applicationID, ok := s.routes.application(entry.Request.Host, entry.Request.URI)
+	s.routesMu.RUnlock()
+	if !ok {
+		return shared_types.AccessLog{}, false
+	}
+
+	seconds, fraction := math.Modf(entry.Timestamp)
+	remoteIP := entry.Request.ClientIP
+	if remoteIP == "" {
+		remoteIP = entry.Request.RemoteIP
+	}
+	var userAgent string
+	if values := entry.Request.Headers["User-Agent"]; len(values) > 0 {
+		userAgent = values[0]
+	}
+	return shared_types.AccessLog{
+		ID:            uuid.New(),
+		ApplicationID: applicationID,
+		Timestamp:     time.Unix(int64(seconds), int64(fraction*1e9)),
+		Method:        entry.Request.Method,
+		Host:          entry.Request.Host,
+		URI:           entry.Request.URI,
+		Protocol:      entry.Request.Proto,
+		Status:        entry.Status,
+		DurationMs:    entry.Duration * 1000,
+		Size:          entry.Size,
+		RemoteIP:      remoteIP,
+		UserAgent:     userAgent,
+	}, true
+}
+
+// writeAccessLogs inserts the queued access logs in batches, once a batch is full or every
+// ingestFlushInterval.
+func (s *TrafficService) writeAccessLogs(ctx context.Context, queue <-chan shared_types.AccessLog) {
+	ticker := time.NewTicker(ingestFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]shared_types.AccessLog, 0, ingestBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.storage.InsertAccessLogs(batch); err != nil {
+			s.logger.Log(logger.Error, "Failed to store access logs", err.Error())
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return
+		case log := <-queue:
+			batch = append(batch, log)
+			if len(batch) >= ingestBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// housekeeping syncs the proxy, rolls up the metrics of the minutes since from and deletes the
+// access logs and metrics past their retention.
+func (s *TrafficService) housekeeping(from time.Time, dropped *atomic.Int64) {
+	s.syncProxy()
+
+	if n := dropped.Swap(0); n > 0 {
+		s.logger.Log(logger.Warning, "Dropped access logs, the database cannot keep up", strconv.FormatInt(n, 10))
+	}
+
+	now := time.Now()
+	if err := s.storage.RollUpMetrics(from.Truncate(time.Minute), now); err != nil {
+		s.logger.Log(logger.Error, "Failed to roll up HTTP metrics", err.Error())
+	}
+
+	if err := s.storage.DeleteAccessLogsBefore(now.Add(-accessLogRetention())); err != nil {
+		s.logger.Log(logger.Error, "Failed to delete expired access logs", err.Error())
+	}
+
+	days := config.AppConfig.Proxy.HTTPMetricsRetentionDays
+	if days <= 0 {
+		days = defaultHTTPMetricsRetentionDays
+	}
+	if err := s.storage.DeleteMetricsBefore(now.AddDate(0, 0, -days)); err != nil {
+		s.logger.Log(logger.Error, "Failed to delete expired HTTP metrics", err.Error())
+	}
+}
+
+func accessLogRetention() time.Duration {
+	hours := config.AppConfig.Proxy.AccessLogRetentionHours
+	if hours <= 0 {
+		hours = defaultAccessLogRetentionHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// syncProxy reloads the domains requests are attributed to and maps their hosts to the access
+// logger of Caddy.
+func (s *TrafficService) syncProxy() {
+	domains, err := s.storage.GetRoutedDomains()
+	if err != nil {
+		s.logger.Log(logger.Error, "Failed to get routed domains", err.Error())
+		return
+	}
+	routes := newDomainRoutes(domains)
+	s.routesMu.Lock()
+	s.routes = routes
+	s.routesMu.Unlock()
+
+	var address string
+	if port := config.AppConfig.Proxy.AccessLogPort; port != "" {
+		host := config.AppConfig.Proxy.AccessLogListenHost
+		if host == "" {
+			host = config.AppConfig.SSH.Host
+		}
+		address = net.JoinHostPort(host, port)
+	}
+	if err := proxy.NewCaddy(&s.logger, "", "", "", proxy.ReverseProxy).SyncAccessLogs(address, routes.names()); err != nil {
+		s.logger.Log(logger.Error, "Failed to sync the access logs of the proxy", err.Error())
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	applicationID := uuid.New()
+	s := &TrafficService{routes: newDomainRoutes([]types.RoutedDomain{{ApplicationID: applicationID, Name: "example.com"}})}
+
+	tests := []struct {
+		name         string
+		line         string
+		ok           bool
+		wantRemoteIP string
+		wantAgent    string
+	}{
+		{
+			name:         "Client IP is preferred",
+			line:         `{"ts":1700000000.25,"request":{"remote_ip":"10.0.0.2","client_ip":"203.0.113.7","proto":"HTTP/2.0","method":"GET","host":"example.com","uri":"/a?b=c","headers":{"User-Agent":["curl/8.0"]}},"duration":0.0125,"size":512,"status":200}`,
+			ok:           true,
+			wantRemoteIP: "203.0.113.7",
+			wantAgent:    "curl/8.0",
+		},
+		{
+			name:         "Remote IP without client IP",
+			line:         `{"ts":1700000000.25,"request":{"remote_ip":"10.0.0.2","proto":"HTTP/2.0","method":"GET","host":"example.com","uri":"/a?b=c"},"duration":0.0125,"size":512,"status":200}`,
+			ok:           true,
+			wantRemoteIP: "10.0.0.2",
+		},
+		{
+			name: "Request of another host",
+			line: `{"ts":1700000000.25,"request":{"host":"api.nixopus.com","uri":"/"},"status":200}`,
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entry caddyAccessLog
+			require.NoError(t, json.Unmarshal([]byte(tt.line), &entry))
+
+			log, ok := s.accessLog(entry)
+			assert.Equal(t, tt.ok, ok)
+			if !tt.ok {
+				return
+			}
+			assert.Equal(t, applicationID, log.ApplicationID)
+			assert.Equal(t, time.Unix(1700000000, 250000000), log.Timestamp)
+			assert.Equal(t, "GET", log.Method)
+			assert.Equal(t, "example.com", log.Host)
+			assert.Equal(t, "/a?b=c", log.URI)
+			assert.Equal(t, "HTTP/2.0", log.Protocol)
+			assert.Equal(t, 200, log.Status)
+			assert.InDelta(t, 12.5, log.DurationMs, 1e-9)
+			assert.Equal(t, int64(512), log.Size)
+			assert.Equal(t, tt.wantRemoteIP, log.RemoteIP)
+			assert.Equal(t, tt.wantAgent, log.UserAgent)
+		})
+	}
+}
+
+func TestAccessLogRetention(t *testing.T) {
+	previous := config.AppConfig.Proxy.AccessLogRetentionHours
+	defer func() { config.AppConfig.Proxy.AccessLogRetentionHours = previous }()
+
+	config.AppConfig.Proxy.AccessLogRetentionHours = 0
+	assert.Equal(t, defaultAccessLogRetentionHours*time.Hour, accessLogRetention())
+
+	config.AppConfig.Proxy.AccessLogRetentionHours = 6
+	assert.Equal(t, 6*time.Hour, accessLogRetention())
+}
+
+func TestAccessLogPeers(t *testing.T) {
+	tests := []struct {
+		name          string
+		caddyEndpoint string
+		allowed       string
+		accepted      []string
+		refused       []string
+	}{
+		{
+			name:          "Caddy on loopback",
+			caddyEndpoint: "http://127.0.0.1:2019",
+			accepted:      []string{"127.0.0.1"},
+			refused:       []string{"127.0.0.2", "203.0.113.7", "::1"},
+		},
+		{
+			name:          "Allowed addresses and ranges",
+			caddyEndpoint: "http://[::1]:2019",
+			allowed:       "203.0.113.7, 172.18.0.0/16,not-an-ip",
+			accepted:      []string{"::1", "203.0.113.7", "172.18.4.2"},
+			refused:       []string{"203.0.113.8", "172.19.0.1", "127.0.0.1"},
+		},
+		{
+			name:     "No endpoint",
+			allowed:  "10.0.0.5",
+			accepted: []string{"10.0.0.5"},
+			refused:  []string{"10.0.0.6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peers, err := accessLogPeers(tt.caddyEndpoint, tt.allowed)
+			require.NoError(t, err)
+			contains := func(ip string) bool {
+				for _, peer := range peers {
+					if peer.Contains(net.ParseIP(ip)) {
+						return true
+					}
+				}
+				return false
+			}
+			for _, ip := range tt.accepted {
+				assert.True(t, contains(ip), ip)
+			}
+			for _, ip := range tt.refused {
+				assert.False(t, contains(ip), ip)
+			}
+		})
+	}
+}
+
+func TestAcceptAccessLogs(t *testing.T) {
+	previous := config.AppConfig.Proxy
+	defer func() { config.AppConfig.Proxy = previous }()
+
+	line := `{"ts":1700000000.25,"request":{"remote_ip":"10.0.0.2","proto":"HTTP/1.1","method":"GET","host":"example.com","uri":"/"},"duration":0.01,"size":1,"status":200}`
+	tests := []struct {
+		name          string
+		caddyEndpoint string
+		want          int
+	}{
+		{name: "Connection of the proxy", caddyEndpoint: "http://127.0.0.1:2019", want: 1},
+		{name: "Connection of anyone else", caddyEndpoint: "http://192.0.2.1:2019", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig.Proxy.CaddyEndpoint = tt.caddyEndpoint
+			config.AppConfig.Proxy.AccessLogAllowedIPs = ""
+
+			s := &TrafficService{
+				logger: logger.NewLogger(),
+				routes: newDomainRoutes([]types.RoutedDomain{{ApplicationID: uuid.New(), Name: "example.com"}}),
+			}
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			queue := make(chan shared_types.AccessLog, 1)
+			var dropped atomic.Int64
+			go s.acceptAccessLogs(listener, queue, &dropped)
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			fmt.Fprintln(conn, line)
+			conn.Close()
+
+			select {
+			case <-queue:
+				assert.Equal(t, 1, tt.want)
+			case <-time.After(200 * time.Millisecond):
+				assert.Equal(t, 0, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/storage"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type TrafficService struct {
+	storage storage.TrafficStorageInterface
+	Ctx     context.Context
+	store   *shared_storage.Store
+	logger  logger.Logger
+	// routes attributes ingested requests to applications, it is rebuilt every minute
+	routes   *domainRoutes
+	routesMu sync.RWMutex
+}
+
+func NewTrafficService(
+	store *shared_storage.Store,
+	ctx context.Context,
+	logger logger.Logger,
+	traffic_repo storage.TrafficStorageInterface,
+) *TrafficService {
+	return &TrafficService{
+		storage: traffic_repo,
+		store:   store,
+		Ctx:     ctx,
+		logger:  logger,
+		routes:  newDomainRoutes(nil),
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+)
+
+// domainRoutes attributes requests to the applications routed on their host, the way Caddy
+// routes them: the longest path prefix matching the path wins.
+type domainRoutes struct {
+	hosts map[string][]types.RoutedDomain
+}
+
+func newDomainRoutes(domains []types.RoutedDomain) *domainRoutes {
+	routes := &domainRoutes{hosts: make(map[string][]types.RoutedDomain)}
+	for _, domain := range domains {
+		name := strings.ToLower(domain.Name)
+		routes.hosts[name] = append(routes.hosts[name], domain)
+		if domain.WWWRedirect {
+			alias := wwwAlias(name)
+			routes.hosts[alias] = append(routes.hosts[alias], domain)
+		}
+	}
+	for _, candidates := range routes.hosts {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return len(candidates[i].PathPrefix) > len(candidates[j].PathPrefix)
+		})
+	}
+	return routes
+}
+
+// application returns the application serving uri on host.
+func (r *domainRoutes) application(host string, uri string) (uuid.UUID, bool) {
+	host = strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	path, _, _ := strings.Cut(uri, "?")
+
+	for _, domain := range r.hosts[host] {
+		prefix := domain.PathPrefix
+		if prefix == "" || path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return domain.ApplicationID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// names returns the hosts of the routed domains, sorted.
+func (r *domainRoutes) names() []string {
+	names := make([]string, 0, len(r.hosts))
+	for host := range r.hosts {
+		names = append(names, host)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func wwwAlias(domain string) string {
+	if bare, ok := strings.CutPrefix(domain, "www."); ok {
+		return bare
+	}
+	return "www." + domain
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainRoutesApplication(t *testing.T) {
+	site := uuid.New()
+	api := uuid.New()
+	docs := uuid.New()
+	routes := newDomainRoutes([]types.RoutedDomain{
+		{ApplicationID: site, Name: "Example.com", WWWRedirect: true},
+		{ApplicationID: api, Name: "example.com", PathPrefix: "/api"},
+		{ApplicationID: docs, Name: "example.com", PathPrefix: "/api/docs/"},
+	})
+
+	tests := []struct {
+		name string
+		host string
+		uri  string
+		want uuid.UUID
+		ok   bool
+	}{
+		{name: "Root", host: "example.com", uri: "/", want: site, ok: true},
+		{name: "Host is case insensitive", host: "EXAMPLE.com", uri: "/about", want: site, ok: true},
+		{name: "Port is ignored", host: "example.com:443", uri: "/", want: site, ok: true},
+		{name: "WWW alias", host: "www.example.com", uri: "/", want: site, ok: true},
+		{name: "Path prefix", host: "example.com", uri: "/api/users", want: api, ok: true},
+		{name: "Path equal to prefix", host: "example.com", uri: "/api", want: api, ok: true},
+		{name: "Query is ignored", host: "example.com", uri: "/api?q=/api/docs/", want: api, ok: true},
+		{name: "Longest prefix wins", host: "example.com", uri: "/api/docs/intro", want: docs, ok: true},
+		{name: "Prefix is matched on segments", host: "example.com", uri: "/apis", want: site, ok: true},
+		{name: "Unknown host", host: "other.com", uri: "/", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := routes.application(tt.host, tt.uri)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDomainRoutesNames(t *testing.T) {
+	routes := newDomainRoutes([]types.RoutedDomain{
+		{ApplicationID: uuid.New(), Name: "www.b.com", WWWRedirect: true},
+		{ApplicationID: uuid.New(), Name: "a.com"},
+		{ApplicationID: uuid.New(), Name: "a.com", PathPrefix: "/api"},
+	})
+	assert.Equal(t, []string{"a.com", "b.com", "www.b.com"}, routes.names())
+}
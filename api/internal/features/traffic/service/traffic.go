@@ -0,0 +1,91 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetMetrics returns the HTTP metrics of an application of the organization. Steps without
+// requests are left out. The latencies of steps longer than a minute are computed from the
+// access logs when they are still kept and marked as approximate otherwise.
+func (s *TrafficService) GetMetrics(request *types.GetMetricsRequest, organizationID uuid.UUID) ([]shared_types.HTTPMetric, error) {
+	if err := s.checkApplication(request.ApplicationID, organizationID); err != nil {
+		return nil, err
+	}
+	metrics, err := s.storage.GetMetrics(request)
+	if err != nil || request.Step <= 1 || len(metrics) == 0 {
+		return metrics, err
+	}
+
+	exactFrom := firstRetainedStep(time.Now().Add(-accessLogRetention()), request.Step)
+	var latencies []types.StepLatency
+	if exactFrom.Before(request.EndTime) {
+		latencies, err = s.storage.GetStepLatencies(request, exactFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+	mergeStepLatencies(metrics, latencies, exactFrom)
+	return metrics, nil
+}
+
+// firstRetainedStep returns the start of the first step of step minutes whose access logs are
+// all kept, the access logs before retainedFrom being deleted.
+func firstRetainedStep(retainedFrom time.Time, step int) time.Time {
+	seconds := int64(step) * 60
+	start := retainedFrom.Unix() / seconds * seconds
+	if start < retainedFrom.Unix() || retainedFrom.Nanosecond() > 0 {
+		start += seconds
+	}
+	return time.Unix(start, 0)
+}
+
+// mergeStepLatencies replaces the approximate latencies of the steps starting at exactFrom or
+// later with the ones computed from their access logs and marks the older steps as approximate.
+func mergeStepLatencies(metrics []shared_types.HTTPMetric, latencies []types.StepLatency, exactFrom time.Time) {
+	exact := make(map[int64]types.StepLatency, len(latencies))
+	for _, latency := range latencies {
+		exact[latency.Minute.Unix()] = latency
+	}
+	for i := range metrics {
+		if metrics[i].Minute.Before(exactFrom) {
+			metrics[i].LatencyApproximate = true
+			continue
+		}
+		if latency, ok := exact[metrics[i].Minute.Unix()]; ok {
+			metrics[i].P50Ms = latency.P50Ms
+			metrics[i].P95Ms = latency.P95Ms
+		}
+	}
+}
+
+// GetAccessLogs searches the access logs of an application of the organization.
+func (s *TrafficService) GetAccessLogs(request *types.GetAccessLogsRequest, organizationID uuid.UUID) (*types.AccessLogPage, error) {
+	if err := s.checkApplication(request.ApplicationID, organizationID); err != nil {
+		return nil, err
+	}
+	logs, totalCount, err := s.storage.SearchAccessLogs(request)
+	if err != nil {
+		return nil, err
+	}
+	return &types.AccessLogPage{
+		Logs:       logs,
+		TotalCount: totalCount,
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+	}, nil
+}
+
+func (s *TrafficService) checkApplication(applicationID uuid.UUID, organizationID uuid.UUID) error {
+	exists, err := s.storage.IsOrganizationApplication(applicationID, organizationID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrApplicationNotFound
+	}
+	return nil
+}
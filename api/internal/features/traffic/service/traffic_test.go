@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstRetainedStep(t *testing.T) {
+	tests := []struct {
+		name         string
+		retainedFrom time.Time
+		step         int
+		want         time.Time
+	}{
+		{name: "On a step boundary", retainedFrom: time.Unix(3600, 0), step: 60, want: time.Unix(3600, 0)},
+		{name: "Inside a step", retainedFrom: time.Unix(3601, 0), step: 60, want: time.Unix(7200, 0)},
+		{name: "Fraction of a second past a boundary", retainedFrom: time.Unix(3600, 1), step: 60, want: time.Unix(7200, 0)},
+		{name: "Step not dividing an hour", retainedFrom: time.Unix(1000, 0), step: 7, want: time.Unix(1260, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(firstRetainedStep(tt.retainedFrom, tt.step)))
+		})
+	}
+}
+
+func TestMergeStepLatencies(t *testing.T) {
+	step := func(hour int64) time.Time { return time.Unix(hour*3600, 0) }
+	metrics := []shared_types.HTTPMetric{
+		{Minute: step(1), P50Ms: 10, P95Ms: 100},
+		{Minute: step(2), P50Ms: 20, P95Ms: 200},
+		{Minute: step(3), P50Ms: 30, P95Ms: 300},
+	}
+	latencies := []types.StepLatency{
+		// scanned in another time zone than the metrics
+		{Minute: step(2).In(time.FixedZone("UTC+2", 2*3600)), P50Ms: 21, P95Ms: 250},
+	}
+
+	mergeStepLatencies(metrics, latencies, step(2))
+
+	assert.True(t, metrics[0].LatencyApproximate)
+	assert.Equal(t, 10.0, metrics[0].P50Ms)
+	assert.Equal(t, 100.0, metrics[0].P95Ms)
+
+	assert.False(t, metrics[1].LatencyApproximate)
+	assert.Equal(t, 21.0, metrics[1].P50Ms)
+	assert.Equal(t, 250.0, metrics[1].P95Ms)
+
+	// logged since the latencies were computed, the metric keeps its own
+	assert.False(t, metrics[2].LatencyApproximate)
+	assert.Equal(t, 30.0, metrics[2].P50Ms)
+}
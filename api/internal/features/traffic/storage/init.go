@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/uptrace/bun"
+)
+
+type TrafficStorage struct {
+	DB  *bun.DB
+	Ctx context.Context
+}
+
+type TrafficStorageInterface interface {
+	GetRoutedDomains() ([]types.RoutedDomain, error)
+	IsOrganizationApplication(applicationID uuid.UUID, organizationID uuid.UUID) (bool, error)
+	InsertAccessLogs(logs []shared_types.AccessLog) error
+	RollUpMetrics(from time.Time, to time.Time) error
+	GetMetrics(request *types.GetMetricsRequest) ([]shared_types.HTTPMetric, error)
+	GetStepLatencies(request *types.GetMetricsRequest, from time.Time) ([]types.StepLatency, error)
+	SearchAccessLogs(request *types.GetAccessLogsRequest) ([]shared_types.AccessLog, int, error)
+	DeleteAccessLogsBefore(before time.Time) error
+	DeleteMetricsBefore(before time.Time) error
+}
+
+// GetRoutedDomains returns the domains of the applications served by Caddy, the only proxy
+// whose access logs are ingested.
+func (s *TrafficStorage) GetRoutedDomains() ([]types.RoutedDomain, error) {
+	var domains []types.RoutedDomain
+	err := s.DB.NewSelect().
+		TableExpr("application_domains AS apd").
+		ColumnExpr("apd.application_id, apd.name, apd.path_prefix").
+		ColumnExpr("COALESCE((a.proxy_settings->>'www_redirect')::boolean, false) AS www_redirect").
+		Join("JOIN applications AS a ON a.id = apd.application_id").
+		Where("a.proxy_server = ?", shared_types.Caddy).
+		Scan(s.Ctx, &domains)
+	return domains, err
+}
+
+func (s *TrafficStorage) IsOrganizationApplication(applicationID uuid.UUID, organizationID uuid.UUID) (bool, error) {
+	return s.DB.NewSelect().
+		Table("applications").
+		Where("id = ?", applicationID).
+		Where("organization_id = ?", organizationID).
+		Exists(s.Ctx)
+}
+
+func (s *TrafficStorage) InsertAccessLogs(logs []shared_types.AccessLog) error {
+	_, err := s.DB.NewInsert().Model(&logs).Exec(s.Ctx)
+	return err
+}
+
+// RollUpMetrics computes the metrics of the minutes between from and to from the access logs,
+// replacing the metrics computed before for these minutes.
+func (s *TrafficStorage) RollUpMetrics(from time.Time, to time.Time) error {
+	_, err := s.DB.NewRaw(`
+		INSERT INTO application_http_metrics (application_id, minute, requests, status_2xx, status_4xx, status_5xx, p50_ms, p95_ms)
+		SELECT application_id, date_trunc('minute', timestamp), COUNT(*),
+			COUNT(*) FILTER (WHERE status BETWEEN 200 AND 299),
+			COUNT(*) FILTER (WHERE status BETWEEN 400 AND 499),
+			COUNT(*) FILTER (WHERE status BETWEEN 500 AND 599),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_ms),
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms)
+		FROM access_logs
+		WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY application_id, date_trunc('minute', timestamp)
+		ON CONFLICT (application_id, minute) DO UPDATE SET
+			requests = EXCLUDED.requests,
+			status_2xx = EXCLUDED.status_2xx,
+			status_4xx = EXCLUDED.status_4xx,
+			status_5xx = EXCLUDED.status_5xx,
+			p50_ms = EXCLUDED.p50_ms,
+			p95_ms = EXCLUDED.p95_ms`, from, to).
+		Exec(s.Ctx)
+	return err
+}
+
+// GetMetrics sums up the metrics of the application over steps of request.Step minutes. The
+// latencies of a step are the averages of the percentiles of its minutes, weighted by their
+// requests, which only approximates the percentiles of the step. GetStepLatencies computes
+// them exactly while the access logs are kept.
+func (s *TrafficStorage) GetMetrics(request *types.GetMetricsRequest) ([]shared_types.HTTPMetric, error) {
+	step := request.Step * 60
+	metrics := []shared_types.HTTPMetric{}
+	err := s.DB.NewSelect().
+		Model((*shared_types.HTTPMetric)(nil)).
+		ColumnExpr("application_id").
+		ColumnExpr("to_timestamp(floor(extract(epoch FROM minute) / ?) * ?) AS minute", step, step).
+		ColumnExpr("SUM(requests) AS requests").
+		ColumnExpr("SUM(status_2xx) AS status_2xx").
+		ColumnExpr("SUM(status_4xx) AS status_4xx").
+		ColumnExpr("SUM(status_5xx) AS status_5xx").
+		ColumnExpr("COALESCE(SUM(p50_ms * requests) / NULLIF(SUM(requests), 0), 0) AS p50_ms").
+		ColumnExpr("COALESCE(SUM(p95_ms * requests) / NULLIF(SUM(requests), 0), 0) AS p95_ms").
+		Where("application_id = ?", request.ApplicationID).
+		Where("minute >= ?", request.StartTime).
+		Where("minute < ?", request.EndTime).
+		GroupExpr("application_id, 2").
+		OrderExpr("2").
+		Scan(s.Ctx, &metrics)
+	return metrics, err
+}
+
+// GetStepLatencies computes the latency percentiles of the steps of request.Step minutes from
+// the access logs of the application logged since from. Steps are aligned the same way as the
+// ones of GetMetrics.
+func (s *TrafficStorage) GetStepLatencies(request *types.GetMetricsRequest, from time.Time) ([]types.StepLatency, error) {
+	step := request.Step * 60
+	latencies := []types.StepLatency{}
+	err := s.DB.NewSelect().
+		Model((*shared_types.AccessLog)(nil)).
+		ColumnExpr("to_timestamp(floor(extract(epoch FROM timestamp) / ?) * ?) AS minute", step, step).
+		ColumnExpr("percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_ms) AS p50_ms").
+		ColumnExpr("percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms) AS p95_ms").
+		Where("application_id = ?", request.ApplicationID).
+		Where("timestamp >= ?", from).
+		Where("timestamp >= ?", request.StartTime).
+		Where("timestamp < ?", request.EndTime).
+		GroupExpr("1").
+		OrderExpr("1").
+		Scan(s.Ctx, &latencies)
+	return latencies, err
+}
+
+func (s *TrafficStorage) SearchAccessLogs(request *types.GetAccessLogsRequest) ([]shared_types.AccessLog, int, error) {
+	query := s.DB.NewSelect().
+		Model((*shared_types.AccessLog)(nil)).
+		Where("application_id = ?", request.ApplicationID)
+
+	if !request.StartTime.IsZero() {
+		query = query.Where("timestamp >= ?", request.StartTime)
+	}
+	if !request.EndTime.IsZero() {
+		query = query.Where("timestamp < ?", request.EndTime)
+	}
+	if from, to, _ := types.StatusRange(request.Status); from != 0 {
+		query = query.Where("status BETWEEN ? AND ?", from, to)
+	}
+	if request.Method != "" {
+		query = query.Where("method = ?", request.Method)
+	}
+	if request.SearchTerm != "" {
+		term := "%" + request.SearchTerm + "%"
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("uri ILIKE ?", term).
+				WhereOr("host ILIKE ?", term).
+				WhereOr("user_agent ILIKE ?", term).
+				WhereOr("remote_ip ILIKE ?", term)
+		})
+	}
+
+	totalCount, err := query.Count(s.Ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logs := []shared_types.AccessLog{}
+	err = query.
+		Order("timestamp DESC").
+		Limit(request.PageSize).
+		Offset((request.Page-1)*request.PageSize).
+		Scan(s.Ctx, &logs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, totalCount, nil
+}
+
+func (s *TrafficStorage) DeleteAccessLogsBefore(before time.Time) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.AccessLog)(nil)).
+		Where("timestamp < ?", before).
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *TrafficStorage) DeleteMetricsBefore(before time.Time) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.HTTPMetric)(nil)).
+		Where("minute < ?", before).
+		Exec(s.Ctx)
+	return err
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	deploy_storage "github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+	"github.com/raghavyuva/nixopus-api/internal/testutils"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsStorage(t *testing.T) {
+	setup := testutils.NewTestSetup()
+	trafficStorage := &storage.TrafficStorage{DB: setup.DB, Ctx: setup.Ctx}
+	deployStorage := &deploy_storage.DeployStorage{DB: setup.DB, Ctx: setup.Ctx}
+
+	user, org, err := setup.CreateTestUserAndOrg()
+	require.NoError(t, err)
+	application := &shared_types.Application{
+		ID:             uuid.New(),
+		Name:           "traffic",
+		Port:           3000,
+		Environment:    shared_types.Development,
+		BuildPack:      shared_types.DockerFile,
+		UserID:         user.ID,
+		OrganizationID: org.ID,
+	}
+	require.NoError(t, deployStorage.AddApplication(application))
+
+	// two minutes of an hour: a fast minute of many requests and a slow one of few
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	var logs []shared_types.AccessLog
+	for i := 0; i < 9; i++ {
+		logs = append(logs, accessLog(application.ID, start.Add(time.Duration(i)*time.Second), 200, 10))
+	}
+	logs = append(logs, accessLog(application.ID, start.Add(time.Minute), 500, 1000))
+	logs = append(logs, accessLog(application.ID, start.Add(time.Minute+time.Second), 404, 1000))
+	require.NoError(t, trafficStorage.InsertAccessLogs(logs))
+
+	require.NoError(t, trafficStorage.RollUpMetrics(start, start.Add(time.Hour)))
+	// rolling up again replaces the metrics instead of adding to them
+	require.NoError(t, trafficStorage.RollUpMetrics(start, start.Add(time.Hour)))
+
+	t.Run("Minutes", func(t *testing.T) {
+		metrics, err := trafficStorage.GetMetrics(&types.GetMetricsRequest{
+			ApplicationID: application.ID, StartTime: start, EndTime: start.Add(time.Hour), Step: 1,
+		})
+		require.NoError(t, err)
+		require.Len(t, metrics, 2)
+
+		assert.True(t, start.Equal(metrics[0].Minute))
+		assert.Equal(t, int64(9), metrics[0].Requests)
+		assert.Equal(t, int64(9), metrics[0].Status2xx)
+		assert.Equal(t, 10.0, metrics[0].P50Ms)
+
+		assert.Equal(t, int64(2), metrics[1].Requests)
+		assert.Equal(t, int64(1), metrics[1].Status4xx)
+		assert.Equal(t, int64(1), metrics[1].Status5xx)
+		assert.Equal(t, 1000.0, metrics[1].P95Ms)
+	})
+
+	t.Run("Step", func(t *testing.T) {
+		request := &types.GetMetricsRequest{
+			ApplicationID: application.ID, StartTime: start, EndTime: start.Add(time.Hour), Step: 60,
+		}
+		metrics, err := trafficStorage.GetMetrics(request)
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		assert.Equal(t, int64(11), metrics[0].Requests)
+
+		latencies, err := trafficStorage.GetStepLatencies(request, start)
+		require.NoError(t, err)
+		require.Len(t, latencies, 1)
+		assert.True(t, start.Equal(latencies[0].Minute))
+		assert.Equal(t, 10.0, latencies[0].P50Ms)
+		// the 95th percentile of the hour is the slow minute, the weighted average is not
+		assert.InDelta(t, 1000.0, latencies[0].P95Ms, 1e-9)
+		assert.Less(t, metrics[0].P95Ms, latencies[0].P95Ms)
+	})
+
+	t.Run("StepLatenciesSince", func(t *testing.T) {
+		latencies, err := trafficStorage.GetStepLatencies(&types.GetMetricsRequest{
+			ApplicationID: application.ID, StartTime: start, EndTime: start.Add(time.Hour), Step: 60,
+		}, start.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, latencies)
+	})
+}
+
+func accessLog(applicationID uuid.UUID, timestamp time.Time, status int, durationMs float64) shared_types.AccessLog {
+	return shared_types.AccessLog{
+		ID:            uuid.New(),
+		ApplicationID: applicationID,
+		Timestamp:     timestamp,
+		Method:        "GET",
+		Host:          "example.com",
+		URI:           "/",
+		Protocol:      "HTTP/1.1",
+		Status:        status,
+		DurationMs:    durationMs,
+	}
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+var (
+	ErrInvalidRequestType   = errors.New("invalid request type")
+	ErrMissingApplicationID = errors.New("application_id is required")
+	ErrInvalidApplicationID = errors.New("application_id is not a valid id")
+	ErrInvalidTime          = errors.New("start_time and end_time must be RFC 3339 timestamps")
+	ErrInvalidTimeRange     = errors.New("start_time must be before end_time")
+	ErrInvalidStep          = errors.New("step must be between 1 and 1440 minutes")
+	ErrTooManyPoints        = errors.New("time range and step give more than 1440 points, use a larger step")
+	ErrInvalidStatus        = errors.New("status must be a status code like 404 or a class like 5xx")
+	ErrInvalidPage          = errors.New("page and page_size must be positive, page_size at most 500")
+	ErrApplicationNotFound  = errors.New("application not found")
+)
+
+const (
+	// MaxMetricPoints is the largest number of points a metrics query returns.
+	MaxMetricPoints = 1440
+	// MaxAccessLogPageSize is the largest page of access logs a search returns.
+	MaxAccessLogPageSize = 500
+)
+
+// GetMetricsRequest queries the HTTP metrics of an application between StartTime and EndTime,
+// summed up over Step minutes.
+type GetMetricsRequest struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Step          int       `json:"step"`
+}
+
+// GetAccessLogsRequest searches the access logs of an application, newest first. Status is a
+// status code or a class like 5xx, SearchTerm is matched against the URI, host and user agent.
+type GetAccessLogsRequest struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Status        string    `json:"status"`
+	Method        string    `json:"method"`
+	SearchTerm    string    `json:"search_term"`
+	Page          int       `json:"page"`
+	PageSize      int       `json:"page_size"`
+}
+
+// AccessLogPage is a page of access logs along with the number of logs matching the search.
+type AccessLogPage struct {
+	Logs       []shared_types.AccessLog `json:"logs"`
+	TotalCount int                      `json:"total_count"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"page_size"`
+}
+
+// StatusRange returns the status codes matched by status, a status code or a class like 5xx.
+// Both are zero for an empty status, which matches any status.
+func StatusRange(status string) (int, int, error) {
+	if status == "" {
+		return 0, 0, nil
+	}
+	if len(status) == 3 && strings.HasSuffix(strings.ToLower(status), "xx") && status[0] >= '1' && status[0] <= '5' {
+		from := int(status[0]-'0') * 100
+		return from, from + 99, nil
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil || code < 100 || code > 599 {
+		return 0, 0, ErrInvalidStatus
+	}
+	return code, code, nil
+}
+
+// RoutedDomain is a domain of an application served by Caddy, which its access logs are
+// attributed to by host and path prefix.
+type RoutedDomain struct {
+	ApplicationID uuid.UUID `bun:"application_id"`
+	Name          string    `bun:"name"`
+	PathPrefix    string    `bun:"path_prefix"`
+	WWWRedirect   bool      `bun:"www_redirect"`
+}
+
+// StepLatency holds the latency percentiles of a step of a metrics query, computed from the
+// access logs of the step.
+type StepLatency struct {
+	Minute time.Time `bun:"minute"`
+	P50Ms  float64   `bun:"p50_ms"`
+	P95Ms  float64   `bun:"p95_ms"`
+}
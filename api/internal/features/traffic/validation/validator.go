@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/traffic/types"
+)
+
+// Validator handles traffic validation logic
+type Validator struct {
+}
+
+// NewValidator creates a new validator instance
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateRequest validates different traffic request types and fills in their defaults
+func (v *Validator) ValidateRequest(req interface{}) error {
+	switch r := req.(type) {
+	case *types.GetMetricsRequest:
+		return validateGetMetricsRequest(r)
+	case *types.GetAccessLogsRequest:
+		return validateGetAccessLogsRequest(r)
+	default:
+		return types.ErrInvalidRequestType
+	}
+}
+
+// validateGetMetricsRequest defaults to the last hour, and to the smallest step that keeps the
+// number of points within types.MaxMetricPoints.
+func validateGetMetricsRequest(req *types.GetMetricsRequest) error {
+	if req.ApplicationID == uuid.Nil {
+		return types.ErrMissingApplicationID
+	}
+	if req.EndTime.IsZero() {
+		req.EndTime = time.Now()
+	}
+	if req.StartTime.IsZero() {
+		req.StartTime = req.EndTime.Add(-time.Hour)
+	}
+	if !req.StartTime.Before(req.EndTime) {
+		return types.ErrInvalidTimeRange
+	}
+
+	minutes := int(req.EndTime.Sub(req.StartTime).Minutes())
+	if req.Step == 0 {
+		req.Step = (minutes + types.MaxMetricPoints - 1) / types.MaxMetricPoints
+		if req.Step == 0 {
+			req.Step = 1
+		}
+	}
+	if req.Step < 1 || req.Step > 1440 {
+		return types.ErrInvalidStep
+	}
+	if minutes/req.Step > types.MaxMetricPoints {
+		return types.ErrTooManyPoints
+	}
+	return nil
+}
+
+func validateGetAccessLogsRequest(req *types.GetAccessLogsRequest) error {
+	if req.ApplicationID == uuid.Nil {
+		return types.ErrMissingApplicationID
+	}
+	if !req.StartTime.IsZero() && !req.EndTime.IsZero() && !req.StartTime.Before(req.EndTime) {
+		return types.ErrInvalidTimeRange
+	}
+
+	req.Status = strings.TrimSpace(req.Status)
+	if _, _, err := types.StatusRange(req.Status); err != nil {
+		return err
+	}
+	req.Method = strings.ToUpper(strings.TrimSpace(req.Method))
+	req.SearchTerm = strings.TrimSpace(req.SearchTerm)
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 100
+	}
+	if req.Page < 0 || req.PageSize < 0 || req.PageSize > types.MaxAccessLogPageSize {
+		return types.ErrInvalidPage
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// CreateSubscription adds a webhook subscription. The response holds the signing secret, which
+// is not shown again.
+func (c *WebhookController) CreateSubscription(f fuego.ContextWithBody[types.CreateSubscriptionRequest]) (*shared_types.Response, error) {
+	request, err := f.Body()
+	if err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := c.validator.ValidateRequest(&request); err != nil {
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := c.service.CreateSubscription(request, user.ID, organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to create webhook subscription", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: webhookErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Webhook subscription created successfully",
+		Data:    subscription,
+	}, nil
+}
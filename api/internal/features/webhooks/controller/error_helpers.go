@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// webhookErrorStatus maps errors of the webhook service to HTTP statuses.
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrSubscriptionNotFound),
+		errors.Is(err, types.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrSubscriptionInactive),
+		errors.Is(err, types.ErrDeliveryStillScheduled):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// requestUser returns the user and organization of the request or an unauthorized error.
+func requestUser(w http.ResponseWriter, r *http.Request) (*shared_types.User, uuid.UUID, error) {
+	user := utils.GetUser(w, r)
+	if user == nil {
+		return nil, uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	organizationID := utils.GetOrganizationID(r)
+	if organizationID == uuid.Nil {
+		return nil, uuid.Nil, fuego.HTTPError{
+			Err:    nil,
+			Status: http.StatusUnauthorized,
+		}
+	}
+	return user, organizationID, nil
+}
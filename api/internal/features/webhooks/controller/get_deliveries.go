@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-fuego/fuego"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// GetDeliveries lists the webhook deliveries of the organization, newest first. The
+// subscription_id query parameter restricts the list to the deliveries of one subscription.
+func (c *WebhookController) GetDeliveries(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	var subscriptionID *uuid.UUID
+	if param := f.QueryParam("subscription_id"); param != "" {
+		id, err := c.validator.ValidateID(param, types.ErrInvalidSubscriptionID)
+		if err != nil {
+			return nil, fuego.HTTPError{
+				Err:    err,
+				Status: http.StatusBadRequest,
+			}
+		}
+		subscriptionID = &id
+	}
+
+	_, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := c.service.GetDeliveries(organizationID, subscriptionID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get webhook deliveries", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: webhookErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Webhook deliveries fetched successfully",
+		Data:    deliveries,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"github.com/go-fuego/fuego"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (c *WebhookController) GetSubscriptions(f fuego.ContextNoBody) (*shared_types.Response, error) {
+	_, organizationID, err := requestUser(f.Response(), f.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := c.service.GetSubscriptions(organizationID)
+	if err != nil {
+		c.logger.Log(logger.Error, "failed to get webhook subscriptions", err.Error())
+		return nil, fuego.HTTPError{
+			Err:    err,
+			Status: webhookErrorStatus(err),
+		}
+	}
+
+	return &shared_types.Response{
+		Status:  "success",
+		Message: "Webhook subscriptions fetched successfully",
+		Data:    subscriptions,
+	}, nil
+}
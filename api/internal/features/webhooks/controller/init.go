@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/validation"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+)
+
+type WebhookController struct {
+	store     *shared_storage.Store
+	validator *validation.Validator
+	service   *service.WebhookService
+	ctx       context.Context
+	logger    logger.Logger
+}
+
+func NewWebhookController(
+	store *shared_storage.Store,
+	ctx context.Context,
+	l logger.Logger,
+) *WebhookController {
+	storage := storage.WebhookStorage{DB: store.DB, Ctx: ctx}
+	webhookService := service.NewWebhookService(store, ctx, l, &storage, docker.NewDockerService())
+	webhookService.Start(ctx)
+
+	return &WebhookController{
+		store:     store,
+		validator: validation.NewValidator(&storage),
+		service:   webhookService,
+		ctx:       ctx,
+		logger:    l,
+	}
+}
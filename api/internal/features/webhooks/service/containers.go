@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+// reconnectDelay is the wait before listening to Docker events again after the stream broke.
+const reconnectDelay = 10 * time.Second
+
+// watchContainers publishes container.died for every container of an application that exits.
+// Containers are matched to their application by the com.application.id label they inherit
+// from the image.
+func (s *WebhookService) watchContainers(ctx context.Context) {
+	if s.docker == nil {
+		return
+	}
+
+	options := events.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("event", string(events.ActionDie)),
+			filters.Arg("label", "com.application.id"),
+		),
+	}
+
+	for {
+		messages, errs := s.docker.ListenEvents(options)
+	listen:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message := <-messages:
+				s.containerDied(message)
+			case err := <-errs:
+				if err != nil {
+					s.logger.Log(logger.Error, "Docker event stream closed", err.Error())
+				}
+				break listen
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (s *WebhookService) containerDied(message events.Message) {
+	attributes := message.Actor.Attributes
+	applicationID, err := uuid.Parse(attributes["com.application.id"])
+	if err != nil {
+		return
+	}
+
+	application, err := s.storage.GetApplication(applicationID)
+	if err != nil {
+		return
+	}
+
+	s.publish(publishedEvent{event: newEvent(application.OrganizationID, shared_types.WebhookEventContainerDied, shared_types.WebhookContainerData{
+		ContainerID:     message.Actor.ID,
+		ContainerName:   attributes["name"],
+		Image:           attributes["image"],
+		ExitCode:        attributes["exitCode"],
+		ApplicationID:   application.ID,
+		ApplicationName: application.Name,
+	})})
+}
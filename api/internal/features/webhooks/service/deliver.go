@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	// deliveryCheckInterval is how often due deliveries are looked up when nothing was published.
+	deliveryCheckInterval = 5 * time.Second
+	// deliveryTimeout bounds a single attempt, including reading the response.
+	deliveryTimeout = 10 * time.Second
+	// deliveryLease keeps a claimed delivery from being sent by another replica while it is attempted.
+	deliveryLease = time.Minute
+	// deliveryBatchSize is the number of deliveries attempted concurrently.
+	deliveryBatchSize = 20
+	// maxDeliveryAttempts is the number of attempts after which a delivery is given up on.
+	maxDeliveryAttempts = 8
+	// initialRetryDelay doubles after every failed attempt, up to maxRetryDelay.
+	initialRetryDelay = 30 * time.Second
+	maxRetryDelay     = time.Hour
+	// deliveryRetention is how long finished deliveries are kept in the delivery log.
+	deliveryRetention = 30 * 24 * time.Hour
+	pruneInterval     = time.Hour
+	// maxResponseBody is how much of the response of an endpoint is kept with the delivery.
+	maxResponseBody = 4096
+)
+
+func (s *WebhookService) sendDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(deliveryCheckInterval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		s.sendDue()
+
+		if time.Since(lastPrune) >= pruneInterval {
+			lastPrune = time.Now()
+			if err := s.storage.DeleteDeliveriesBefore(now().Add(-deliveryRetention)); err != nil {
+				s.logger.Log(logger.Error, "Failed to prune webhook deliveries", err.Error())
+			}
+		}
+	}
+}
+
+// sendDue attempts due deliveries in batches until none are left.
+func (s *WebhookService) sendDue() {
+	for {
+		deliveries, err := s.storage.ClaimDueDeliveries(now(), deliveryLease, deliveryBatchSize)
+		if err != nil {
+			s.logger.Log(logger.Error, "Failed to claim due webhook deliveries", err.Error())
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *shared_types.WebhookDelivery) {
+				defer wg.Done()
+				s.attempt(delivery)
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		if len(deliveries) < deliveryBatchSize {
+			return
+		}
+	}
+}
+
+// attempt sends a delivery once and schedules its next attempt when it failed.
+func (s *WebhookService) attempt(delivery *shared_types.WebhookDelivery) {
+	attemptedAt := now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &attemptedAt
+	delivery.ResponseStatus = 0
+	delivery.ResponseBody = ""
+	delivery.Error = ""
+
+	// deliveries of disabled subscriptions are given up on right away
+	giveUp := false
+	subscription, err := s.storage.GetSubscriptionByID(delivery.SubscriptionID)
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case !subscription.Active:
+		delivery.Error = "webhook subscription is disabled"
+		giveUp = true
+	default:
+		delivery.ResponseStatus, delivery.ResponseBody, err = s.send(subscription, delivery, attemptedAt)
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+	}
+
+	switch {
+	case delivery.Error == "":
+		delivery.Status = shared_types.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+	case giveUp || delivery.Attempts >= maxDeliveryAttempts:
+		delivery.Status = shared_types.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+	default:
+		next := attemptedAt.Add(retryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+
+	if err := s.storage.UpdateDelivery(delivery); err != nil {
+		s.logger.Log(logger.Error, "Failed to update webhook delivery", delivery.ID.String()+": "+err.Error())
+	}
+}
+
+// send posts the payload of a delivery to the subscription. Any response outside of the 2xx
+// range counts as a failure. The client refuses to connect to addresses that are not public,
+// so deliveries can not be used to reach services of the host or its network.
+func (s *WebhookService) send(subscription *shared_types.WebhookSubscription, delivery *shared_types.WebhookDelivery, at time.Time) (int, string, error) {
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Nixopus-Webhooks")
+	req.Header.Set("X-Nixopus-Event", string(delivery.EventType))
+	req.Header.Set("X-Nixopus-Delivery", delivery.ID.String())
+	req.Header.Set("X-Nixopus-Timestamp", timestamp)
+	req.Header.Set("X-Nixopus-Signature", Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	// redirects are not followed, the body of the redirect is not from the subscribed endpoint
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		return resp.StatusCode, "", fmt.Errorf("endpoint redirected to %q, redirects are not followed", resp.Header.Get("Location"))
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(body), fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// Sign returns the X-Nixopus-Signature header of a payload: the hex encoded HMAC-SHA256 of
+// the timestamp, a dot and the payload, keyed with the subscription secret.
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay is the wait before the attempt following the given number of failed attempts.
+func retryDelay(attempts int) time.Duration {
+	delay := initialRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"event":"deployment.succeeded"}`)
+
+	signature := Sign("secret", "1700000000", payload)
+	assert.Equal(t, "sha256=cfbd01582160f5911af0cdcd6a0b5060348f2192a334e2d1e08bedef59104dc1", signature)
+	assert.NotEqual(t, signature, Sign("other", "1700000000", payload))
+	assert.NotEqual(t, signature, Sign("secret", "1700000001", payload))
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 30 * time.Second},
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 7, want: 32 * time.Minute},
+		{attempts: 8, want: time.Hour},
+		{attempts: 100, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, retryDelay(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
+
+func TestSend(t *testing.T) {
+	previous := config.AppConfig.Server.OutboundAllowedNetworks
+	defer func() { config.AppConfig.Server.OutboundAllowedNetworks = previous }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("broken"))
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	s := &WebhookService{client: utils.NewPublicHTTPClient(deliveryTimeout)}
+	delivery := &shared_types.WebhookDelivery{ID: uuid.New(), EventType: "deployment.succeeded", Payload: []byte("{}")}
+	send := func(path string) (int, string, error) {
+		return s.send(&shared_types.WebhookSubscription{URL: server.URL + path, Secret: "secret"}, delivery, time.Now())
+	}
+
+	config.AppConfig.Server.OutboundAllowedNetworks = ""
+	status, body, err := send("/")
+	assert.True(t, errors.Is(err, utils.ErrNonPublicAddress))
+	assert.Equal(t, 0, status)
+	assert.Empty(t, body)
+
+	config.AppConfig.Server.OutboundAllowedNetworks = "127.0.0.0/8"
+	status, body, err = send("/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", body)
+
+	status, body, err = send("/redirect")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusFound, status)
+	assert.Empty(t, body)
+
+	status, body, err = send("/fail")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, "broken", body)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (s *WebhookService) GetDeliveries(organizationID uuid.UUID, subscriptionID *uuid.UUID) ([]shared_types.WebhookDelivery, error) {
+	return s.storage.GetDeliveries(organizationID, subscriptionID)
+}
+
+func (s *WebhookService) GetDelivery(id uuid.UUID, organizationID uuid.UUID) (*shared_types.WebhookDelivery, error) {
+	return s.storage.GetDelivery(id, organizationID)
+}
+
+// ReplayDelivery sends the event of a finished delivery again as a new delivery. The payload,
+// and so the event id, is unchanged; the new delivery links back to the one it replays.
+func (s *WebhookService) ReplayDelivery(id uuid.UUID, organizationID uuid.UUID) (*shared_types.WebhookDelivery, error) {
+	delivery, err := s.storage.GetDelivery(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == shared_types.WebhookDeliveryPending {
+		return nil, types.ErrDeliveryStillScheduled
+	}
+
+	subscription, err := s.storage.GetSubscription(delivery.SubscriptionID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.Active {
+		return nil, types.ErrSubscriptionInactive
+	}
+
+	createdAt := now()
+	replay := shared_types.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: delivery.SubscriptionID,
+		OrganizationID: organizationID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         shared_types.WebhookDeliveryPending,
+		NextAttemptAt:  &createdAt,
+		ReplayOf:       &delivery.ID,
+		CreatedAt:      createdAt,
+	}
+	if err := s.storage.CreateDeliveries([]shared_types.WebhookDelivery{replay}); err != nil {
+		return nil, err
+	}
+
+	s.nudge()
+	return &replay, nil
+}
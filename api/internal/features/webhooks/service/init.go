@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/storage"
+	shared_storage "github.com/raghavyuva/nixopus-api/internal/storage"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+// eventBufferSize is how many published events may wait for their deliveries to be recorded
+// before new events are dropped.
+const eventBufferSize = 256
+
+type WebhookService struct {
+	storage storage.WebhookStorageInterface
+	docker  docker.DockerRepository
+	Ctx     context.Context
+	store   *shared_storage.Store
+	logger  logger.Logger
+	client  *http.Client
+	events  chan publishedEvent
+	// wake asks the sender to look for due deliveries before its next tick
+	wake chan struct{}
+}
+
+func NewWebhookService(
+	store *shared_storage.Store,
+	ctx context.Context,
+	logger logger.Logger,
+	webhook_repo storage.WebhookStorageInterface,
+	dockerRepo docker.DockerRepository,
+) *WebhookService {
+	return &WebhookService{
+		storage: webhook_repo,
+		docker:  dockerRepo,
+		store:   store,
+		Ctx:     ctx,
+		logger:  logger,
+		client:  utils.NewPublicHTTPClient(deliveryTimeout),
+		events:  make(chan publishedEvent, eventBufferSize),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultService *WebhookService
+)
+
+// Start records and sends webhook deliveries until ctx is done, and makes the service the one
+// events are published to. It also watches Docker for containers of applications that die.
+func (s *WebhookService) Start(ctx context.Context) {
+	defaultMu.Lock()
+	defaultService = s
+	defaultMu.Unlock()
+
+	go s.recordEvents(ctx)
+	go s.sendDeliveries(ctx)
+	go s.watchContainers(ctx)
+}
+
+func current() *WebhookService {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultService
+}
+
+// nudge wakes the sender without blocking when it is already awake.
+func (s *WebhookService) nudge() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// now is the time stamped on events and delivery attempts.
+func now() time.Time {
+	return time.Now().UTC()
+}
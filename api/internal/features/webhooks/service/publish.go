@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+type publishedEvent struct {
+	event shared_types.WebhookEvent
+	// userID publishes the event to every organization of the user when set
+	userID uuid.UUID
+}
+
+// Publish sends an event to the webhook subscriptions of an organization. It never blocks the
+// caller, deliveries are recorded and sent in the background. Events published before the
+// webhook service is started are dropped.
+func Publish(organizationID uuid.UUID, eventType shared_types.WebhookEventType, data interface{}) {
+	if s := current(); s != nil {
+		s.publish(publishedEvent{event: newEvent(organizationID, eventType, data)})
+	}
+}
+
+// PublishLogin sends a user.login event to every organization the user belongs to.
+func PublishLogin(user *shared_types.User, r *http.Request) {
+	s := current()
+	if s == nil || user == nil || r == nil {
+		return
+	}
+	data := shared_types.WebhookLoginData{
+		UserID:    user.ID,
+		Email:     user.Email,
+		IP:        r.RemoteAddr,
+		UserAgent: r.UserAgent(),
+	}
+	s.publish(publishedEvent{
+		event:  newEvent(uuid.Nil, shared_types.WebhookEventUserLogin, data),
+		userID: user.ID,
+	})
+}
+
+func newEvent(organizationID uuid.UUID, eventType shared_types.WebhookEventType, data interface{}) shared_types.WebhookEvent {
+	return shared_types.WebhookEvent{
+		ID:             uuid.New(),
+		Type:           eventType,
+		OrganizationID: organizationID,
+		CreatedAt:      now(),
+		Data:           data,
+	}
+}
+
+func (s *WebhookService) publish(event publishedEvent) {
+	select {
+	case s.events <- event:
+	default:
+		s.logger.Log(logger.Error, "Webhook event buffer is full, dropping event", string(event.event.Type))
+	}
+}
+
+func (s *WebhookService) recordEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case published := <-s.events:
+			if err := s.record(published); err != nil {
+				s.logger.Log(logger.Error, "Failed to record webhook deliveries", string(published.event.Type)+": "+err.Error())
+				continue
+			}
+			s.nudge()
+		}
+	}
+}
+
+// record creates a pending delivery of the event for every active subscription to it.
+func (s *WebhookService) record(published publishedEvent) error {
+	organizationIDs := []uuid.UUID{published.event.OrganizationID}
+	if published.userID != uuid.Nil {
+		ids, err := s.storage.GetUserOrganizationIDs(published.userID)
+		if err != nil {
+			return err
+		}
+		organizationIDs = ids
+	}
+
+	var deliveries []shared_types.WebhookDelivery
+	for _, organizationID := range organizationIDs {
+		subscriptions, err := s.storage.GetActiveSubscriptions(organizationID)
+		if err != nil {
+			return err
+		}
+
+		event := published.event
+		event.OrganizationID = organizationID
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		for _, subscription := range subscriptions {
+			if !subscribed(subscription, event.Type) {
+				continue
+			}
+			next := event.CreatedAt
+			deliveries = append(deliveries, shared_types.WebhookDelivery{
+				ID:             uuid.New(),
+				SubscriptionID: subscription.ID,
+				OrganizationID: organizationID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Payload:        payload,
+				Status:         shared_types.WebhookDeliveryPending,
+				NextAttemptAt:  &next,
+				CreatedAt:      event.CreatedAt,
+			})
+		}
+	}
+	return s.storage.CreateDeliveries(deliveries)
+}
+
+func subscribed(subscription shared_types.WebhookSubscription, eventType shared_types.WebhookEventType) bool {
+	return slices.Contains(subscription.Events, shared_types.WebhookEventAll) ||
+		slices.Contains(subscription.Events, eventType)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+func (s *WebhookService) CreateSubscription(request types.CreateSubscriptionRequest, userID uuid.UUID, organizationID uuid.UUID) (*types.SubscriptionSecretResponse, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	active := true
+	if request.Active != nil {
+		active = *request.Active
+	}
+
+	createdAt := time.Now()
+	subscription := &shared_types.WebhookSubscription{
+		ID:             uuid.New(),
+		URL:            request.URL,
+		Description:    request.Description,
+		Secret:         secret,
+		Events:         request.Events,
+		Active:         active,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+	if err := s.storage.CreateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	return &types.SubscriptionSecretResponse{WebhookSubscription: subscription, Secret: secret}, nil
+}
+
+func (s *WebhookService) GetSubscriptions(organizationID uuid.UUID) ([]shared_types.WebhookSubscription, error) {
+	return s.storage.GetSubscriptions(organizationID)
+}
+
+func (s *WebhookService) GetSubscription(id uuid.UUID, organizationID uuid.UUID) (*shared_types.WebhookSubscription, error) {
+	return s.storage.GetSubscription(id, organizationID)
+}
+
+func (s *WebhookService) UpdateSubscription(id uuid.UUID, request types.UpdateSubscriptionRequest, organizationID uuid.UUID) (*shared_types.WebhookSubscription, error) {
+	subscription, err := s.storage.GetSubscription(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.URL != nil {
+		subscription.URL = *request.URL
+	}
+	if request.Description != nil {
+		subscription.Description = *request.Description
+	}
+	if request.Events != nil {
+		subscription.Events = request.Events
+	}
+	if request.Active != nil {
+		subscription.Active = *request.Active
+	}
+
+	if err := s.storage.UpdateSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *WebhookService) DeleteSubscription(id uuid.UUID, organizationID uuid.UUID) error {
+	if _, err := s.storage.GetSubscription(id, organizationID); err != nil {
+		return err
+	}
+	return s.storage.DeleteSubscription(id)
+}
+
+// RotateSecret replaces the signing secret of a subscription. Deliveries that are still pending
+// are signed with the new secret.
+func (s *WebhookService) RotateSecret(id uuid.UUID, organizationID uuid.UUID) (*types.SubscriptionSecretResponse, error) {
+	subscription, err := s.storage.GetSubscription(id, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = secret
+	if err := s.storage.UpdateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	return &types.SubscriptionSecretResponse{WebhookSubscription: subscription, Secret: secret}, nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/uptrace/bun"
+)
+
+type WebhookStorage struct {
+	DB  *bun.DB
+	Ctx context.Context
+}
+
+type WebhookStorageInterface interface {
+	CreateSubscription(subscription *shared_types.WebhookSubscription) error
+	GetSubscription(id uuid.UUID, organizationID uuid.UUID) (*shared_types.WebhookSubscription, error)
+	GetSubscriptionByID(id uuid.UUID) (*shared_types.WebhookSubscription, error)
+	GetSubscriptions(organizationID uuid.UUID) ([]shared_types.WebhookSubscription, error)
+	GetActiveSubscriptions(organizationID uuid.UUID) ([]shared_types.WebhookSubscription, error)
+	UpdateSubscription(subscription *shared_types.WebhookSubscription) error
+	DeleteSubscription(id uuid.UUID) error
+	CreateDeliveries(deliveries []shared_types.WebhookDelivery) error
+	GetDelivery(id uuid.UUID, organizationID uuid.UUID) (*shared_types.WebhookDelivery, error)
+	GetDeliveries(organizationID uuid.UUID, subscriptionID *uuid.UUID) ([]shared_types.WebhookDelivery, error)
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]shared_types.WebhookDelivery, error)
+	UpdateDelivery(delivery *shared_types.WebhookDelivery) error
+	DeleteDeliveriesBefore(before time.Time) error
+	GetApplication(id uuid.UUID) (*shared_types.Application, error)
+	GetUserOrganizationIDs(userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+func (s *WebhookStorage) CreateSubscription(subscription *shared_types.WebhookSubscription) error {
+	_, err := s.DB.NewInsert().Model(subscription).Exec(s.Ctx)
+	return err
+}
+
+func (s *WebhookStorage) GetSubscription(id uuid.UUID, organizationID uuid.UUID) (*shared_types.WebhookSubscription, error) {
+	var subscription shared_types.WebhookSubscription
+	err := s.DB.NewSelect().
+		Model(&subscription).
+		Where("id = ? AND organization_id = ?", id, organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (s *WebhookStorage) GetSubscriptionByID(id uuid.UUID) (*shared_types.WebhookSubscription, error) {
+	var subscription shared_types.WebhookSubscription
+	err := s.DB.NewSelect().
+		Model(&subscription).
+		Where("id = ?", id).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (s *WebhookStorage) GetSubscriptions(organizationID uuid.UUID) ([]shared_types.WebhookSubscription, error) {
+	var subscriptions []shared_types.WebhookSubscription
+	err := s.DB.NewSelect().
+		Model(&subscriptions).
+		Where("organization_id = ?", organizationID).
+		Order("created_at DESC").
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (s *WebhookStorage) GetActiveSubscriptions(organizationID uuid.UUID) ([]shared_types.WebhookSubscription, error) {
+	var subscriptions []shared_types.WebhookSubscription
+	err := s.DB.NewSelect().
+		Model(&subscriptions).
+		Where("organization_id = ?", organizationID).
+		Where("active = ?", true).
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (s *WebhookStorage) UpdateSubscription(subscription *shared_types.WebhookSubscription) error {
+	subscription.UpdatedAt = time.Now()
+	_, err := s.DB.NewUpdate().
+		Model(subscription).
+		Column("url", "description", "secret", "events", "active", "updated_at").
+		WherePK().
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *WebhookStorage) DeleteSubscription(id uuid.UUID) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.WebhookSubscription)(nil)).
+		Where("id = ?", id).
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *WebhookStorage) CreateDeliveries(deliveries []shared_types.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	_, err := s.DB.NewInsert().Model(&deliveries).Exec(s.Ctx)
+	return err
+}
+
+func (s *WebhookStorage) GetDelivery(id uuid.UUID, organizationID uuid.UUID) (*shared_types.WebhookDelivery, error) {
+	var delivery shared_types.WebhookDelivery
+	err := s.DB.NewSelect().
+		Model(&delivery).
+		Where("id = ? AND organization_id = ?", id, organizationID).
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, types.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (s *WebhookStorage) GetDeliveries(organizationID uuid.UUID, subscriptionID *uuid.UUID) ([]shared_types.WebhookDelivery, error) {
+	var deliveries []shared_types.WebhookDelivery
+	query := s.DB.NewSelect().
+		Model(&deliveries).
+		Where("organization_id = ?", organizationID)
+	if subscriptionID != nil {
+		query = query.Where("subscription_id = ?", *subscriptionID)
+	}
+	err := query.Order("created_at DESC").Limit(500).Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries whose next attempt is due by moving
+// that attempt lease into the future, and returns them. Rows locked by another replica are
+// skipped, and a delivery whose sender died is picked up again once its lease expires.
+func (s *WebhookStorage) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]shared_types.WebhookDelivery, error) {
+	due := s.DB.NewSelect().
+		TableExpr("webhook_deliveries").
+		Column("id").
+		Where("status = ?", shared_types.WebhookDeliveryPending).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var deliveries []shared_types.WebhookDelivery
+	err := s.DB.NewUpdate().
+		Model(&deliveries).
+		Set("next_attempt_at = ?", now.Add(lease)).
+		Where("wd.id IN (?)", due).
+		Returning("*").
+		Scan(s.Ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookStorage) UpdateDelivery(delivery *shared_types.WebhookDelivery) error {
+	_, err := s.DB.NewUpdate().
+		Model(delivery).
+		Column("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "response_body", "error").
+		WherePK().
+		Exec(s.Ctx)
+	return err
+}
+
+// DeleteDeliveriesBefore removes finished deliveries created before the given time.
+func (s *WebhookStorage) DeleteDeliveriesBefore(before time.Time) error {
+	_, err := s.DB.NewDelete().
+		Model((*shared_types.WebhookDelivery)(nil)).
+		Where("status <> ?", shared_types.WebhookDeliveryPending).
+		Where("created_at < ?", before).
+		Exec(s.Ctx)
+	return err
+}
+
+func (s *WebhookStorage) GetApplication(id uuid.UUID) (*shared_types.Application, error) {
+	var application shared_types.Application
+	err := s.DB.NewSelect().
+		Model(&application).
+		Where("id = ?", id).
+		Scan(s.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (s *WebhookStorage) GetUserOrganizationIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	var organizationIDs []uuid.UUID
+	err := s.DB.NewSelect().
+		Model((*shared_types.OrganizationUsers)(nil)).
+		Column("organization_id").
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NULL").
+		Scan(s.Ctx, &organizationIDs)
+	if err != nil {
+		return nil, err
+	}
+	return organizationIDs, nil
+}
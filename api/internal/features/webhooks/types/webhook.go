@@ -0,0 +1,43 @@
+package types
+
+import (
+	"errors"
+
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+var (
+	ErrInvalidRequestType     = errors.New("invalid request type")
+	ErrInvalidSubscriptionID  = errors.New("invalid webhook subscription id")
+	ErrInvalidDeliveryID      = errors.New("invalid webhook delivery id")
+	ErrSubscriptionNotFound   = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound       = errors.New("webhook delivery not found")
+	ErrInvalidURL             = errors.New("url must be an absolute http or https url")
+	ErrPrivateURL             = errors.New("url must point to a public address, add private networks to OUTBOUND_ALLOWED_NETWORKS to allow them")
+	ErrMissingEvents          = errors.New("at least one event is required")
+	ErrUnknownEvent           = errors.New("unknown webhook event")
+	ErrDescriptionTooLong     = errors.New("description must be at most 255 characters")
+	ErrSubscriptionInactive   = errors.New("webhook subscription is disabled, enable it before replaying deliveries")
+	ErrDeliveryStillScheduled = errors.New("delivery is still being retried")
+)
+
+type CreateSubscriptionRequest struct {
+	URL         string                          `json:"url"`
+	Description string                          `json:"description,omitempty"`
+	Events      []shared_types.WebhookEventType `json:"events"`
+	Active      *bool                           `json:"active,omitempty"`
+}
+
+type UpdateSubscriptionRequest struct {
+	URL         *string                         `json:"url,omitempty"`
+	Description *string                         `json:"description,omitempty"`
+	Events      []shared_types.WebhookEventType `json:"events,omitempty"`
+	Active      *bool                           `json:"active,omitempty"`
+}
+
+// SubscriptionSecretResponse is returned when a subscription is created or its secret is
+// rotated, the only times the signing secret is shown.
+type SubscriptionSecretResponse struct {
+	*shared_types.WebhookSubscription
+	Secret string `json:"secret"`
+}
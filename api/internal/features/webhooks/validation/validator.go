@@ -0,0 +1,103 @@
+package validation
+
+import (
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/types"
+	shared_types "github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/raghavyuva/nixopus-api/internal/utils"
+)
+
+const maxDescriptionLength = 255
+
+// Validator handles webhook validation logic
+type Validator struct {
+	storage storage.WebhookStorageInterface
+}
+
+// NewValidator creates a new validator instance
+func NewValidator(storage storage.WebhookStorageInterface) *Validator {
+	return &Validator{
+		storage: storage,
+	}
+}
+
+// ValidateID parses a subscription or delivery ID taken from the request
+func (v *Validator) ValidateID(id string, invalid error) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil || parsed == uuid.Nil {
+		return uuid.Nil, invalid
+	}
+	return parsed, nil
+}
+
+// ValidateRequest validates different webhook request types
+func (v *Validator) ValidateRequest(req interface{}) error {
+	switch r := req.(type) {
+	case *types.CreateSubscriptionRequest:
+		if err := validateURL(r.URL); err != nil {
+			return err
+		}
+		if err := validateDescription(r.Description); err != nil {
+			return err
+		}
+		return validateEvents(r.Events)
+	case *types.UpdateSubscriptionRequest:
+		if r.URL != nil {
+			if err := validateURL(*r.URL); err != nil {
+				return err
+			}
+		}
+		if r.Description != nil {
+			if err := validateDescription(*r.Description); err != nil {
+				return err
+			}
+		}
+		if r.Events != nil {
+			return validateEvents(r.Events)
+		}
+		return nil
+	default:
+		return types.ErrInvalidRequestType
+	}
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return types.ErrInvalidURL
+	}
+	// names are checked when a delivery connects, after they are resolved
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return types.ErrPrivateURL
+	}
+	if ip := net.ParseIP(host); ip != nil && !utils.AllowedOutboundIP(ip) {
+		return types.ErrPrivateURL
+	}
+	return nil
+}
+
+func validateDescription(description string) error {
+	if len(description) > maxDescriptionLength {
+		return types.ErrDescriptionTooLong
+	}
+	return nil
+}
+
+func validateEvents(events []shared_types.WebhookEventType) error {
+	if len(events) == 0 {
+		return types.ErrMissingEvents
+	}
+	for _, event := range events {
+		if event != shared_types.WebhookEventAll && !slices.Contains(shared_types.WebhookEvents, event) {
+			return types.ErrUnknownEvent
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/raghavyuva/nixopus-api/internal/features/webhooks/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateURL(t *testing.T) {
+	previous := config.AppConfig.Server.OutboundAllowedNetworks
+	defer func() { config.AppConfig.Server.OutboundAllowedNetworks = previous }()
+	config.AppConfig.Server.OutboundAllowedNetworks = "192.168.1.0/24"
+
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{url: "https://hooks.example.com/nixopus"},
+		{url: "http://93.184.216.34:8080/hook"},
+		{url: "ftp://example.com/hook", wantErr: types.ErrInvalidURL},
+		{url: "https:///hook", wantErr: types.ErrInvalidURL},
+		{url: "not a url", wantErr: types.ErrInvalidURL},
+		{url: "http://localhost:8080/hook", wantErr: types.ErrPrivateURL},
+		{url: "http://api.localhost/hook", wantErr: types.ErrPrivateURL},
+		{url: "http://127.0.0.1:2019/config", wantErr: types.ErrPrivateURL},
+		{url: "http://[::1]/hook", wantErr: types.ErrPrivateURL},
+		{url: "http://169.254.169.254/latest/meta-data/", wantErr: types.ErrPrivateURL},
+		{url: "http://10.0.0.5/hook", wantErr: types.ErrPrivateURL},
+		{url: "http://192.168.1.20/hook"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validateURL(tt.url))
+		})
+	}
+}
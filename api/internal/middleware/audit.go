@@ -178,6 +178,14 @@ func mapResourceType(resourceType string) types.AuditResourceType {
 		return types.AuditResourceTerminal
 	case "integration", "integrations":
 		return types.AuditResourceIntegration
+	case "database", "databases":
+		return types.AuditResourceDatabase
+	case "backup", "backups":
+		return types.AuditResourceBackup
+	case "template", "templates":
+		return types.AuditResourceTemplate
+	case "webhook", "webhooks":
+		return types.AuditResourceWebhook
 	default:
 		return types.AuditResourceOrganization // Default fallback
 	}
@@ -0,0 +1,449 @@
+package queue
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/vmihailenco/taskq/v3"
+)
+
+// The helpers in this file read and modify the redis structures used by taskq's redisq
+// backend directly. Every queue keeps waiting and reserved messages in a stream consumed
+// by the "taskq" group, messages waiting for a retry in a sorted set scored by the time
+// they become due, and, added by this package, messages that ran out of retries in a list.
+
+const (
+	redisPrefix    = "taskq:"
+	streamGroup    = "taskq"
+	deadLetterSize = 100
+)
+
+// TaskState describes where a message currently is in its queue.
+type TaskState string
+
+const (
+	TaskStateWaiting  TaskState = "waiting"
+	TaskStateInFlight TaskState = "in_flight"
+	TaskStateRetrying TaskState = "retrying"
+	TaskStateDead     TaskState = "dead"
+)
+
+// Position is where a waiting task is moved to when it is reprioritized.
+type Position string
+
+const (
+	PositionFirst Position = "first"
+	PositionLast  Position = "last"
+)
+
+var (
+	ErrTaskNotFound = errors.New("task not found in queue")
+	ErrTaskInFlight = errors.New("task is being processed by a worker")
+	ErrTaskRetrying = errors.New("retrying tasks can only be moved to the first position")
+)
+
+// QueuedMessage is a snapshot of a single message of a queue.
+type QueuedMessage struct {
+	ID       string    `json:"id"`
+	Queue    string    `json:"queue"`
+	TaskName string    `json:"task_name"`
+	State    TaskState `json:"state"`
+	// Attempts is the number of times the message has been handed to a worker.
+	Attempts int `json:"attempts"`
+	// Worker is the consumer holding an in-flight message.
+	Worker     string        `json:"worker,omitempty"`
+	IdleFor    time.Duration `json:"idle_for,omitempty"`
+	EnqueuedAt time.Time     `json:"enqueued_at,omitempty"`
+	// ScheduledAt is when a retrying message becomes due again.
+	ScheduledAt time.Time `json:"scheduled_at,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	FailedAt    time.Time `json:"failed_at,omitempty"`
+	// Args holds the msgpack encoded handler arguments.
+	Args []byte `json:"-"`
+}
+
+type deadLetter struct {
+	ID       string    `json:"id"`
+	TaskName string    `json:"task_name"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+	Body     string    `json:"body"`
+}
+
+func streamKey(queueName string) string {
+	return redisPrefix + "{" + queueName + "}:stream"
+}
+
+func zsetKey(queueName string) string {
+	return redisPrefix + "{" + queueName + "}:zset"
+}
+
+func deadLetterKey(queueName string) string {
+	return redisPrefix + "{" + queueName + "}:dead"
+}
+
+// undeliveredEntries is shared by the scripts below. It returns the stream entries that
+// have not been read by the consumer group yet, in delivery order.
+const undeliveredEntries = `
+local function undelivered(stream, group)
+	local last = '0-0'
+	local ok, groups = pcall(redis.call, 'XINFO', 'GROUPS', stream)
+	if not ok then
+		return redis.call('XRANGE', stream, '-', '+')
+	end
+	for _, g in ipairs(groups) do
+		local name, id
+		for i = 1, #g, 2 do
+			if g[i] == 'name' then name = g[i + 1] end
+			if g[i] == 'last-delivered-id' then id = g[i + 1] end
+		end
+		if name == group then last = id end
+	end
+	return redis.call('XRANGE', stream, '(' .. last, '+')
+end
+`
+
+// moveToFrontScript re-appends the undelivered entries of the stream so that the target
+// entry is delivered next while the others keep their relative order.
+var moveToFrontScript = redis.NewScript(undeliveredEntries + `
+local entries = undelivered(KEYS[1], ARGV[1])
+local found = false
+for _, e in ipairs(entries) do
+	if e[1] == ARGV[2] then found = true end
+end
+if not found then return 0 end
+for _, e in ipairs(entries) do
+	redis.call('XDEL', KEYS[1], e[1])
+end
+for _, e in ipairs(entries) do
+	if e[1] == ARGV[2] then redis.call('XADD', KEYS[1], '*', unpack(e[2])) end
+end
+for _, e in ipairs(entries) do
+	if e[1] ~= ARGV[2] then redis.call('XADD', KEYS[1], '*', unpack(e[2])) end
+end
+return 1
+`)
+
+// moveToBackScript re-appends the target entry if it has not been delivered yet.
+var moveToBackScript = redis.NewScript(undeliveredEntries + `
+for _, e in ipairs(undelivered(KEYS[1], ARGV[1])) do
+	if e[1] == ARGV[2] then
+		redis.call('XDEL', KEYS[1], e[1])
+		redis.call('XADD', KEYS[1], '*', unpack(e[2]))
+		return 1
+	end
+end
+return 0
+`)
+
+// removeWaitingScript deletes the target entry if it has not been delivered yet.
+var removeWaitingScript = redis.NewScript(undeliveredEntries + `
+for _, e in ipairs(undelivered(KEYS[1], ARGV[1])) do
+	if e[1] == ARGV[2] then
+		redis.call('XDEL', KEYS[1], e[1])
+		return 1
+	end
+end
+return 0
+`)
+
+// purgeScript deletes every undelivered entry of the stream and every delayed message.
+var purgeScript = redis.NewScript(undeliveredEntries + `
+local count = 0
+for _, e in ipairs(undelivered(KEYS[1], ARGV[1])) do
+	count = count + redis.call('XDEL', KEYS[1], e[1])
+end
+count = count + redis.call('ZCARD', KEYS[2])
+redis.call('DEL', KEYS[2])
+return count
+`)
+
+// dueNowScript moves a delayed message to the end of the stream, returning the new entry id.
+var dueNowScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then return false end
+return redis.call('XADD', KEYS[2], '*', 'body', ARGV[1])
+`)
+
+// Inspect returns the waiting, in-flight and retrying messages of a queue, at most limit of each.
+func Inspect(ctx context.Context, queueName string, limit int64) ([]QueuedMessage, error) {
+	stream := streamKey(queueName)
+
+	pending, err := redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  streamGroup,
+		Start:  "-",
+		End:    "+",
+		Count:  limit,
+	}).Result()
+	if err != nil && !isMissingGroup(err) {
+		return nil, err
+	}
+	inFlight := make(map[string]redis.XPendingExt, len(pending))
+	for _, p := range pending {
+		inFlight[p.ID] = p
+	}
+
+	entries, err := redisClient.XRangeN(ctx, stream, "-", "+", limit+int64(len(pending))).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []QueuedMessage
+	for _, entry := range entries {
+		body, ok := entry.Values["body"].(string)
+		if !ok {
+			continue
+		}
+		msg, err := decodeMessage(queueName, body)
+		if err != nil {
+			continue
+		}
+		msg.ID = entry.ID
+		msg.EnqueuedAt = streamIDTime(entry.ID)
+		msg.State = TaskStateWaiting
+		if p, ok := inFlight[entry.ID]; ok {
+			msg.State = TaskStateInFlight
+			msg.Worker = p.Consumer
+			msg.IdleFor = p.Idle
+			if msg.Attempts == 0 {
+				msg.Attempts = 1
+			}
+		}
+		messages = append(messages, msg)
+	}
+
+	delayed, err := redisClient.ZRangeWithScores(ctx, zsetKey(queueName), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, z := range delayed {
+		body, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		msg, err := decodeMessage(queueName, body)
+		if err != nil {
+			continue
+		}
+		msg.State = TaskStateRetrying
+		msg.ScheduledAt = time.UnixMilli(int64(z.Score))
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// DeadLetters returns the most recent messages of a queue that ran out of retries.
+func DeadLetters(ctx context.Context, queueName string, limit int64) ([]QueuedMessage, error) {
+	raw, err := redisClient.LRange(ctx, deadLetterKey(queueName), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []QueuedMessage
+	for _, r := range raw {
+		var dl deadLetter
+		if err := json.Unmarshal([]byte(r), &dl); err != nil {
+			continue
+		}
+		msg := QueuedMessage{
+			ID:       dl.ID,
+			Queue:    queueName,
+			TaskName: dl.TaskName,
+			State:    TaskStateDead,
+			Attempts: dl.Attempts,
+			Error:    dl.Error,
+			FailedAt: dl.FailedAt,
+		}
+		if body, err := base64.StdEncoding.DecodeString(dl.Body); err == nil {
+			if decoded, err := decodeMessage(queueName, string(body)); err == nil {
+				msg.Args = decoded.Args
+			}
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// RecordDeadLetter keeps a message that ran out of retries so that it can be inspected later.
+// It is meant to be used as the FallbackHandler of a task.
+func RecordDeadLetter(queueName string, msg *taskq.Message) error {
+	body, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	dl := deadLetter{
+		ID:       msg.ID,
+		TaskName: msg.TaskName,
+		Attempts: msg.ReservedCount,
+		FailedAt: time.Now(),
+		Body:     base64.StdEncoding.EncodeToString(body),
+	}
+	if msg.Err != nil {
+		dl.Error = msg.Err.Error()
+	}
+
+	data, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pipe := redisClient.TxPipeline()
+	pipe.LPush(ctx, deadLetterKey(queueName), data)
+	pipe.LTrim(ctx, deadLetterKey(queueName), 0, deadLetterSize-1)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// MoveTask changes the position of a waiting task. Moving a retrying task to the first
+// position makes it due immediately instead of waiting for its backoff to expire.
+func MoveTask(ctx context.Context, queueName string, id string, position Position) error {
+	stream := streamKey(queueName)
+
+	if member, ok, err := findDelayed(ctx, queueName, id); err != nil {
+		return err
+	} else if ok {
+		if position != PositionFirst {
+			return ErrTaskRetrying
+		}
+		newID, err := dueNowScript.Run(ctx, redisClient, []string{zsetKey(queueName), stream}, member).Text()
+		if err != nil {
+			if err == redis.Nil {
+				return ErrTaskNotFound
+			}
+			return err
+		}
+		id = newID
+	}
+
+	script := moveToFrontScript
+	if position == PositionLast {
+		script = moveToBackScript
+	}
+	moved, err := script.Run(ctx, redisClient, []string{stream}, streamGroup, id).Int()
+	if err != nil {
+		return err
+	}
+	if moved == 0 {
+		return notWaitingError(ctx, queueName, id)
+	}
+	return nil
+}
+
+// RemoveTask deletes a waiting, retrying or dead-lettered task. In-flight tasks are owned by a
+// worker and cannot be removed.
+func RemoveTask(ctx context.Context, queueName string, id string) error {
+	if member, ok, err := findDelayed(ctx, queueName, id); err != nil {
+		return err
+	} else if ok {
+		return redisClient.ZRem(ctx, zsetKey(queueName), member).Err()
+	}
+
+	raw, err := redisClient.LRange(ctx, deadLetterKey(queueName), 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	for _, r := range raw {
+		var dl deadLetter
+		if err := json.Unmarshal([]byte(r), &dl); err == nil && dl.ID == id {
+			return redisClient.LRem(ctx, deadLetterKey(queueName), 1, r).Err()
+		}
+	}
+
+	removed, err := removeWaitingScript.Run(ctx, redisClient, []string{streamKey(queueName)}, streamGroup, id).Int()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return notWaitingError(ctx, queueName, id)
+	}
+	return nil
+}
+
+// PurgeQueue deletes every waiting and retrying task of a queue, or every dead-lettered
+// task when state is TaskStateDead. In-flight tasks are left to their workers.
+// It returns the number of deleted tasks.
+func PurgeQueue(ctx context.Context, queueName string, state TaskState) (int, error) {
+	if state == TaskStateDead {
+		count, err := redisClient.LLen(ctx, deadLetterKey(queueName)).Result()
+		if err != nil {
+			return 0, err
+		}
+		return int(count), redisClient.Del(ctx, deadLetterKey(queueName)).Err()
+	}
+
+	return purgeScript.Run(ctx, redisClient, []string{streamKey(queueName), zsetKey(queueName)}, streamGroup).Int()
+}
+
+// findDelayed looks up the sorted set member of a retrying message by its id.
+func findDelayed(ctx context.Context, queueName string, id string) (string, bool, error) {
+	members, err := redisClient.ZRange(ctx, zsetKey(queueName), 0, -1).Result()
+	if err != nil {
+		return "", false, err
+	}
+	for _, member := range members {
+		msg, err := decodeMessage(queueName, member)
+		if err == nil && msg.ID == id {
+			return member, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// notWaitingError tells apart a task that is being processed from one that does not exist.
+func notWaitingError(ctx context.Context, queueName string, id string) error {
+	pending, err := redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: streamKey(queueName),
+		Group:  streamGroup,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err == nil && len(pending) > 0 {
+		return ErrTaskInFlight
+	}
+	return ErrTaskNotFound
+}
+
+func decodeMessage(queueName string, body string) (QueuedMessage, error) {
+	var msg taskq.Message
+	if err := msg.UnmarshalBinary([]byte(body)); err != nil {
+		return QueuedMessage{}, err
+	}
+
+	args, err := msg.MarshalArgs()
+	if err != nil {
+		return QueuedMessage{}, err
+	}
+
+	return QueuedMessage{
+		ID:       msg.ID,
+		Queue:    queueName,
+		TaskName: msg.TaskName,
+		Attempts: msg.ReservedCount,
+		Args:     args,
+	}, nil
+}
+
+// streamIDTime returns the time encoded in the millisecond part of a stream entry id.
+func streamIDTime(id string) time.Time {
+	ms, err := strconv.ParseInt(strings.SplitN(id, "-", 2)[0], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+func isMissingGroup(err error) bool {
+	return strings.HasPrefix(err.Error(), "NOGROUP")
+}
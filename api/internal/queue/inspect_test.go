@@ -0,0 +1,210 @@
+package queue
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/taskq/v3"
+)
+
+const testQueue = "inspect-test"
+
+// setupRedis points the package at the redis of REDIS_URL and clears the keys of the test queue.
+func setupRedis(t *testing.T) context.Context {
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		url = "redis://localhost:6379"
+	}
+	opts, err := redis.ParseURL(url)
+	require.NoError(t, err)
+	client := redis.NewClient(opts)
+
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("redis is not available: %v", err)
+	}
+	redisClient = client
+
+	clear := func() {
+		client.Del(ctx, streamKey(testQueue), zsetKey(testQueue), deadLetterKey(testQueue))
+	}
+	clear()
+	t.Cleanup(func() {
+		clear()
+		client.Close()
+	})
+	return ctx
+}
+
+func encodedMessage(t *testing.T, taskName string) string {
+	msg := taskq.NewMessage(context.Background(), taskName)
+	msg.ID = taskName + "-id"
+	msg.TaskName = taskName
+	body, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	return string(body)
+}
+
+// enqueue adds messages to the stream of the test queue and returns their entry ids.
+func enqueue(t *testing.T, ctx context.Context, taskNames ...string) []string {
+	var ids []string
+	for _, name := range taskNames {
+		id, err := redisClient.XAdd(ctx, &redis.XAddArgs{
+			Stream: streamKey(testQueue),
+			Values: map[string]interface{}{"body": encodedMessage(t, name)},
+		}).Result()
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// deliver hands the next waiting message to a worker, as taskq's consumer does.
+func deliver(t *testing.T, ctx context.Context) {
+	err := redisClient.XGroupCreateMkStream(ctx, streamKey(testQueue), streamGroup, "0").Err()
+	if err != nil && err.Error() != "BUSYGROUP Consumer Group name already exists" {
+		require.NoError(t, err)
+	}
+	_, err = redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    streamGroup,
+		Consumer: "worker-1",
+		Streams:  []string{streamKey(testQueue), ">"},
+		Count:    1,
+		Block:    -1,
+	}).Result()
+	require.NoError(t, err)
+}
+
+// waiting returns the task names of the waiting messages in delivery order.
+func waiting(t *testing.T, ctx context.Context) []string {
+	messages, err := Inspect(ctx, testQueue, 100)
+	require.NoError(t, err)
+	var names []string
+	for _, msg := range messages {
+		if msg.State == TaskStateWaiting {
+			names = append(names, msg.TaskName)
+		}
+	}
+	return names
+}
+
+func messageID(t *testing.T, ctx context.Context, taskName string) string {
+	messages, err := Inspect(ctx, testQueue, 100)
+	require.NoError(t, err)
+	for _, msg := range messages {
+		if msg.TaskName == taskName {
+			return msg.ID
+		}
+	}
+	t.Fatalf("message %s not found", taskName)
+	return ""
+}
+
+func TestMoveTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		delivered bool
+		task      string
+		position  Position
+		want      []string
+		err       error
+	}{
+		{name: "First without a consumer group", task: "c", position: PositionFirst, want: []string{"c", "a", "b"}},
+		{name: "Last without a consumer group", task: "a", position: PositionLast, want: []string{"b", "c", "a"}},
+		{name: "First after a delivery", delivered: true, task: "c", position: PositionFirst, want: []string{"c", "b"}},
+		{name: "Last after a delivery", delivered: true, task: "b", position: PositionLast, want: []string{"c", "b"}},
+		{name: "In flight task", delivered: true, task: "a", position: PositionFirst, want: []string{"b", "c"}, err: ErrTaskInFlight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := setupRedis(t)
+			enqueue(t, ctx, "a", "b", "c")
+			if tt.delivered {
+				deliver(t, ctx)
+			}
+
+			err := MoveTask(ctx, testQueue, messageID(t, ctx, tt.task), tt.position)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, waiting(t, ctx))
+		})
+	}
+}
+
+func TestMoveRetryingTask(t *testing.T) {
+	ctx := setupRedis(t)
+	enqueue(t, ctx, "a")
+	due := float64(time.Now().Add(time.Hour).UnixMilli())
+	require.NoError(t, redisClient.ZAdd(ctx, zsetKey(testQueue), &redis.Z{Score: due, Member: encodedMessage(t, "retry")}).Err())
+
+	messages, err := Inspect(ctx, testQueue, 100)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	retrying := messages[1]
+	assert.Equal(t, TaskStateRetrying, retrying.State)
+
+	assert.Equal(t, ErrTaskRetrying, MoveTask(ctx, testQueue, retrying.ID, PositionLast))
+	assert.NoError(t, MoveTask(ctx, testQueue, retrying.ID, PositionFirst))
+	assert.Equal(t, []string{"retry", "a"}, waiting(t, ctx))
+	assert.Zero(t, redisClient.ZCard(ctx, zsetKey(testQueue)).Val())
+}
+
+func TestRemoveTask(t *testing.T) {
+	ctx := setupRedis(t)
+	enqueue(t, ctx, "a", "b", "c")
+	deliver(t, ctx)
+
+	assert.NoError(t, RemoveTask(ctx, testQueue, messageID(t, ctx, "b")))
+	assert.Equal(t, []string{"c"}, waiting(t, ctx))
+	assert.Equal(t, ErrTaskInFlight, RemoveTask(ctx, testQueue, messageID(t, ctx, "a")))
+	assert.Equal(t, ErrTaskNotFound, RemoveTask(ctx, testQueue, "0-1"))
+}
+
+func TestPurgeQueue(t *testing.T) {
+	ctx := setupRedis(t)
+	enqueue(t, ctx, "a", "b", "c")
+	deliver(t, ctx)
+	require.NoError(t, redisClient.ZAdd(ctx, zsetKey(testQueue), &redis.Z{Score: 1, Member: encodedMessage(t, "retry")}).Err())
+
+	msg := taskq.NewMessage(ctx, "dead")
+	msg.TaskName = "dead"
+	require.NoError(t, RecordDeadLetter(testQueue, msg))
+
+	count, err := PurgeQueue(ctx, testQueue, TaskStateWaiting)
+	require.NoError(t, err)
+	// the two waiting tasks and the retrying one, the in-flight task stays with its worker
+	assert.Equal(t, 3, count)
+
+	messages, err := Inspect(ctx, testQueue, 100)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, TaskStateInFlight, messages[0].State)
+
+	count, err = PurgeQueue(ctx, testQueue, TaskStateDead)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	dead, err := DeadLetters(ctx, testQueue, 10)
+	require.NoError(t, err)
+	assert.Empty(t, dead)
+}
+
+func TestStreamIDTime(t *testing.T) {
+	tests := []struct {
+		id   string
+		want time.Time
+	}{
+		{id: "1700000000000-0", want: time.UnixMilli(1700000000000)},
+		{id: "1700000000123-7", want: time.UnixMilli(1700000000123)},
+		{id: "invalid", want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			assert.Equal(t, tt.want, streamIDTime(tt.id))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	audit_service "github.com/raghavyuva/nixopus-api/internal/features/audit/service"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
+	"github.com/raghavyuva/nixopus-api/internal/features/terminal"
+	"github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	// containerTarget is the terminal target opening a shell in a container instead of the host.
+	containerTarget = "container"
+	// applicationLabel is the label linking the containers of a deployment to their application.
+	applicationLabel = "com.application.id"
+	maxShellLength   = 128
+)
+
+var (
+	errMissingContainerID   = errors.New("Missing containerId")
+	errInvalidShell         = errors.New("Invalid shell")
+	errContainerNotFound    = errors.New("Container not found")
+	errContainerForbidden   = errors.New("Not allowed to access this container")
+	errNotApplicationTarget = errors.New("Only containers of applications can be accessed")
+)
+
+// openContainerTerminal creates a terminal running the requested shell in a container of an
+// application. The user must be allowed to update containers in the organization of the
+// application. Opening and closing the session are recorded in the audit log.
+func (s *SocketServer) openContainerTerminal(conn *websocket.Conn, terminalId string, data map[string]interface{}) (*terminal.ContainerTerminal, error) {
+	containerID, _ := data["containerId"].(string)
+	if containerID == "" {
+		return nil, errMissingContainerID
+	}
+	shell, _ := data["shell"].(string)
+	if !validShell(shell) {
+		return nil, errInvalidShell
+	}
+
+	user, application, err := s.authorizeContainer(conn, containerID, "update")
+	if err != nil {
+		return nil, err
+	}
+
+	term := terminal.NewContainerTerminal(conn, s.writeLock(conn), &logger.Logger{}, s.dockerService, terminalId, containerID, shell)
+	sessionID := uuid.New()
+	metadata := map[string]any{
+		"event":          "opened",
+		"target":         containerTarget,
+		"container_id":   containerID,
+		"shell":          term.Shell,
+		"application_id": application.ID.String(),
+	}
+	s.auditTerminal(conn, user.ID, application.OrganizationID, sessionID, metadata)
+
+	go func() {
+		<-term.Done()
+
+		s.terminalMutex.Lock()
+		if s.terminals[conn][terminalId] == terminal.Session(term) {
+			delete(s.terminals[conn], terminalId)
+		}
+		s.terminalMutex.Unlock()
+
+		closed := make(map[string]any, len(metadata)+1)
+		for key, value := range metadata {
+			closed[key] = value
+		}
+		closed["event"] = "closed"
+		closed["duration_seconds"] = int(time.Since(term.StartedAt).Seconds())
+		s.auditTerminal(conn, user.ID, application.OrganizationID, sessionID, closed)
+	}()
+
+	return term, nil
+}
+
+// authorizeContainer returns the user behind the connection and the application the container
+// belongs to, when the user may take action on containers in the organization of the
+// application. Containers that do not belong to an application, like those of Nixopus itself,
+// are refused.
+func (s *SocketServer) authorizeContainer(conn *websocket.Conn, containerID string, action string) (*types.User, types.Application, error) {
+	details, err := s.dockerService.GetContainerById(containerID)
+	if err != nil {
+		return nil, types.Application{}, errContainerNotFound
+	}
+	if details.Config == nil {
+		return nil, types.Application{}, errNotApplicationTarget
+	}
+	return s.authorizeApplication(conn, details.Config.Labels, action)
+}
+
+// authorizeApplication returns the user behind the connection and the application named by
+// the labels of a container, when the user may take action on containers in the organization
+// of the application.
+func (s *SocketServer) authorizeApplication(conn *websocket.Conn, labels map[string]string, action string) (*types.User, types.Application, error) {
+	applicationID, err := uuid.Parse(labels[applicationLabel])
+	if err != nil {
+		return nil, types.Application{}, errNotApplicationTarget
+	}
+
+	application, err := s.applications.FindApplicationByID(applicationID)
+	if err != nil {
+		return nil, types.Application{}, errNotApplicationTarget
+	}
+
+	user, err := s.connectionUser(conn)
+	if err != nil {
+		return nil, types.Application{}, errContainerForbidden
+	}
+	if !hasPermission(user, application.OrganizationID, "container", action) {
+		return nil, types.Application{}, errContainerForbidden
+	}
+	return user, application, nil
+}
+
+// connectionUser returns the user behind the connection along with their roles and permissions.
+func (s *SocketServer) connectionUser(conn *websocket.Conn) (*types.User, error) {
+	userID, ok := s.conns.Load(conn)
+	if !ok {
+		return nil, errors.New("connection is not authenticated")
+	}
+	id, ok := userID.(uuid.UUID)
+	if !ok {
+		return nil, errors.New("connection is not authenticated")
+	}
+
+	user, err := s.users.FindUserByID(id.String())
+	if err != nil {
+		return nil, err
+	}
+	// the roles and permissions of the user are only loaded by email
+	return s.users.FindUserByEmail(user.Email)
+}
+
+// hasPermission reports whether the role of the user in the organization grants action on
+// resource, as the RBAC middleware checks it for HTTP requests.
+func hasPermission(user *types.User, organizationID uuid.UUID, resource, action string) bool {
+	for _, orgUser := range user.OrganizationUsers {
+		if orgUser.OrganizationID != organizationID || orgUser.Role == nil {
+			continue
+		}
+		for _, permission := range orgUser.Role.Permissions {
+			if permission.Resource == resource && permission.Name == action {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validShell reports whether shell can be run as a single program. It is executed without a
+// shell around it, so it may not carry arguments.
+func validShell(shell string) bool {
+	if len(shell) > maxShellLength {
+		return false
+	}
+	return !strings.ContainsFunc(shell, unicode.IsSpace)
+}
+
+func (s *SocketServer) auditTerminal(conn *websocket.Conn, userID, organizationID, sessionID uuid.UUID, metadata map[string]any) {
+	request := &audit_service.AuditLogRequest{
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Action:         types.AuditActionAccess,
+		ResourceType:   types.AuditResourceTerminal,
+		ResourceID:     sessionID,
+		Metadata:       metadata,
+		IPAddress:      conn.RemoteAddr().String(),
+		RequestID:      sessionID,
+	}
+	l := logger.NewLogger()
+	if err := audit_service.NewAuditService(s.db, context.Background(), l).LogAction(request); err != nil {
+		l.Log(logger.Warning, "Failed to audit terminal session", err.Error())
+	}
+}
@@ -0,0 +1,258 @@
+package realtime
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	user_storage "github.com/raghavyuva/nixopus-api/internal/features/auth/storage"
+	deploy_storage "github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
+	"github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// applicationsByID serves the applications containers may be labelled with.
+type applicationsByID struct {
+	deploy_storage.DeployRepository
+	applications map[uuid.UUID]types.Application
+}
+
+func (a applicationsByID) FindApplicationByID(applicationID uuid.UUID) (types.Application, error) {
+	application, ok := a.applications[applicationID]
+	if !ok {
+		return types.Application{}, errors.New("application not found")
+	}
+	return application, nil
+}
+
+// usersByID serves users along with the roles and permissions they have in their organizations.
+type usersByID struct {
+	user_storage.AuthRepository
+	users map[uuid.UUID]*types.User
+}
+
+func (u usersByID) FindUserByID(id string) (*types.User, error) {
+	for _, user := range u.users {
+		if user.ID.String() == id {
+			return &types.User{ID: user.ID, Email: user.Email}, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (u usersByID) FindUserByEmail(email string) (*types.User, error) {
+	for _, user := range u.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+// memberOf returns a user whose role in the organization grants the container permissions.
+func memberOf(organizationID uuid.UUID, email string, permissions ...string) *types.User {
+	role := &types.Role{ID: uuid.New(), Name: "role"}
+	for _, name := range permissions {
+		role.Permissions = append(role.Permissions, types.Permission{ID: uuid.New(), Name: name, Resource: "container"})
+	}
+	return &types.User{
+		ID:    uuid.New(),
+		Email: email,
+		OrganizationUsers: []*types.OrganizationUsers{
+			{OrganizationID: organizationID, Role: role},
+		},
+	}
+}
+
+func TestAuthorizeApplication(t *testing.T) {
+	organizationID := uuid.New()
+	foreignOrganizationID := uuid.New()
+	application := types.Application{ID: uuid.New(), OrganizationID: organizationID}
+	foreignApplication := types.Application{ID: uuid.New(), OrganizationID: foreignOrganizationID}
+
+	admin := memberOf(organizationID, "admin@example.com", "read", "update")
+	viewer := memberOf(organizationID, "viewer@example.com", "read")
+	noRole := &types.User{
+		ID:                uuid.New(),
+		Email:             "invited@example.com",
+		OrganizationUsers: []*types.OrganizationUsers{{OrganizationID: organizationID}},
+	}
+
+	s := &SocketServer{
+		conns: &sync.Map{},
+		applications: applicationsByID{applications: map[uuid.UUID]types.Application{
+			application.ID:        application,
+			foreignApplication.ID: foreignApplication,
+		}},
+		users: usersByID{users: map[uuid.UUID]*types.User{
+			admin.ID:  admin,
+			viewer.ID: viewer,
+			noRole.ID: noRole,
+		}},
+	}
+	connectionOf := func(user *types.User) *websocket.Conn {
+		conn := &websocket.Conn{}
+		if user != nil {
+			s.conns.Store(conn, user.ID)
+		}
+		return conn
+	}
+
+	tests := []struct {
+		name    string
+		user    *types.User
+		labels  map[string]string
+		action  string
+		wantErr error
+	}{
+		{
+			name:   "member with update permission opens a terminal",
+			user:   admin,
+			labels: map[string]string{applicationLabel: application.ID.String()},
+			action: "update",
+		},
+		{
+			name:   "read-only role follows logs",
+			user:   viewer,
+			labels: map[string]string{applicationLabel: application.ID.String()},
+			action: "read",
+		},
+		{
+			name:    "read-only role cannot open a terminal",
+			user:    viewer,
+			labels:  map[string]string{applicationLabel: application.ID.String()},
+			action:  "update",
+			wantErr: errContainerForbidden,
+		},
+		{
+			name:    "member without a role",
+			user:    noRole,
+			labels:  map[string]string{applicationLabel: application.ID.String()},
+			action:  "read",
+			wantErr: errContainerForbidden,
+		},
+		{
+			name:    "container of another organization",
+			user:    admin,
+			labels:  map[string]string{applicationLabel: foreignApplication.ID.String()},
+			action:  "update",
+			wantErr: errContainerForbidden,
+		},
+		{
+			name:    "container without application label",
+			user:    admin,
+			labels:  map[string]string{"com.docker.compose.project": "nixopus"},
+			action:  "update",
+			wantErr: errNotApplicationTarget,
+		},
+		{
+			name:    "container without labels",
+			user:    admin,
+			action:  "update",
+			wantErr: errNotApplicationTarget,
+		},
+		{
+			name:    "malformed application label",
+			user:    admin,
+			labels:  map[string]string{applicationLabel: "not-a-uuid"},
+			action:  "update",
+			wantErr: errNotApplicationTarget,
+		},
+		{
+			name:    "unknown application",
+			user:    admin,
+			labels:  map[string]string{applicationLabel: uuid.NewString()},
+			action:  "update",
+			wantErr: errNotApplicationTarget,
+		},
+		{
+			name:    "unauthenticated connection",
+			labels:  map[string]string{applicationLabel: application.ID.String()},
+			action:  "read",
+			wantErr: errContainerForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, got, err := s.authorizeApplication(connectionOf(tt.user), tt.labels, tt.action)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, user)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.user.ID, user.ID)
+			assert.Equal(t, application.ID, got.ID)
+		})
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	organizationID := uuid.New()
+	user := memberOf(organizationID, "viewer@example.com", "read")
+
+	assert.True(t, hasPermission(user, organizationID, "container", "read"))
+	assert.False(t, hasPermission(user, organizationID, "container", "update"))
+	assert.False(t, hasPermission(user, organizationID, "deploy", "read"))
+	assert.False(t, hasPermission(user, uuid.New(), "container", "read"))
+}
+
+func TestOpenContainerTerminalRejectsRequest(t *testing.T) {
+	s := &SocketServer{}
+
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name:    "missing container",
+			data:    map[string]interface{}{"shell": "/bin/sh"},
+			wantErr: errMissingContainerID,
+		},
+		{
+			name:    "shell with arguments",
+			data:    map[string]interface{}{"containerId": "abc", "shell": "sh -c 'cat /etc/shadow'"},
+			wantErr: errInvalidShell,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, err := s.openContainerTerminal(&websocket.Conn{}, "terminal", tt.data)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, term)
+		})
+	}
+}
+
+func TestValidShell(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  bool
+	}{
+		{shell: "", want: true},
+		{shell: "/bin/sh", want: true},
+		{shell: "bash", want: true},
+		{shell: "/usr/bin/zsh", want: true},
+		{shell: "bash -i", want: false},
+		{shell: "sh -c id", want: false},
+		{shell: "sh\t-c", want: false},
+		{shell: "sh\n", want: false},
+		{shell: " /bin/sh", want: false},
+		{shell: "/bin/sh -c", want: false},
+		{shell: "/" + strings.Repeat("a", maxShellLength), want: false},
+		{shell: strings.Repeat("a", maxShellLength), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			assert.Equal(t, tt.want, validShell(tt.shell))
+		})
+	}
+}
@@ -2,8 +2,10 @@ package realtime
 
 import (
 	"encoding/json"
+	"slices"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/raghavyuva/nixopus-api/internal/features/dashboard"
 	"github.com/raghavyuva/nixopus-api/internal/features/logger"
@@ -24,7 +26,7 @@ func (s *SocketServer) handleStopDashboardMonitor(conn *websocket.Conn) {
 		monitor.Stop()
 		delete(s.dashboardMonitors, conn)
 
-		conn.WriteJSON(types.Payload{
+		s.writeJSON(conn, types.Payload{
 			Action: "dashboard_monitor_stopped",
 			Data:   nil,
 		})
@@ -44,7 +46,7 @@ func (s *SocketServer) handleDashboardMonitor(conn *websocket.Conn, msg types.Pa
 	s.dashboardMutex.Lock()
 	monitor, exists := s.dashboardMonitors[conn]
 	if !exists {
-		newMonitor, err := dashboard.NewDashboardMonitor(conn, logger.NewLogger())
+		newMonitor, err := dashboard.NewDashboardMonitor(conn, s.writeLock(conn), logger.NewLogger())
 		if err != nil {
 			s.dashboardMutex.Unlock()
 			s.sendError(conn, "Failed to create dashboard monitor")
@@ -82,6 +84,7 @@ func (s *SocketServer) handleDashboardMonitor(conn *websocket.Conn, msg types.Pa
 		if len(operations) == 0 {
 			operations = dashboard.AllOperations
 		}
+		operations = s.authorizeContainerStats(conn, monitor, dataMap, operations)
 
 		config := dashboard.MonitoringConfig{
 			Interval:   interval,
@@ -125,5 +128,30 @@ func (s *SocketServer) sendResponse(conn *websocket.Conn, response types.Payload
 		return
 	}
 
-	conn.WriteMessage(websocket.TextMessage, jsonData)
+	s.writeMessage(conn, websocket.TextMessage, jsonData)
+}
+
+// authorizeContainerStats scopes the container stats of the monitor to the organization_id of
+// the request. The operation is dropped when the user may not read the containers of the
+// organization.
+func (s *SocketServer) authorizeContainerStats(conn *websocket.Conn, monitor *dashboard.DashboardMonitor, data map[string]interface{}, operations []dashboard.DashboardOperation) []dashboard.DashboardOperation {
+	if !slices.Contains(operations, dashboard.GetContainerStats) {
+		return operations
+	}
+
+	id, _ := data["organization_id"].(string)
+	organizationID, err := uuid.Parse(id)
+	if err == nil {
+		var user *types.User
+		if user, err = s.connectionUser(conn); err == nil && hasPermission(user, organizationID, "container", "read") {
+			monitor.OrganizationID = organizationID
+			monitor.ContainerStats = s.containerStats
+			return operations
+		}
+	}
+
+	s.sendError(conn, "Not allowed to read the container stats of this organization")
+	return slices.DeleteFunc(slices.Clone(operations), func(operation dashboard.DashboardOperation) bool {
+		return operation == dashboard.GetContainerStats
+	})
 }
@@ -11,9 +11,14 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/joho/godotenv"
+	user_storage "github.com/raghavyuva/nixopus-api/internal/features/auth/storage"
+	container_service "github.com/raghavyuva/nixopus-api/internal/features/container/service"
 	"github.com/raghavyuva/nixopus-api/internal/features/dashboard"
 	deploy "github.com/raghavyuva/nixopus-api/internal/features/deploy/controller"
+	"github.com/raghavyuva/nixopus-api/internal/features/deploy/docker"
 	"github.com/raghavyuva/nixopus-api/internal/features/deploy/realtime"
+	deploy_storage "github.com/raghavyuva/nixopus-api/internal/features/deploy/storage"
+	"github.com/raghavyuva/nixopus-api/internal/features/logger"
 	"github.com/raghavyuva/nixopus-api/internal/features/terminal"
 	"github.com/raghavyuva/nixopus-api/internal/types"
 	"github.com/uptrace/bun"
@@ -29,6 +34,7 @@ type topics string
 
 const (
 	MonitorApplicationDeployment topics = "monitor_application_deployment"
+	DeploymentQueues             topics = "deployment_queues"
 )
 
 var upgrader = websocket.Upgrader{
@@ -41,15 +47,23 @@ var upgrader = websocket.Upgrader{
 
 type SocketServer struct {
 	conns               *sync.Map
+	writeLocks          sync.Map // *websocket.Conn -> *sync.Mutex, see writeLock
 	topicsMu            sync.RWMutex
 	topics              map[string]map[*websocket.Conn]bool
 	shutdown            chan struct{}
 	deployController    *deploy.DeployController
 	db                  *bun.DB
 	ctx                 context.Context
+	applications        deploy_storage.DeployRepository // looks up the application of a container, see authorizeApplication
+	users               user_storage.AuthRepository
 	postgres_listener   PostgresListener
 	terminalMutex       sync.RWMutex
-	terminals           map[*websocket.Conn]map[string]*terminal.Terminal // conn -> terminalId -> terminal session for handling multiple terminal sessions per connection
+	terminals           map[*websocket.Conn]map[string]terminal.Session // conn -> terminalId -> terminal session for handling multiple terminal sessions per connection
+	dockerService       *docker.DockerService
+	containerStats      *container_service.ContainerStatsService
+	containerLogs       *container_service.ContainerLogService
+	logMutex            sync.Mutex
+	logConnections      map[*websocket.Conn]*logConnection
 	dashboardMonitors   map[*websocket.Conn]*dashboard.DashboardMonitor
 	dashboardMutex      sync.Mutex
 	applicationMonitors map[*websocket.Conn]*realtime.ApplicationMonitor
@@ -64,6 +78,7 @@ func NewSocketServer(deployController *deploy.DeployController, db *bun.DB, ctx
 	}
 
 	pgListener := NewPostgresListener()
+	dockerService := docker.NewDockerService()
 
 	server := &SocketServer{
 		conns:               &sync.Map{},
@@ -71,9 +86,15 @@ func NewSocketServer(deployController *deploy.DeployController, db *bun.DB, ctx
 		deployController:    deployController,
 		db:                  db,
 		ctx:                 ctx,
+		applications:        &deploy_storage.DeployStorage{DB: db, Ctx: ctx},
+		users:               &user_storage.UserStorage{DB: db, Ctx: ctx},
 		topics:              make(map[string]map[*websocket.Conn]bool),
 		postgres_listener:   *pgListener,
-		terminals:           make(map[*websocket.Conn]map[string]*terminal.Terminal),
+		terminals:           make(map[*websocket.Conn]map[string]terminal.Session),
+		dockerService:       dockerService,
+		containerStats:      container_service.NewContainerStatsService(db, ctx, logger.NewLogger(), dockerService),
+		containerLogs:       container_service.NewContainerLogService(dockerService),
+		logConnections:      make(map[*websocket.Conn]*logConnection),
 		dashboardMonitors:   make(map[*websocket.Conn]*dashboard.DashboardMonitor),
 		applicationMonitors: make(map[*websocket.Conn]*realtime.ApplicationMonitor),
 	}
@@ -82,6 +103,7 @@ func NewSocketServer(deployController *deploy.DeployController, db *bun.DB, ctx
 		log.Printf("Error initializing postgres listener: %v", err)
 		return nil, err
 	}
+	go server.monitorDeploymentQueues()
 	return server, nil
 }
 
@@ -120,6 +142,7 @@ func (s *SocketServer) HandleHTTP(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Auth error: %v", err)
 		s.sendError(conn, "Invalid authorization token")
 		conn.Close()
+		s.writeLocks.Delete(conn)
 		return
 	}
 
@@ -170,6 +193,8 @@ func (s *SocketServer) handleDisconnect(conn *websocket.Conn) {
 	}
 	s.dashboardMutex.Unlock()
 
+	s.stopLogStreams(conn)
+
 	s.applicationMutex.Lock()
 	if monitor, exists := s.applicationMonitors[conn]; exists {
 		monitor.Stop()
@@ -178,6 +203,7 @@ func (s *SocketServer) handleDisconnect(conn *websocket.Conn) {
 	s.applicationMutex.Unlock()
 
 	conn.Close()
+	s.writeLocks.Delete(conn)
 	fmt.Printf("Client disconnected: %s (User ID: %v)\n", conn.RemoteAddr(), userID)
 }
 
@@ -195,7 +221,7 @@ func (s *SocketServer) handlePing(conn *websocket.Conn) {
 }
 
 func (s *SocketServer) sendError(conn *websocket.Conn, message string) {
-	conn.WriteJSON(types.Payload{
+	s.writeJSON(conn, types.Payload{
 		Action: "error",
 		Data:   message,
 	})
@@ -0,0 +1,301 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	container_types "github.com/raghavyuva/nixopus-api/internal/features/container/types"
+	"github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const (
+	maxLogStreamsPerConnection = 8
+	// logQueueSize is how many lines of a stream wait to be sent before new lines are dropped,
+	// so a slow client never holds up the Docker daemon.
+	logQueueSize     = 2000
+	logBatchSize     = 200
+	logFlushInterval = 100 * time.Millisecond
+	defaultLogTail   = "100"
+)
+
+var (
+	errMissingStreamID   = errors.New("Missing stream_id")
+	errMissingLogSource  = errors.New("Either container_id or service_id is required")
+	errNoLogStreams      = errors.New("At least one of stdout and stderr must be followed")
+	errTooManyLogStreams = errors.New("Too many log streams on this connection")
+	errServiceNotFound   = errors.New("Service not found")
+)
+
+// logConnection holds the log streams of a connection.
+type logConnection struct {
+	streams map[string]*logStream
+}
+
+type logStream struct {
+	cancel context.CancelFunc
+}
+
+// logBatch carries lines of a stream to the client. Dropped counts the lines left out since
+// the previous batch because the client did not keep up.
+type logBatch struct {
+	StreamID string                    `json:"stream_id"`
+	Lines    []container_types.LogLine `json:"lines"`
+	Dropped  int64                     `json:"dropped,omitempty"`
+}
+
+// handleFollowLogs starts following the logs of a container or a swarm service of an
+// application. The logs of a service merge all of its replicas, each line labelled with its
+// task. Starting a stream with the id of a running one replaces it.
+//
+// Parameters:
+//
+//	conn - the *websocket.Conn representing the client connection.
+//	msg - the types.Payload carrying stream_id, container_id or service_id and the options.
+func (s *SocketServer) handleFollowLogs(conn *websocket.Conn, msg types.Payload) {
+	data, ok := msg.Data.(map[string]interface{})
+	if !ok {
+		s.sendError(conn, "Invalid follow logs data")
+		return
+	}
+	streamID, _ := data["stream_id"].(string)
+	if streamID == "" {
+		s.sendError(conn, errMissingStreamID.Error())
+		return
+	}
+	containerID, _ := data["container_id"].(string)
+	serviceID, _ := data["service_id"].(string)
+	if (containerID == "") == (serviceID == "") {
+		s.sendError(conn, errMissingLogSource.Error())
+		return
+	}
+	options, err := followLogsOptions(data)
+	if err != nil {
+		s.sendError(conn, err.Error())
+		return
+	}
+
+	if containerID != "" {
+		_, _, err = s.authorizeContainer(conn, containerID, "read")
+	} else {
+		err = s.authorizeService(conn, serviceID)
+	}
+	if err != nil {
+		s.sendError(conn, err.Error())
+		return
+	}
+
+	s.logMutex.Lock()
+	lc, exists := s.logConnections[conn]
+	if !exists {
+		lc = &logConnection{streams: make(map[string]*logStream)}
+		s.logConnections[conn] = lc
+	}
+	if previous, running := lc.streams[streamID]; running {
+		previous.cancel()
+		delete(lc.streams, streamID)
+	}
+	if len(lc.streams) >= maxLogStreamsPerConnection {
+		s.logMutex.Unlock()
+		s.sendError(conn, errTooManyLogStreams.Error())
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	stream := &logStream{cancel: cancel}
+	lc.streams[streamID] = stream
+	s.logMutex.Unlock()
+
+	s.writeJSON(conn, types.Payload{
+		Action: "logs_started",
+		Data:   map[string]interface{}{"stream_id": streamID},
+	})
+
+	queue := make(chan container_types.LogLine, logQueueSize)
+	var dropped atomic.Int64
+	emit := func(line container_types.LogLine) {
+		select {
+		case queue <- line:
+		default:
+			dropped.Add(1)
+		}
+	}
+
+	followErr := make(chan error, 1)
+	go func() {
+		defer close(queue)
+		if containerID != "" {
+			followErr <- s.containerLogs.FollowContainer(ctx, containerID, options, emit)
+		} else {
+			followErr <- s.containerLogs.FollowService(ctx, serviceID, options, emit)
+		}
+	}()
+	go s.writeLogs(ctx, conn, lc, streamID, stream, queue, &dropped, followErr)
+}
+
+// writeLogs sends the queued lines of a stream in batches, once a batch is full or every
+// logFlushInterval, until the logs end. The stream is stopped when a batch cannot be written
+// to the client within logWriteTimeout.
+func (s *SocketServer) writeLogs(ctx context.Context, conn *websocket.Conn, lc *logConnection, streamID string, stream *logStream, queue <-chan container_types.LogLine, dropped *atomic.Int64, followErr <-chan error) {
+	defer s.removeLogStream(conn, streamID, stream)
+
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+
+	lines := make([]container_types.LogLine, 0, logBatchSize)
+	flush := func() bool {
+		n := dropped.Swap(0)
+		if len(lines) == 0 && n == 0 {
+			return true
+		}
+		err := s.writeJSON(conn, types.Payload{
+			Action: "logs",
+			Topic:  streamID,
+			Data:   logBatch{StreamID: streamID, Lines: lines, Dropped: n},
+		})
+		lines = make([]container_types.LogLine, 0, logBatchSize)
+		return err == nil
+	}
+
+	for {
+		select {
+		case line, ok := <-queue:
+			if !ok {
+				if !flush() {
+					return
+				}
+				if ctx.Err() != nil {
+					// stopped by the client or the connection is gone
+					return
+				}
+				ended := map[string]interface{}{"stream_id": streamID}
+				if err := <-followErr; err != nil {
+					ended["error"] = err.Error()
+				}
+				s.writeJSON(conn, types.Payload{Action: "logs_ended", Data: ended})
+				return
+			}
+			lines = append(lines, line)
+			if len(lines) >= logBatchSize && !flush() {
+				return
+			}
+		case <-ticker.C:
+			if !flush() {
+				return
+			}
+		}
+	}
+}
+
+// handleStopFollowLogs stops a log stream of the connection.
+//
+// Parameters:
+//
+//	conn - the *websocket.Conn representing the client connection.
+//	msg - the types.Payload carrying the stream_id to stop.
+func (s *SocketServer) handleStopFollowLogs(conn *websocket.Conn, msg types.Payload) {
+	data, _ := msg.Data.(map[string]interface{})
+	streamID, _ := data["stream_id"].(string)
+	if streamID == "" {
+		s.sendError(conn, errMissingStreamID.Error())
+		return
+	}
+
+	s.logMutex.Lock()
+	lc, exists := s.logConnections[conn]
+	var stream *logStream
+	if exists {
+		stream = lc.streams[streamID]
+		delete(lc.streams, streamID)
+	}
+	s.logMutex.Unlock()
+
+	if stream == nil {
+		s.sendError(conn, "Log stream not found")
+		return
+	}
+	stream.cancel()
+	s.writeJSON(conn, types.Payload{
+		Action: "logs_stopped",
+		Data:   map[string]interface{}{"stream_id": streamID},
+	})
+}
+
+// stopLogStreams stops all log streams of a connection that is closing.
+func (s *SocketServer) stopLogStreams(conn *websocket.Conn) {
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+
+	if lc, exists := s.logConnections[conn]; exists {
+		for _, stream := range lc.streams {
+			stream.cancel()
+		}
+		delete(s.logConnections, conn)
+	}
+}
+
+func (s *SocketServer) removeLogStream(conn *websocket.Conn, streamID string, stream *logStream) {
+	stream.cancel()
+
+	s.logMutex.Lock()
+	defer s.logMutex.Unlock()
+	if lc, exists := s.logConnections[conn]; exists && lc.streams[streamID] == stream {
+		delete(lc.streams, streamID)
+	}
+}
+
+// authorizeService checks that the swarm service runs an application whose containers the
+// user behind the connection may read.
+func (s *SocketServer) authorizeService(conn *websocket.Conn, serviceID string) error {
+	service, err := s.dockerService.GetServiceByID(serviceID)
+	if err != nil {
+		return errServiceNotFound
+	}
+	spec := service.Spec.TaskTemplate.ContainerSpec
+	if spec == nil {
+		return errNotApplicationTarget
+	}
+	_, _, err = s.authorizeApplication(conn, spec.Labels, "read")
+	return err
+}
+
+// followLogsOptions reads the options of a log stream. tail is a number of lines or "all",
+// the last 100 lines by default, and both stdout and stderr are followed unless one of them is
+// turned off.
+func followLogsOptions(data map[string]interface{}) (container_types.FollowLogsOptions, error) {
+	options := container_types.FollowLogsOptions{
+		Tail:   defaultLogTail,
+		Stdout: true,
+		Stderr: true,
+	}
+	options.Since, _ = data["since"].(string)
+	options.Timestamps, _ = data["timestamps"].(bool)
+
+	switch tail := data["tail"].(type) {
+	case float64:
+		if tail < 0 {
+			return options, errors.New("tail must not be negative")
+		}
+		options.Tail = strconv.Itoa(int(tail))
+	case string:
+		if tail != "all" {
+			if n, err := strconv.Atoi(tail); err != nil || n < 0 {
+				return options, errors.New("tail must be a number or all")
+			}
+		}
+		options.Tail = tail
+	}
+
+	if stdout, ok := data["stdout"].(bool); ok {
+		options.Stdout = stdout
+	}
+	if stderr, ok := data["stderr"].(bool); ok {
+		options.Stderr = stderr
+	}
+	if !options.Stdout && !options.Stderr {
+		return options, errNoLogStreams
+	}
+	return options, nil
+}
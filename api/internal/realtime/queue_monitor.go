@@ -0,0 +1,73 @@
+package realtime
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	user_storage "github.com/raghavyuva/nixopus-api/internal/features/auth/storage"
+	"github.com/raghavyuva/nixopus-api/internal/types"
+)
+
+const queueMonitorInterval = 5 * time.Second
+
+// isAdminConnection reports whether the user behind the connection is an admin.
+func (s *SocketServer) isAdminConnection(conn *websocket.Conn) bool {
+	userID, ok := s.conns.Load(conn)
+	if !ok {
+		return false
+	}
+
+	id, ok := userID.(uuid.UUID)
+	if !ok {
+		return false
+	}
+
+	userStorage := user_storage.UserStorage{
+		DB:  s.db,
+		Ctx: s.ctx,
+	}
+
+	user, err := userStorage.FindUserByID(id.String())
+	if err != nil || user == nil {
+		return false
+	}
+
+	return user.Type == types.UserTypeAdmin
+}
+
+// hasSubscribers reports whether at least one connection is subscribed to the topic.
+func (s *SocketServer) hasSubscribers(topic topics) bool {
+	s.topicsMu.RLock()
+	defer s.topicsMu.RUnlock()
+	return len(s.topics[string(topic)]) > 0
+}
+
+// monitorDeploymentQueues periodically broadcasts the state of the deployment queues
+// to the subscribers of the DeploymentQueues topic until the server shuts down.
+func (s *SocketServer) monitorDeploymentQueues() {
+	ticker := time.NewTicker(queueMonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdown:
+			return
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.hasSubscribers(DeploymentQueues) {
+				continue
+			}
+
+			queues, err := s.deployController.GetDeploymentQueueStatus()
+			if err != nil {
+				log.Printf("Error inspecting deployment queues: %v", err)
+				continue
+			}
+
+			s.BroadcastToTopic(DeploymentQueues, "", queues)
+		}
+	}
+}
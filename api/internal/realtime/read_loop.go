@@ -51,6 +51,12 @@ func (s *SocketServer) readLoop(conn *websocket.Conn) {
 		case types.STOP_DASHBOARD_MONITOR:
 			s.handleStopDashboardMonitor(conn)
 
+		case types.FOLLOW_LOGS:
+			s.handleFollowLogs(conn, msg)
+
+		case types.STOP_FOLLOW_LOGS:
+			s.handleStopFollowLogs(conn, msg)
+
 		case types.MONITOR_APPLICATION:
 			// s.handleMonitorApplication(conn, msg, user)
 
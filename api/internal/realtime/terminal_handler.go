@@ -9,6 +9,7 @@ import (
 
 // handleTerminal handles the terminal connection.
 // It creates a new terminal if it doesn't exist, otherwise it writes the message to the existing terminal.
+// The terminal is a shell on the host, or a shell in a container when target is "container".
 // Parameters:
 //
 //	conn - the *websocket.Conn representing the client connection.
@@ -35,15 +36,26 @@ func (s *SocketServer) handleTerminal(conn *websocket.Conn, msg types.Payload) {
 
 	// Ensure map exists for this connection
 	if s.terminals[conn] == nil {
-		s.terminals[conn] = make(map[string]*terminal.Terminal)
+		s.terminals[conn] = make(map[string]terminal.Session)
 	}
 
 	term, exists := s.terminals[conn][terminalId]
 	if !exists {
-		newTerminal, err := terminal.NewTerminal(conn, &logger.Logger{}, terminalId)
-		if err != nil {
-			s.sendError(conn, "Failed to start terminal")
-			return
+		var newTerminal terminal.Session
+		if target, _ := dataMap["target"].(string); target == containerTarget {
+			containerTerminal, err := s.openContainerTerminal(conn, terminalId, dataMap)
+			if err != nil {
+				s.sendError(conn, err.Error())
+				return
+			}
+			newTerminal = containerTerminal
+		} else {
+			hostTerminal, err := terminal.NewTerminal(conn, s.writeLock(conn), &logger.Logger{}, terminalId)
+			if err != nil {
+				s.sendError(conn, "Failed to start terminal")
+				return
+			}
+			newTerminal = hostTerminal
 		}
 		s.terminals[conn][terminalId] = newTerminal
 		go newTerminal.Start()
@@ -27,6 +27,11 @@ func (s *SocketServer) handleSubscribe(conn *websocket.Conn, msg types.Payload)
 			}
 		}
 
+		if topics(msg.Topic) == DeploymentQueues && !s.isAdminConnection(conn) {
+			s.sendError(conn, "Only admins can subscribe to the deployment queues")
+			return
+		}
+
 		s.SubscribeToTopic(topics(msg.Topic), resourceID, conn)
 		return
 	}
@@ -80,7 +85,7 @@ func (s *SocketServer) SubscribeToTopic(topic topics, resourceID string, conn *w
 	}
 	s.topics[topicKey][conn] = true
 
-	conn.WriteJSON(types.Payload{
+	s.writeJSON(conn, types.Payload{
 		Action: "subscribed",
 		Topic:  string(topicKey),
 		Data:   nil,
@@ -112,7 +117,7 @@ func (s *SocketServer) UnsubscribeFromTopic(topic topics, resourceID string, con
 			delete(s.topics, topicKey)
 		}
 
-		conn.WriteJSON(types.Payload{
+		s.writeJSON(conn, types.Payload{
 			Action: "unsubscribed",
 			Topic:  string(topicKey),
 			Data:   nil,
@@ -139,7 +144,7 @@ func (s *SocketServer) BroadcastToTopic(topic topics, resourceID string, payload
 
 	if connections, exists := s.topics[topicKey]; exists {
 		for conn := range connections {
-			err := conn.WriteJSON(types.Payload{
+			err := s.writeJSON(conn, types.Payload{
 				Action: "message",
 				Topic:  string(topicKey),
 				Data:   payload,
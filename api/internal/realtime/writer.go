@@ -0,0 +1,43 @@
+package realtime
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// writeTimeout bounds a single write to a client, so a client that stopped reading can not
+// hold up the other writers of its connection for long.
+const writeTimeout = 10 * time.Second
+
+// writeLock returns the lock every write to conn is made under. A websocket connection supports
+// a single writer at a time while topics, terminals, log streams and the dashboard monitor all
+// write from their own goroutines, so they share this lock instead of keeping their own.
+func (s *SocketServer) writeLock(conn *websocket.Conn) *sync.Mutex {
+	mu, _ := s.writeLocks.LoadOrStore(conn, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// writeMessage writes a message to conn under its write lock. The deadline is set and cleared
+// while the lock is held, so it only applies to this write.
+func (s *SocketServer) writeMessage(conn *websocket.Conn, messageType int, data []byte) error {
+	mu := s.writeLock(conn)
+	mu.Lock()
+	defer mu.Unlock()
+
+	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	err := conn.WriteMessage(messageType, data)
+	_ = conn.SetWriteDeadline(time.Time{})
+	return err
+}
+
+// writeJSON writes v to conn as a JSON text message under its write lock.
+func (s *SocketServer) writeJSON(conn *websocket.Conn, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.writeMessage(conn, websocket.TextMessage, data)
+}
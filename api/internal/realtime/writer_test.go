@@ -0,0 +1,58 @@
+package realtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/raghavyuva/nixopus-api/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConcurrentWrites writes to a connection from as many goroutines as there are messages.
+// Gorilla panics on concurrent writes, so this fails unless every write holds the same lock.
+func TestConcurrentWrites(t *testing.T) {
+	const writers = 50
+	s := &SocketServer{}
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if i%2 == 0 {
+					s.sendError(conn, "error")
+					return
+				}
+				mu := s.writeLock(conn)
+				mu.Lock()
+				defer mu.Unlock()
+				assert.NoError(t, conn.WriteJSON(types.Payload{Action: "message"}))
+			}(i)
+		}
+		wg.Wait()
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := 0; i < writers; i++ {
+		var payload types.Payload
+		require.NoError(t, client.ReadJSON(&payload))
+		assert.Contains(t, []types.AvailableActions{"error", "message"}, payload.Action)
+	}
+}
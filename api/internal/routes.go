@@ -14,7 +14,10 @@ import (
 	auth "github.com/raghavyuva/nixopus-api/internal/features/auth/controller"
 	authService "github.com/raghavyuva/nixopus-api/internal/features/auth/service"
 	user_storage "github.com/raghavyuva/nixopus-api/internal/features/auth/storage"
+	backup "github.com/raghavyuva/nixopus-api/internal/features/backup/controller"
+	certificate "github.com/raghavyuva/nixopus-api/internal/features/certificate/controller"
 	container "github.com/raghavyuva/nixopus-api/internal/features/container/controller"
+	database "github.com/raghavyuva/nixopus-api/internal/features/database/controller"
 	deploy "github.com/raghavyuva/nixopus-api/internal/features/deploy/controller"
 	domain "github.com/raghavyuva/nixopus-api/internal/features/domain/controller"
 	feature_flags_controller "github.com/raghavyuva/nixopus-api/internal/features/feature-flags/controller"
@@ -33,9 +36,12 @@ import (
 	permissions_storage "github.com/raghavyuva/nixopus-api/internal/features/permission/storage"
 	role_service "github.com/raghavyuva/nixopus-api/internal/features/role/service"
 	role_storage "github.com/raghavyuva/nixopus-api/internal/features/role/storage"
+	template "github.com/raghavyuva/nixopus-api/internal/features/template/controller"
+	traffic "github.com/raghavyuva/nixopus-api/internal/features/traffic/controller"
 	update "github.com/raghavyuva/nixopus-api/internal/features/update/controller"
 	update_service "github.com/raghavyuva/nixopus-api/internal/features/update/service"
 	user "github.com/raghavyuva/nixopus-api/internal/features/user/controller"
+	webhooks "github.com/raghavyuva/nixopus-api/internal/features/webhooks/controller"
 	"github.com/raghavyuva/nixopus-api/internal/middleware"
 	"github.com/raghavyuva/nixopus-api/internal/realtime"
 	"github.com/raghavyuva/nixopus-api/internal/storage"
@@ -105,6 +111,10 @@ func (router *Router) Routes() {
 	webhookGroup := fuego.Group(server, apiV1.Path+"/webhook")
 	fuego.Post(webhookGroup, "", deployController.HandleGithubWebhook)
 
+	// requests for sleeping applications are forwarded here by the proxy and carry a wake token instead of a session
+	wakeGroup := fuego.Group(server, apiV1.Path+"/wake")
+	fuego.Handle(wakeGroup, "/{application_id}/{path...}", http.HandlerFunc(deployController.WakeApplication))
+
 	router.WebSocketServer(server, deployController)
 
 	userStorage := &user_storage.UserStorage{DB: router.app.Store.DB, Ctx: router.app.Ctx}
@@ -165,6 +175,19 @@ func (router *Router) Routes() {
 	})
 	router.DomainRoutes(domainGroup, domainsAllGroup, domainController)
 
+	certificateController := certificate.NewCertificateController(router.app.Store, router.app.Ctx, l, notificationManager)
+	certificateGroup := fuego.Group(server, apiV1.Path+"/certificates")
+	fuego.Use(certificateGroup, func(next http.Handler) http.Handler {
+		return middleware.RBACMiddleware(next, router.app, "domain")
+	})
+	fuego.Use(certificateGroup, func(next http.Handler) http.Handler {
+		return middleware.FeatureFlagMiddleware(next, router.app, "domain", router.cache)
+	})
+	fuego.Use(certificateGroup, func(next http.Handler) http.Handler {
+		return middleware.AuditMiddleware(next, router.app, l, "domain")
+	})
+	router.CertificateRoutes(certificateGroup, certificateController)
+
 	githubConnectorController := githubConnector.NewGithubConnectorController(router.app.Store, router.app.Ctx, l, notificationManager)
 	githubConnectorGroup := fuego.Group(server, apiV1.Path+"/github-connector")
 	fuego.Use(githubConnectorGroup, func(next http.Handler) http.Handler {
@@ -226,6 +249,68 @@ func (router *Router) Routes() {
 	})
 	router.DeployRoutes(deployGroup, deployController)
 
+	trafficController := traffic.NewTrafficController(router.app.Store, router.app.Ctx, l)
+	trafficGroup := fuego.Group(server, apiV1.Path+"/traffic")
+	fuego.Use(trafficGroup, func(next http.Handler) http.Handler {
+		return middleware.RBACMiddleware(next, router.app, "deploy")
+	})
+	fuego.Use(trafficGroup, func(next http.Handler) http.Handler {
+		return middleware.FeatureFlagMiddleware(next, router.app, "deploy", router.cache)
+	})
+	router.TrafficRoutes(trafficGroup, trafficController)
+
+	databaseController := database.NewDatabaseController(router.app.Store, router.app.Ctx, l, notificationManager)
+	databaseGroup := fuego.Group(server, apiV1.Path+"/databases")
+	fuego.Use(databaseGroup, func(next http.Handler) http.Handler {
+		return middleware.RBACMiddleware(next, router.app, "database")
+	})
+	fuego.Use(databaseGroup, func(next http.Handler) http.Handler {
+		return middleware.FeatureFlagMiddleware(next, router.app, "deploy", router.cache)
+	})
+	fuego.Use(databaseGroup, func(next http.Handler) http.Handler {
+		return middleware.AuditMiddleware(next, router.app, l, "database")
+	})
+	router.DatabaseRoutes(databaseGroup, databaseController)
+
+	backupController := backup.NewBackupController(router.app.Store, router.app.Ctx, l, notificationManager)
+	backupGroup := fuego.Group(server, apiV1.Path+"/backups")
+	fuego.Use(backupGroup, func(next http.Handler) http.Handler {
+		return middleware.RBACMiddleware(next, router.app, "backup")
+	})
+	fuego.Use(backupGroup, func(next http.Handler) http.Handler {
+		return middleware.FeatureFlagMiddleware(next, router.app, "deploy", router.cache)
+	})
+	fuego.Use(backupGroup, func(next http.Handler) http.Handler {
+		return middleware.AuditMiddleware(next, router.app, l, "backup")
+	})
+	router.BackupRoutes(backupGroup, backupController)
+
+	templateController := template.NewTemplateController(router.app.Store, router.app.Ctx, l, notificationManager)
+	templateGroup := fuego.Group(server, apiV1.Path+"/templates")
+	fuego.Use(templateGroup, func(next http.Handler) http.Handler {
+		return middleware.RBACMiddleware(next, router.app, "template")
+	})
+	fuego.Use(templateGroup, func(next http.Handler) http.Handler {
+		return middleware.FeatureFlagMiddleware(next, router.app, "deploy", router.cache)
+	})
+	fuego.Use(templateGroup, func(next http.Handler) http.Handler {
+		return middleware.AuditMiddleware(next, router.app, l, "template")
+	})
+	router.TemplateRoutes(templateGroup, templateController)
+
+	webhookController := webhooks.NewWebhookController(router.app.Store, router.app.Ctx, l)
+	webhookSubscriptionGroup := fuego.Group(server, apiV1.Path+"/webhooks")
+	fuego.Use(webhookSubscriptionGroup, func(next http.Handler) http.Handler {
+		return middleware.RBACMiddleware(next, router.app, "webhook")
+	})
+	fuego.Use(webhookSubscriptionGroup, func(next http.Handler) http.Handler {
+		return middleware.FeatureFlagMiddleware(next, router.app, "notifications", router.cache)
+	})
+	fuego.Use(webhookSubscriptionGroup, func(next http.Handler) http.Handler {
+		return middleware.AuditMiddleware(next, router.app, l, "webhook")
+	})
+	router.WebhookRoutes(webhookSubscriptionGroup, webhookController)
+
 	auditController := audit.NewAuditController(router.app.Store.DB, router.app.Ctx, l)
 	auditGroup := fuego.Group(server, apiV1.Path+"/audit")
 	fuego.Use(auditGroup, func(next http.Handler) http.Handler {
@@ -356,6 +441,8 @@ func (router *Router) DomainRoutes(s *fuego.Server, domainsGroup *fuego.Server,
 	fuego.Put(s, "", domainController.UpdateDomain)
 	fuego.Delete(s, "", domainController.DeleteDomain)
 	fuego.Get(s, "/generate", domainController.GenerateRandomSubDomain)
+	fuego.Post(s, "/verify", domainController.VerifyDomain)
+	fuego.Get(s, "/status", domainController.GetDomainStatus)
 	fuego.Get(domainsGroup, "", domainController.GetDomains)
 }
 
@@ -369,6 +456,13 @@ func (router *Router) GithubConnectorRoutes(s *fuego.Server, githubConnectorCont
 
 func (router *Router) DeployRoutes(f *fuego.Server, deployController *deploy.DeployController) {
 	fuego.Get(f, "/applications", deployController.GetApplications)
+	fuego.Get(f, "/port-allocations", deployController.GetPortAllocations)
+	fuego.Get(f, "/server-ports", deployController.GetServerPorts)
+	fuego.Get(f, "/queues", deployController.GetDeploymentQueues)
+	fuego.Get(f, "/queues/dead-letters", deployController.GetDeadLetters)
+	fuego.Post(f, "/queues/reprioritize", deployController.ReprioritizeQueueTask)
+	fuego.Post(f, "/queues/purge", deployController.PurgeQueue)
+	fuego.Post(f, "/proxy/reconcile", deployController.ReconcileProxy)
 	deploy_application_group := fuego.Group(f, "/application")
 	router.DeployApplicationRoutes(deploy_application_group, deployController)
 }
@@ -382,11 +476,79 @@ func (router *Router) DeployApplicationRoutes(f *fuego.Server, deployController
 	fuego.Get(f, "/deployments/{deployment_id}", deployController.GetDeploymentById)
 	fuego.Post(f, "/rollback", deployController.HandleRollback)
 	fuego.Post(f, "/restart", deployController.HandleRestart)
+	fuego.Post(f, "/stop", deployController.StopApplication)
+	fuego.Post(f, "/start", deployController.StartApplication)
+	fuego.Put(f, "/proxy", deployController.UpdateProxySettings)
+	fuego.Put(f, "/maintenance", deployController.UpdateMaintenance)
+	fuego.Post(f, "/domains", deployController.AddApplicationDomain)
+	fuego.Put(f, "/domains", deployController.UpdateApplicationDomain)
+	fuego.Delete(f, "/domains", deployController.RemoveApplicationDomain)
+	fuego.Post(f, "/ports", deployController.AddApplicationPort)
+	fuego.Delete(f, "/ports", deployController.RemoveApplicationPort)
 	fuego.Get(f, "/logs/{application_id}", deployController.GetLogs)
 	fuego.Get(f, "/deployments/{deployment_id}/logs", deployController.GetDeploymentLogs)
 	fuego.Get(f, "/deployments", deployController.GetApplicationDeployments)
 }
 
+func (router *Router) DatabaseRoutes(f *fuego.Server, databaseController *database.DatabaseController) {
+	fuego.Get(f, "", databaseController.GetDatabases)
+	fuego.Post(f, "", databaseController.CreateDatabase)
+	fuego.Get(f, "/{id}", databaseController.GetDatabase)
+	fuego.Delete(f, "/{id}", databaseController.DeleteDatabase)
+	fuego.Post(f, "/{id}/start", databaseController.StartDatabase)
+	fuego.Post(f, "/{id}/stop", databaseController.StopDatabase)
+	fuego.Get(f, "/{id}/logs", databaseController.GetDatabaseLogs)
+	fuego.Post(f, "/{id}/links", databaseController.LinkDatabase)
+	fuego.Delete(f, "/{id}/links/{application_id}", databaseController.UnlinkDatabase)
+}
+
+func (router *Router) TrafficRoutes(f *fuego.Server, trafficController *traffic.TrafficController) {
+	fuego.Get(f, "/metrics", trafficController.GetMetrics)
+	fuego.Get(f, "/logs", trafficController.GetAccessLogs)
+}
+
+func (router *Router) CertificateRoutes(f *fuego.Server, certificateController *certificate.CertificateController) {
+	fuego.Get(f, "", certificateController.GetCertificates)
+	fuego.Put(f, "", certificateController.UpdateCertificate)
+	fuego.Post(f, "/check", certificateController.CheckCertificate)
+}
+
+func (router *Router) BackupRoutes(f *fuego.Server, backupController *backup.BackupController) {
+	fuego.Get(f, "", backupController.GetBackups)
+	fuego.Post(f, "", backupController.CreateBackup)
+	fuego.Get(f, "/destination", backupController.GetDestination)
+	fuego.Put(f, "/destination", backupController.UpdateDestination)
+	fuego.Get(f, "/schedules", backupController.GetSchedules)
+	fuego.Post(f, "/schedules", backupController.CreateSchedule)
+	fuego.Put(f, "/schedules/{id}", backupController.UpdateSchedule)
+	fuego.Delete(f, "/schedules/{id}", backupController.DeleteSchedule)
+	fuego.Post(f, "/schedules/{id}/run", backupController.RunSchedule)
+	fuego.Get(f, "/{id}", backupController.GetBackup)
+	fuego.Delete(f, "/{id}", backupController.DeleteBackup)
+	fuego.Post(f, "/{id}/restore", backupController.RestoreBackup)
+}
+
+func (router *Router) TemplateRoutes(f *fuego.Server, templateController *template.TemplateController) {
+	fuego.Get(f, "", templateController.GetTemplates)
+	fuego.Post(f, "", templateController.CreateTemplate)
+	fuego.Get(f, "/{slug}", templateController.GetTemplate)
+	fuego.Put(f, "/{slug}", templateController.UpdateTemplate)
+	fuego.Delete(f, "/{slug}", templateController.DeleteTemplate)
+	fuego.Post(f, "/{slug}/deploy", templateController.DeployTemplate)
+}
+
+func (router *Router) WebhookRoutes(f *fuego.Server, webhookController *webhooks.WebhookController) {
+	fuego.Get(f, "/subscriptions", webhookController.GetSubscriptions)
+	fuego.Post(f, "/subscriptions", webhookController.CreateSubscription)
+	fuego.Get(f, "/subscriptions/{id}", webhookController.GetSubscription)
+	fuego.Put(f, "/subscriptions/{id}", webhookController.UpdateSubscription)
+	fuego.Delete(f, "/subscriptions/{id}", webhookController.DeleteSubscription)
+	fuego.Post(f, "/subscriptions/{id}/rotate-secret", webhookController.RotateSecret)
+	fuego.Get(f, "/deliveries", webhookController.GetDeliveries)
+	fuego.Get(f, "/deliveries/{id}", webhookController.GetDelivery)
+	fuego.Post(f, "/deliveries/{id}/replay", webhookController.ReplayDelivery)
+}
+
 func (router *Router) FileManagerRoutes(f *fuego.Server, fileManagerController *file_manager.FileManagerController) {
 	fuego.Get(f, "", fileManagerController.ListFiles)
 	fuego.Post(f, "/create-directory", fileManagerController.CreateDirectory)
@@ -425,6 +587,7 @@ func (router *Router) FeatureFlagRoutes(readGroup *fuego.Server, writeGroup *fue
 
 func (router *Router) ContainerRoutes(s *fuego.Server, containerController *container.ContainerController) {
 	fuego.Get(s, "", containerController.ListContainers)
+	fuego.Get(s, "/stats/history", containerController.GetStatsHistory)
 	fuego.Get(s, "/{container_id}", containerController.GetContainer)
 	fuego.Delete(s, "/{container_id}", containerController.RemoveContainer)
 	fuego.Post(s, "/{container_id}/start", containerController.StartContainer)
@@ -24,6 +24,25 @@ type Application struct {
 	Domain               string                   `json:"domain" bun:"domain,notnull"`
 	DockerfilePath       string                   `json:"dockerfile_path" bun:"dockerfile_path,notnull,default:Dockerfile"`
 	BasePath             string                   `json:"base_path" bun:"base_path,notnull,default:/"`
+	BuildCommand         string                   `json:"build_command" bun:"build_command,notnull"`
+	BuildImage           string                   `json:"build_image" bun:"build_image,notnull,default:node:lts-alpine"`
+	OutputDirectory      string                   `json:"output_directory" bun:"output_directory,notnull"`
+	SpaFallback          bool                     `json:"spa_fallback" bun:"spa_fallback,notnull,default:false"`
+	NotFoundPage         string                   `json:"not_found_page" bun:"not_found_page,notnull"`
+	CacheControl         string                   `json:"cache_control" bun:"cache_control,notnull"`
+	DirectoryBrowsing    bool                     `json:"directory_browsing" bun:"directory_browsing,notnull,default:false"`
+	ScaleToZero          bool                     `json:"scale_to_zero" bun:"scale_to_zero,notnull,default:false"`
+	IdleTimeout          int                      `json:"idle_timeout" bun:"idle_timeout,notnull,default:15"`
+	RunState             RunState                 `json:"run_state" bun:"run_state,nullzero,notnull,default:'running'"`
+	ComposeFile          string                   `json:"compose_file" bun:"compose_file,notnull"`
+	Template             string                   `json:"template" bun:"template,notnull"`
+	GitSubmodules        bool                     `json:"git_submodules" bun:"git_submodules,notnull,default:false"`
+	GitLFS               bool                     `json:"git_lfs" bun:"git_lfs,notnull,default:false"`
+	CloneDepth           int                      `json:"clone_depth" bun:"clone_depth,notnull,default:0"`
+	CloneFilter          string                   `json:"clone_filter" bun:"clone_filter,notnull"`
+	ReportGithubStatus   bool                     `json:"report_github_status" bun:"report_github_status,notnull,default:false"`
+	ProxySettings        ProxySettings            `json:"proxy_settings" bun:"proxy_settings,type:jsonb,notnull,default:'{}'"`
+	Maintenance          MaintenanceSettings      `json:"maintenance" bun:"maintenance,type:jsonb,notnull,default:'{}'"`
 	UserID               uuid.UUID                `json:"user_id" bun:"user_id,notnull,type:uuid"`
 	OrganizationID       uuid.UUID                `json:"organization_id" bun:"organization_id,notnull,type:uuid"`
 	CreatedAt            time.Time                `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
@@ -33,6 +52,61 @@ type Application struct {
 	Logs                 []*ApplicationLogs       `json:"logs,omitempty" bun:"rel:has-many,join:id=application_id"`
 	Deployments          []*ApplicationDeployment `json:"deployments,omitempty" bun:"rel:has-many,join:id=application_id"`
 	Organization         *Organization            `json:"organization,omitempty" bun:"rel:belongs-to,join:organization_id=id"`
+	Domains              []*ApplicationDomain     `json:"domains,omitempty" bun:"rel:has-many,join:id=application_id"`
+	Ports                []*ApplicationPort       `json:"ports,omitempty" bun:"rel:has-many,join:id=application_id"`
+
+	// BasicAuthPasswordHash is the bcrypt hash of the password protecting the domain with basic auth
+	BasicAuthPasswordHash string `json:"-" bun:"basic_auth_password_hash,notnull"`
+}
+
+// ProxySettings are the options of the proxy route serving the domain of an application.
+// Timeouts are in seconds and MaxBodySize in bytes, zero leaves the proxy default in place.
+type ProxySettings struct {
+	// ResponseHeaders are set on every response, overriding the ones sent by the application
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	// SecurityHeaders sets X-Content-Type-Options, X-Frame-Options and Referrer-Policy
+	SecurityHeaders       bool   `json:"security_headers,omitempty"`
+	FrameOptions          string `json:"frame_options,omitempty"`
+	HSTS                  bool   `json:"hsts,omitempty"`
+	HSTSMaxAge            int    `json:"hsts_max_age,omitempty"`
+	HSTSIncludeSubdomains bool   `json:"hsts_include_subdomains,omitempty"`
+	HSTSPreload           bool   `json:"hsts_preload,omitempty"`
+	// HTTPSRedirect redirects plain HTTP requests reaching the route to HTTPS
+	HTTPSRedirect bool `json:"https_redirect,omitempty"`
+	// WWWRedirect redirects the www variant of the domain to the domain, or the bare domain
+	// to the domain when the domain itself starts with www.
+	WWWRedirect       bool   `json:"www_redirect,omitempty"`
+	BasicAuthUsername string `json:"basic_auth_username,omitempty"`
+	// Compression encodes responses with zstd or gzip, depending on what the client accepts
+	Compression     bool  `json:"compression,omitempty"`
+	MaxBodySize     int64 `json:"max_body_size,omitempty"`
+	ConnectTimeout  int   `json:"connect_timeout,omitempty"`
+	ResponseTimeout int   `json:"response_timeout,omitempty"`
+	// PathPrefix routes only the requests below this path of the domains to the application,
+	// so that several applications can share a domain. Empty routes the whole domain.
+	PathPrefix string `json:"path_prefix,omitempty"`
+	// StripPathPrefix removes PathPrefix from the path before the request reaches the application
+	StripPathPrefix bool `json:"strip_path_prefix,omitempty"`
+	// RoutePriority orders the routes of a shared domain, higher first. Routes of the same
+	// priority are ordered by the length of their path prefix, longest first.
+	RoutePriority int `json:"route_priority,omitempty"`
+}
+
+// MaintenanceSettings put the domains of an application in maintenance: visitors get a 503 page,
+// or are redirected, while the containers keep running so that migrations can be done.
+type MaintenanceSettings struct {
+	Enabled bool `json:"enabled"`
+	// Page is the HTML served with the 503 status, a default page is served when empty
+	Page string `json:"page,omitempty"`
+	// RedirectURL redirects visitors there with a 302 instead of serving the page
+	RedirectURL string `json:"redirect_url,omitempty"`
+	// BypassIPs are the addresses and CIDR ranges whose requests still reach the application
+	BypassIPs []string `json:"bypass_ips,omitempty"`
+	// BypassToken lets requests carrying it in the X-Nixopus-Maintenance-Bypass header through
+	BypassToken string `json:"bypass_token,omitempty"`
+	// DuringReleaseCommands turns maintenance on from the pre run command until the post run
+	// command of a deployment finished
+	DuringReleaseCommands bool `json:"during_release_commands,omitempty"`
 }
 
 type ApplicationDeployment struct {
@@ -49,6 +123,33 @@ type ApplicationDeployment struct {
 	ContainerName   string                       `json:"container_name" bun:"container_name"`
 	ContainerImage  string                       `json:"container_image" bun:"container_image"`
 	ContainerStatus string                       `json:"container_status" bun:"container_status"`
+	Attempts        int                          `json:"attempts" bun:"attempts,notnull,default:0"`
+	CompletedPhase  DeploymentPhase              `json:"completed_phase" bun:"completed_phase,notnull,default:''"`
+	// GithubDeploymentID is the GitHub deployment the statuses of this deployment are reported to
+	GithubDeploymentID int64 `json:"github_deployment_id,omitempty" bun:"github_deployment_id,nullzero"`
+}
+
+// DeploymentPhase is a step of a deployment whose side effects are persisted, so that a
+// retried deployment can resume after the last phase it completed instead of starting over.
+type DeploymentPhase string
+
+const (
+	PhaseBuilt     DeploymentPhase = "built"
+	PhasePreRun    DeploymentPhase = "pre_run"
+	PhaseRolledOut DeploymentPhase = "rolled_out"
+	PhasePostRun   DeploymentPhase = "post_run"
+)
+
+// PortAllocation reserves a host port on a server for an application, so that
+// concurrent deployments never publish their services on the same port.
+type PortAllocation struct {
+	bun.BaseModel `bun:"table:port_allocations,alias:pa" swaggerignore:"true"`
+	ID            uuid.UUID    `json:"id" bun:"id,pk,type:uuid"`
+	ServerHost    string       `json:"server_host" bun:"server_host,notnull"`
+	Port          int          `json:"port" bun:"port,notnull"`
+	ApplicationID uuid.UUID    `json:"application_id" bun:"application_id,notnull,type:uuid"`
+	CreatedAt     time.Time    `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	Application   *Application `json:"application,omitempty" bun:"rel:belongs-to,join:application_id=id"`
 }
 
 type ApplicationStatus struct {
@@ -81,6 +182,7 @@ type ApplicationLogs struct {
 	UpdatedAt               time.Time `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
 	Log                     string    `json:"log" bun:"log,notnull"`
 	ApplicationDeploymentID uuid.UUID `json:"application_deployment_id" bun:"application_deployment_id,notnull,type:uuid"`
+	Attempt                 int       `json:"attempt" bun:"attempt,nullzero,notnull,default:1"`
 
 	ApplicationDeployment *ApplicationDeployment `json:"application_deployment,omitempty" bun:"rel:belongs-to,join:application_deployment_id=id"`
 	Application           *Application           `json:"application,omitempty" bun:"rel:belongs-to,join:application_id=id"`
@@ -99,6 +201,17 @@ const (
 	Deployed  Status = "deployed"
 )
 
+// RunState tells whether the swarm service of an application is scaled up.
+type RunState string
+
+const (
+	RunStateRunning RunState = "running"
+	// RunStateStopped is set when a user stops the application. It stays down until started again.
+	RunStateStopped RunState = "stopped"
+	// RunStateSleeping is set when an idle application is scaled to zero. The next request wakes it.
+	RunStateSleeping RunState = "sleeping"
+)
+
 type Environment string
 
 const (
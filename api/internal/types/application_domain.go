@@ -0,0 +1,37 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// DomainMode is how the proxy answers requests for a domain of an application.
+type DomainMode string
+
+const (
+	// DomainModeServe serves the application on the domain
+	DomainModeServe DomainMode = "serve"
+	// DomainModeRedirect permanently redirects the domain to the primary domain of the application
+	DomainModeRedirect DomainMode = "redirect"
+)
+
+// ApplicationDomain is a host an application is reachable on. Exactly one domain of an
+// application is primary, its name is mirrored in Application.Domain.
+type ApplicationDomain struct {
+	bun.BaseModel `bun:"table:application_domains,alias:apd" swaggerignore:"true"`
+	ID            uuid.UUID `json:"id" bun:"id,pk,type:uuid"`
+	ApplicationID uuid.UUID `json:"application_id" bun:"application_id,notnull,type:uuid"`
+	// DomainID is the domain of the organization the host belongs to, unset for hosts
+	// that were entered before domains had to be registered.
+	DomainID  *uuid.UUID `json:"domain_id,omitempty" bun:"domain_id,type:uuid"`
+	Name      string     `json:"name" bun:"name,notnull"`
+	IsPrimary bool       `json:"is_primary" bun:"is_primary,notnull,default:false"`
+	Mode      DomainMode `json:"mode" bun:"mode,notnull,default:'serve'"`
+	// PathPrefix mirrors the path prefix of the application, a host can be shared by
+	// applications routed below different paths.
+	PathPrefix string    `json:"path_prefix" bun:"path_prefix,notnull"`
+	CreatedAt  time.Time `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
+}
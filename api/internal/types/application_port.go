@@ -0,0 +1,34 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// PortProtocol is the transport protocol of a port exposed by an application.
+type PortProtocol string
+
+const (
+	PortProtocolTCP PortProtocol = "tcp"
+	PortProtocolUDP PortProtocol = "udp"
+)
+
+// ApplicationPort is a TCP or UDP port of an application published on its server, next to the
+// HTTP port the proxy routes the domains of the application to.
+type ApplicationPort struct {
+	bun.BaseModel `bun:"table:application_ports,alias:ap" swaggerignore:"true"`
+	ID            uuid.UUID    `json:"id" bun:"id,pk,type:uuid"`
+	ApplicationID uuid.UUID    `json:"application_id" bun:"application_id,notnull,type:uuid"`
+	ServerHost    string       `json:"server_host" bun:"server_host,notnull"`
+	Protocol      PortProtocol `json:"protocol" bun:"protocol,notnull"`
+	TargetPort    int          `json:"target_port" bun:"target_port,notnull"`
+	PublishedPort int          `json:"published_port" bun:"published_port,notnull"`
+	// SNI routes the TLS connections for this server name through the proxy to the port, which
+	// is then published on an allocated port instead of a fixed one. Empty publishes the port
+	// to clients directly.
+	SNI         string       `json:"sni,omitempty" bun:"sni,notnull"`
+	CreatedAt   time.Time    `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	Application *Application `json:"application,omitempty" bun:"rel:belongs-to,join:application_id=id"`
+}
@@ -35,6 +35,10 @@ const (
 	AuditResourceAudit           AuditResourceType = "audit"
 	AuditResourceTerminal        AuditResourceType = "terminal"
 	AuditResourceIntegration     AuditResourceType = "integration"
+	AuditResourceDatabase        AuditResourceType = "database"
+	AuditResourceBackup          AuditResourceType = "backup"
+	AuditResourceTemplate        AuditResourceType = "template"
+	AuditResourceWebhook         AuditResourceType = "webhook"
 )
 
 type AuditLog struct {
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// BackupDestination is where the backups of an organization are uploaded to.
+type BackupDestination struct {
+	bun.BaseModel  `bun:"table:backup_destinations,alias:bd" swaggerignore:"true"`
+	ID             uuid.UUID             `json:"id" bun:"id,pk,type:uuid"`
+	OrganizationID uuid.UUID             `json:"organization_id" bun:"organization_id,notnull,type:uuid"`
+	Type           BackupDestinationType `json:"type" bun:"type,notnull"`
+	LocalPath      string                `json:"local_path" bun:"local_path,notnull"`
+	Endpoint       string                `json:"endpoint" bun:"endpoint,notnull"`
+	Region         string                `json:"region" bun:"region,notnull"`
+	Bucket         string                `json:"bucket" bun:"bucket,notnull"`
+	Prefix         string                `json:"prefix" bun:"prefix,notnull"`
+	AccessKey      string                `json:"access_key" bun:"access_key,notnull"`
+	SecretKey      string                `json:"-" bun:"secret_key,notnull"`
+	UseSSL         bool                  `json:"use_ssl" bun:"use_ssl,notnull,default:true"`
+	ForcePathStyle bool                  `json:"force_path_style" bun:"force_path_style,notnull,default:true"`
+	CreatedAt      time.Time             `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time             `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+type BackupDestinationType string
+
+const (
+	BackupDestinationLocal BackupDestinationType = "local"
+	BackupDestinationS3    BackupDestinationType = "s3"
+)
+
+type BackupResourceType string
+
+const (
+	BackupResourceDatabase BackupResourceType = "database"
+	BackupResourceVolume   BackupResourceType = "volume"
+)
+
+// BackupSchedule backs a database or a volume up every IntervalHours and keeps the last
+// RetentionCount backups it created.
+type BackupSchedule struct {
+	bun.BaseModel  `bun:"table:backup_schedules,alias:bs" swaggerignore:"true"`
+	ID             uuid.UUID          `json:"id" bun:"id,pk,type:uuid"`
+	OrganizationID uuid.UUID          `json:"organization_id" bun:"organization_id,notnull,type:uuid"`
+	UserID         uuid.UUID          `json:"user_id" bun:"user_id,notnull,type:uuid"`
+	ResourceType   BackupResourceType `json:"resource_type" bun:"resource_type,notnull"`
+	DatabaseID     *uuid.UUID         `json:"database_id,omitempty" bun:"database_id,type:uuid"`
+	VolumeName     string             `json:"volume_name" bun:"volume_name,notnull"`
+	IntervalHours  int                `json:"interval_hours" bun:"interval_hours,notnull"`
+	RetentionCount int                `json:"retention_count" bun:"retention_count,notnull"`
+	Enabled        bool               `json:"enabled" bun:"enabled,notnull,default:true"`
+	LastRunAt      *time.Time         `json:"last_run_at,omitempty" bun:"last_run_at"`
+	NextRunAt      time.Time          `json:"next_run_at" bun:"next_run_at,notnull"`
+	CreatedAt      time.Time          `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time          `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
+	Database       *Database          `json:"database,omitempty" bun:"rel:belongs-to,join:database_id=id"`
+}
+
+type BackupStatus string
+
+const (
+	BackupStatusRunning   BackupStatus = "running"
+	BackupStatusCompleted BackupStatus = "completed"
+	BackupStatusFailed    BackupStatus = "failed"
+)
+
+// Backup is a single dump of a database or archive of a volume.
+type Backup struct {
+	bun.BaseModel   `bun:"table:backups,alias:b" swaggerignore:"true"`
+	ID              uuid.UUID             `json:"id" bun:"id,pk,type:uuid"`
+	OrganizationID  uuid.UUID             `json:"organization_id" bun:"organization_id,notnull,type:uuid"`
+	ScheduleID      *uuid.UUID            `json:"schedule_id,omitempty" bun:"schedule_id,type:uuid"`
+	ResourceType    BackupResourceType    `json:"resource_type" bun:"resource_type,notnull"`
+	DatabaseID      *uuid.UUID            `json:"database_id,omitempty" bun:"database_id,type:uuid"`
+	DatabaseEngine  DatabaseEngine        `json:"database_engine,omitempty" bun:"database_engine,notnull"`
+	DatabaseVersion string                `json:"database_version,omitempty" bun:"database_version,notnull"`
+	DatabaseName    string                `json:"database_name,omitempty" bun:"database_name,notnull"`
+	VolumeName      string                `json:"volume_name" bun:"volume_name,notnull"`
+	Status          BackupStatus          `json:"status" bun:"status,notnull"`
+	DestinationType BackupDestinationType `json:"destination_type" bun:"destination_type,notnull"`
+	StorageKey      string                `json:"storage_key" bun:"storage_key,notnull"`
+	Size            int64                 `json:"size" bun:"size,notnull,default:0"`
+	Checksum        string                `json:"checksum" bun:"checksum,notnull"`
+	Error           string                `json:"error,omitempty" bun:"error,notnull"`
+	StartedAt       time.Time             `json:"started_at" bun:"started_at,notnull"`
+	CompletedAt     *time.Time            `json:"completed_at,omitempty" bun:"completed_at"`
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// CertificateMode tells where the certificate served for a domain comes from.
+type CertificateMode string
+
+const (
+	// CertificateModeACME lets Caddy obtain and renew a certificate from a public ACME CA.
+	CertificateModeACME CertificateMode = "acme"
+	// CertificateModeCustom serves a certificate and key uploaded by the user.
+	CertificateModeCustom CertificateMode = "custom"
+	// CertificateModeInternal serves a certificate issued by the internal CA of Caddy, for
+	// private domains that cannot be validated by a public CA.
+	CertificateModeInternal CertificateMode = "internal"
+)
+
+// DomainCertificate is the TLS configuration and the last observed certificate of a domain
+// of an application.
+type DomainCertificate struct {
+	bun.BaseModel       `bun:"table:domain_certificates,alias:dc" swaggerignore:"true"`
+	ID                  uuid.UUID       `json:"id" bun:"id,pk,type:uuid"`
+	ApplicationDomainID uuid.UUID       `json:"application_domain_id" bun:"application_domain_id,notnull,type:uuid"`
+	Name                string          `json:"name" bun:"name,notnull"`
+	Mode                CertificateMode `json:"mode" bun:"mode,notnull"`
+	CertificatePEM      string          `json:"-" bun:"certificate_pem,notnull"`
+	PrivateKeyPEM       string          `json:"-" bun:"private_key_pem,notnull"`
+	Issuer              string          `json:"issuer" bun:"issuer,notnull"`
+	Subject             string          `json:"subject" bun:"subject,notnull"`
+	NotBefore           *time.Time      `json:"not_before" bun:"not_before"`
+	NotAfter            *time.Time      `json:"not_after" bun:"not_after"`
+	LastError           string          `json:"last_error" bun:"last_error,notnull"`
+	CheckedAt           *time.Time      `json:"checked_at" bun:"checked_at"`
+	ExpiryNotifiedAt    *time.Time      `json:"expiry_notified_at" bun:"expiry_notified_at"`
+	CreatedAt           time.Time       `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt           time.Time       `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
+
+	// ApplicationID and UserID are the application the domain belongs to and its owner,
+	// selected along with the certificate.
+	ApplicationID uuid.UUID `json:"application_id" bun:"application_id,scanonly"`
+	UserID        uuid.UUID `json:"-" bun:"user_id,scanonly"`
+}
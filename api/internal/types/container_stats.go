@@ -0,0 +1,30 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ContainerStat is the resource usage of a container of an application during a bucket of a
+// few minutes, down-sampled from the samples taken during it. Network and block IO are the
+// bytes transferred during the bucket.
+type ContainerStat struct {
+	bun.BaseModel  `bun:"table:container_stats,alias:cs" swaggerignore:"true"`
+	ApplicationID  uuid.UUID `json:"application_id" bun:"application_id,pk,type:uuid"`
+	DeploymentID   uuid.UUID `json:"deployment_id" bun:"deployment_id,pk,type:uuid"`
+	ContainerID    string    `json:"container_id" bun:"container_id,pk"`
+	Bucket         time.Time `json:"bucket" bun:"bucket,pk"`
+	ContainerName  string    `json:"container_name" bun:"container_name,notnull"`
+	Samples        int       `json:"samples" bun:"samples,notnull"`
+	CPUPercent     float64   `json:"cpu_percent" bun:"cpu_percent,notnull"`
+	CPUPercentMax  float64   `json:"cpu_percent_max" bun:"cpu_percent_max,notnull"`
+	MemoryUsage    int64     `json:"memory_usage" bun:"memory_usage,notnull"`
+	MemoryUsageMax int64     `json:"memory_usage_max" bun:"memory_usage_max,notnull"`
+	MemoryLimit    int64     `json:"memory_limit" bun:"memory_limit,notnull"`
+	NetworkRx      int64     `json:"network_rx" bun:"network_rx,notnull"`
+	NetworkTx      int64     `json:"network_tx" bun:"network_tx,notnull"`
+	BlockRead      int64     `json:"block_read" bun:"block_read,notnull"`
+	BlockWrite     int64     `json:"block_write" bun:"block_write,notnull"`
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Database is a managed database server running as a Swarm service with its data on a
+// persistent volume.
+type Database struct {
+	bun.BaseModel  `bun:"table:databases,alias:db" swaggerignore:"true"`
+	ID             uuid.UUID      `json:"id" bun:"id,pk,type:uuid"`
+	Name           string         `json:"name" bun:"name,notnull"`
+	Engine         DatabaseEngine `json:"engine" bun:"engine,notnull"`
+	Version        string         `json:"version" bun:"version,notnull"`
+	ServiceName    string         `json:"service_name" bun:"service_name,notnull"`
+	VolumeName     string         `json:"volume_name" bun:"volume_name,notnull"`
+	DatabaseName   string         `json:"database_name" bun:"database_name,notnull"`
+	Username       string         `json:"username" bun:"username,notnull"`
+	Password       string         `json:"-" bun:"password,notnull"`
+	Port           int            `json:"port" bun:"port,notnull"`
+	Status         DatabaseStatus `json:"status" bun:"status,notnull"`
+	UserID         uuid.UUID      `json:"user_id" bun:"user_id,notnull,type:uuid"`
+	OrganizationID uuid.UUID      `json:"organization_id" bun:"organization_id,notnull,type:uuid"`
+	CreatedAt      time.Time      `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time      `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
+
+	Links []*ApplicationDatabase `json:"links,omitempty" bun:"rel:has-many,join:id=database_id"`
+}
+
+type DatabaseEngine string
+
+const (
+	DatabaseEnginePostgres DatabaseEngine = "postgres"
+	DatabaseEngineMySQL    DatabaseEngine = "mysql"
+	DatabaseEngineRedis    DatabaseEngine = "redis"
+	DatabaseEngineMongoDB  DatabaseEngine = "mongodb"
+)
+
+type DatabaseStatus string
+
+const (
+	DatabaseStatusRunning DatabaseStatus = "running"
+	DatabaseStatusStopped DatabaseStatus = "stopped"
+	DatabaseStatusFailed  DatabaseStatus = "failed"
+)
+
+// ApplicationDatabase links a database to an application, whose services receive the
+// connection string of the database in the EnvVariable environment variable.
+type ApplicationDatabase struct {
+	bun.BaseModel `bun:"table:application_databases,alias:adb" swaggerignore:"true"`
+	ID            uuid.UUID    `json:"id" bun:"id,pk,type:uuid"`
+	ApplicationID uuid.UUID    `json:"application_id" bun:"application_id,notnull,type:uuid"`
+	DatabaseID    uuid.UUID    `json:"database_id" bun:"database_id,notnull,type:uuid"`
+	EnvVariable   string       `json:"env_variable" bun:"env_variable,notnull"`
+	CreatedAt     time.Time    `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	Database      *Database    `json:"database,omitempty" bun:"rel:belongs-to,join:database_id=id"`
+	Application   *Application `json:"application,omitempty" bun:"rel:belongs-to,join:application_id=id"`
+}
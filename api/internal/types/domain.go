@@ -18,6 +18,17 @@ type Domain struct {
 	DeletedAt      *time.Time `json:"deleted_at,omitempty" bun:"deleted_at"`
 	Name           string     `json:"name" bun:"name,notnull"`
 	OrganizationID uuid.UUID  `json:"organization_id" bun:"organization_id,notnull"`
+
+	// VerificationToken is published in a TXT record to prove the organization controls the domain
+	VerificationToken string `json:"verification_token" bun:"verification_token,notnull"`
+	// VerifiedAt is unset until the TXT record was found, unverified domains cannot be used by applications
+	VerifiedAt    *time.Time `json:"verified_at,omitempty" bun:"verified_at"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty" bun:"last_checked_at"`
+}
+
+// IsVerified reports whether the organization proved it controls the domain.
+func (d Domain) IsVerified() bool {
+	return d.VerifiedAt != nil
 }
 
 type Server struct {
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Template is an application that can be deployed from a compose file without a repository.
+// Built-in templates are loaded from the templates directory, organizations can add their own.
+type Template struct {
+	bun.BaseModel  `bun:"table:templates,alias:tpl" swaggerignore:"true"`
+	ID             uuid.UUID     `json:"id" bun:"id,pk,type:uuid"`
+	Slug           string        `json:"slug" bun:"slug,notnull"`
+	Name           string        `json:"name" bun:"name,notnull"`
+	Description    string        `json:"description" bun:"description,notnull"`
+	Category       string        `json:"category" bun:"category,notnull"`
+	Website        string        `json:"website" bun:"website,notnull"`
+	Port           int           `json:"port" bun:"port,notnull"`
+	Compose        string        `json:"compose" bun:"compose,notnull"`
+	Env            []TemplateEnv `json:"env" bun:"env,type:jsonb,notnull"`
+	Volumes        []string      `json:"volumes" bun:"volumes,type:jsonb,notnull"`
+	Builtin        bool          `json:"builtin" bun:"-"`
+	UserID         uuid.UUID     `json:"user_id" bun:"user_id,notnull,type:uuid"`
+	OrganizationID uuid.UUID     `json:"organization_id" bun:"organization_id,notnull,type:uuid"`
+	CreatedAt      time.Time     `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time     `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// TemplateEnv is an environment variable a template expects. Variables with Generate set
+// get a random value when the template is deployed, unless one is given.
+type TemplateEnv struct {
+	Name        string             `json:"name" yaml:"name"`
+	Description string             `json:"description,omitempty" yaml:"description"`
+	Default     string             `json:"default,omitempty" yaml:"default"`
+	Required    bool               `json:"required,omitempty" yaml:"required"`
+	Generate    TemplateSecretKind `json:"generate,omitempty" yaml:"generate"`
+}
+
+// TemplateSecretKind is the format of a generated secret.
+type TemplateSecretKind string
+
+const (
+	TemplateSecretPassword TemplateSecretKind = "password"
+	TemplateSecretHex      TemplateSecretKind = "hex"
+	TemplateSecretBase64   TemplateSecretKind = "base64"
+)
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// AccessLog is a request to an application as logged by the proxy. Access logs are only kept
+// for a short time, the HTTP metrics of the application are rolled up from them.
+type AccessLog struct {
+	bun.BaseModel `bun:"table:access_logs,alias:al" swaggerignore:"true"`
+	ID            uuid.UUID `json:"id" bun:"id,pk,type:uuid"`
+	ApplicationID uuid.UUID `json:"application_id" bun:"application_id,notnull,type:uuid"`
+	Timestamp     time.Time `json:"timestamp" bun:"timestamp,notnull"`
+	Method        string    `json:"method" bun:"method,notnull"`
+	Host          string    `json:"host" bun:"host,notnull"`
+	URI           string    `json:"uri" bun:"uri,notnull"`
+	Protocol      string    `json:"protocol" bun:"protocol,notnull"`
+	Status        int       `json:"status" bun:"status,notnull"`
+	DurationMs    float64   `json:"duration_ms" bun:"duration_ms,notnull"`
+	Size          int64     `json:"size" bun:"size,notnull"`
+	RemoteIP      string    `json:"remote_ip" bun:"remote_ip,notnull"`
+	UserAgent     string    `json:"user_agent" bun:"user_agent,notnull"`
+}
+
+// HTTPMetric sums up the requests to an application during a minute, or during a longer step
+// when metrics are queried at a coarser resolution. The latencies of a longer step are exact
+// while its access logs are kept, after that they are approximated from the latencies of its
+// minutes and LatencyApproximate is set.
+type HTTPMetric struct {
+	bun.BaseModel `bun:"table:application_http_metrics,alias:ahm" swaggerignore:"true"`
+	ApplicationID uuid.UUID `json:"application_id" bun:"application_id,pk,type:uuid"`
+	Minute        time.Time `json:"minute" bun:"minute,pk"`
+	Requests      int64     `json:"requests" bun:"requests,notnull"`
+	Status2xx     int64     `json:"status_2xx" bun:"status_2xx,notnull"`
+	Status4xx     int64     `json:"status_4xx" bun:"status_4xx,notnull"`
+	Status5xx     int64     `json:"status_5xx" bun:"status_5xx,notnull"`
+	P50Ms         float64   `json:"p50_ms" bun:"p50_ms,notnull"`
+	P95Ms         float64   `json:"p95_ms" bun:"p95_ms,notnull"`
+
+	LatencyApproximate bool `json:"latency_approximate" bun:"-"`
+}
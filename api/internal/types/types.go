@@ -14,6 +14,9 @@ type Config struct {
 
 type ServerConfig struct {
 	Port string `mapstructure:"port" validate:"required"`
+	// OutboundAllowedNetworks is a comma separated list of private CIDRs that webhooks and backup
+	// destinations may still connect to, for example a MinIO on the local network.
+	OutboundAllowedNetworks string `mapstructure:"outbound_allowed_networks"`
 }
 
 type DatabaseConfig struct {
@@ -42,7 +45,14 @@ type SSHConfig struct {
 }
 
 type DeploymentConfig struct {
-	MountPath string `mapstructure:"mount_path" validate:"required"`
+	MountPath     string `mapstructure:"mount_path" validate:"required"`
+	TemplatesPath string `mapstructure:"templates_path"`
+	// ReleaseCommandTimeout is how many minutes a pre run or post run command may take before
+	// its container is removed and the deployment fails.
+	ReleaseCommandTimeout int `mapstructure:"release_command_timeout"`
+	// BackupsPath is the directory local backup destinations are kept in, every organization
+	// gets a subdirectory of it. Defaults to backups in MountPath.
+	BackupsPath string `mapstructure:"backups_path"`
 }
 
 type DockerConfig struct {
@@ -53,6 +63,36 @@ type DockerConfig struct {
 
 type ProxyConfig struct {
 	CaddyEndpoint string `mapstructure:"caddy_endpoint" validate:"required"`
+	// CertificateExpiryDays is how many days before expiry the owner of a domain is warned.
+	CertificateExpiryDays int `mapstructure:"certificate_expiry_days"`
+
+	// Nginx settings are used by the applications routed through Nginx instead of Caddy
+	NginxConfigDir       string `mapstructure:"nginx_config_dir"`
+	NginxCertificatesDir string `mapstructure:"nginx_certificates_dir"`
+	NginxTestCommand     string `mapstructure:"nginx_test_command"`
+	NginxReloadCommand   string `mapstructure:"nginx_reload_command"`
+
+	// Layer4Listen is the address Caddy accepts the TLS connections routed by SNI on. It needs a
+	// Caddy build with the layer4 module, empty turns SNI routing off.
+	Layer4Listen string `mapstructure:"layer4_listen"`
+	// ReconcileMode is what the periodic reconciliation of the Caddy routes does with drift:
+	// "report" (the default) only logs it, "fix" repairs it and "off" turns it off.
+	ReconcileMode string `mapstructure:"reconcile_mode"`
+
+	// AccessLogPort is the port the API ingests the access logs of Caddy on, Caddy sends them to
+	// the SSH host. Empty turns access logs and HTTP metrics off.
+	AccessLogPort string `mapstructure:"access_log_port"`
+	// AccessLogListenHost is the host the access log listener binds to and Caddy sends the access
+	// logs to, such as the name of the API on the network it shares with Caddy. Empty binds to
+	// every interface and has Caddy send them to the SSH host.
+	AccessLogListenHost string `mapstructure:"access_log_listen_host"`
+	// AccessLogAllowedIPs lists the addresses and CIDR ranges, comma separated, besides those of
+	// the host of CaddyEndpoint that access logs are accepted from.
+	AccessLogAllowedIPs string `mapstructure:"access_log_allowed_ips"`
+	// AccessLogRetentionHours is how long raw access logs are kept.
+	AccessLogRetentionHours int `mapstructure:"access_log_retention_hours"`
+	// HTTPMetricsRetentionDays is how long the per minute HTTP metrics are kept.
+	HTTPMetricsRetentionDays int `mapstructure:"http_metrics_retention_days"`
 }
 
 type CORSConfig struct {
@@ -85,6 +125,8 @@ const (
 	DASHBOARD_MONITOR      AvailableActions = "dashboard_monitor"
 	STOP_DASHBOARD_MONITOR AvailableActions = "stop_dashboard_monitor"
 	MONITOR_APPLICATION    AvailableActions = "monitor_application"
+	FOLLOW_LOGS            AvailableActions = "follow_logs"
+	STOP_FOLLOW_LOGS       AvailableActions = "stop_follow_logs"
 )
 
 type Payload struct {
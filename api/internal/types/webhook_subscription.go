@@ -0,0 +1,124 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// WebhookSubscription sends the events of an organization to an HTTP endpoint, signed with
+// its secret.
+type WebhookSubscription struct {
+	bun.BaseModel  `bun:"table:webhook_subscriptions,alias:ws" swaggerignore:"true"`
+	ID             uuid.UUID          `json:"id" bun:"id,pk,type:uuid"`
+	URL            string             `json:"url" bun:"url,notnull"`
+	Description    string             `json:"description" bun:"description,notnull"`
+	Secret         string             `json:"-" bun:"secret,notnull"`
+	Events         []WebhookEventType `json:"events" bun:"events,type:jsonb,notnull"`
+	Active         bool               `json:"active" bun:"active,notnull"`
+	UserID         uuid.UUID          `json:"user_id" bun:"user_id,notnull,type:uuid"`
+	OrganizationID uuid.UUID          `json:"organization_id" bun:"organization_id,notnull,type:uuid"`
+	CreatedAt      time.Time          `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time          `json:"updated_at" bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a subscription.
+type WebhookDelivery struct {
+	bun.BaseModel  `bun:"table:webhook_deliveries,alias:wd" swaggerignore:"true"`
+	ID             uuid.UUID             `json:"id" bun:"id,pk,type:uuid"`
+	SubscriptionID uuid.UUID             `json:"subscription_id" bun:"subscription_id,notnull,type:uuid"`
+	OrganizationID uuid.UUID             `json:"organization_id" bun:"organization_id,notnull,type:uuid"`
+	EventID        uuid.UUID             `json:"event_id" bun:"event_id,notnull,type:uuid"`
+	EventType      WebhookEventType      `json:"event_type" bun:"event_type,notnull"`
+	Payload        json.RawMessage       `json:"payload" bun:"payload,type:jsonb,notnull"`
+	Status         WebhookDeliveryStatus `json:"status" bun:"status,notnull"`
+	Attempts       int                   `json:"attempts" bun:"attempts,notnull"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" bun:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty" bun:"last_attempt_at"`
+	ResponseStatus int                   `json:"response_status" bun:"response_status,notnull"`
+	ResponseBody   string                `json:"response_body" bun:"response_body,notnull"`
+	Error          string                `json:"error" bun:"error,notnull"`
+	ReplayOf       *uuid.UUID            `json:"replay_of,omitempty" bun:"replay_of,type:uuid"`
+	CreatedAt      time.Time             `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookEventDeploymentStarted   WebhookEventType = "deployment.started"
+	WebhookEventDeploymentSucceeded WebhookEventType = "deployment.succeeded"
+	WebhookEventDeploymentFailed    WebhookEventType = "deployment.failed"
+	WebhookEventApplicationCreated  WebhookEventType = "application.created"
+	WebhookEventApplicationDeleted  WebhookEventType = "application.deleted"
+	WebhookEventContainerDied       WebhookEventType = "container.died"
+	WebhookEventUserLogin           WebhookEventType = "user.login"
+	// WebhookEventAll subscribes to every event
+	WebhookEventAll WebhookEventType = "*"
+)
+
+// WebhookEvents lists the events a subscription can choose from.
+var WebhookEvents = []WebhookEventType{
+	WebhookEventDeploymentStarted,
+	WebhookEventDeploymentSucceeded,
+	WebhookEventDeploymentFailed,
+	WebhookEventApplicationCreated,
+	WebhookEventApplicationDeleted,
+	WebhookEventContainerDied,
+	WebhookEventUserLogin,
+}
+
+// WebhookEvent is the JSON body of a webhook delivery.
+type WebhookEvent struct {
+	ID             uuid.UUID        `json:"id"`
+	Type           WebhookEventType `json:"type"`
+	OrganizationID uuid.UUID        `json:"organization_id"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Data           interface{}      `json:"data"`
+}
+
+type WebhookDeploymentData struct {
+	ApplicationID   uuid.UUID   `json:"application_id"`
+	ApplicationName string      `json:"application_name"`
+	Domain          string      `json:"domain"`
+	Environment     Environment `json:"environment"`
+	DeploymentID    uuid.UUID   `json:"deployment_id"`
+	CommitHash      string      `json:"commit_hash,omitempty"`
+	Attempt         int         `json:"attempt"`
+	Error           string      `json:"error,omitempty"`
+}
+
+type WebhookApplicationData struct {
+	ApplicationID uuid.UUID   `json:"application_id"`
+	Name          string      `json:"name"`
+	Domain        string      `json:"domain"`
+	Environment   Environment `json:"environment"`
+	BuildPack     BuildPack   `json:"build_pack"`
+	Branch        string      `json:"branch,omitempty"`
+	UserID        uuid.UUID   `json:"user_id"`
+}
+
+type WebhookContainerData struct {
+	ContainerID     string    `json:"container_id"`
+	ContainerName   string    `json:"container_name"`
+	Image           string    `json:"image"`
+	ExitCode        string    `json:"exit_code"`
+	ApplicationID   uuid.UUID `json:"application_id"`
+	ApplicationName string    `json:"application_name"`
+}
+
+type WebhookLoginData struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+}
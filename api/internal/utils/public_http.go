@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+)
+
+// ErrNonPublicAddress is returned when an outbound request configured by a user would reach an
+// address that is not publicly routable, such as the host itself or a cloud metadata endpoint.
+var ErrNonPublicAddress = errors.New("address is not publicly routable")
+
+// nonPublicNetworks are ranges that the net.IP helpers do not cover.
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // this network
+	"100.64.0.0/10", // carrier grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved
+	"64:ff9b::/96",  // NAT64, maps onto IPv4 addresses
+)
+
+// IsPublicIP reports whether ip is a publicly routable unicast address.
+func IsPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowedOutboundIP reports whether user configured requests may connect to ip. Public addresses
+// are always allowed, other addresses only when they are inside OUTBOUND_ALLOWED_NETWORKS.
+func AllowedOutboundIP(ip net.IP) bool {
+	if IsPublicIP(ip) {
+		return true
+	}
+	for _, network := range allowedNetworks() {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPublicHTTPClient returns an HTTP client for requests to URLs configured by users, like
+// webhooks and object storage endpoints. The address is checked when the connection is made,
+// after DNS resolution, so a name that resolves or is rebound to a private address is refused.
+// Redirects are not followed and proxies from the environment are not used.
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnlyControl,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicOnlyControl runs before every connection the dialer makes, with the resolved address.
+func publicOnlyControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !AllowedOutboundIP(ip) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, host)
+	}
+	return nil
+}
+
+// allowedNetworks parses OUTBOUND_ALLOWED_NETWORKS, a comma separated list of CIDRs. Entries that
+// do not parse are ignored.
+func allowedNetworks() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range strings.Split(config.AppConfig.Server.OutboundAllowedNetworks, ",") {
+		if _, network, err := net.ParseCIDR(strings.TrimSpace(cidr)); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/raghavyuva/nixopus-api/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.216.34", want: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{ip: "127.0.0.1", want: false},
+		{ip: "::1", want: false},
+		{ip: "10.1.2.3", want: false},
+		{ip: "172.16.0.1", want: false},
+		{ip: "192.168.1.10", want: false},
+		{ip: "169.254.169.254", want: false},
+		{ip: "fe80::1", want: false},
+		{ip: "fd00::1", want: false},
+		{ip: "0.0.0.0", want: false},
+		{ip: "::", want: false},
+		{ip: "100.64.0.1", want: false},
+		{ip: "224.0.0.1", want: false},
+		{ip: "::ffff:127.0.0.1", want: false},
+		{ip: "64:ff9b::a9fe:a9fe", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPublicIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestAllowedOutboundIP(t *testing.T) {
+	previous := config.AppConfig.Server.OutboundAllowedNetworks
+	defer func() { config.AppConfig.Server.OutboundAllowedNetworks = previous }()
+
+	config.AppConfig.Server.OutboundAllowedNetworks = "192.168.1.0/24, not-a-cidr"
+	assert.True(t, AllowedOutboundIP(net.ParseIP("192.168.1.20")))
+	assert.False(t, AllowedOutboundIP(net.ParseIP("192.168.2.20")))
+	assert.True(t, AllowedOutboundIP(net.ParseIP("93.184.216.34")))
+}
+
+func TestPublicHTTPClient(t *testing.T) {
+	previous := config.AppConfig.Server.OutboundAllowedNetworks
+	defer func() { config.AppConfig.Server.OutboundAllowedNetworks = previous }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config.AppConfig.Server.OutboundAllowedNetworks = ""
+	_, err := NewPublicHTTPClient(5 * time.Second).Get(server.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNonPublicAddress))
+
+	config.AppConfig.Server.OutboundAllowedNetworks = "127.0.0.0/8"
+	resp, err := NewPublicHTTPClient(5 * time.Second).Get(server.URL + "/redirect")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+}
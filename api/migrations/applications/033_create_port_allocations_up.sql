@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS port_allocations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    server_host TEXT NOT NULL,
+    port INTEGER NOT NULL CHECK (port BETWEEN 1 AND 65535),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_port_allocations_server_port UNIQUE (server_host, port),
+    CONSTRAINT uq_port_allocations_server_application UNIQUE (server_host, application_id)
+);
+
+CREATE INDEX idx_port_allocations_application_id ON port_allocations(application_id);
@@ -0,0 +1,7 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS build_command;
+ALTER TABLE applications DROP COLUMN IF EXISTS build_image;
+ALTER TABLE applications DROP COLUMN IF EXISTS output_directory;
+ALTER TABLE applications DROP COLUMN IF EXISTS spa_fallback;
+ALTER TABLE applications DROP COLUMN IF EXISTS not_found_page;
+ALTER TABLE applications DROP COLUMN IF EXISTS cache_control;
+ALTER TABLE applications DROP COLUMN IF EXISTS directory_browsing;
@@ -0,0 +1,7 @@
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS build_command TEXT NOT NULL DEFAULT '';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS build_image VARCHAR(255) NOT NULL DEFAULT 'node:lts-alpine';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS output_directory VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS spa_fallback BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS not_found_page VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS cache_control VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS directory_browsing BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+ALTER TABLE application_logs DROP COLUMN IF EXISTS attempt;
+ALTER TABLE application_deployment DROP COLUMN IF EXISTS completed_phase;
+ALTER TABLE application_deployment DROP COLUMN IF EXISTS attempts;
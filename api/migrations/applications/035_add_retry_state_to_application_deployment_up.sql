@@ -0,0 +1,3 @@
+ALTER TABLE application_deployment ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE application_deployment ADD COLUMN IF NOT EXISTS completed_phase VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE application_logs ADD COLUMN IF NOT EXISTS attempt INTEGER NOT NULL DEFAULT 1;
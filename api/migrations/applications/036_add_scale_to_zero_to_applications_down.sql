@@ -0,0 +1,3 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS run_state;
+ALTER TABLE applications DROP COLUMN IF EXISTS idle_timeout;
+ALTER TABLE applications DROP COLUMN IF EXISTS scale_to_zero;
@@ -0,0 +1,3 @@
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS scale_to_zero BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS idle_timeout INTEGER NOT NULL DEFAULT 15 CHECK (idle_timeout > 0);
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS run_state VARCHAR(16) NOT NULL DEFAULT 'running' CHECK (run_state IN ('running', 'stopped', 'sleeping'));
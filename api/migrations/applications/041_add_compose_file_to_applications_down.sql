@@ -0,0 +1,2 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS template;
+ALTER TABLE applications DROP COLUMN IF EXISTS compose_file;
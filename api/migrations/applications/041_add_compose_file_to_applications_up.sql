@@ -0,0 +1,2 @@
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS compose_file TEXT NOT NULL DEFAULT '';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS template VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS clone_filter;
+ALTER TABLE applications DROP COLUMN IF EXISTS clone_depth;
+ALTER TABLE applications DROP COLUMN IF EXISTS git_lfs;
+ALTER TABLE applications DROP COLUMN IF EXISTS git_submodules;
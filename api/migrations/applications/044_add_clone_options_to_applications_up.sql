@@ -0,0 +1,4 @@
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS git_submodules BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS git_lfs BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS clone_depth INTEGER NOT NULL DEFAULT 0 CHECK (clone_depth >= 0);
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS clone_filter VARCHAR(16) NOT NULL DEFAULT '' CHECK (clone_filter IN ('', 'blob:none', 'tree:0'));
@@ -0,0 +1,2 @@
+ALTER TABLE application_deployment DROP COLUMN IF EXISTS github_deployment_id;
+ALTER TABLE applications DROP COLUMN IF EXISTS report_github_status;
@@ -0,0 +1,2 @@
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS report_github_status BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE application_deployment ADD COLUMN IF NOT EXISTS github_deployment_id BIGINT;
@@ -0,0 +1,2 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS basic_auth_password_hash;
+ALTER TABLE applications DROP COLUMN IF EXISTS proxy_settings;
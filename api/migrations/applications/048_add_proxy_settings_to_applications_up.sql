@@ -0,0 +1,2 @@
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS proxy_settings JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS basic_auth_password_hash TEXT NOT NULL DEFAULT '';
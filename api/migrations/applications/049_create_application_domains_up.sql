@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS application_domains (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    domain_id UUID REFERENCES domains(id) ON DELETE SET NULL,
+    name TEXT NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    mode TEXT NOT NULL DEFAULT 'serve' CHECK (mode IN ('serve', 'redirect')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_application_domains_primary_serves CHECK (NOT is_primary OR mode = 'serve')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_domains_name ON application_domains(LOWER(name));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_domains_primary ON application_domains(application_id) WHERE is_primary;
+CREATE INDEX IF NOT EXISTS idx_application_domains_domain_id ON application_domains(domain_id);
+
+INSERT INTO application_domains (application_id, domain_id, name, is_primary, mode)
+SELECT DISTINCT ON (LOWER(a.domain))
+    a.id,
+    (
+        SELECT d.id FROM domains d
+        WHERE d.organization_id = a.organization_id
+          AND d.deleted_at IS NULL
+          AND (LOWER(a.domain) = LOWER(TRIM(LEADING '*.' FROM d.name))
+               OR LOWER(a.domain) LIKE '%.' || LOWER(TRIM(LEADING '*.' FROM d.name)))
+        ORDER BY LENGTH(d.name) DESC
+        LIMIT 1
+    ),
+    a.domain,
+    TRUE,
+    'serve'
+FROM applications a
+WHERE a.domain <> ''
+ORDER BY LOWER(a.domain), a.created_at;
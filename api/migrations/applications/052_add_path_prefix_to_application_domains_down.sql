@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_application_domains_name_path;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_domains_name ON application_domains(LOWER(name));
+
+ALTER TABLE application_domains DROP COLUMN IF EXISTS path_prefix;
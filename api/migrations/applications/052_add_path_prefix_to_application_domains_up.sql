@@ -0,0 +1,4 @@
+ALTER TABLE application_domains ADD COLUMN IF NOT EXISTS path_prefix TEXT NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS idx_application_domains_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_domains_name_path ON application_domains(LOWER(name), path_prefix);
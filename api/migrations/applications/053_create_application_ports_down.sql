@@ -0,0 +1 @@
+DROP TABLE IF EXISTS application_ports;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS application_ports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    server_host TEXT NOT NULL,
+    protocol TEXT NOT NULL CHECK (protocol IN ('tcp', 'udp')),
+    target_port INTEGER NOT NULL CHECK (target_port BETWEEN 1 AND 65535),
+    published_port INTEGER NOT NULL CHECK (published_port BETWEEN 1 AND 65535),
+    sni TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_application_ports_sni_tcp CHECK (sni = '' OR protocol = 'tcp')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_ports_published ON application_ports(server_host, protocol, published_port);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_ports_target ON application_ports(application_id, protocol, target_port);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_ports_sni ON application_ports(LOWER(sni)) WHERE sni <> '';
@@ -0,0 +1 @@
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS maintenance JSONB NOT NULL DEFAULT '{}';
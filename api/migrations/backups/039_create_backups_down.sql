@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS backups;
+DROP TABLE IF EXISTS backup_schedules;
+DROP TABLE IF EXISTS backup_destinations;
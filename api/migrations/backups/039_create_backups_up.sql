@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS backup_destinations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL UNIQUE REFERENCES organizations(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('local', 's3')),
+    local_path TEXT NOT NULL DEFAULT '',
+    endpoint TEXT NOT NULL DEFAULT '',
+    region VARCHAR(100) NOT NULL DEFAULT '',
+    bucket VARCHAR(255) NOT NULL DEFAULT '',
+    prefix TEXT NOT NULL DEFAULT '',
+    access_key TEXT NOT NULL DEFAULT '',
+    secret_key TEXT NOT NULL DEFAULT '',
+    use_ssl BOOLEAN NOT NULL DEFAULT true,
+    force_path_style BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS backup_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    resource_type VARCHAR(20) NOT NULL CHECK (resource_type IN ('database', 'volume')),
+    database_id UUID REFERENCES databases(id) ON DELETE CASCADE,
+    volume_name VARCHAR(255) NOT NULL DEFAULT '',
+    interval_hours INTEGER NOT NULL CHECK (interval_hours > 0),
+    retention_count INTEGER NOT NULL CHECK (retention_count > 0),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_backup_schedules_organization_id ON backup_schedules(organization_id);
+CREATE INDEX idx_backup_schedules_next_run_at ON backup_schedules(next_run_at) WHERE enabled;
+
+CREATE TABLE IF NOT EXISTS backups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    schedule_id UUID REFERENCES backup_schedules(id) ON DELETE SET NULL,
+    resource_type VARCHAR(20) NOT NULL CHECK (resource_type IN ('database', 'volume')),
+    database_id UUID REFERENCES databases(id) ON DELETE SET NULL,
+    database_engine VARCHAR(20) NOT NULL DEFAULT '',
+    database_version VARCHAR(50) NOT NULL DEFAULT '',
+    database_name VARCHAR(255) NOT NULL DEFAULT '',
+    volume_name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('running', 'completed', 'failed')),
+    destination_type VARCHAR(10) NOT NULL,
+    storage_key TEXT NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    checksum VARCHAR(64) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_backups_organization_id ON backups(organization_id);
+CREATE INDEX idx_backups_schedule_id ON backups(schedule_id);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS domain_certificates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_domain_id UUID NOT NULL UNIQUE REFERENCES application_domains(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    mode VARCHAR(10) NOT NULL DEFAULT 'acme' CHECK (mode IN ('acme', 'custom', 'internal')),
+    certificate_pem TEXT NOT NULL DEFAULT '',
+    private_key_pem TEXT NOT NULL DEFAULT '',
+    issuer TEXT NOT NULL DEFAULT '',
+    subject TEXT NOT NULL DEFAULT '',
+    not_before TIMESTAMP WITH TIME ZONE,
+    not_after TIMESTAMP WITH TIME ZONE,
+    last_error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP WITH TIME ZONE,
+    expiry_notified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_domain_certificates_custom_pem CHECK (mode <> 'custom' OR (certificate_pem <> '' AND private_key_pem <> ''))
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_certificates_not_after ON domain_certificates(not_after);
+
+INSERT INTO domain_certificates (application_domain_id, name)
+SELECT id, name FROM application_domains
+ON CONFLICT (application_domain_id) DO NOTHING;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS container_stats (
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    -- the nil uuid for containers started before deployments were labelled
+    deployment_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    container_id TEXT NOT NULL,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL,
+    container_name TEXT NOT NULL DEFAULT '',
+    samples INTEGER NOT NULL DEFAULT 0,
+    cpu_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    cpu_percent_max DOUBLE PRECISION NOT NULL DEFAULT 0,
+    memory_usage BIGINT NOT NULL DEFAULT 0,
+    memory_usage_max BIGINT NOT NULL DEFAULT 0,
+    memory_limit BIGINT NOT NULL DEFAULT 0,
+    network_rx BIGINT NOT NULL DEFAULT 0,
+    network_tx BIGINT NOT NULL DEFAULT 0,
+    block_read BIGINT NOT NULL DEFAULT 0,
+    block_write BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (application_id, deployment_id, container_id, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_container_stats_application_bucket ON container_stats(application_id, bucket);
+CREATE INDEX IF NOT EXISTS idx_container_stats_bucket ON container_stats(bucket);
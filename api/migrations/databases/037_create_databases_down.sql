@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS application_databases;
+DROP TABLE IF EXISTS databases;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS databases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    engine VARCHAR(20) NOT NULL CHECK (engine IN ('postgres', 'mysql', 'redis', 'mongodb')),
+    version VARCHAR(50) NOT NULL,
+    service_name VARCHAR(255) NOT NULL UNIQUE,
+    volume_name VARCHAR(255) NOT NULL,
+    database_name VARCHAR(255) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    password TEXT NOT NULL,
+    port INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'stopped', 'failed')),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, name)
+);
+
+CREATE INDEX idx_databases_organization_id ON databases(organization_id);
+
+CREATE TABLE IF NOT EXISTS application_databases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    database_id UUID NOT NULL REFERENCES databases(id) ON DELETE CASCADE,
+    env_variable VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (application_id, database_id),
+    UNIQUE (application_id, env_variable)
+);
+
+CREATE INDEX idx_application_databases_database_id ON application_databases(database_id);
@@ -0,0 +1,8 @@
+DELETE FROM role_permissions 
+WHERE permission_id IN (
+    SELECT id FROM permissions 
+    WHERE resource = 'database'
+);
+
+DELETE FROM permissions 
+WHERE resource = 'database'; 
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_domains_verified_name_unique;
+DROP INDEX IF EXISTS idx_domains_organization_name_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domains_name_unique ON domains(name) WHERE deleted_at IS NULL;
+
+ALTER TABLE domains DROP COLUMN IF EXISTS last_checked_at;
+ALTER TABLE domains DROP COLUMN IF EXISTS verified_at;
+ALTER TABLE domains DROP COLUMN IF EXISTS verification_token;
@@ -0,0 +1,14 @@
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS verification_token TEXT NOT NULL DEFAULT '';
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS last_checked_at TIMESTAMP WITH TIME ZONE;
+
+-- domains added before verification existed were checked against the server address only,
+-- they stay usable and get a token so they can be verified again after a rename
+UPDATE domains SET verification_token = md5(random()::text || id::text) || md5(random()::text || id::text)
+WHERE verification_token = '';
+UPDATE domains SET verified_at = created_at WHERE verified_at IS NULL AND deleted_at IS NULL;
+
+-- a name can be claimed by several organizations, but verified by one of them only
+DROP INDEX IF EXISTS idx_domains_name_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domains_organization_name_unique ON domains(organization_id, LOWER(name)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domains_verified_name_unique ON domains(LOWER(name)) WHERE deleted_at IS NULL AND verified_at IS NOT NULL;
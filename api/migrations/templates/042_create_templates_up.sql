@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    slug VARCHAR(64) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    category VARCHAR(64) NOT NULL DEFAULT '',
+    website VARCHAR(2048) NOT NULL DEFAULT '',
+    port INTEGER NOT NULL CHECK (port > 0 AND port <= 65535),
+    compose TEXT NOT NULL,
+    env JSONB NOT NULL DEFAULT '[]',
+    volumes JSONB NOT NULL DEFAULT '[]',
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, slug)
+);
+
+CREATE INDEX idx_templates_organization_id ON templates(organization_id);
@@ -0,0 +1,8 @@
+DELETE FROM role_permissions 
+WHERE permission_id IN (
+    SELECT id FROM permissions 
+    WHERE resource = 'template'
+);
+
+DELETE FROM permissions 
+WHERE resource = 'template'; 
\ No newline at end of file
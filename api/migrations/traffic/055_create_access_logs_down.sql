@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS application_http_metrics;
+DROP TABLE IF EXISTS access_logs;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS access_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    method TEXT NOT NULL DEFAULT '',
+    host TEXT NOT NULL DEFAULT '',
+    uri TEXT NOT NULL DEFAULT '',
+    protocol TEXT NOT NULL DEFAULT '',
+    status INTEGER NOT NULL,
+    duration_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    size BIGINT NOT NULL DEFAULT 0,
+    remote_ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_logs_application_timestamp ON access_logs(application_id, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_access_logs_timestamp ON access_logs(timestamp);
+
+CREATE TABLE IF NOT EXISTS application_http_metrics (
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    minute TIMESTAMP WITH TIME ZONE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    status_2xx BIGINT NOT NULL DEFAULT 0,
+    status_4xx BIGINT NOT NULL DEFAULT 0,
+    status_5xx BIGINT NOT NULL DEFAULT 0,
+    p50_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    p95_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    PRIMARY KEY (application_id, minute)
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_http_metrics_minute ON application_http_metrics(minute);
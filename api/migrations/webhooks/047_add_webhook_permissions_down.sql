@@ -0,0 +1,8 @@
+DELETE FROM role_permissions 
+WHERE permission_id IN (
+    SELECT id FROM permissions 
+    WHERE resource = 'webhook'
+);
+
+DELETE FROM permissions 
+WHERE resource = 'webhook'; 
\ No newline at end of file